@@ -16,6 +16,9 @@ import (
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/internal/service"
 	"github.com/crea8r/muninn/server/internal/task"
+	"github.com/crea8r/muninn/server/pkg/blobstore"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc"
 	_ "github.com/lib/pq"
 )
 
@@ -38,11 +41,30 @@ func main() {
 
 	// Initialize services
 	queries := database.New(db)
-	automationSvc := service.NewAutomationService(queries)
-	taskRunner := task.NewRunner(queries, automationSvc)
+	automationSvc := service.NewAutomationService(queries, db)
+	duplicateSvc := service.NewDuplicateService(queries, db)
+	trashSvc := service.NewTrashService(queries, db)
+	taskRunner := task.NewRunner(queries, automationSvc, duplicateSvc, trashSvc)
+
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	sso, err := oidc.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure single sign-on: %v", err)
+	}
+	blobs, err := blobstore.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure blob store: %v", err)
+	}
+	limits, err := blobstore.LimitsFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure attachment limits: %v", err)
+	}
 
 	// Setup router
-	router := api.SetupRouter(queries, db)
+	router := api.SetupRouter(queries, db, mail, sso, blobs, limits)
 	server := &http.Server{
 		Addr:    ":" + getPort(),
 		Handler: router,
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/blobstore"
+	"github.com/crea8r/muninn/server/pkg/pagination"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// attachmentFormMemory is how much of an upload is kept in memory, the rest
+// goes to a temporary file
+const attachmentFormMemory = 1 << 20
+
+// AttachmentHandler uploads files and links them to objects, facts and
+// tasks. Contents are downloaded through signed URLs of the blob store.
+type AttachmentHandler struct {
+	DB          *database.Queries
+	Attachments *service.AttachmentService
+}
+
+func NewAttachmentHandler(db *database.Queries, attachments *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{DB: db, Attachments: attachments}
+}
+
+func (h *AttachmentHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
+type Attachment struct {
+	ID              uuid.UUID                `json:"id"`
+	Filename        string                   `json:"filename"`
+	ContentType     string                   `json:"content_type"`
+	Size            int64                    `json:"size"`
+	Sha256          string                   `json:"sha256"`
+	CreatorID       uuid.UUID                `json:"creator_id"`
+	CreatorUsername string                   `json:"creator_username"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Links           []service.AttachmentLink `json:"links,omitempty"`
+}
+
+// attachmentLinks reads the object_id, fact_id and task_id values of an
+// upload form
+func attachmentLinks(r *http.Request) ([]service.AttachmentLink, error) {
+	var links []service.AttachmentLink
+	for _, kind := range []struct{ field, kind string }{{"object_id", "object"}, {"fact_id", "fact"}, {"task_id", "task"}} {
+		for _, value := range r.MultipartForm.Value[kind.field] {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", kind.field, value)
+			}
+			links = append(links, service.AttachmentLink{Kind: kind.kind, ID: id})
+		}
+	}
+	return links, nil
+}
+
+// Upload stores the file of a multipart form as an attachment, linked to the
+// objects, facts and tasks of the form
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	limits := h.Attachments.Limits()
+	// Leave room for the other fields and the multipart framing
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxSize+attachmentFormMemory)
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("File is larger than the limit of %d bytes", limits.MaxSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	links, err := attachmentLinks(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	attachment, err := h.Attachments.Upload(r.Context(), uuid.MustParse(claims.OrgID), uuid.MustParse(claims.CreatorID), service.Upload{
+		Filename: header.Filename,
+		Body:     file,
+		Checksum: r.FormValue("checksum"),
+	}, links)
+	switch err {
+	case nil:
+	case service.ErrAttachmentTooLarge:
+		http.Error(w, fmt.Sprintf("File is larger than the limit of %d bytes", limits.MaxSize), http.StatusRequestEntityTooLarge)
+		return
+	case service.ErrAttachmentType:
+		http.Error(w, "File type is not allowed", http.StatusUnsupportedMediaType)
+		return
+	case service.ErrAttachmentChecksum:
+		http.Error(w, "File does not match the checksum", http.StatusBadRequest)
+		return
+	case sql.ErrNoRows:
+		http.Error(w, "Object, fact or task to link not found", http.StatusNotFound)
+		return
+	default:
+		http.Error(w, "Failed to upload attachment", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeAttachment(w, r, attachment.ID, http.StatusCreated)
+}
+
+// List returns the attachments of the org, newest first, optionally only those
+// linked to object_id, fact_id or task_id
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	params := pagination.NewParams(r)
+	if err := params.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var filters [3]uuid.NullUUID
+	for i, field := range []string{"object_id", "fact_id", "task_id"} {
+		value := r.URL.Query().Get(field)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid "+field, http.StatusBadRequest)
+			return
+		}
+		filters[i] = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	rows, err := h.q(r.Context()).ListAttachments(r.Context(), database.ListAttachmentsParams{
+		OrgID:  uuid.MustParse(claims.OrgID),
+		ObjID:  filters[0],
+		FactID: filters[1],
+		TaskID: filters[2],
+		Limit:  params.PageSize,
+		Offset: params.GetOffset(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to list attachments", http.StatusInternalServerError)
+		return
+	}
+	result := pagination.PaginatedResult[Attachment]{
+		Items:      make([]Attachment, len(rows)),
+		TotalCount: int64(0),
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+	}
+	for i, row := range rows {
+		result.TotalCount = row.TotalCount
+		result.Items[i] = Attachment{
+			ID:              row.ID,
+			Filename:        row.Filename,
+			ContentType:     row.ContentType,
+			Size:            row.Size,
+			Sha256:          row.Sha256,
+			CreatorID:       row.CreatorID,
+			CreatorUsername: row.CreatorUsername,
+			CreatedAt:       row.CreatedAt,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Get returns an attachment with the objects, facts and tasks it is linked to
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	h.writeAttachment(w, r, id, http.StatusOK)
+}
+
+// writeAttachment answers with the attachment and its links
+func (h *AttachmentHandler) writeAttachment(w http.ResponseWriter, r *http.Request, id uuid.UUID, status int) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	row, err := h.q(r.Context()).GetAttachment(r.Context(), database.GetAttachmentParams{ID: id, OrgID: uuid.MustParse(claims.OrgID)})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get attachment", http.StatusInternalServerError)
+		return
+	}
+	linkRows, err := h.q(r.Context()).ListAttachmentLinks(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to get attachment links", http.StatusInternalServerError)
+		return
+	}
+	links := make([]service.AttachmentLink, len(linkRows))
+	for i, link := range linkRows {
+		links[i] = service.AttachmentLink{Kind: link.Kind, ID: link.ID, Title: link.Title}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Attachment{
+		ID:              row.ID,
+		Filename:        row.Filename,
+		ContentType:     row.ContentType,
+		Size:            row.Size,
+		Sha256:          row.Sha256,
+		CreatorID:       row.CreatorID,
+		CreatorUsername: row.CreatorUsername,
+		CreatedAt:       row.CreatedAt,
+		Links:           links,
+	})
+}
+
+// DownloadURL returns a signed URL of the content of an attachment, valid for
+// expires_in seconds
+func (h *AttachmentHandler) DownloadURL(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	var expires time.Duration
+	if value := r.URL.Query().Get("expires_in"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 1 {
+			http.Error(w, "Invalid expires_in", http.StatusBadRequest)
+			return
+		}
+		// Longer than stores allow is capped, the cap also keeps it from overflowing
+		expires = time.Duration(min(seconds, int64(blobstore.MaxURLExpiry/time.Second))) * time.Second
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	url, expiresAt, err := h.Attachments.DownloadURL(r.Context(), uuid.MustParse(claims.OrgID), id, expires)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to sign download URL", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+
+// Delete deletes an attachment and its content
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.Attachments.Delete(r.Context(), uuid.MustParse(claims.OrgID), id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete attachment", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddLink links an attachment to an object, a fact or a task
+func (h *AttachmentHandler) AddLink(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	var link service.AttachmentLink
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !service.IsValidAttachmentLinkKind(link.Kind) {
+		http.Error(w, "Invalid kind, expected object, fact or task", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.Attachments.Link(r.Context(), uuid.MustParse(claims.OrgID), id, link)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Attachment or "+link.Kind+" not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to link attachment", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveLink unlinks an attachment from an object, a fact or a task
+func (h *AttachmentHandler) RemoveLink(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	kind := chi.URLParam(r, "kind")
+	if !service.IsValidAttachmentLinkKind(kind) {
+		http.Error(w, "Invalid kind, expected object, fact or task", http.StatusBadRequest)
+		return
+	}
+	targetID, err := uuid.Parse(chi.URLParam(r, "targetId"))
+	if err != nil {
+		http.Error(w, "Invalid "+kind+" ID", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.Attachments.Unlink(r.Context(), uuid.MustParse(claims.OrgID), id, service.AttachmentLink{Kind: kind, ID: targetID})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Link not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to unlink attachment", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Limits returns the size and type limits of uploads
+func (h *AttachmentHandler) Limits(w http.ResponseWriter, r *http.Request) {
+	limits := h.Attachments.Limits()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"max_size": limits.MaxSize,
+		"types":    limits.Types,
+	})
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+type AuditHandler struct {
+	DB *database.Queries
+}
+
+func NewAuditHandler(db *database.Queries) *AuditHandler {
+	return &AuditHandler{DB: db}
+}
+
+func (h *AuditHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
+type AuditEventResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	ActorID        ctype.NullUUID  `json:"actor_id"`
+	ActorUsername  string          `json:"actor_username"`
+	ImpersonatorID ctype.NullUUID  `json:"impersonator_id"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       uuid.UUID       `json:"entity_id"`
+	Action         string          `json:"action"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// ListEvents returns the audit events of the org, newest first. They can be
+// filtered by entity_type, entity_id, actor_id and a from/to time range.
+// Admins can export them as CSV with format=csv.
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	query := r.URL.Query()
+	asCSV := query.Get("format") == "csv"
+	if asCSV && claims.Role != middleware.RoleAdmin {
+		http.Error(w, "Only admins can export the audit log", http.StatusForbidden)
+		return
+	}
+
+	params := database.ListAuditEventsParams{
+		OrgID:  uuid.MustParse(claims.OrgID),
+		Limit:  100,
+		Offset: 0,
+	}
+	maxLimit := 1000
+	if asCSV {
+		params.Limit = 10000
+		maxLimit = 100000
+	}
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && l <= maxLimit {
+		params.Limit = int32(l)
+	}
+	if o, err := strconv.Atoi(query.Get("offset")); err == nil && o > 0 {
+		params.Offset = int32(o)
+	}
+	if entityType := query.Get("entity_type"); entityType != "" {
+		params.EntityType = sql.NullString{String: entityType, Valid: true}
+	}
+	for name, target := range map[string]*uuid.NullUUID{
+		"entity_id": &params.EntityID,
+		"actor_id":  &params.ActorID,
+	} {
+		if v := query.Get(name); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*target = uuid.NullUUID{UUID: id, Valid: true}
+		}
+	}
+	for name, target := range map[string]*sql.NullTime{
+		"from": &params.From,
+		"to":   &params.To,
+	} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "Invalid "+name+", expected an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			*target = sql.NullTime{Time: t, Valid: true}
+		}
+	}
+
+	events, err := h.q(r.Context()).ListAuditEvents(r.Context(), params)
+	if err != nil {
+		http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+		return
+	}
+	if asCSV {
+		writeAuditCSV(w, events)
+		return
+	}
+	response := make([]AuditEventResponse, len(events))
+	for i, e := range events {
+		response[i] = AuditEventResponse{
+			ID:             e.ID,
+			ActorID:        ctype.NullUUID{NullUUID: e.ActorID},
+			ActorUsername:  e.ActorUsername,
+			ImpersonatorID: ctype.NullUUID{NullUUID: e.ImpersonatorID},
+			EntityType:     e.EntityType,
+			EntityID:       e.EntityID,
+			Action:         e.Action,
+			Before:         auditJSON(e.Before),
+			After:          auditJSON(e.After),
+			CreatedAt:      e.CreatedAt,
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeAuditCSV(w http.ResponseWriter, events []database.ListAuditEventsRow) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-`+time.Now().Format("20060102-150405")+`.csv"`)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"created_at", "actor_id", "actor_username", "impersonator_id",
+		"entity_type", "entity_id", "action", "before", "after"})
+	for _, e := range events {
+		cw.Write([]string{
+			e.CreatedAt.Format(time.RFC3339),
+			nullUUIDString(e.ActorID),
+			e.ActorUsername,
+			nullUUIDString(e.ImpersonatorID),
+			e.EntityType,
+			e.EntityID.String(),
+			e.Action,
+			string(auditJSON(e.Before)),
+			string(auditJSON(e.After)),
+		})
+	}
+	cw.Flush()
+}
+
+func auditJSON(m pqtype.NullRawMessage) json.RawMessage {
+	if !m.Valid {
+		return json.RawMessage("null")
+	}
+	return m.RawMessage
+}
+
+func nullUUIDString(id uuid.NullUUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return id.UUID.String()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -21,6 +22,10 @@ func NewAutomationHandler(db *database.Queries) *AutomationHandler {
     return &AutomationHandler{db: db}
 }
 
+func (h *AutomationHandler) q(ctx context.Context) *database.Queries {
+    return middleware.Queries(ctx, h.db)
+}
+
 // ListAutomatedActionsRequest represents the query parameters for listing actions
 type ListAutomatedActionsRequest struct {
     Page     int32  `json:"page"`
@@ -67,7 +72,7 @@ func (h *AutomationHandler) CreateAction(w http.ResponseWriter, r *http.Request)
         return
     }
 
-    action, err := h.db.CreateAutomatedAction(r.Context(), database.CreateAutomatedActionParams{
+    action, err := h.q(r.Context()).CreateAutomatedAction(r.Context(), database.CreateAutomatedActionParams{
         OrgID:        uuid.MustParse(claims.OrgID),
         Name:         input.Name,
         Description:  input.Description,
@@ -100,7 +105,7 @@ func (h *AutomationHandler) ListActions(w http.ResponseWriter, r *http.Request)
     search := r.URL.Query().Get("q")
 
     // Get actions with their latest execution
-    actions, err := h.db.ListAutomatedActions(r.Context(), database.ListAutomatedActionsParams{
+    actions, err := h.q(r.Context()).ListAutomatedActions(r.Context(), database.ListAutomatedActionsParams{
         OrgID:    orgID,
         Column2:   search,
         Limit:    int32(pageSize),
@@ -112,7 +117,7 @@ func (h *AutomationHandler) ListActions(w http.ResponseWriter, r *http.Request)
     }
 
     // Get total count
-    totalCount, err := h.db.CountAutomatedActions(r.Context(), database.CountAutomatedActionsParams{
+    totalCount, err := h.q(r.Context()).CountAutomatedActions(r.Context(), database.CountAutomatedActionsParams{
         OrgID:  orgID,
         Column2: search,
     })
@@ -149,7 +154,7 @@ func (h *AutomationHandler) ListActions(w http.ResponseWriter, r *http.Request)
         }
 
         // Get latest execution if exists
-        lastExec, err := h.db.GetLatestExecution(r.Context(), action.ID)
+        lastExec, err := h.q(r.Context()).GetLatestExecution(r.Context(), action.ID)
         if err == nil {
             response.Actions[i].LastExecution = &ExecutionSummary{
                 ID:               lastExec.ID,
@@ -181,7 +186,7 @@ func (h *AutomationHandler) GetExecutionLogs(w http.ResponseWriter, r *http.Requ
     }
 
     // Verify action belongs to org
-    action, err := h.db.GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
+    action, err := h.q(r.Context()).GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
     if err != nil {
         http.Error(w, "Action not found", http.StatusNotFound)
         return
@@ -192,7 +197,7 @@ func (h *AutomationHandler) GetExecutionLogs(w http.ResponseWriter, r *http.Requ
     }
 
     // Get executions
-    executions, err := h.db.ListActionExecutions(r.Context(), database.ListActionExecutionsParams{
+    executions, err := h.q(r.Context()).ListActionExecutions(r.Context(), database.ListActionExecutionsParams{
         ActionID: uuid.MustParse(actionID),
         Limit:    int32(pageSize),
         Offset:   int32((page - 1) * pageSize),
@@ -203,7 +208,7 @@ func (h *AutomationHandler) GetExecutionLogs(w http.ResponseWriter, r *http.Requ
     }
 
     // Get total count
-    totalCount, err := h.db.CountActionExecutions(r.Context(), uuid.MustParse(actionID))
+    totalCount, err := h.q(r.Context()).CountActionExecutions(r.Context(), uuid.MustParse(actionID))
     if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
@@ -273,7 +278,7 @@ func (h *AutomationHandler) UpdateAction(w http.ResponseWriter, r *http.Request)
     }
 
     // Verify action belongs to org
-    action, err := h.db.GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
+    action, err := h.q(r.Context()).GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
     if err != nil {
         http.Error(w, "Action not found", http.StatusNotFound)
         return
@@ -284,7 +289,7 @@ func (h *AutomationHandler) UpdateAction(w http.ResponseWriter, r *http.Request)
     }
 
     // Update action
-    updatedAction, err := h.db.UpdateAutomatedAction(r.Context(), database.UpdateAutomatedActionParams{
+    updatedAction, err := h.q(r.Context()).UpdateAutomatedAction(r.Context(), database.UpdateAutomatedActionParams{
         ID:           uuid.MustParse(actionID),
         Name:         input.Name,
         Description:  input.Description,
@@ -306,7 +311,7 @@ func (h *AutomationHandler) DeleteAction(w http.ResponseWriter, r *http.Request)
     claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
     // Verify action belongs to org
-    action, err := h.db.GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
+    action, err := h.q(r.Context()).GetAutomatedAction(r.Context(), uuid.MustParse(actionID))
     if err != nil {
         http.Error(w, "Action not found", http.StatusNotFound)
         return
@@ -317,7 +322,7 @@ func (h *AutomationHandler) DeleteAction(w http.ResponseWriter, r *http.Request)
     }
 
     // Delete action
-    err = h.db.DeleteAutomatedAction(r.Context(), uuid.MustParse(actionID))
+    err = h.q(r.Context()).DeleteAutomatedAction(r.Context(), uuid.MustParse(actionID))
     if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/models"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CommentHandler serves the discussions about objects and tasks
+type CommentHandler struct {
+	Comments *service.CommentService
+	authz    *middleware.Authorizer
+}
+
+func NewCommentHandler(comments *service.CommentService, authz *middleware.Authorizer) *CommentHandler {
+	return &CommentHandler{Comments: comments, authz: authz}
+}
+
+// writeCommentError answers with the status of an error of the comment service
+func writeCommentError(w http.ResponseWriter, err error, notFound string) {
+	if err == sql.ErrNoRows {
+		http.Error(w, notFound, http.StatusNotFound)
+		return
+	}
+	if err == service.ErrCommentNotAuthor {
+		http.Error(w, "Only the author can change the comment", http.StatusForbidden)
+		return
+	}
+	if _, ok := err.(service.CommentError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, "Failed to save comment", http.StatusInternalServerError)
+}
+
+// writeComment answers with the comment as the discussion shows it
+func (h *CommentHandler) writeComment(w http.ResponseWriter, r *http.Request, orgID, id uuid.UUID, status int) {
+	rows, err := h.Comments.Get(r.Context(), orgID, id)
+	if err != nil {
+		http.Error(w, "Failed to get comment", http.StatusInternalServerError)
+		return
+	}
+	if len(rows) == 0 {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.NewComments(rows)[0])
+}
+
+func (h *CommentHandler) list(w http.ResponseWriter, r *http.Request, target service.CommentTarget) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	rows, err := h.Comments.List(r.Context(), uuid.MustParse(claims.OrgID), target)
+	if err != nil {
+		http.Error(w, "Failed to list comments", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewComments(rows))
+}
+
+func (h *CommentHandler) create(w http.ResponseWriter, r *http.Request, target service.CommentTarget) {
+	var input struct {
+		Text     string         `json:"text"`
+		ParentID ctype.NullUUID `json:"parentId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	comment, err := h.Comments.Create(r.Context(), orgID, uuid.MustParse(claims.CreatorID), target, input.ParentID.NullUUID, input.Text)
+	if err != nil {
+		writeCommentError(w, err, "Not found, or the parent comment is about something else")
+		return
+	}
+	h.writeComment(w, r, orgID, comment.ID, http.StatusCreated)
+}
+
+// ListObjectComments returns the comments about an object, oldest first
+func (h *CommentHandler) ListObjectComments(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, service.CommentTarget{ObjID: uuid.NullUUID{UUID: objectID, Valid: true}})
+}
+
+// CreateObjectComment comments on an object, or replies to parentId
+func (h *CommentHandler) CreateObjectComment(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	h.create(w, r, service.CommentTarget{ObjID: uuid.NullUUID{UUID: objectID, Valid: true}})
+}
+
+// ListTaskComments returns the comments about a task, oldest first
+func (h *CommentHandler) ListTaskComments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, service.CommentTarget{TaskID: uuid.NullUUID{UUID: taskID, Valid: true}})
+}
+
+// CreateTaskComment comments on a task, or replies to parentId
+func (h *CommentHandler) CreateTaskComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+	h.create(w, r, service.CommentTarget{TaskID: uuid.NullUUID{UUID: taskID, Valid: true}})
+}
+
+// Update changes the text of a comment of the creator
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	if _, err := h.Comments.Update(r.Context(), orgID, uuid.MustParse(claims.CreatorID), id, input.Text); err != nil {
+		writeCommentError(w, err, "Comment not found")
+		return
+	}
+	h.writeComment(w, r, orgID, id, http.StatusOK)
+}
+
+// Delete deletes a comment of the creator, members with comment:moderate
+// delete those of others too
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	moderate, err := h.authz.Can(r.Context(), claims, "comment:moderate")
+	if err != nil {
+		http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+		return
+	}
+	err = h.Comments.Delete(r.Context(), uuid.MustParse(claims.OrgID), uuid.MustParse(claims.CreatorID), id, moderate)
+	if err != nil {
+		writeCommentError(w, err, "Comment not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddReaction adds an emoji reaction of the creator to a comment
+func (h *CommentHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		Emoji string `json:"emoji"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	if err := h.Comments.React(r.Context(), orgID, uuid.MustParse(claims.CreatorID), id, input.Emoji); err != nil {
+		writeCommentError(w, err, "Comment not found")
+		return
+	}
+	h.writeComment(w, r, orgID, id, http.StatusOK)
+}
+
+// RemoveReaction removes an emoji reaction of the creator
+func (h *CommentHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	if err := h.Comments.Unreact(r.Context(), orgID, uuid.MustParse(claims.CreatorID), id, chi.URLParam(r, "emoji")); err != nil {
+		writeCommentError(w, err, "Reaction not found")
+		return
+	}
+	h.writeComment(w, r, orgID, id, http.StatusOK)
+}
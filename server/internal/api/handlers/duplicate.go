@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/crea8r/muninn/server/pkg/pagination"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// DuplicateHandler serves the queue of possible duplicate objects found by
+// service.DuplicateService. Accepting a suggestion merges the pair.
+type DuplicateHandler struct {
+	DB         *database.Queries
+	Duplicates *service.DuplicateService
+	Merge      *MergeObjectsHandler
+}
+
+func NewDuplicateHandler(db *database.Queries, duplicates *service.DuplicateService, merge *MergeObjectsHandler) *DuplicateHandler {
+	return &DuplicateHandler{DB: db, Duplicates: duplicates, Merge: merge}
+}
+
+func (h *DuplicateHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
+type DuplicateObject struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	IDString string    `json:"id_string"`
+}
+
+type DuplicateSuggestionResponse struct {
+	ID             uuid.UUID         `json:"id"`
+	Objects        []DuplicateObject `json:"objects"`
+	Score          float32           `json:"score"`
+	Signals        json.RawMessage   `json:"signals"`
+	Status         string            `json:"status"`
+	ResolvedBy     ctype.NullUUID    `json:"resolved_by"`
+	ResolvedAt     ctype.NullTime    `json:"resolved_at"`
+	MergeHistoryID ctype.NullUUID    `json:"merge_history_id"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// ListSuggestions returns the suggestions of the org with the given status,
+// pending by default, most likely duplicates first
+func (h *DuplicateHandler) ListSuggestions(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "pending"
+	case "pending", "accepted", "dismissed":
+	default:
+		http.Error(w, "Invalid status, expected pending, accepted or dismissed", http.StatusBadRequest)
+		return
+	}
+	params := pagination.NewParams(r)
+	if err := params.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.q(r.Context()).ListDuplicateSuggestions(r.Context(), database.ListDuplicateSuggestionsParams{
+		OrgID:  uuid.MustParse(claims.OrgID),
+		Status: status,
+		Limit:  params.PageSize,
+		Offset: params.GetOffset(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to list duplicate suggestions", http.StatusInternalServerError)
+		return
+	}
+	result := pagination.PaginatedResult[DuplicateSuggestionResponse]{
+		Items:      make([]DuplicateSuggestionResponse, len(rows)),
+		TotalCount: int64(0),
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+	}
+	for i, s := range rows {
+		result.TotalCount = s.TotalCount
+		result.Items[i] = DuplicateSuggestionResponse{
+			ID: s.ID,
+			Objects: []DuplicateObject{
+				{ID: s.ObjAID, Name: s.ObjAName, IDString: s.ObjAIDString},
+				{ID: s.ObjBID, Name: s.ObjBName, IDString: s.ObjBIDString},
+			},
+			Score:          s.Score,
+			Signals:        s.Signals,
+			Status:         s.Status,
+			ResolvedBy:     ctype.NullUUID{NullUUID: s.ResolvedBy},
+			ResolvedAt:     ctype.NullTime{NullTime: s.ResolvedAt},
+			MergeHistoryID: ctype.NullUUID{NullUUID: s.MergeHistoryID},
+			CreatedAt:      s.CreatedAt,
+			UpdatedAt:      s.UpdatedAt,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+type AcceptDuplicateRequest struct {
+	// TargetObjectID is the object of the pair that is kept, the older one
+	// by default
+	TargetObjectID uuid.UUID `json:"target_object_id"`
+}
+
+// pendingSuggestion locks the suggestion and writes the error when it is not
+// pending anymore
+func (h *DuplicateHandler) pendingSuggestion(w http.ResponseWriter, r *http.Request, orgID uuid.UUID) (*database.GetDuplicateSuggestionRow, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return nil, false
+	}
+	suggestion, err := h.q(r.Context()).GetDuplicateSuggestion(r.Context(), database.GetDuplicateSuggestionParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Suggestion not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "Failed to get suggestion", http.StatusInternalServerError)
+		return nil, false
+	}
+	if suggestion.Status != "pending" {
+		http.Error(w, "Suggestion is already "+suggestion.Status, http.StatusConflict)
+		return nil, false
+	}
+	return &suggestion, true
+}
+
+// AcceptSuggestion merges the pair the way the merge preview proposes, then
+// marks the suggestion accepted. The merge can be undone like any other.
+func (h *DuplicateHandler) AcceptSuggestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims := ctx.Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	var req AcceptDuplicateRequest
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	suggestion, ok := h.pendingSuggestion(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	target, source := suggestion.ObjAID, suggestion.ObjBID
+	if suggestion.ObjBCreatedAt.Before(suggestion.ObjACreatedAt) {
+		target, source = source, target
+	}
+	switch req.TargetObjectID {
+	case uuid.Nil, target:
+	case source:
+		target, source = source, target
+	default:
+		http.Error(w, "target_object_id must be one of the pair", http.StatusBadRequest)
+		return
+	}
+
+	historyID, err := h.accept(ctx, claims, suggestion.ID, target, source)
+	if err != nil {
+		if _, ok := err.(mergeValidationError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":          "Objects merged successfully",
+		"target_object_id": target.String(),
+		"history_id":       historyID.String(),
+	})
+}
+
+func (h *DuplicateHandler) accept(ctx context.Context, claims *middleware.Claims, suggestionID, target, source uuid.UUID) (uuid.UUID, error) {
+	orgID := uuid.MustParse(claims.OrgID)
+	preview, err := h.Merge.previewMerge(ctx, claims, MergePreviewRequest{
+		TargetObjectID:  target,
+		SourceObjectIDs: []uuid.UUID{source},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	historyID, err := h.Merge.mergeObjects(ctx, claims, preview.Proposed)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	_, err = h.q(ctx).ResolveDuplicateSuggestion(ctx, database.ResolveDuplicateSuggestionParams{
+		ID:             suggestionID,
+		Status:         "accepted",
+		ResolvedBy:     uuid.NullUUID{UUID: uuid.MustParse(claims.CreatorID), Valid: true},
+		MergeHistoryID: uuid.NullUUID{UUID: historyID, Valid: true},
+		OrgID:          orgID,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Error accepting suggestion: %v", err)
+	}
+	// Other pairs with the merged away object are stale now
+	if err := h.Duplicates.ClearStale(ctx, orgID); err != nil {
+		return uuid.Nil, fmt.Errorf("Error clearing stale suggestions: %v", err)
+	}
+	return historyID, nil
+}
+
+// DismissSuggestion marks the pair as no duplicates, later scans do not
+// suggest it again
+func (h *DuplicateHandler) DismissSuggestion(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	suggestion, ok := h.pendingSuggestion(w, r, orgID)
+	if !ok {
+		return
+	}
+	_, err := h.q(r.Context()).ResolveDuplicateSuggestion(r.Context(), database.ResolveDuplicateSuggestionParams{
+		ID:         suggestion.ID,
+		Status:     "dismissed",
+		ResolvedBy: uuid.NullUUID{UUID: uuid.MustParse(claims.CreatorID), Valid: true},
+		OrgID:      orgID,
+	})
+	if err != nil {
+		http.Error(w, "Failed to dismiss suggestion", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Scan looks for duplicates among the objects changed since the last scan
+// without waiting for the background detector
+func (h *DuplicateHandler) Scan(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	found, err := h.Duplicates.Scan(r.Context(), uuid.MustParse(claims.OrgID))
+	if err != nil {
+		http.Error(w, "Failed to scan for duplicates", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int64{"found": found})
+}
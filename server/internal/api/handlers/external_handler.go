@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
@@ -25,6 +27,10 @@ func NewExternalHandler(db *sql.DB, queries *database.Queries) *ExternalHandler
 	return &ExternalHandler{db: db, queries: queries}
 }
 
+func (h *ExternalHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.queries)
+}
+
 type CreateExternalFactRequest struct {
 	Aliases     []string  `json:"aliases"`      // List of id_strings or aliases to link
 	Text        string    `json:"text"`         // Fact content
@@ -50,15 +56,8 @@ func (h *ExternalHandler) CreateFact(w http.ResponseWriter, r *http.Request) {
 			return
 	}
 
-	// Start transaction
-	tx, err := h.db.BeginTx(ctx, nil)
-	if err != nil {
-			http.Error(w, "Failed to start transaction", http.StatusInternalServerError)
-			return
-	}
-	defer tx.Rollback()
-
-	qtx := h.queries.WithTx(tx)
+	// The request transaction is rolled back if any step fails
+	qtx := h.q(ctx)
 
 	// Track objects we find or create
 	type objectRef struct {
@@ -116,6 +115,7 @@ func (h *ExternalHandler) CreateFact(w http.ResponseWriter, r *http.Request) {
 			HappenedAt: sql.NullTime{Time: req.HappenedAt, Valid: !req.HappenedAt.IsZero()},
 			Location:   req.Location,
 			CreatorID:  creatorID,
+			Kind:       service.FactKindNote,
 	})
 	if err != nil {
 			http.Error(w, "Failed to create fact", http.StatusInternalServerError)
@@ -131,12 +131,6 @@ func (h *ExternalHandler) CreateFact(w http.ResponseWriter, r *http.Request) {
 			OrgID:    uuid.MustParse(claims.OrgID),
 	});
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-			http.Error(w, "Failed to commit transaction", http.StatusInternalServerError)
-			return
-	}
-
 	response := CreateExternalFactResponse{
 			FactID:    fact.ID,
 			ObjectIDs: objectIDs,
@@ -185,7 +179,7 @@ func (h *ExternalHandler) UpsertObjectTypeValue(w http.ResponseWriter, r *http.R
 			http.Error(w, "Invalid object_id format", http.StatusBadRequest)
 			return
 		}
-		r, err := h.queries.SyncObjectAliases(ctx, database.SyncObjectAliasesParams{
+		r, err := h.q(ctx).SyncObjectAliases(ctx, database.SyncObjectAliasesParams{
 			Column1: aliases,
 			OrgID:   orgID,
 		});
@@ -195,7 +189,7 @@ func (h *ExternalHandler) UpsertObjectTypeValue(w http.ResponseWriter, r *http.R
 			return
 		}else if err == sql.ErrNoRows {
 			idString := aliases[0]
-			newObj, errCreateObj := h.queries.CreateObject(ctx, database.CreateObjectParams{
+			newObj, errCreateObj := h.q(ctx).CreateObject(ctx, database.CreateObjectParams{
 				Name:        idString, // Use alias as initial name
 				Description: idString,
 				IDString:   idString,
@@ -208,12 +202,13 @@ func (h *ExternalHandler) UpsertObjectTypeValue(w http.ResponseWriter, r *http.R
 			objectID = newObj.ID
 			aliasesWithoutId := aliases[1:]
 			if len(aliases) > 1 {
-				h.queries.UpdateObject(ctx, database.UpdateObjectParams{
+				h.q(ctx).UpdateObject(ctx, database.UpdateObjectParams{
 					ID: objectID,
 					Name: idString,
 					Description: strings.Join(aliasesWithoutId, ", "),
 					IDString: idString,
 					Aliases: pq.StringArray(aliasesWithoutId),
+					OrgID: orgID,
 				})
 			}
 		} else if err == nil {
@@ -234,7 +229,7 @@ func (h *ExternalHandler) UpsertObjectTypeValue(w http.ResponseWriter, r *http.R
 	}
 
 	// Perform upsert
-	result, err := h.queries.UpsertObjectTypeValue(ctx, database.UpsertObjectTypeValueParams{
+	result, err := h.q(ctx).UpsertObjectTypeValue(ctx, database.UpsertObjectTypeValueParams{
 		ObjID:      objectID,
 		TypeID:     objectTypeID,
 		TypeValues: req.TypeValues,
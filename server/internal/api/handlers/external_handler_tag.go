@@ -55,7 +55,7 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid object_id format", http.StatusBadRequest)
 			return
 		}
-		r, err := h.queries.SyncObjectAliases(ctx, database.SyncObjectAliasesParams{
+		r, err := h.q(ctx).SyncObjectAliases(ctx, database.SyncObjectAliasesParams{
 			Column1: aliases,
 			OrgID:   orgID,
 		});
@@ -65,7 +65,7 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 			return
 		}else if err == sql.ErrNoRows {
 			idString := aliases[0]
-			newObj, errCreateObj := h.queries.CreateObject(ctx, database.CreateObjectParams{
+			newObj, errCreateObj := h.q(ctx).CreateObject(ctx, database.CreateObjectParams{
 				Name:        idString, // Use alias as initial name
 				Description: idString,
 				IDString:   idString,
@@ -77,12 +77,13 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 			}
 			objectID = newObj.ID
 			if len(aliases) > 1 {
-				h.queries.UpdateObject(ctx, database.UpdateObjectParams{
+				h.q(ctx).UpdateObject(ctx, database.UpdateObjectParams{
 					ID: objectID,
 					Name: idString,
 					Description: strings.Join(aliases, ", "),
 					IDString: idString,
 					Aliases: pq.StringArray(aliases),
+					OrgID: orgID,
 				})
 			}
 		} else if err == nil {
@@ -90,15 +91,8 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Start transaction
-	tx, err := h.db.BeginTx(ctx, nil)
-	if err != nil {
-		http.Error(w, "Failed to start transaction", http.StatusInternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	qtx := h.queries.WithTx(tx)
+	// The request transaction is rolled back if any step fails
+	qtx := h.q(ctx)
 
 	// Process each tag
 	var processedTags []TagDetail
@@ -157,11 +151,15 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Try to link tag to object
-		err = qtx.AddTagToObject(ctx, database.AddTagToObjectParams{
+		_, err = qtx.AddTagToObject(ctx, database.AddTagToObjectParams{
 			ObjID:  objectID,
 			TagID:  tagID,
 			OrgID:  orgID,
 		})
+		if err == sql.ErrNoRows {
+			http.Error(w, "Object not found", http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			// Ignore duplicate tag links
 			if !strings.Contains(err.Error(), "duplicate key value") {
@@ -179,12 +177,6 @@ func (h *ExternalHandler) TagObject(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-			http.Error(w, "Failed to commit transaction", http.StatusInternalServerError)
-			return
-	}
-
 	// Send response
 	response := TagObjectResponse{
 			ObjectID: req.ObjectID,
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -9,17 +10,23 @@ import (
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
 	"github.com/crea8r/muninn/server/pkg/ctype"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type FactHandler struct {
-	db *database.Queries
+	db    *database.Queries
+	facts *service.FactService
 }
 
-func NewFactHandler(db *database.Queries) *FactHandler {
-	return &FactHandler{db: db}
+func NewFactHandler(db *database.Queries, facts *service.FactService) *FactHandler {
+	return &FactHandler{db: db, facts: facts}
+}
+
+func (h *FactHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
 }
 
 type FactToCreate struct {
@@ -27,6 +34,21 @@ type FactToCreate struct {
 	HappenedAt ctype.NullTime`json:"happenedAt"`
 	Location   string    `json:"location"`
 	ObjectIDs  []string  `json:"objectIds"`
+	// Kind is a note unless set, the other fields depend on the kind
+	Kind       string    `json:"kind"`
+	Duration   int32     `json:"duration"`
+	Direction  string    `json:"direction"`
+	Outcome    string    `json:"outcome"`
+	Attendees  service.FactAttendees `json:"attendees"`
+}
+
+// writeFactError answers with the status of an error of the fact service
+func writeFactError(w http.ResponseWriter, err error) {
+	if _, ok := err.(service.FactError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
 func (h *FactHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +61,23 @@ func (h *FactHandler) Create(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	creatorID := claims.CreatorID
 
-	fact, err := h.db.CreateFact(r.Context(), database.CreateFactParams{
+	details := service.FactDetails{
+		Kind:            input.Kind,
+		DurationMinutes: input.Duration,
+		Direction:       input.Direction,
+		Outcome:         input.Outcome,
+		Attendees:       input.Attendees,
+	}
+	if details.Kind == "" {
+		details.Kind = service.FactKindNote
+	}
+	if err := h.facts.ValidateDetails(r.Context(), uuid.MustParse(claims.OrgID), details); err != nil {
+		writeFactError(w, err)
+		return
+	}
+	kind, duration, direction, outcome := details.Params()
+
+	fact, err := h.q(r.Context()).CreateFact(r.Context(), database.CreateFactParams{
 		Text:       input.Text,
 		HappenedAt: sql.NullTime{
 			Time:  input.HappenedAt.Time,
@@ -47,6 +85,10 @@ func (h *FactHandler) Create(w http.ResponseWriter, r *http.Request) {
 		},
 		Location:   input.Location,
 		CreatorID:  uuid.MustParse(creatorID),
+		Kind:            kind,
+		DurationMinutes: duration,
+		Direction:       direction,
+		Outcome:         outcome,
 	})
 
 	if err != nil {
@@ -61,7 +103,7 @@ func (h *FactHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 
 		orgID := claims.OrgID
-		err = h.db.AddObjectsToFact(r.Context(), database.AddObjectsToFactParams{
+		err = h.q(r.Context()).AddObjectsToFact(r.Context(), database.AddObjectsToFactParams{
 			Column1: objectIDs,
 			FactID: fact.ID,
 			OrgID: uuid.MustParse(orgID),
@@ -73,6 +115,20 @@ func (h *FactHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Objects and creators mentioned in the text are linked and notified
+	// whether or not the client sent them in objectIds
+	err = h.facts.SyncMentions(r.Context(), uuid.MustParse(claims.OrgID), uuid.MustParse(creatorID), fact, "", nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.facts.SetAttendees(r.Context(), uuid.MustParse(claims.OrgID), fact.ID, details.Attendees)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	json.NewEncoder(w).Encode(fact)
 }
 
@@ -85,14 +141,63 @@ func (h *FactHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Location   string    `json:"location"`
 		ToAddObjectIDs  []string  `json:"toAddObjectIDs"`
 		ToRemoveObjectIDs  []string  `json:"toRemoveObjectIDs"`
+		// Structured fields left out keep their value, unless the kind
+		// changes, which clears those not sent
+		Kind       *string   `json:"kind"`
+		Duration   *int32    `json:"duration"`
+		Direction  *string   `json:"direction"`
+		Outcome    *string   `json:"outcome"`
+		Attendees  *service.FactAttendees `json:"attendees"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := claims.OrgID
+
+	previous, err := h.q(r.Context()).GetFactByID(r.Context(), database.GetFactByIDParams{
+		ID:    uuid.MustParse(factID),
+		OrgID: uuid.MustParse(orgID),
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Fact not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	details := service.FactDetails{Kind: previous.Kind}
+	kindChanged := input.Kind != nil && *input.Kind != previous.Kind
+	if kindChanged {
+		details.Kind = *input.Kind
+	} else {
+		details.DurationMinutes = previous.DurationMinutes.Int32
+		details.Direction = previous.Direction.String
+		details.Outcome = previous.Outcome
+	}
+	if input.Duration != nil {
+		details.DurationMinutes = *input.Duration
+	}
+	if input.Direction != nil {
+		details.Direction = *input.Direction
+	}
+	if input.Outcome != nil {
+		details.Outcome = *input.Outcome
+	}
+	if input.Attendees != nil {
+		details.Attendees = *input.Attendees
+	}
+	if err := h.facts.ValidateDetails(r.Context(), uuid.MustParse(orgID), details); err != nil {
+		writeFactError(w, err)
+		return
+	}
+	kind, duration, direction, outcome := details.Params()
 
-	fact, err := h.db.UpdateFact(r.Context(), database.UpdateFactParams{
+	fact, err := h.q(r.Context()).UpdateFact(r.Context(), database.UpdateFactParams{
 		ID:         uuid.MustParse(factID),
 		Text:       input.Text,
 		HappenedAt: sql.NullTime{
@@ -100,23 +205,31 @@ func (h *FactHandler) Update(w http.ResponseWriter, r *http.Request) {
 			Valid: input.HappenedAt.Valid,
 		},
 		Location:   input.Location,
+		OrgID:      uuid.MustParse(orgID),
+		Kind:            kind,
+		DurationMinutes: duration,
+		Direction:       direction,
+		Outcome:         outcome,
 	})
 
+	if err == sql.ErrNoRows {
+		http.Error(w, "Fact not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
-	orgID := claims.OrgID
 
 	if(len(input.ToRemoveObjectIDs) > 0) {
 		removingObjectIDs := make([]uuid.UUID, len(input.ToRemoveObjectIDs))
 		for i, id := range input.ToRemoveObjectIDs {
 			removingObjectIDs[i] = uuid.MustParse(id)
 		}
-		err = h.db.RemoveObjectsFromFact(r.Context(), database.RemoveObjectsFromFactParams{
+		err = h.q(r.Context()).RemoveObjectsFromFact(r.Context(), database.RemoveObjectsFromFactParams{
 			FactID: fact.ID,
 			Column2: removingObjectIDs,
+			OrgID: uuid.MustParse(orgID),
 		})
 
 		if err != nil {
@@ -125,12 +238,12 @@ func (h *FactHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	addingObjectIDs := make([]uuid.UUID, len(input.ToAddObjectIDs))
 	if(len(input.ToAddObjectIDs) > 0) {
-		addingObjectIDs := make([]uuid.UUID, len(input.ToAddObjectIDs))
 		for i, id := range input.ToAddObjectIDs {
 			addingObjectIDs[i] = uuid.MustParse(id)
 		}
-		err = h.db.AddObjectsToFact(r.Context(), database.AddObjectsToFactParams{
+		err = h.q(r.Context()).AddObjectsToFact(r.Context(), database.AddObjectsToFactParams{
 			Column1: addingObjectIDs,
 			FactID: fact.ID,
 			OrgID: uuid.MustParse(orgID),
@@ -142,17 +255,41 @@ func (h *FactHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Mentions taken out of the text unlink the objects they linked, those
+	// linked by hand, now or before, stay
+	err = h.facts.SyncMentions(r.Context(), uuid.MustParse(orgID), uuid.MustParse(claims.CreatorID), fact, previous.Text, addingObjectIDs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if input.Attendees != nil || kindChanged {
+		err = h.facts.SetAttendees(r.Context(), uuid.MustParse(orgID), fact.ID, details.Attendees)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	json.NewEncoder(w).Encode(fact)
 }
 
 func (h *FactHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	factID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	err := h.db.DeleteFact(r.Context(), uuid.MustParse(factID))
+	rows, err := h.q(r.Context()).DeleteFact(r.Context(), database.DeleteFactParams{
+		ID:    uuid.MustParse(factID),
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if rows == 0 {
+		http.Error(w, "Fact not found", http.StatusNotFound)
+		return
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -164,6 +301,7 @@ func (h *FactHandler) List(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
 	search := r.URL.Query().Get("search")
+	kind := r.URL.Query().Get("kind")
 
 	if page < 1 {
 		page = 1
@@ -177,20 +315,22 @@ func (h *FactHandler) List(w http.ResponseWriter, r *http.Request) {
 		Description string `json:"description"`
 	}
 
-	facts, err := h.db.ListFactsByOrgID(r.Context(), database.ListFactsByOrgIDParams{
+	facts, err := h.q(r.Context()).ListFactsByOrgID(r.Context(), database.ListFactsByOrgIDParams{
 		OrgID:  uuid.MustParse(orgID),
 		Column2:   search,
 		Limit:  int32(pageSize),
 		Offset: int32((page - 1) * pageSize),
+		Column5: kind,
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	totalCount, err := h.db.CountFactsByOrgID(r.Context(), database.CountFactsByOrgIDParams{
+	totalCount, err := h.q(r.Context()).CountFactsByOrgID(r.Context(), database.CountFactsByOrgIDParams{
 		OrgID: uuid.MustParse(orgID),
 		Column2:  search,
+		Column3:  kind,
 	})
 
 	if err != nil {
@@ -206,6 +346,15 @@ func (h *FactHandler) List(w http.ResponseWriter, r *http.Request) {
 		CreatorName    string       `json:"creatorName"`
 		CreatedAt      time.Time    `json:"createdAt"`
 		RelatedObjects []RelatedObjectStruct  `json:"relatedObjects"`
+		Kind           string       `json:"kind"`
+		Duration       *int32       `json:"duration"`
+		Direction      *string      `json:"direction"`
+		Outcome        string       `json:"outcome"`
+		Attendees      json.RawMessage `json:"attendees"`
+		Edited         bool         `json:"edited"`
+		EditedAt       ctype.NullTime `json:"editedAt"`
+		LastEditorID   ctype.NullUUID `json:"lastEditorId"`
+		LastEditorName string       `json:"lastEditorName"`
 	}
 	returningFacts := make([]FactType, len(facts))
 	
@@ -224,6 +373,19 @@ func (h *FactHandler) List(w http.ResponseWriter, r *http.Request) {
 			CreatorName:    fact.CreatorName,
 			CreatedAt:      fact.CreatedAt,
 			RelatedObjects: relatedObjects,
+			Kind:           fact.Kind,
+			Outcome:        fact.Outcome,
+			Attendees:      fact.Attendees,
+			Edited:         fact.Edited,
+			EditedAt:       ctype.NullTime{NullTime: fact.EditedAt},
+			LastEditorID:   ctype.NullUUID{NullUUID: fact.LastEditorID},
+			LastEditorName: fact.LastEditorName,
+		}
+		if fact.DurationMinutes.Valid {
+			returningFacts[i].Duration = &fact.DurationMinutes.Int32
+		}
+		if fact.Direction.Valid {
+			returningFacts[i].Direction = &fact.Direction.String
 		}
 	}
 
@@ -240,4 +402,164 @@ func (h *FactHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+// ListKinds returns the built-in kinds of fact followed by those of the org
+func (h *FactHandler) ListKinds(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	kinds, err := h.facts.Kinds(r.Context(), uuid.MustParse(claims.OrgID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kinds)
+}
+
+// CreateKind adds a kind of fact to the org
+func (h *FactHandler) CreateKind(w http.ResponseWriter, r *http.Request) {
+	var input service.FactKind
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	kind, err := h.facts.CreateKind(r.Context(), uuid.MustParse(claims.OrgID), uuid.MustParse(claims.CreatorID), input)
+	if err != nil {
+		writeFactError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(kind)
+}
+
+// UpdateKind changes the description and the fields of a kind of the org
+func (h *FactHandler) UpdateKind(w http.ResponseWriter, r *http.Request) {
+	var input service.FactKind
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input.Name = chi.URLParam(r, "name")
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	kind, err := h.facts.UpdateKind(r.Context(), uuid.MustParse(claims.OrgID), input)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Kind not found, built-in kinds cannot be changed", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeFactError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kind)
+}
+
+// DeleteKind deletes a kind of the org no fact is of
+func (h *FactHandler) DeleteKind(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err := h.facts.DeleteKind(r.Context(), uuid.MustParse(claims.OrgID), chi.URLParam(r, "name"))
+	if err == sql.ErrNoRows {
+		http.Error(w, "Kind not found, built-in kinds cannot be deleted", http.StatusNotFound)
+		return
+	}
+	if err == service.ErrFactKindInUse {
+		http.Error(w, "Facts of this kind exist, change their kind first", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FactRevision is a version of a fact and the creator who wrote it
+type FactRevision struct {
+	Revision   int32          `json:"revision"`
+	Text       string         `json:"text"`
+	HappenedAt ctype.NullTime `json:"happenedAt"`
+	Location   string         `json:"location"`
+	Kind       string         `json:"kind"`
+	Duration   *int32         `json:"duration"`
+	Direction  *string        `json:"direction"`
+	Outcome    string         `json:"outcome"`
+	EditorID   ctype.NullUUID `json:"editorId"`
+	EditorName string         `json:"editorName"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+// ListRevisions returns the versions of a fact, newest first
+func (h *FactHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	factID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid fact ID", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	_, err = h.q(r.Context()).GetFactByID(r.Context(), database.GetFactByIDParams{ID: factID, OrgID: orgID})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Fact not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows, err := h.q(r.Context()).ListFactRevisions(r.Context(), database.ListFactRevisionsParams{
+		FactID: factID,
+		OrgID:  orgID,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	revisions := make([]FactRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = FactRevision{
+			Revision:   row.Revision,
+			Text:       row.Text,
+			HappenedAt: ctype.NullTime{NullTime: row.HappenedAt},
+			Location:   row.Location,
+			Kind:       row.Kind,
+			Outcome:    row.Outcome,
+			EditorID:   ctype.NullUUID{NullUUID: row.EditorID},
+			EditorName: row.EditorName,
+			CreatedAt:  row.CreatedAt,
+		}
+		if row.DurationMinutes.Valid {
+			revisions[i].Duration = &row.DurationMinutes.Int32
+		}
+		if row.Direction.Valid {
+			revisions[i].Direction = &row.Direction.String
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// Revert sets a fact back to one of its revisions
+func (h *FactHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	factID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid fact ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || revision < 1 {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	fact, err := h.facts.Revert(r.Context(), uuid.MustParse(claims.OrgID), uuid.MustParse(claims.CreatorID), factID, int32(revision))
+	if err == sql.ErrNoRows {
+		http.Error(w, "Fact or revision not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeFactError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(fact)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
@@ -17,13 +18,17 @@ func NewFeedHandler(db *database.Queries) *FeedHandler {
 	return &FeedHandler{db: db}
 }
 
+func (h *FeedHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
+}
+
 // ListFeeds handles GET requests to retrieve feed items
 func (h *FeedHandler) ListFeeds(w http.ResponseWriter, r *http.Request) {
 	// Extract creator ID from the context
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	
 	// Get feed items
-	feeds, err := h.db.GetFeed(r.Context(), uuid.MustParse(claims.CreatorID))
+	feeds, err := h.q(r.Context()).GetFeed(r.Context(), uuid.MustParse(claims.CreatorID))
 	if err != nil {
 		http.Error(w, "Failed to retrieve feed items", http.StatusInternalServerError)
 		return
@@ -76,7 +81,7 @@ func (h *FeedHandler) MarkFeedsAsSeen(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Mark feeds as seen
-	err := h.db.MarkFeedAsSeen(r.Context(), feedIDs)
+	err := h.q(r.Context()).MarkFeedAsSeen(r.Context(), feedIDs)
 	if err != nil {
 		http.Error(w, "Failed to mark feeds as seen", http.StatusInternalServerError)
 		return
@@ -26,6 +26,10 @@ func NewFunnelHandler(db *database.Queries) *FunnelHandler {
 	return &FunnelHandler{db: db}
 }
 
+func (h *FunnelHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
+}
+
 func (h *FunnelHandler) CreateFunnel(w http.ResponseWriter, r *http.Request) {
 	var funnel models.Funnel
 	if err := json.NewDecoder(r.Body).Decode(&funnel); err != nil {
@@ -38,7 +42,7 @@ func (h *FunnelHandler) CreateFunnel(w http.ResponseWriter, r *http.Request) {
 	creatorID := uuid.MustParse(claims.CreatorID)
 
 	funnelID := uuid.New()
-	createdFunnel, err := h.db.CreateFunnel(ctx, database.CreateFunnelParams{
+	createdFunnel, err := h.q(ctx).CreateFunnel(ctx, database.CreateFunnelParams{
 		ID:          funnelID,
 		Name:        funnel.Name,
 		Description: funnel.Description,
@@ -50,7 +54,7 @@ func (h *FunnelHandler) CreateFunnel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, step := range funnel.Steps {
-		_, err := h.db.CreateStep(ctx, database.CreateStepParams{
+		_, err := h.q(ctx).CreateStep(ctx, database.CreateStepParams{
 			ID:         uuid.New(),
 			FunnelID:   funnelID,
 			Name:       step.Name,
@@ -75,13 +79,21 @@ func (h *FunnelHandler) UpdateFunnel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ctx := r.Context()
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	funnelID := uuid.MustParse(update.ID)
 
-	// Update funnel
-	updatedFunnel, err := h.db.UpdateFunnel(ctx, database.UpdateFunnelParams{
-		ID:          uuid.MustParse(update.ID),
+	// Update funnel, steps below are only touched once the funnel is known to
+	// belong to the org
+	updatedFunnel, err := h.q(ctx).UpdateFunnel(ctx, database.UpdateFunnelParams{
+		ID:          funnelID,
 		Name:        update.Name,
 		Description: update.Description,
+		OrgID:       uuid.MustParse(claims.OrgID),
 	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Funnel not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -94,9 +106,9 @@ func (h *FunnelHandler) UpdateFunnel(w http.ResponseWriter, r *http.Request) {
 				update.StepMapping[oldStepID] = newUUIDStepID.String()
 			}
 		}
-		_, err := h.db.CreateStep(ctx, database.CreateStepParams{
+		_, err := h.q(ctx).CreateStep(ctx, database.CreateStepParams{
 			ID:         newUUIDStepID,
-			FunnelID:   uuid.MustParse(update.ID),
+			FunnelID:   funnelID,
 			Name:       step.Name,
 			Definition: step.Definition,
 			Example:    step.Example,
@@ -110,14 +122,19 @@ func (h *FunnelHandler) UpdateFunnel(w http.ResponseWriter, r *http.Request) {
 	}
 	// Update existing steps
 	for _, step := range update.StepsUpdate {
-		_, err := h.db.UpdateStep(ctx, database.UpdateStepParams{
+		_, err := h.q(ctx).UpdateStep(ctx, database.UpdateStepParams{
 			ID:         uuid.MustParse(step.ID),
 			Name:       step.Name,
 			Definition: step.Definition,
 			Example:    step.Example,
 			Action:     step.Action,
 			StepOrder:  int32(step.StepOrder),
+			FunnelID:   funnelID,
 		})
+		if err == sql.ErrNoRows {
+			http.Error(w, "Step not found", http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -125,17 +142,25 @@ func (h *FunnelHandler) UpdateFunnel(w http.ResponseWriter, r *http.Request) {
 	}
 	// Delete steps
 	for _, stepID := range update.StepsDelete {
-		err := h.db.DeleteStep(ctx, uuid.MustParse(stepID))
+		rows, err := h.q(ctx).DeleteStep(ctx, database.DeleteStepParams{
+			ID:       uuid.MustParse(stepID),
+			FunnelID: funnelID,
+		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if rows == 0 {
+			http.Error(w, "Step not found or cannot be deleted due to existing objects", http.StatusNotFound)
+			return
+		}
 	}
 	// Update obj_step mappings
 	for oldStepID, newStepID := range update.StepMapping {
-		err := h.db.UpdateObjStep(ctx, database.UpdateObjStepParams{
+		err := h.q(ctx).UpdateObjStep(ctx, database.UpdateObjStepParams{
 			StepID:    uuid.MustParse(oldStepID),
 			StepID_2: uuid.MustParse(newStepID),
+			FunnelID: funnelID,
 		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -153,13 +178,17 @@ func (h *FunnelHandler) DeleteFunnel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	err := h.db.DeleteFunnel(ctx, uuid.MustParse(funnelID))
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	rows, err := h.q(ctx).DeleteFunnel(ctx, database.DeleteFunnelParams{
+		ID:    uuid.MustParse(funnelID),
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			http.Error(w, "Funnel not found or cannot be deleted due to existing references", http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Funnel not found or cannot be deleted due to existing references", http.StatusNotFound)
 		return
 	}
 
@@ -213,7 +242,7 @@ func (h *FunnelHandler) ListFunnels(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
-	funnels, err := h.db.ListFunnels(ctx, database.ListFunnelsParams{
+	funnels, err := h.q(ctx).ListFunnels(ctx, database.ListFunnelsParams{
 		OrgID:  uuid.MustParse(orgID),
 		Column2:  query,
 		Limit:  int32(pageSize),
@@ -224,7 +253,7 @@ func (h *FunnelHandler) ListFunnels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	totalCount, err := h.db.CountFunnels(ctx, database.CountFunnelsParams{
+	totalCount, err := h.q(ctx).CountFunnels(ctx, database.CountFunnelsParams{
 		OrgID: uuid.MustParse(orgID),
 		Column2: query,
 	})
@@ -234,7 +263,7 @@ func (h *FunnelHandler) ListFunnels(w http.ResponseWriter, r *http.Request) {
 	}
 	funnelWithSteps := make([]ListFunnelsRowWithStep, len(funnels))
 	for i, funnel := range funnels {
-		steps, err := h.db.ListStepsByFunnel(ctx, funnel.ID)
+		steps, err := h.q(ctx).ListStepsByFunnel(ctx, funnel.ID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -278,21 +307,22 @@ func (h *FunnelHandler) GetFunnel(w http.ResponseWriter, r *http.Request) {
 	params := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	orgId := uuid.MustParse(params.OrgID)
 	
-	// Retrieve the funnel from the database
-	funnel, err := h.db.GetFunnel(ctx, funnelID)
-	if err != nil {
-		http.Error(w, "Error fetching funnel", http.StatusInternalServerError)
+	// Retrieve the funnel from the database, funnels of other orgs are not found
+	funnel, err := h.q(ctx).GetFunnel(ctx, database.GetFunnelParams{
+		ID:    funnelID,
+		OrgID: orgId,
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Funnel not found", http.StatusNotFound)
 		return
 	}
-
-	// Check if the creator has access to this funnel
-	if funnel.OrgID != orgId {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	if err != nil {
+		http.Error(w, "Error fetching funnel", http.StatusInternalServerError)
 		return
 	}
 
 	// Fetch steps for the funnel
-	steps, err := h.db.ListStepsByFunnel(ctx, funnelID)
+	steps, err := h.q(ctx).ListStepsByFunnel(ctx, funnelID)
 	if err != nil {
 		http.Error(w, "Error fetching funnel steps", http.StatusInternalServerError)
 		return
@@ -326,16 +356,24 @@ func (h *FunnelHandler) GetFunnelView(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
 	// Fetch funnel details
-	funnel, err := h.db.GetFunnel(ctx, funnelID)
+	funnel, err := h.q(ctx).GetFunnel(ctx, database.GetFunnelParams{
+		ID:    funnelID,
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Funnel not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to fetch funnel", http.StatusInternalServerError)
 		return
 	}
 
 	// Fetch steps for the funnel
-	steps, err := h.db.ListStepsByFunnel(ctx, funnelID)
+	steps, err := h.q(ctx).ListStepsByFunnel(ctx, funnelID)
 	if err != nil {
 		http.Error(w, "Failed to fetch funnel steps", http.StatusInternalServerError)
 		return
@@ -380,7 +418,7 @@ func (h *FunnelHandler) getObjectsForStep(ctx context.Context, stepID uuid.UUID,
 	offset := (page - 1) * limit
 
 	// Create a new query to fetch objects for a specific step with pagination and search
-	objects, err := h.db.GetObjectsForStep(ctx, database.GetObjectsForStepParams{
+	objects, err := h.q(ctx).GetObjectsForStep(ctx, database.GetObjectsForStepParams{
 		StepID:      stepID,
 		Column2: searchQuery,
 		Limit:       int32(limit),
@@ -391,7 +429,7 @@ func (h *FunnelHandler) getObjectsForStep(ctx context.Context, stepID uuid.UUID,
 	}
 
 	// Count total objects for the step (for pagination)
-	totalCount, err := h.db.CountObjectsForStep(ctx, database.CountObjectsForStepParams{
+	totalCount, err := h.q(ctx).CountObjectsForStep(ctx, database.CountObjectsForStepParams{
 		StepID:      stepID,
 		Column2: searchQuery,
 	})
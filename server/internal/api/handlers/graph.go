@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GraphHandler serves the network of objects as nodes and edges
+type GraphHandler struct {
+	Graph *service.GraphService
+}
+
+func NewGraphHandler(graph *service.GraphService) *GraphHandler {
+	return &GraphHandler{Graph: graph}
+}
+
+// graphQuery reads depth, relation_type_ids and obj_type_ids
+func graphQuery(r *http.Request) (service.GraphQuery, error) {
+	var query service.GraphQuery
+	if depth := r.URL.Query().Get("depth"); depth != "" {
+		value, err := strconv.ParseInt(depth, 10, 32)
+		if err != nil {
+			return query, fmt.Errorf("invalid depth: %s", depth)
+		}
+		if value < 1 {
+			return query, fmt.Errorf("depth must be at least 1")
+		}
+		query.Depth = int32(value)
+	}
+	var err error
+	if query.RelationTypeIDs, err = parseUUIDs(r.URL.Query().Get("relation_type_ids")); err != nil {
+		return query, fmt.Errorf("invalid relation type IDs: %v", err)
+	}
+	if query.ObjTypeIDs, err = parseUUIDs(r.URL.Query().Get("obj_type_ids")); err != nil {
+		return query, fmt.Errorf("invalid object type IDs: %v", err)
+	}
+	return query, nil
+}
+
+func writeGraph(w http.ResponseWriter, graph *service.Graph, err error) {
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object not found", http.StatusNotFound)
+		return
+	}
+	if _, ok := err.(service.GraphError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to query the graph", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(graph)
+}
+
+// Neighborhood returns the objects within depth hops of the object, e.g. the
+// people within 2 hops of a company with depth=2 and obj_type_ids set to the
+// person type
+func (h *GraphHandler) Neighborhood(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	query, err := graphQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	graph, err := h.Graph.Neighborhood(r.Context(), uuid.MustParse(claims.OrgID), objectID, query)
+	writeGraph(w, graph, err)
+}
+
+// ShortestPath returns a shortest chain of relationships between the objects
+// from and to, no longer than depth
+func (h *GraphHandler) ShortestPath(w http.ResponseWriter, r *http.Request) {
+	fromID, err := uuid.Parse(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from object ID", http.StatusBadRequest)
+		return
+	}
+	toID, err := uuid.Parse(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to object ID", http.StatusBadRequest)
+		return
+	}
+	query, err := graphQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	graph, err := h.Graph.ShortestPath(r.Context(), uuid.MustParse(claims.OrgID), fromID, toID, query)
+	writeGraph(w, graph, err)
+}
+
+// SharedFacts returns the objects that appear in facts together with the
+// object, at least min_shared times
+func (h *GraphHandler) SharedFacts(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	minShared := int64(1)
+	if value := r.URL.Query().Get("min_shared"); value != "" {
+		if minShared, err = strconv.ParseInt(value, 10, 32); err != nil {
+			http.Error(w, "Invalid min_shared", http.StatusBadRequest)
+			return
+		}
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	graph, err := h.Graph.SharedFacts(r.Context(), uuid.MustParse(claims.OrgID), objectID, int32(minShared))
+	writeGraph(w, graph, err)
+}
+
+// Limits returns the graph limits of the org
+func (h *GraphHandler) Limits(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	limits, err := h.Graph.Limits(r.Context(), uuid.MustParse(claims.OrgID))
+	if err != nil {
+		http.Error(w, "Failed to get graph limits", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limits)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
 )
 
 type ImportTaskHandler struct {
@@ -28,6 +29,10 @@ func NewImportTaskHandler(db *sql.DB) *ImportTaskHandler {
 	}
 }
 
+func (h *ImportTaskHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.queries)
+}
+
 type ImportRequest struct {
 	ObjTypeID string          `json:"obj_type_id"`
 	FileName  string          `json:"file_name"`
@@ -55,14 +60,14 @@ func (h *ImportTaskHandler) CreateImportTask(w http.ResponseWriter, r *http.Requ
 	creatorID := uuid.MustParse(params.CreatorID)
 
 	// Check if there's an ongoing import for this organization
-	_, err := h.queries.GetOngoingImportTask(ctx, orgID)
+	_, err := h.q(ctx).GetOngoingImportTask(ctx, orgID)
 	if err != nil && err != sql.ErrNoRows {
 			http.Error(w, "Failed to check ongoing imports", http.StatusInternalServerError)
 			return
 	}
 	
 	// Create a new import task
-	task, err := h.queries.CreateImportTask(ctx, database.CreateImportTaskParams{
+	task, err := h.q(ctx).CreateImportTask(ctx, database.CreateImportTaskParams{
 		OrgID:     orgID,
 		CreatorID: creatorID,
 		ObjTypeID: uuid.MustParse(req.ObjTypeID),
@@ -75,8 +80,11 @@ func (h *ImportTaskHandler) CreateImportTask(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Start asynchronous processing
-	go h.processImportTask(task.ID, req, req.FileName, creatorID, orgID)
+	// Start asynchronous processing once the task is committed, the worker
+	// does not see it before
+	middleware.AfterCommit(ctx, func() {
+		go h.processImportTask(task.ID, req, req.FileName, creatorID, orgID)
+	})
 
 	// Return task ID to client
 	json.NewEncoder(w).Encode(map[string]string{"task_id": task.ID.String()})
@@ -149,13 +157,24 @@ func (h *ImportTaskHandler) processBatch(ctx context.Context, _ uuid.UUID, objTy
 	}
 	defer tx.Rollback()
 
+	// The batch runs after the request ended, scope it to the org itself
+	if err := middleware.SetOrgID(ctx, tx, OrgId.String()); err != nil {
+		return fmt.Errorf("failed to scope transaction: %w", err)
+	}
+	if err := middleware.SetActor(ctx, tx, creatorId.String(), ""); err != nil {
+		return fmt.Errorf("failed to scope transaction: %w", err)
+	}
+
 	// Create a new Queries instance that uses this transaction
 	qtx := h.queries.WithTx(tx)
 
 	// Process each row in the batch
 	for _, row := range batch {
 		// Check if object exists
-		obj, err := qtx.GetObjectByIDString(ctx, row.IDString)
+		obj, err := qtx.GetObjectByIDString(ctx, database.GetObjectByIDStringParams{
+			IDString: row.IDString,
+			OrgID:    OrgId,
+		})
 		if err != nil && err != sql.ErrNoRows {
 			return fmt.Errorf("failed to check existing object: %w", err)
 		}
@@ -224,6 +243,7 @@ func (h *ImportTaskHandler) processBatch(ctx context.Context, _ uuid.UUID, objTy
 			},
 			Location:   fact.Location,
 			CreatorID:  creatorId,
+			Kind:       service.FactKindNote,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create fact: %w", err)
@@ -281,7 +301,7 @@ func (h *ImportTaskHandler) GetImportTaskStatus(w http.ResponseWriter, r *http.R
     taskID := uuid.MustParse(r.URL.Query().Get("task_id"))
 	ctx := r.Context()
 
-	task, err := h.queries.GetImportTask(ctx, taskID)
+	task, err := h.q(ctx).GetImportTask(ctx, taskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Import task not found", http.StatusNotFound)
@@ -318,7 +338,7 @@ func (h *ImportTaskHandler) GetImportHistory(w http.ResponseWriter, r *http.Requ
 
 	offset := (page - 1) * pageSize
 
-	tasks, err := h.queries.GetImportTaskHistory(ctx, database.GetImportTaskHistoryParams{
+	tasks, err := h.q(ctx).GetImportTaskHistory(ctx, database.GetImportTaskHistoryParams{
 		OrgID:  orgID,
 		Limit:  int32(pageSize),
 		Offset: int32(offset),
@@ -328,7 +348,7 @@ func (h *ImportTaskHandler) GetImportHistory(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	totalCount, err := h.queries.CountImportTasks(ctx, orgID)
+	totalCount, err := h.q(ctx).CountImportTasks(ctx, orgID)
 	if err != nil {
 		http.Error(w, "Failed to get total count", http.StatusInternalServerError)
 		return
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -19,6 +21,10 @@ func NewListHandler(db *database.Queries) *ListHandler {
 	return &ListHandler{db: db}
 }
 
+func (h *ListHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
+}
+
 func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	var input struct {
@@ -32,7 +38,7 @@ func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	list, err := h.db.CreateList(r.Context(), database.CreateListParams{
+	list, err := h.q(r.Context()).CreateList(r.Context(), database.CreateListParams{
 		Name:          input.Name,
 		Description:   input.Description,
 		FilterSetting: input.FilterSetting,
@@ -44,9 +50,10 @@ func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a creator_list with empty params
-	creatorList, err := h.db.CreateCreatorList(r.Context(), database.CreateCreatorListParams{
+	creatorList, err := h.q(r.Context()).CreateCreatorList(r.Context(), database.CreateCreatorListParams{
 		ListID:    list.ID,
 		CreatorID: uuid.MustParse(claims.CreatorID),
+		OrgID:     uuid.MustParse(claims.OrgID),
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -80,13 +87,19 @@ func (h *ListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	list, err := h.db.UpdateList(r.Context(), database.UpdateListParams{
+	list, err := h.q(r.Context()).UpdateList(r.Context(), database.UpdateListParams{
 		ID:            uuid.MustParse(listID),
 		Name:          input.Name,
 		Description:   input.Description,
 		FilterSetting: input.FilterSetting,
+		OrgID:         uuid.MustParse(claims.OrgID),
 	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "List not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -97,15 +110,27 @@ func (h *ListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
 
 func (h *ListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
 	listID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
 
-	err := h.db.DeleteList(r.Context(), uuid.MustParse(listID))
+	rows, err := h.q(r.Context()).DeleteList(r.Context(), database.DeleteListParams{
+		ID:    uuid.MustParse(listID),
+		OrgID: orgID,
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_, err = h.db.GetListByID(r.Context(), uuid.MustParse(listID))
-	if err == nil {
-		http.Error(w, "List is associated with data", http.StatusBadRequest)
+	if rows == 0 {
+		_, err = h.q(r.Context()).GetListByID(r.Context(), database.GetListByIDParams{
+			ID:    uuid.MustParse(listID),
+			OrgID: orgID,
+		})
+		if err == nil {
+			http.Error(w, "List is associated with data", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "List not found", http.StatusNotFound)
 		return
 	}
 
@@ -122,11 +147,17 @@ func (h *ListHandler) UpdateCreatorList(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	creatorList, err := h.db.UpdateCreatorList(r.Context(), database.UpdateCreatorListParams{
+	creatorList, err := h.q(r.Context()).UpdateCreatorList(r.Context(), database.UpdateCreatorListParams{
 		ID: 	 uuid.MustParse(id),
 		Params:    input.Params,
+		CreatorID: uuid.MustParse(claims.CreatorID),
 	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Creator list not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -137,12 +168,20 @@ func (h *ListHandler) UpdateCreatorList(w http.ResponseWriter, r *http.Request)
 
 func (h *ListHandler) DeleteCreatorList(w http.ResponseWriter, r *http.Request) {
 	creatorListID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	err := h.db.DeleteCreatorList(r.Context(), uuid.MustParse(creatorListID))
+	rows, err := h.q(r.Context()).DeleteCreatorList(r.Context(), database.DeleteCreatorListParams{
+		ID:        uuid.MustParse(creatorListID),
+		CreatorID: uuid.MustParse(claims.CreatorID),
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if rows == 0 {
+		http.Error(w, "Creator list not found", http.StatusNotFound)
+		return
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -160,7 +199,7 @@ func (h *ListHandler) ListListsByOrgID(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
-	lists, err := h.db.ListListsByOrgID(r.Context(), database.ListListsByOrgIDParams{
+	lists, err := h.q(r.Context()).ListListsByOrgID(r.Context(), database.ListListsByOrgIDParams{
 		OrgID:  uuid.MustParse(orgID),
 		Limit:  int32(pageSize),
 		Offset: int32((page - 1) * pageSize),
@@ -170,7 +209,7 @@ func (h *ListHandler) ListListsByOrgID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	totalCount, err := h.db.CountListsByOrgID(r.Context(), uuid.MustParse(orgID))
+	totalCount, err := h.q(r.Context()).CountListsByOrgID(r.Context(), uuid.MustParse(orgID))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -193,7 +232,7 @@ func (h *ListHandler) ListListsByOrgID(w http.ResponseWriter, r *http.Request) {
 
 func (h *ListHandler) ListCreatorListsByCreatorID(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
-	creatorLists, err := h.db.ListCreatorListsByCreatorID(r.Context(), uuid.MustParse(claims.CreatorID))
+	creatorLists, err := h.q(r.Context()).ListCreatorListsByCreatorID(r.Context(), uuid.MustParse(claims.CreatorID))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -204,8 +243,16 @@ func (h *ListHandler) ListCreatorListsByCreatorID(w http.ResponseWriter, r *http
 
 func (h *ListHandler) GetCreateListByID(w http.ResponseWriter, r *http.Request) {
 	listID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	list, err := h.db.GetCreatorListByID(r.Context(), uuid.MustParse(listID))
+	list, err := h.q(r.Context()).GetCreatorListByID(r.Context(), database.GetCreatorListByIDParams{
+		ID:        uuid.MustParse(listID),
+		CreatorID: uuid.MustParse(claims.CreatorID),
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Creator list not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -218,10 +265,15 @@ func (h *ListHandler) CreateCreatorList(w http.ResponseWriter, r *http.Request){
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	ListId := chi.URLParam(r, "id")
 
-	creatorList, err := h.db.CreateCreatorList(r.Context(), database.CreateCreatorListParams{
+	creatorList, err := h.q(r.Context()).CreateCreatorList(r.Context(), database.CreateCreatorListParams{
 		ListID:    uuid.MustParse(ListId),
 		CreatorID: uuid.MustParse(claims.CreatorID),
+		OrgID:     uuid.MustParse(claims.OrgID),
 	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "List not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1,14 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 type MergeObjectsHandler struct {
@@ -23,6 +26,10 @@ func NewMergeObjectsHandler(db *sql.DB) *MergeObjectsHandler {
     }
 }
 
+func (h *MergeObjectsHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.queries)
+}
+
 type ObjectTypeValue struct {
 	TypeID     uuid.UUID       `json:"typeId"`
 	TypeValues json.RawMessage `json:"typeValues"`
@@ -48,80 +55,104 @@ func (h *MergeObjectsHandler) MergeObjects(w http.ResponseWriter, r *http.Reques
 
     // Get creator ID from context
     claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+
+    historyID, err := h.mergeObjects(r.Context(), claims, req)
+    if err != nil {
+        if _, ok := err.(mergeValidationError); ok {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    // Return success response
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{
+        "message": "Objects merged successfully",
+        "history_id": historyID.String(),
+    })
+}
+
+// mergeValidationError is a merge the objects do not allow, such as objects
+// of another org
+type mergeValidationError string
+
+func (e mergeValidationError) Error() string {
+    return string(e)
+}
+
+// mergeObjects merges the sources into the target in the request transaction
+// and returns the id of the merge history
+func (h *MergeObjectsHandler) mergeObjects(ctx context.Context, claims *middleware.Claims, req MergeObjectsRequest) (uuid.UUID, error) {
     creatorID := uuid.MustParse(claims.CreatorID)
 
     // Create array of all objects including target
     allObjects := append([]uuid.UUID{req.TargetObjectID}, req.SourceObjectIDs...)
 
     // Validate merge request
-    validation, err := h.queries.ValidateMergeObjects(r.Context(), database.ValidateMergeObjectsParams{
+    validation, err := h.q(ctx).ValidateMergeObjects(ctx, database.ValidateMergeObjectsParams{
         Column1: allObjects,
         ID:     creatorID,
     });
     if err != nil {
-			http.Error(w, "Error validating merge request", http.StatusInternalServerError)
-			return
+        return uuid.Nil, errors.New("Error validating merge request")
     }
 
     if validation.ValidationResult != "valid" {
-			http.Error(w, validation.ValidationResult, http.StatusBadRequest)
-			return
+        return uuid.Nil, mergeValidationError(validation.ValidationResult)
     }
 
-    // Begin transaction
-    tx, err := h.db.BeginTx(r.Context(), nil)
+    // Snapshot what the merge changes before changing anything, so it can be undone
+    orgID := uuid.MustParse(claims.OrgID)
+    snapshot, err := h.snapshotMerge(ctx, req.TargetObjectID, req.SourceObjectIDs, orgID)
     if err != nil {
-        http.Error(w, "Error starting transaction", http.StatusInternalServerError)
-        return
+        return uuid.Nil, fmt.Errorf("Error snapshotting merge: %v", err)
     }
-    defer tx.Rollback()
 
-    _,err = h.queries.UpdateObject(r.Context(), database.UpdateObjectParams{
+    // Every step runs in the request transaction, a failure rolls back the merge
+    _,err = h.q(ctx).UpdateObject(ctx, database.UpdateObjectParams{
         ID:         req.TargetObjectID,
         Name:       req.Name,
         Description: req.Description,
         IDString:   req.IDString,
         Aliases:   req.Aliases,
+        OrgID:     orgID,
     });
 
     if err != nil {
-        http.Error(w, fmt.Sprintf("Error updating object: %v", err), http.StatusInternalServerError)
-        return
+        return uuid.Nil, fmt.Errorf("Error updating object: %v", err)
     }
 
     // Handle object type values if provided
     for _, typeValue := range req.TypeValues {
-        _, err := h.queries.UpsertObjectTypeValue(r.Context(), database.UpsertObjectTypeValueParams{
+        upserted, err := h.q(ctx).UpsertObjectTypeValue(ctx, database.UpsertObjectTypeValueParams{
             ObjID:      req.TargetObjectID,
             TypeID:     typeValue.TypeID,
             TypeValues: typeValue.TypeValues,
         })
         if err != nil {
-            http.Error(w, fmt.Sprintf("Error updating object type value: %v", err), http.StatusInternalServerError)
-            return
+            return uuid.Nil, fmt.Errorf("Error updating object type value: %v", err)
+        }
+        if !snapshot.hasTypeValue(upserted.ID) {
+            snapshot.CreatedTypeValueIDs = append(snapshot.CreatedTypeValueIDs, upserted.ID)
         }
     }
 
+    snapshotJSON, err := json.Marshal(snapshot)
+    if err != nil {
+        return uuid.Nil, fmt.Errorf("Error snapshotting merge: %v", err)
+    }
+
     // Perform merge
-    err = h.queries.MergeObjects(r.Context(), database.MergeObjectsParams{
+    historyID, err := h.q(ctx).MergeObjects(ctx, database.MergeObjectsParams{
         TargetObjectID:  req.TargetObjectID,
         SourceObjectIds: req.SourceObjectIDs,
         CreatorID:      creatorID,
+        Snapshot:       pqtype.NullRawMessage{RawMessage: snapshotJSON, Valid: true},
     })
     if err != nil {
-        http.Error(w, fmt.Sprintf("Error performing merge: %v", err), http.StatusInternalServerError)
-        return
+        return uuid.Nil, fmt.Errorf("Error performing merge: %v", err)
     }
-
-    // Commit transaction
-    if err = tx.Commit(); err != nil {
-        http.Error(w, "Error committing transaction", http.StatusInternalServerError)
-        return
-    }
-
-    // Return success response
-    w.WriteHeader(http.StatusOK)
-    json.NewEncoder(w).Encode(map[string]string{
-        "message": "Objects merged successfully",
-    })
-}
\ No newline at end of file
+    return historyID, nil
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+)
+
+type MergePreviewRequest struct {
+	TargetObjectID  uuid.UUID   `json:"target_object_id"`
+	SourceObjectIDs []uuid.UUID `json:"source_object_ids"`
+}
+
+// MergeCandidate is one value proposed for a field and the objects having it
+type MergeCandidate struct {
+	Value     json.RawMessage `json:"value"`
+	ObjectIDs []uuid.UUID     `json:"object_ids"`
+}
+
+// MergeFieldConflict is a field the objects disagree on. TypeID is null for
+// fields of the object itself.
+type MergeFieldConflict struct {
+	TypeID     ctype.NullUUID   `json:"type_id"`
+	TypeName   string           `json:"type_name"`
+	Field      string           `json:"field"`
+	Candidates []MergeCandidate `json:"candidates"`
+}
+
+type MergeMoves struct {
+	Facts     int32 `json:"facts"`
+	Tasks     int32 `json:"tasks"`
+	Tags      int32 `json:"tags"`
+	Steps     int32 `json:"steps"`
+	Relations int32 `json:"relations"`
+}
+
+type MergeStepWarning struct {
+	ObjectID uuid.UUID `json:"object_id"`
+	StepID   uuid.UUID `json:"step_id"`
+	StepName string    `json:"step_name"`
+}
+
+// MergeWarning is a funnel in which the objects are in different steps, the
+// merged object ends up in all of them
+type MergeWarning struct {
+	FunnelID   uuid.UUID          `json:"funnel_id"`
+	FunnelName string             `json:"funnel_name"`
+	Message    string             `json:"message"`
+	Steps      []MergeStepWarning `json:"steps"`
+}
+
+type MergePreviewResponse struct {
+	// Proposed can be sent to POST /objects/merge as it is
+	Proposed  MergeObjectsRequest  `json:"proposed"`
+	Conflicts []MergeFieldConflict `json:"conflicts"`
+	Moves     MergeMoves           `json:"moves"`
+	Warnings  []MergeWarning       `json:"warnings"`
+}
+
+// PreviewMerge proposes the merged record of the target and the sources. The
+// values of the target win, the sources fill in what it lacks in the order
+// they were given. Fields with different values are listed as conflicts.
+func (h *MergeObjectsHandler) PreviewMerge(w http.ResponseWriter, r *http.Request) {
+	var req MergePreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	response, err := h.previewMerge(r.Context(), claims, req)
+	if err != nil {
+		if _, ok := err.(mergeValidationError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *MergeObjectsHandler) previewMerge(ctx context.Context, claims *middleware.Claims, req MergePreviewRequest) (*MergePreviewResponse, error) {
+	orgID := uuid.MustParse(claims.OrgID)
+
+	allObjects := append([]uuid.UUID{req.TargetObjectID}, req.SourceObjectIDs...)
+	validation, err := h.q(ctx).ValidateMergeObjects(ctx, database.ValidateMergeObjectsParams{
+		Column1: allObjects,
+		ID:      uuid.MustParse(claims.CreatorID),
+	})
+	if err != nil {
+		return nil, errors.New("Error validating merge request")
+	}
+	if validation.ValidationResult != "valid" {
+		return nil, mergeValidationError(validation.ValidationResult)
+	}
+
+	objects, err := h.q(ctx).ListObjectsForMerge(ctx, database.ListObjectsForMergeParams{
+		ObjectIds: allObjects,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return nil, errors.New("Failed to get objects")
+	}
+	byID := make(map[uuid.UUID]database.ListObjectsForMergeRow, len(objects))
+	for _, o := range objects {
+		byID[o.ID] = o
+	}
+	// Merge order: the target, then the sources as given
+	ordered := make([]database.ListObjectsForMergeRow, 0, len(allObjects))
+	for _, id := range allObjects {
+		if o, ok := byID[id]; ok {
+			ordered = append(ordered, o)
+		}
+	}
+	if len(ordered) != len(allObjects) {
+		return nil, mergeValidationError("Objects not found")
+	}
+	target := ordered[0]
+
+	response := MergePreviewResponse{
+		Proposed: MergeObjectsRequest{
+			TargetObjectID:  req.TargetObjectID,
+			SourceObjectIDs: req.SourceObjectIDs,
+			TypeValues:      []ObjectTypeValue{},
+			Name:            target.Name,
+			Description:     target.Description,
+			IDString:        target.IDString,
+			Aliases:         []string{},
+		},
+		Conflicts: []MergeFieldConflict{},
+		Warnings:  []MergeWarning{},
+	}
+
+	// Sources lose their id_string in the merge, it stays findable as an alias
+	for _, o := range ordered {
+		for _, alias := range append([]string{o.IDString}, o.Aliases...) {
+			if alias != "" && alias != target.IDString && !containsString(response.Proposed.Aliases, alias) {
+				response.Proposed.Aliases = append(response.Proposed.Aliases, alias)
+			}
+		}
+	}
+	for _, field := range []string{"name", "description"} {
+		values := make(map[uuid.UUID]json.RawMessage, len(ordered))
+		for _, o := range ordered {
+			value := o.Name
+			if field == "description" {
+				value = o.Description
+			}
+			values[o.ID], _ = json.Marshal(value)
+		}
+		if _, candidates := mergeCandidates(allObjects, values); len(candidates) > 1 {
+			response.Conflicts = append(response.Conflicts, MergeFieldConflict{
+				Field:      field,
+				Candidates: candidates,
+			})
+		}
+	}
+
+	typeValues, err := h.q(ctx).ListObjectTypeValuesForMerge(ctx, database.ListObjectTypeValuesForMergeParams{
+		ObjectIds: allObjects,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return nil, errors.New("Failed to get type values")
+	}
+	// Type values are grouped by type, in the order of the query
+	var typeIDs []uuid.UUID
+	typeNames := map[uuid.UUID]string{}
+	valuesByType := map[uuid.UUID]map[uuid.UUID]map[string]json.RawMessage{}
+	for _, tv := range typeValues {
+		if _, ok := valuesByType[tv.TypeID]; !ok {
+			typeIDs = append(typeIDs, tv.TypeID)
+			typeNames[tv.TypeID] = tv.TypeName
+			valuesByType[tv.TypeID] = map[uuid.UUID]map[string]json.RawMessage{}
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(tv.TypeValues, &values); err != nil {
+			return nil, errors.New("Failed to read type values")
+		}
+		valuesByType[tv.TypeID][tv.ObjID] = values
+	}
+	for _, typeID := range typeIDs {
+		byObject := valuesByType[typeID]
+		var fields []string
+		for _, values := range byObject {
+			for field := range values {
+				if !containsString(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+		sort.Strings(fields)
+		merged := map[string]json.RawMessage{}
+		for _, field := range fields {
+			values := map[uuid.UUID]json.RawMessage{}
+			for objID, v := range byObject {
+				if value, ok := v[field]; ok {
+					values[objID] = value
+				}
+			}
+			value, candidates := mergeCandidates(allObjects, values)
+			if value != nil {
+				merged[field] = value
+			}
+			if len(candidates) > 1 {
+				response.Conflicts = append(response.Conflicts, MergeFieldConflict{
+					TypeID:     ctype.NullUUID{NullUUID: uuid.NullUUID{UUID: typeID, Valid: true}},
+					TypeName:   typeNames[typeID],
+					Field:      field,
+					Candidates: candidates,
+				})
+			}
+		}
+		mergedJSON, err := json.Marshal(merged)
+		if err != nil {
+			return nil, errors.New("Failed to merge type values")
+		}
+		response.Proposed.TypeValues = append(response.Proposed.TypeValues, ObjectTypeValue{
+			TypeID:     typeID,
+			TypeValues: mergedJSON,
+		})
+	}
+
+	moves, err := h.q(ctx).CountObjectMergeMoves(ctx, database.CountObjectMergeMovesParams{
+		SourceObjectIds: req.SourceObjectIDs,
+		OrgID:           orgID,
+		TargetObjectID:  req.TargetObjectID,
+	})
+	if err != nil {
+		return nil, errors.New("Failed to count what the merge moves")
+	}
+	response.Moves = MergeMoves(moves)
+
+	steps, err := h.q(ctx).ListObjectStepsForMerge(ctx, database.ListObjectStepsForMergeParams{
+		ObjectIds: allObjects,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return nil, errors.New("Failed to get funnel steps")
+	}
+	for i := 0; i < len(steps); {
+		j := i
+		distinct := map[uuid.UUID]bool{}
+		warning := MergeWarning{
+			FunnelID:   steps[i].FunnelID,
+			FunnelName: steps[i].FunnelName,
+			Message:    "The objects are in different steps of this funnel, the merged object will be in all of them",
+		}
+		for ; j < len(steps) && steps[j].FunnelID == steps[i].FunnelID; j++ {
+			distinct[steps[j].StepID] = true
+			warning.Steps = append(warning.Steps, MergeStepWarning{
+				ObjectID: steps[j].ObjID,
+				StepID:   steps[j].StepID,
+				StepName: steps[j].StepName,
+			})
+		}
+		if len(distinct) > 1 {
+			response.Warnings = append(response.Warnings, warning)
+		}
+		i = j
+	}
+
+	return &response, nil
+}
+
+// mergeCandidates returns the value of the first object in order with a value
+// for the field, and every distinct value with the objects having it. Empty
+// strings, lists and objects are no value.
+func mergeCandidates(order []uuid.UUID, values map[uuid.UUID]json.RawMessage) (json.RawMessage, []MergeCandidate) {
+	var chosen json.RawMessage
+	candidates := []MergeCandidate{}
+	for _, id := range order {
+		value, ok := values[id]
+		if !ok || isEmptyJSON(value) {
+			continue
+		}
+		if chosen == nil {
+			chosen = value
+		}
+		found := false
+		for i := range candidates {
+			if jsonEqual(candidates[i].Value, value) {
+				candidates[i].ObjectIDs = append(candidates[i].ObjectIDs, id)
+				found = true
+				break
+			}
+		}
+		if !found {
+			candidates = append(candidates, MergeCandidate{Value: value, ObjectIDs: []uuid.UUID{id}})
+		}
+	}
+	return chosen, candidates
+}
+
+func isEmptyJSON(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "", "null", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// jsonEqual compares values regardless of formatting and key order
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
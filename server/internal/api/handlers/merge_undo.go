@@ -0,0 +1,560 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// mergeSnapshot is what a merge changed, as it was before the merge. It is
+// kept in object_merge_history to undo the merge.
+type mergeSnapshot struct {
+	Target     mergedObject      `json:"target"`
+	Sources    []mergedObject    `json:"sources"`
+	TypeValues []mergedTypeValue `json:"type_values"`
+	Facts      []mergedLink      `json:"facts"`
+	Tasks      []mergedLink      `json:"tasks"`
+	Steps      []mergedLink      `json:"steps"`
+	Tags       []mergedLink      `json:"tags"`
+	Relations  []mergedRelation  `json:"relations"`
+	FactTexts  []mergedText      `json:"fact_texts"`
+	TaskTexts  []mergedText      `json:"task_texts"`
+	// Type values of the target that the merge created
+	CreatedTypeValueIDs []uuid.UUID `json:"created_type_value_ids"`
+}
+
+type mergedObject struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IDString    string    `json:"id_string"`
+	Aliases     []string  `json:"aliases"`
+}
+
+type mergedTypeValue struct {
+	ID         uuid.UUID       `json:"id"`
+	ObjID      uuid.UUID       `json:"obj_id"`
+	TypeID     uuid.UUID       `json:"type_id"`
+	TypeValues json.RawMessage `json:"type_values"`
+}
+
+// mergedLink is a fact, task, funnel step or tag of an object
+type mergedLink struct {
+	ObjID uuid.UUID `json:"obj_id"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// mergedRelation is a relationship with a source object at one end
+type mergedRelation struct {
+	ID        uuid.UUID `json:"id"`
+	FromObjID uuid.UUID `json:"from_obj_id"`
+	ToObjID   uuid.UUID `json:"to_obj_id"`
+}
+
+// mergedText is the text of a fact or task that mentioned a source object
+type mergedText struct {
+	ID   uuid.UUID `json:"id"`
+	Text string    `json:"text"`
+}
+
+func (s *mergeSnapshot) hasTypeValue(id uuid.UUID) bool {
+	for _, tv := range s.TypeValues {
+		if tv.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// linkIDs returns the ids of the links of the object
+func linkIDs(links []mergedLink, objID uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, link := range links {
+		if link.ObjID == objID {
+			ids = append(ids, link.ID)
+		}
+	}
+	return ids
+}
+
+func (h *MergeObjectsHandler) snapshotMerge(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID, orgID uuid.UUID) (*mergeSnapshot, error) {
+	data, err := h.q(ctx).SnapshotObjectMerge(ctx, database.SnapshotObjectMergeParams{
+		TargetObjectID:  targetID,
+		OrgID:           orgID,
+		SourceObjectIds: sourceIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var snapshot mergeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// MergeConflict is something an undo would set back that changed after the
+// merge. Undoing anyway keeps the later change.
+type MergeConflict struct {
+	EntityType string         `json:"entity_type"`
+	EntityID   uuid.UUID      `json:"entity_id"`
+	Fields     []string       `json:"fields"`
+	Reason     string         `json:"reason"`
+	ChangedBy  string         `json:"changed_by"`
+	ChangedAt  ctype.NullTime `json:"changed_at"`
+}
+
+type UndoMergeResponse struct {
+	HistoryID         uuid.UUID       `json:"history_id"`
+	RestoredObjectIDs []uuid.UUID     `json:"restored_object_ids"`
+	MovedFacts        int32           `json:"moved_facts"`
+	MovedTasks        int32           `json:"moved_tasks"`
+	MovedSteps        int32           `json:"moved_steps"`
+	MovedRelations    int64           `json:"moved_relations"`
+	RemovedTags       int64           `json:"removed_tags"`
+	Conflicts         []MergeConflict `json:"conflicts"`
+}
+
+// mergeConflicts lists what changed since the merge among the target, the
+// type values and the texts the merge rewrote, and source objects whose
+// id_string another object took meanwhile
+func (h *MergeObjectsHandler) mergeConflicts(ctx context.Context, history database.ObjectMergeHistory, snapshot *mergeSnapshot) ([]MergeConflict, error) {
+	ids := []uuid.UUID{history.TargetObjectID}
+	for _, tv := range snapshot.TypeValues {
+		ids = append(ids, tv.ID)
+	}
+	ids = append(ids, snapshot.CreatedTypeValueIDs...)
+	for _, t := range snapshot.FactTexts {
+		ids = append(ids, t.ID)
+	}
+	for _, t := range snapshot.TaskTexts {
+		ids = append(ids, t.ID)
+	}
+	for _, rel := range snapshot.Relations {
+		ids = append(ids, rel.ID)
+	}
+	events, err := h.q(ctx).ListMergeConflictEvents(ctx, database.ListMergeConflictEventsParams{
+		OrgID:     history.OrgID,
+		EntityIds: ids,
+		MergedAt:  history.MergedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []MergeConflict{}
+	byEntity := map[uuid.UUID]int{}
+	for _, e := range events {
+		// Later changes of the target are only a conflict for the fields the
+		// undo sets back
+		if e.EntityType == "obj" && e.EntityID != history.TargetObjectID {
+			continue
+		}
+		var changed map[string]json.RawMessage
+		if e.After.Valid {
+			json.Unmarshal(e.After.RawMessage, &changed)
+		} else if e.Before.Valid {
+			json.Unmarshal(e.Before.RawMessage, &changed)
+		}
+		i, ok := byEntity[e.EntityID]
+		if !ok {
+			conflicts = append(conflicts, MergeConflict{
+				EntityType: e.EntityType,
+				EntityID:   e.EntityID,
+				Fields:     []string{},
+				Reason:     "Changed after the merge",
+			})
+			i = len(conflicts) - 1
+			byEntity[e.EntityID] = i
+		}
+		c := &conflicts[i]
+		for field := range changed {
+			if !containsString(c.Fields, field) {
+				c.Fields = append(c.Fields, field)
+			}
+		}
+		sort.Strings(c.Fields)
+		c.ChangedBy = e.ActorUsername
+		c.ChangedAt = ctype.NullTime{NullTime: sql.NullTime{Time: e.CreatedAt, Valid: true}}
+	}
+	targetKeepsIDString := false
+	if i, ok := byEntity[history.TargetObjectID]; ok {
+		targetKeepsIDString = containsString(conflicts[i].Fields, "id_string")
+		fields := []string{}
+		for _, field := range conflicts[i].Fields {
+			if field == "name" || field == "description" || field == "id_string" || field == "aliases" {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			conflicts = append(conflicts[:i], conflicts[i+1:]...)
+		} else {
+			conflicts[i].Fields = fields
+		}
+	}
+
+	for _, source := range snapshot.Sources {
+		taken, err := h.q(ctx).GetObjectByIDString(ctx, database.GetObjectByIDStringParams{
+			IDString: source.IDString,
+			OrgID:    history.OrgID,
+		})
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// The target gets its own id_string back first
+		if taken.ID == history.TargetObjectID && !targetKeepsIDString {
+			continue
+		}
+		if taken.IDString == source.IDString && taken.ID != source.ID {
+			conflicts = append(conflicts, MergeConflict{
+				EntityType: "obj",
+				EntityID:   source.ID,
+				Fields:     []string{"id_string"},
+				Reason:     fmt.Sprintf("id_string %q is used by object %s", source.IDString, taken.ID),
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UndoMerge restores the source objects of a merge with their facts, tasks,
+// funnel steps and relationships, and sets the target, its type values and the rewritten fact
+// and task texts back to before the merge. If any of them changed after the
+// merge it answers 409 with the conflicts, unless force is set, then later
+// changes are kept.
+func (h *MergeObjectsHandler) UndoMerge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	historyID, err := uuid.Parse(chi.URLParam(r, "historyId"))
+	if err != nil {
+		http.Error(w, "Invalid merge history ID", http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		Force bool `json:"force"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := ctx.Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	creatorID := uuid.MustParse(claims.CreatorID)
+
+	history, err := h.q(ctx).GetObjectMergeHistory(ctx, database.GetObjectMergeHistoryParams{
+		ID:    historyID,
+		OrgID: orgID,
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Merge not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting merge: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if history.UndoneAt.Valid {
+		http.Error(w, "Merge was already undone", http.StatusConflict)
+		return
+	}
+	if !history.Snapshot.Valid {
+		http.Error(w, "Merge was made before merges could be undone", http.StatusConflict)
+		return
+	}
+	purged, err := h.q(ctx).CountPurgedMergeSources(ctx, history.SourceObjectIds)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error checking merged objects: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if purged > 0 {
+		http.Error(w, "Merged objects were purged from the trash", http.StatusConflict)
+		return
+	}
+	var snapshot mergeSnapshot
+	if err := json.Unmarshal(history.Snapshot.RawMessage, &snapshot); err != nil {
+		http.Error(w, fmt.Sprintf("Error reading merge snapshot: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	conflicts, err := h.mergeConflicts(ctx, history, &snapshot)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error checking merge conflicts: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if len(conflicts) > 0 && !input.Force {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":     "Merged data changed after the merge, undo with force to keep the changes",
+			"conflicts": conflicts,
+		})
+		return
+	}
+	changed := map[uuid.UUID][]string{}
+	for _, c := range conflicts {
+		changed[c.EntityID] = c.Fields
+	}
+
+	response := UndoMergeResponse{
+		HistoryID:         history.ID,
+		RestoredObjectIDs: []uuid.UUID{},
+		Conflicts:         conflicts,
+	}
+	// Every step runs in the request transaction, a failure rolls back the undo.
+	// The target goes first, sources may get back an id_string it took.
+	target := database.RestoreMergeTargetParams{
+		Name:        sql.NullString{String: snapshot.Target.Name, Valid: true},
+		Description: sql.NullString{String: snapshot.Target.Description, Valid: true},
+		IDString:    sql.NullString{String: snapshot.Target.IDString, Valid: true},
+		Aliases:     snapshot.Target.Aliases,
+		ID:          history.TargetObjectID,
+		OrgID:       orgID,
+	}
+	if target.Aliases == nil {
+		target.Aliases = []string{}
+	}
+	for _, field := range changed[history.TargetObjectID] {
+		switch field {
+		case "name":
+			target.Name = sql.NullString{}
+		case "description":
+			target.Description = sql.NullString{}
+		case "id_string":
+			target.IDString = sql.NullString{}
+		case "aliases":
+			target.Aliases = nil
+		}
+	}
+	if _, err := h.q(ctx).RestoreMergeTarget(ctx, target); err != nil {
+		http.Error(w, fmt.Sprintf("Error restoring target object: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	for _, source := range snapshot.Sources {
+		idString := sql.NullString{String: source.IDString, Valid: true}
+		if _, taken := changed[source.ID]; taken {
+			idString = sql.NullString{}
+		}
+		restored, err := h.q(ctx).RestoreMergedObject(ctx, database.RestoreMergedObjectParams{
+			IDString: idString,
+			ID:       source.ID,
+			OrgID:    orgID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error restoring object: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if restored > 0 {
+			response.RestoredObjectIDs = append(response.RestoredObjectIDs, source.ID)
+		}
+		moved, err := h.q(ctx).MoveMergedLinksBack(ctx, database.MoveMergedLinksBackParams{
+			TargetObjectID: history.TargetObjectID,
+			OrgID:          orgID,
+			SourceObjectID: source.ID,
+			FactIds:        linkIDs(snapshot.Facts, source.ID),
+			TaskIds:        linkIDs(snapshot.Tasks, source.ID),
+			StepIds:        linkIDs(snapshot.Steps, source.ID),
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error moving links back: %v", err), http.StatusInternalServerError)
+			return
+		}
+		response.MovedFacts += moved.Facts
+		response.MovedTasks += moved.Tasks
+		response.MovedSteps += moved.Steps
+	}
+
+	// Tags were copied, those the target did not have go
+	targetTags := linkIDs(snapshot.Tags, history.TargetObjectID)
+	copiedTags := []uuid.UUID{}
+	for _, tag := range snapshot.Tags {
+		if tag.ObjID != history.TargetObjectID && !containsUUID(targetTags, tag.ID) && !containsUUID(copiedTags, tag.ID) {
+			copiedTags = append(copiedTags, tag.ID)
+		}
+	}
+	response.RemovedTags, err = h.q(ctx).RemoveMergedTags(ctx, database.RemoveMergedTagsParams{
+		TargetObjectID: history.TargetObjectID,
+		TagIds:         copiedTags,
+		OrgID:          orgID,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error removing tags: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Relationships go back to the sources, those among the merged objects
+	// were deleted by the merge and come back
+	for _, rel := range snapshot.Relations {
+		if _, ok := changed[rel.ID]; ok {
+			continue
+		}
+		moved, err := h.q(ctx).RestoreMergedRelation(ctx, database.RestoreMergedRelationParams{
+			FromObjID: rel.FromObjID,
+			ToObjID:   rel.ToObjID,
+			ID:        rel.ID,
+			OrgID:     orgID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error moving relation back: %v", err), http.StatusInternalServerError)
+			return
+		}
+		response.MovedRelations += moved
+	}
+
+	for _, tv := range snapshot.TypeValues {
+		if _, ok := changed[tv.ID]; ok {
+			continue
+		}
+		_, err := h.q(ctx).UpdateObjectTypeValue(ctx, database.UpdateObjectTypeValueParams{
+			ID:      tv.ID,
+			OrgID:   orgID,
+			Column3: tv.TypeValues,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			http.Error(w, fmt.Sprintf("Error restoring type value: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	for _, id := range snapshot.CreatedTypeValueIDs {
+		if _, ok := changed[id]; ok {
+			continue
+		}
+		_, err := h.q(ctx).RemoveObjectTypeValue(ctx, database.RemoveObjectTypeValueParams{
+			ID:    id,
+			OrgID: orgID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error removing type value: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	for _, t := range snapshot.FactTexts {
+		if _, ok := changed[t.ID]; ok {
+			continue
+		}
+		_, err := h.q(ctx).RestoreMergedFactText(ctx, database.RestoreMergedFactTextParams{
+			ID:    t.ID,
+			Text:  t.Text,
+			OrgID: orgID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error restoring fact: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	for _, t := range snapshot.TaskTexts {
+		if _, ok := changed[t.ID]; ok {
+			continue
+		}
+		_, err := h.q(ctx).RestoreMergedTaskText(ctx, database.RestoreMergedTaskTextParams{
+			ID:      t.ID,
+			Content: t.Text,
+			OrgID:   orgID,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error restoring task: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	_, err = h.q(ctx).MarkObjectMergeUndone(ctx, database.MarkObjectMergeUndoneParams{
+		ID:       history.ID,
+		UndoneBy: uuid.NullUUID{UUID: creatorID, Valid: true},
+		OrgID:    orgID,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error marking merge as undone: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+type MergeHistoryResponse struct {
+	ID              uuid.UUID      `json:"id"`
+	TargetObjectID  uuid.UUID      `json:"target_object_id"`
+	SourceObjectIDs []uuid.UUID    `json:"source_object_ids"`
+	MergedAt        time.Time      `json:"merged_at"`
+	CreatorID       uuid.UUID      `json:"creator_id"`
+	CreatorUsername string         `json:"creator_username"`
+	UndoneAt        ctype.NullTime `json:"undone_at"`
+	UndoneBy        ctype.NullUUID `json:"undone_by"`
+	Undoable        bool           `json:"undoable"`
+}
+
+// ListHistory returns the latest merges of the org, or those of object_id as
+// target or source
+func (h *MergeObjectsHandler) ListHistory(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	params := database.ListObjectMergeHistoryParams{
+		OrgID: uuid.MustParse(claims.OrgID),
+		Limit: 50,
+	}
+	if v := r.URL.Query().Get("object_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid object_id", http.StatusBadRequest)
+			return
+		}
+		params.ObjectID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+		params.Limit = int32(l)
+	}
+
+	merges, err := h.q(r.Context()).ListObjectMergeHistory(r.Context(), params)
+	if err != nil {
+		http.Error(w, "Failed to list merges", http.StatusInternalServerError)
+		return
+	}
+	response := make([]MergeHistoryResponse, len(merges))
+	for i, m := range merges {
+		response[i] = MergeHistoryResponse{
+			ID:              m.ID,
+			TargetObjectID:  m.TargetObjectID,
+			SourceObjectIDs: m.SourceObjectIds,
+			MergedAt:        m.MergedAt,
+			CreatorID:       m.CreatorID,
+			CreatorUsername: m.CreatorUsername,
+			UndoneAt:        ctype.NullTime{NullTime: m.UndoneAt},
+			UndoneBy:        ctype.NullUUID{NullUUID: m.UndoneBy},
+			Undoable:        m.Undoable,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
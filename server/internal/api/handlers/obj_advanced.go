@@ -126,6 +126,18 @@ func (h *AdvancedObjectHandler) ListObjects(w http.ResponseWriter, r *http.Reque
 			return
 	}
 
+	relationTypeIDs, err := parseUUIDs(r.URL.Query().Get("relation_type_ids"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid relation type IDs: %v", err)})
+		return
+	}
+
+	relatedObjectIDs, err := parseUUIDs(r.URL.Query().Get("related_object_ids"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid related object IDs: %v", err)})
+		return
+	}
+
 	// Parse type value criteria
 	typeValueCriteria, err := parseTypeValueCriteria(r)
 	if err != nil {
@@ -163,6 +175,8 @@ func (h *AdvancedObjectHandler) ListObjects(w http.ResponseWriter, r *http.Reque
 		TypeValueField:    typeValueField,
 		Ascending:    ascending,
 		SubStatusFilter: subStatusFilter,
+		RelationTypeIDs:  relationTypeIDs,
+		RelatedObjectIDs: relatedObjectIDs,
 	}
 
 	// Get results from service
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -35,14 +36,20 @@ func (h *ObjStepHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	objStep, err := h.ObjectModel.CreateObjStep(r.Context(), input.ObjID, input.StepID, uuid.MustParse(claims.CreatorID))
+	orgId := uuid.MustParse(claims.OrgID)
+
+	objStep, err := h.ObjectModel.CreateObjStep(r.Context(), input.ObjID, input.StepID, uuid.MustParse(claims.CreatorID), orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object or step not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	fmt.Println("Created obj step ",objStep)
 	if input.SubStatus != 0 {
-		err = h.ObjectModel.UpdateObjStepSubStatus(r.Context(), objStep.ID, input.SubStatus)
+		err = h.ObjectModel.UpdateObjStepSubStatus(r.Context(), objStep.ID, orgId, input.SubStatus)
 		if err != nil {
 			fmt.Println("Error updating sub status ",err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -60,8 +67,14 @@ func (h *ObjStepHandler) SoftDelete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid obj_step ID", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
 
-	err = h.ObjectModel.SoftDeleteObjStep(r.Context(), id)
+	err = h.ObjectModel.SoftDeleteObjStep(r.Context(), id, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Obj step not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -76,8 +89,14 @@ func (h *ObjStepHandler) HardDelete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid obj_step ID", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
 
-	err = h.ObjectModel.HardDeleteObjStep(r.Context(), id)
+	err = h.ObjectModel.HardDeleteObjStep(r.Context(), id, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Obj step not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -102,8 +121,14 @@ func (h *ObjStepHandler) UpdateSubStatus(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
 
-	err = h.ObjectModel.UpdateObjStepSubStatus(r.Context(), id, input.SubStatus)
+	err = h.ObjectModel.UpdateObjStepSubStatus(r.Context(), id, orgId, input.SubStatus)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Obj step not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
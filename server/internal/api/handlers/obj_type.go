@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -20,6 +21,10 @@ func NewObjectTypeHandler(db *database.Queries) *ObjectTypeHandler {
 	return &ObjectTypeHandler{DB: db}
 }
 
+func (h *ObjectTypeHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
 func (h *ObjectTypeHandler) CreateObjectType(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Name        string          `json:"name"`
@@ -34,13 +39,13 @@ func (h *ObjectTypeHandler) CreateObjectType(w http.ResponseWriter, r *http.Requ
 	}
 
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
-	creator, err := h.DB.GetCreatorByID(r.Context(), uuid.MustParse(claims.CreatorID))
+	creator, err := h.q(r.Context()).GetCreatorByID(r.Context(), uuid.MustParse(claims.CreatorID))
 	if err != nil {
 		http.Error(w, "Failed to get creator", http.StatusInternalServerError)
 		return
 	}
 
-	objType, err := h.DB.CreateObjectType(r.Context(), database.CreateObjectTypeParams{
+	objType, err := h.q(r.Context()).CreateObjectType(r.Context(), database.CreateObjectTypeParams{
 		Name:        req.Name,
 		Description: req.Description,
 		Fields:      req.Fields,
@@ -74,13 +79,15 @@ func (h *ObjectTypeHandler) UpdateObjectType(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	objType, err := h.DB.UpdateObjectType(r.Context(), database.UpdateObjectTypeParams{
+	objType, err := h.q(r.Context()).UpdateObjectType(r.Context(), database.UpdateObjectTypeParams{
 		ID:          objTypeID,
 		Name:        req.Name,
 		Description: req.Description,
 		Fields:      req.Fields,
 		Icon:				 req.Icon,
+		OrgID:       uuid.MustParse(claims.OrgID),
 	})
 
 	if err != nil {
@@ -102,7 +109,12 @@ func (h *ObjectTypeHandler) DeleteObjectType(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	rowsAffected, err := h.DB.DeleteObjectType(r.Context(), objTypeID)
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+
+	rowsAffected, err := h.q(r.Context()).DeleteObjectType(r.Context(), database.DeleteObjectTypeParams{
+		ID:    objTypeID,
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
 	if err != nil {
 		http.Error(w, "Failed to delete object type", http.StatusInternalServerError)
 		return
@@ -131,7 +143,7 @@ func (h *ObjectTypeHandler) ListObjectTypes(w http.ResponseWriter, r *http.Reque
 
 	offset := (page - 1) * pageSize
 
-	objectTypes, err := h.DB.ListObjectTypes(r.Context(), database.ListObjectTypesParams{
+	objectTypes, err := h.q(r.Context()).ListObjectTypes(r.Context(), database.ListObjectTypesParams{
 		OrgID:  uuid.MustParse(claims.OrgID),
 		Column2:  query,
 		Limit:  int32(pageSize),
@@ -143,7 +155,7 @@ func (h *ObjectTypeHandler) ListObjectTypes(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	totalCount, err := h.DB.CountObjectTypes(r.Context(), database.CountObjectTypesParams{
+	totalCount, err := h.q(r.Context()).CountObjectTypes(r.Context(), database.CountObjectTypesParams{
 		OrgID: uuid.MustParse(claims.OrgID),
 		Column2: query,
 	})
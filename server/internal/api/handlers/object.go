@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"fmt"
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/internal/models"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/ctype"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
@@ -18,10 +24,15 @@ import (
 type ObjectHandler struct {
 	ObjectModel *models.ObjectModel
 	DB *database.Queries
+	Attachments *service.AttachmentService
 }
 
-func NewObjectHandler(objectModel *models.ObjectModel, db *database.Queries) *ObjectHandler {
-	return &ObjectHandler{ObjectModel: objectModel, DB: db}
+func NewObjectHandler(objectModel *models.ObjectModel, db *database.Queries, attachments *service.AttachmentService) *ObjectHandler {
+	return &ObjectHandler{ObjectModel: objectModel, DB: db, Attachments: attachments}
+}
+
+func (h *ObjectHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
 }
 
 func (h *ObjectHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -69,8 +80,14 @@ func (h *ObjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
 
-	object, err := h.ObjectModel.Update(r.Context(), id, input.Name, input.Description, input.IDString, input.Aliases)
+	object, err := h.ObjectModel.Update(r.Context(), id, orgId, input.Name, input.Description, input.IDString, input.Aliases)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -86,8 +103,14 @@ func (h *ObjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid object ID", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
 
-	err = h.ObjectModel.Delete(r.Context(), id)
+	err = h.ObjectModel.Delete(r.Context(), id, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -143,11 +166,42 @@ func (h *ObjectHandler) GetDetails(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	orgId := uuid.MustParse(claims.OrgID)
 
+	// With at, the object is reconstructed as it was at the time
+	if at := r.URL.Query().Get("at"); at != "" {
+		asOf, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			http.Error(w, "Invalid at, expected an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		object, err := h.ObjectModel.GetAt(r.Context(), id, orgId, asOf)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Object not found at this time", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(object)
+		return
+	}
+
 	objectDetails, err := h.ObjectModel.GetDetails(r.Context(), id, orgId)
 	if err != nil {
 		http.Error(w, "Object not found", http.StatusNotFound)
 		return
 	}
+	// A photo from an attachment is shown through a signed URL, the object
+	// is shown without it if the URL can not be signed
+	if objectDetails.PhotoAttachmentID.Valid {
+		url, _, err := h.Attachments.DownloadURL(r.Context(), orgId, objectDetails.PhotoAttachmentID.UUID, 0)
+		if err != nil {
+			log.Printf("Error signing photo URL of object %s: %v", id, err)
+		} else {
+			objectDetails.Photo = url
+		}
+	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(objectDetails)
@@ -174,6 +228,10 @@ func (h *ObjectHandler) AddTag(w http.ResponseWriter, r *http.Request) {
 	orgId := uuid.MustParse(claims.OrgID)
 	
 	err = h.ObjectModel.AddTag(r.Context(), objectID, input.TagID, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object or tag not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -182,6 +240,39 @@ func (h *ObjectHandler) AddTag(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// SetPhoto makes an image attachment of the org the photo of the object, a
+// null attachmentId clears it
+func (h *ObjectHandler) SetPhoto(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		AttachmentID ctype.NullUUID `json:"attachmentId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.Attachments.SetObjectPhoto(r.Context(), uuid.MustParse(claims.OrgID), objectID, input.AttachmentID.NullUUID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		http.Error(w, "Object or attachment not found", http.StatusNotFound)
+		return
+	case service.ErrAttachmentNotImage:
+		http.Error(w, "The photo must be an image", http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, "Failed to set photo", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *ObjectHandler) RemoveTag(w http.ResponseWriter, r *http.Request) {
 	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -199,6 +290,10 @@ func (h *ObjectHandler) RemoveTag(w http.ResponseWriter, r *http.Request) {
 	orgId := uuid.MustParse(claims.OrgID)	
 
 	err = h.ObjectModel.RemoveTag(r.Context(), objectID, tagID, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -229,8 +324,11 @@ func (h *ObjectHandler) AddObjectTypeValue(w http.ResponseWriter, r *http.Reques
 
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	orgId := uuid.MustParse(claims.OrgID)
-	// TODO: orgId is unused in model, need to check it somewhere
 	typeValue, err := h.ObjectModel.AddObjectTypeValue(r.Context(), objectID, input.TypeID, input.Values, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object or object type not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -250,6 +348,10 @@ func (h *ObjectHandler) RemoveObjectTypeValue(w http.ResponseWriter, r *http.Req
 	orgId := uuid.MustParse(claims.OrgID)
 	
 	err = h.ObjectModel.RemoveObjectTypeValue(r.Context(), typeValueID, orgId)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Type value not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -285,6 +387,10 @@ func (h *ObjectHandler) UpdateObjectTypeValue(w http.ResponseWriter, r *http.Req
 	OrgID := uuid.MustParse(claims.OrgID)
 
 	updatedTypeValue, err := h.ObjectModel.UpdateObjectTypeValue(r.Context(), typeValueID, OrgID, input.Values)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Type value not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -294,6 +400,82 @@ func (h *ObjectHandler) UpdateObjectTypeValue(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(updatedTypeValue)
 }
 
+// History lists the field level changes of the object and of its type values
+// with who made them, newest first
+func (h *ObjectHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	limit, offset := 100, 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
+
+	history, err := h.ObjectModel.History(r.Context(), id, orgId, limit, offset)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// RestoreObjectTypeValue sets a type value of the object back to its values at
+// a previous time
+func (h *ObjectHandler) RestoreObjectTypeValue(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	typeValueID, err := uuid.Parse(chi.URLParam(r, "typeValueId"))
+	if err != nil {
+		http.Error(w, "Invalid type value ID", http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		At time.Time `json:"at"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.At.IsZero() {
+		http.Error(w, "at is required", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgId := uuid.MustParse(claims.OrgID)
+
+	typeValue, err := h.ObjectModel.RestoreObjectTypeValue(r.Context(), objectID, typeValueID, orgId, input.At)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Type value not found at this time", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(typeValue)
+}
+
 type ObjectWithTagsAndTypeValues struct {
 	ID          uuid.UUID       `json:"id"`
 	Name        string          `json:"name"`
@@ -349,7 +531,7 @@ func (h *ObjectHandler) ListObjectsByTypeWithAdvancedFilter(w http.ResponseWrite
 	}
 
 	// Fetch objects
-	objects, err := h.DB.ListObjectsByTypeWithAdvancedFilter(ctx, database.ListObjectsByTypeWithAdvancedFilterParams{
+	objects, err := h.q(ctx).ListObjectsByTypeWithAdvancedFilter(ctx, database.ListObjectsByTypeWithAdvancedFilterParams{
 		TypeID:     typeID,
 		OrgID:      orgID,
 		Column3: typeValuesFilter,
@@ -365,7 +547,7 @@ func (h *ObjectHandler) ListObjectsByTypeWithAdvancedFilter(w http.ResponseWrite
 		return
 	}
 	// Count total objects
-	totalCount, err := h.DB.CountObjectsByTypeWithAdvancedFilter(ctx, database.CountObjectsByTypeWithAdvancedFilterParams{
+	totalCount, err := h.q(ctx).CountObjectsByTypeWithAdvancedFilter(ctx, database.CountObjectsByTypeWithAdvancedFilterParams{
 		TypeID:     typeID,
 		OrgID:      orgID,
 		Column3: typeValuesFilter,
@@ -394,7 +576,14 @@ func (h *ObjectHandler) ListObjectsByTypeWithAdvancedFilter(w http.ResponseWrite
 	}
 
 	// get the object type
-	objectType, err := h.DB.GetObjectTypeByID(ctx, typeID)
+	objectType, err := h.q(ctx).GetObjectTypeByID(ctx, database.GetObjectTypeByIDParams{
+		ID:    typeID,
+		OrgID: orgID,
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object type not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to get object type", http.StatusInternalServerError)
 		return
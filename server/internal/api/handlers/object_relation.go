@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/models"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type relationValuesInput struct {
+	Attributes json.RawMessage `json:"attributes"`
+	StartsAt   ctype.NullTime  `json:"startsAt"`
+	EndsAt     ctype.NullTime  `json:"endsAt"`
+}
+
+// values checks the input and returns it as models.RelationValues, or the
+// message of a bad request
+func (in relationValuesInput) values() (models.RelationValues, string) {
+	if len(in.Attributes) > 0 && string(in.Attributes) != "null" {
+		var attributes map[string]interface{}
+		if err := json.Unmarshal(in.Attributes, &attributes); err != nil {
+			return models.RelationValues{}, "attributes must be an object"
+		}
+	}
+	if in.StartsAt.Valid && in.EndsAt.Valid && in.EndsAt.Time.Before(in.StartsAt.Time) {
+		return models.RelationValues{}, "endsAt must not be before startsAt"
+	}
+	return models.RelationValues{
+		Attributes: in.Attributes,
+		StartsAt:   in.StartsAt.NullTime,
+		EndsAt:     in.EndsAt.NullTime,
+	}, ""
+}
+
+// AddRelation relates the object to another. The object is the first of the
+// pair unless direction is incoming, e.g. a person "works at" a company is
+// added to the person as outgoing, or to the company as incoming.
+func (h *ObjectHandler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		relationValuesInput
+		TypeID    uuid.UUID `json:"typeId"`
+		ObjectID  uuid.UUID `json:"objectId"`
+		Direction string    `json:"direction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	values, invalid := input.values()
+	if invalid != "" {
+		http.Error(w, invalid, http.StatusBadRequest)
+		return
+	}
+	fromID, toID := objectID, input.ObjectID
+	switch input.Direction {
+	case "", "outgoing":
+	case "incoming":
+		fromID, toID = toID, fromID
+	default:
+		http.Error(w, "direction must be outgoing or incoming", http.StatusBadRequest)
+		return
+	}
+	if fromID == toID {
+		http.Error(w, "An object can not be related to itself", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	relation, err := h.ObjectModel.AddRelation(r.Context(), fromID, toID, input.TypeID,
+		uuid.MustParse(claims.OrgID), uuid.MustParse(claims.CreatorID), values)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Object or relation type not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(relation)
+}
+
+func (h *ObjectHandler) UpdateRelation(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	relationID, err := uuid.Parse(chi.URLParam(r, "relationId"))
+	if err != nil {
+		http.Error(w, "Invalid relation ID", http.StatusBadRequest)
+		return
+	}
+	var input relationValuesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	values, invalid := input.values()
+	if invalid != "" {
+		http.Error(w, invalid, http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	relation, err := h.ObjectModel.UpdateRelation(r.Context(), objectID, relationID, uuid.MustParse(claims.OrgID), values)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Relation not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(relation)
+}
+
+func (h *ObjectHandler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid object ID", http.StatusBadRequest)
+		return
+	}
+	relationID, err := uuid.Parse(chi.URLParam(r, "relationId"))
+	if err != nil {
+		http.Error(w, "Invalid relation ID", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.ObjectModel.RemoveRelation(r.Context(), objectID, relationID, uuid.MustParse(claims.OrgID))
+	if err == sql.ErrNoRows {
+		http.Error(w, "Relation not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type RelationTypeHandler struct {
+	DB *database.Queries
+}
+
+func NewRelationTypeHandler(db *database.Queries) *RelationTypeHandler {
+	return &RelationTypeHandler{DB: db}
+}
+
+func (h *RelationTypeHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
+type relationTypeInput struct {
+	Name        string `json:"name"`
+	InverseName string `json:"inverse_name"`
+	// Directional defaults to true
+	Directional *bool  `json:"directional"`
+	Description string `json:"description"`
+}
+
+func (in relationTypeInput) directional() bool {
+	return in.Directional == nil || *in.Directional
+}
+
+// relationTypeNameTaken tells whether err is the unique (org_id, name) of the
+// relation types, another type of the org has the name already
+func relationTypeNameTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func (h *RelationTypeHandler) CreateRelationType(w http.ResponseWriter, r *http.Request) {
+	var req relationTypeInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	relationType, err := h.q(r.Context()).CreateObjRelationType(r.Context(), database.CreateObjRelationTypeParams{
+		OrgID:       uuid.MustParse(claims.OrgID),
+		Name:        req.Name,
+		InverseName: req.InverseName,
+		Directional: req.directional(),
+		Description: req.Description,
+		CreatorID:   uuid.MustParse(claims.CreatorID),
+	})
+	if relationTypeNameTaken(err) {
+		http.Error(w, "A relation type with the same name exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to create relation type", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(relationType)
+}
+
+func (h *RelationTypeHandler) UpdateRelationType(w http.ResponseWriter, r *http.Request) {
+	relationTypeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid relation type ID", http.StatusBadRequest)
+		return
+	}
+	var req relationTypeInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	relationType, err := h.q(r.Context()).UpdateObjRelationType(r.Context(), database.UpdateObjRelationTypeParams{
+		ID:          relationTypeID,
+		Name:        req.Name,
+		InverseName: req.InverseName,
+		Directional: req.directional(),
+		Description: req.Description,
+		OrgID:       uuid.MustParse(claims.OrgID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Relation type not found", http.StatusNotFound)
+		} else if relationTypeNameTaken(err) {
+			http.Error(w, "A relation type with the same name exists", http.StatusConflict)
+		} else {
+			http.Error(w, "Failed to update relation type", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(relationType)
+}
+
+func (h *RelationTypeHandler) DeleteRelationType(w http.ResponseWriter, r *http.Request) {
+	relationTypeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid relation type ID", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	rowsAffected, err := h.q(r.Context()).DeleteObjRelationType(r.Context(), database.DeleteObjRelationTypeParams{
+		ID:    relationTypeID,
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
+	if err != nil {
+		http.Error(w, "Failed to delete relation type", http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "Relation type not found or is in use", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RelationTypeHandler) ListRelationTypes(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	relationTypes, err := h.q(r.Context()).ListObjRelationTypes(r.Context(), uuid.MustParse(claims.OrgID))
+	if err != nil {
+		http.Error(w, "Failed to list relation types", http.StatusInternalServerError)
+		return
+	}
+	if relationTypes == nil {
+		relationTypes = []database.ListObjRelationTypesRow{}
+	}
+
+	json.NewEncoder(w).Encode(relationTypes)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
@@ -17,16 +18,20 @@ func NewSummarizeHandler(db *database.Queries) *SummarizeHandler {
 	return &SummarizeHandler{db: db}
 }
 
+func (h *SummarizeHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
+}
+
 // ListFeeds handles GET requests to retrieve feed items
 func (h *SummarizeHandler) PersonalSummarize(w http.ResponseWriter, r *http.Request) {
 	// Extract creator ID from the context
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
-	unseen, err := h.db.CountUnseenFeed(r.Context(), uuid.MustParse(claims.CreatorID))
+	unseen, err := h.q(r.Context()).CountUnseenFeed(r.Context(), uuid.MustParse(claims.CreatorID))
 	if err != nil {
 		http.Error(w, "Failed to count unseen feed items", http.StatusInternalServerError)
 		return
 	}
-	ongoingTask, err := h.db.CountOngoingTask(r.Context(), uuid.NullUUID{Valid: true, UUID: uuid.MustParse(claims.CreatorID)})
+	ongoingTask, err := h.q(r.Context()).CountOngoingTask(r.Context(), uuid.NullUUID{Valid: true, UUID: uuid.MustParse(claims.CreatorID)})
 	if err != nil {
 		http.Error(w, "Failed to count ongoing task", http.StatusInternalServerError)
 		return
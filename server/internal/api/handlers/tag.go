@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"io"
@@ -22,6 +23,10 @@ func NewTagHandler(db *database.Queries) *TagHandler {
 	return &TagHandler{DB: db}
 }
 
+func (h *TagHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
 type ColorSchema struct {
 	Background string `json:"background"`
 	Text       string `json:"text"`
@@ -45,7 +50,7 @@ func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	orgId := uuid.MustParse(claims.OrgID)
 	
-	tag, err := h.DB.CreateTag(r.Context(), database.CreateTagParams{
+	tag, err := h.q(r.Context()).CreateTag(r.Context(), database.CreateTagParams{
 		Name:        req.Name,
 		Description: req.Description,
 		ColorSchema: req.ColorSchema,
@@ -75,11 +80,13 @@ func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	tag, err := h.DB.UpdateTag(r.Context(), database.UpdateTagParams{
+	tag, err := h.q(r.Context()).UpdateTag(r.Context(), database.UpdateTagParams{
 		ID:          tagID,
 		Description: req.Description,
 		ColorSchema: req.ColorSchema,
+		OrgID:       uuid.MustParse(claims.OrgID),
 	})
 
 	if err != nil {
@@ -101,7 +108,12 @@ func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rowsAffected, err := h.DB.DeleteTag(r.Context(), tagID)
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+
+	rowsAffected, err := h.q(r.Context()).DeleteTag(r.Context(), database.DeleteTagParams{
+		ID:    tagID,
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
 	if err != nil {
 		http.Error(w, "Failed to delete tag", http.StatusInternalServerError)
 		return
@@ -132,7 +144,7 @@ func (h *TagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
 
 	offset := (page - 1) * pageSize
 
-	tags, err := h.DB.ListTags(r.Context(), database.ListTagsParams{
+	tags, err := h.q(r.Context()).ListTags(r.Context(), database.ListTagsParams{
 		OrgID:  orgId,
 		Column2:  query,
 		Limit:  int32(pageSize),
@@ -144,7 +156,7 @@ func (h *TagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	totalCount, err := h.DB.CountTags(r.Context(), database.CountTagsParams{
+	totalCount, err := h.q(r.Context()).CountTags(r.Context(), database.CountTagsParams{
 		OrgID: orgId,
 		Column2: query,
 	})
@@ -176,7 +188,7 @@ func (h *TagHandler) GetTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tag, err := h.DB.GetTagByID(r.Context(), tagID)
+	tag, err := h.q(r.Context()).GetTagByID(r.Context(), tagID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Tag not found", http.StatusNotFound)
@@ -204,7 +216,7 @@ func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
 		uuids = append(uuids, uuid)
 	}
 
-	tags, err := h.DB.GetTagsByIDs(r.Context(), uuids)
+	tags, err := h.q(r.Context()).GetTagsByIDs(r.Context(), uuids)
 
 	if err != nil {
 		http.Error(w, "Failed to get tags", http.StatusInternalServerError)
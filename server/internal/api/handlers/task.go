@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/models"
 	"github.com/crea8r/muninn/server/pkg/ctype"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -168,6 +170,10 @@ func NewTaskHandler(db *database.Queries) *TaskHandler {
 	return &TaskHandler{db: db}
 }
 
+func (h *TaskHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.db)
+}
+
 type createTaskRequest struct {
 	Content   string    `json:"content"`
 	Deadline  ctype.NullTime `json:"deadline"`
@@ -204,13 +210,13 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	
 	// Check if assigned_id is in the same organization
 	if req.AssignedID.Valid {
-		assigned, err := h.db.GetCreatorByID(r.Context(), req.AssignedID.UUID)
+		assigned, err := h.q(r.Context()).GetCreatorByID(r.Context(), req.AssignedID.UUID)
 		if err != nil || assigned.OrgID != OrgID {
 			http.Error(w, "Invalid assigned_id", http.StatusBadRequest)
 			return
 		}
 	}
-	task, err := h.db.CreateTask(r.Context(), database.CreateTaskParams{
+	task, err := h.q(r.Context()).CreateTask(r.Context(), database.CreateTaskParams{
 		Content:    req.Content,
 		Deadline:   sql.NullTime{Time: req.Deadline.Time, Valid: req.Deadline.Valid},
 		RemindAt:   sql.NullTime{Time: req.RemindAt.Time, Valid: req.RemindAt.Valid},
@@ -227,7 +233,7 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Associate objects with the task
 	if len(req.ObjectIDs) > 0 {
-		err = h.db.AddObjectsToTask(r.Context(), database.AddObjectsToTaskParams{
+		err = h.q(r.Context()).AddObjectsToTask(r.Context(), database.AddObjectsToTaskParams{
 			Column1: req.ObjectIDs,
 			TaskID: task.ID,
 			OrgID: OrgID,
@@ -260,14 +266,14 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Check if assigned_id is in the same organization
 	if req.AssignedID != uuid.Nil {
-		assigned, err := h.db.GetCreatorByID(r.Context(), req.AssignedID)
+		assigned, err := h.q(r.Context()).GetCreatorByID(r.Context(), req.AssignedID)
 		if err != nil || assigned.OrgID != OrgID {
 			http.Error(w, "Invalid assigned_id", http.StatusBadRequest)
 			return
 		}
 	}
 
-	task, err := h.db.UpdateTask(r.Context(), database.UpdateTaskParams{
+	task, err := h.q(r.Context()).UpdateTask(r.Context(), database.UpdateTaskParams{
 		ID:         taskID,
 		Content:    req.Content,
 		Deadline:   sql.NullTime{Time: req.Deadline, Valid: !req.Deadline.IsZero()},
@@ -275,8 +281,13 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Status:     req.Status,
 		AssignedID: uuid.NullUUID{UUID: req.AssignedID, Valid: req.AssignedID != uuid.Nil},
 		ParentID:   uuid.NullUUID{UUID: req.ParentID, Valid: req.ParentID != uuid.Nil},
+		OrgID:      OrgID,
 	})
 
+	if err == sql.ErrNoRows {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -285,9 +296,10 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Update associated objects
 	if len(req.ToRemoveObjectIDs) > 0 {
 		// Remove existing associations
-		err = h.db.RemoveObjectsFromTask(r.Context(), database.RemoveObjectsFromTaskParams{
+		err = h.q(r.Context()).RemoveObjectsFromTask(r.Context(), database.RemoveObjectsFromTaskParams{
 			TaskID: taskID,
 			Column2: req.ToRemoveObjectIDs,
+			OrgID: OrgID,
 		})
 		if err != nil {
 			http.Error(w, "Error removing existing object associations", http.StatusInternalServerError)
@@ -296,7 +308,7 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	if len(req.ToAddObjectIDs) > 0 {
 		// Add new associations
-		err = h.db.AddObjectsToTask(r.Context(), database.AddObjectsToTaskParams{
+		err = h.q(r.Context()).AddObjectsToTask(r.Context(), database.AddObjectsToTaskParams{
 			Column1: req.ToAddObjectIDs,
 			TaskID: taskID,
 			OrgID: OrgID,
@@ -319,8 +331,12 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 	CreatorId := uuid.MustParse(claims.CreatorID)
+	OrgID := uuid.MustParse(claims.OrgID)
 	// Validate that the task belongs to the organization
-	task, err := h.db.GetTaskByID(r.Context(), taskID)
+	task, err := h.q(r.Context()).GetTaskByID(r.Context(), database.GetTaskByIDParams{
+		ID:    taskID,
+		OrgID: OrgID,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Task not found", http.StatusNotFound)
@@ -334,21 +350,29 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.db.DeleteTask(r.Context(), taskID)
+	rows, err := h.q(r.Context()).DeleteTask(r.Context(), database.DeleteTaskParams{
+		ID:    taskID,
+		OrgID: OrgID,
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if rows == 0 {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 /**
 * Not a common route
-* Only admin use this function to see all organization tasks
+* Only roles with task:read_all use this function to see all organization tasks
 */
 func (h *TaskHandler) ListAllTasksInOrg(w http.ResponseWriter, r *http.Request) {
-	orgID := r.Context().Value("orgID").(uuid.UUID)
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
 
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
@@ -361,7 +385,7 @@ func (h *TaskHandler) ListAllTasksInOrg(w http.ResponseWriter, r *http.Request)
 		pageSize = 20
 	}
 
-	tasks, err := h.db.ListTasksByOrgID(r.Context(), database.ListTasksByOrgIDParams{
+	tasks, err := h.q(r.Context()).ListTasksByOrgID(r.Context(), database.ListTasksByOrgIDParams{
 		OrgID:    orgID,
 		Column2:   search,
 		Limit:    int32(pageSize),
@@ -373,7 +397,7 @@ func (h *TaskHandler) ListAllTasksInOrg(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	totalCount, err := h.db.CountTasksByOrgID(r.Context(), database.CountTasksByOrgIDParams{
+	totalCount, err := h.q(r.Context()).CountTasksByOrgID(r.Context(), database.CountTasksByOrgIDParams{
 		OrgID:  orgID,
 		Column2: search,
 	})
@@ -407,8 +431,12 @@ func (h *TaskHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid task ID", http.StatusBadRequest)
 		return
 	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
 
-	task, err := h.db.GetTaskByID(r.Context(), taskID)
+	task, err := h.q(r.Context()).GetTaskByID(r.Context(), database.GetTaskByIDParams{
+		ID:    taskID,
+		OrgID: uuid.MustParse(claims.OrgID),
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Task not found", http.StatusNotFound)
@@ -419,20 +447,32 @@ func (h *TaskHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch associated objects
-	objects, err := h.db.ListObjectsByTaskID(r.Context(), taskID)
+	objects, err := h.q(r.Context()).ListObjectsByTaskID(r.Context(), taskID)
 	if err != nil {
 		http.Error(w, "Error fetching associated objects", http.StatusInternalServerError)
 		return
 	}
 
+	// Fetch the discussion about the task
+	comments, err := h.q(r.Context()).ListComments(r.Context(), database.ListCommentsParams{
+		OrgID:  uuid.MustParse(claims.OrgID),
+		TaskID: uuid.NullUUID{UUID: taskID, Valid: true},
+	})
+	if err != nil {
+		http.Error(w, "Error fetching comments", http.StatusInternalServerError)
+		return
+	}
+
 	responseTask := ConvertIDRowTask(task)
 
 	response := struct {
-		Task    ResponseTask `json:"task"`
-		Objects []database.ListObjectsByTaskIDRow `json:"objects"`
+		Task     ResponseTask `json:"task"`
+		Objects  []database.ListObjectsByTaskIDRow `json:"objects"`
+		Comments []models.Comment `json:"comments"`
 	}{
-		Task:    responseTask,
-		Objects: objects,
+		Task:     responseTask,
+		Objects:  objects,
+		Comments: models.NewComments(comments),
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -455,7 +495,7 @@ func (h *TaskHandler) ListByObjectID(w http.ResponseWriter, r *http.Request) {
 		pageSize = 20
 	}
 
-	tasks, err := h.db.ListTasksByObjectID(r.Context(), database.ListTasksByObjectIDParams{
+	tasks, err := h.q(r.Context()).ListTasksByObjectID(r.Context(), database.ListTasksByObjectIDParams{
 		ID: objectID,
 		Column2: search,
 		Limit:   int32(pageSize),
@@ -467,7 +507,7 @@ func (h *TaskHandler) ListByObjectID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	totalCount, err := h.db.CountTasksByObjectID(r.Context(), database.CountTasksByObjectIDParams{
+	totalCount, err := h.q(r.Context()).CountTasksByObjectID(r.Context(), database.CountTasksByObjectIDParams{
 		ObjID: objectID,
 		Column2: search,
 	})
@@ -531,7 +571,7 @@ func (h *TaskHandler) ListWithFilter(w http.ResponseWriter, r *http.Request) {
 		}
 		assignedUUID = parsed
 	}
-	tasks, err := h.db.ListTasksWithFilter(r.Context(), database.ListTasksWithFilterParams{
+	tasks, err := h.q(r.Context()).ListTasksWithFilter(r.Context(), database.ListTasksWithFilterParams{
 		Column1: creatorUUID,
 		Column2: assignedUUID,
 		Column3: query,
@@ -544,7 +584,7 @@ func (h *TaskHandler) ListWithFilter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	totalCount, err := h.db.CountTasksWithFilter(r.Context(), database.CountTasksWithFilterParams{
+	totalCount, err := h.q(r.Context()).CountTasksWithFilter(r.Context(), database.CountTasksWithFilterParams{
 		Column1: creatorUUID,
 		Column2: assignedUUID,
 		Column3: query,
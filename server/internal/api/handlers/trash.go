@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/crea8r/muninn/server/pkg/pagination"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TrashHandler lists the soft-deleted records of the org and restores them.
+// The task runner purges them once the retention of the org has passed.
+type TrashHandler struct {
+	DB    *database.Queries
+	Trash *service.TrashService
+}
+
+func NewTrashHandler(db *database.Queries, trash *service.TrashService) *TrashHandler {
+	return &TrashHandler{DB: db, Trash: trash}
+}
+
+func (h *TrashHandler) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, h.DB)
+}
+
+type TrashItem struct {
+	Kind              string         `json:"kind"`
+	ID                uuid.UUID      `json:"id"`
+	Title             string         `json:"title"`
+	DeletedAt         time.Time      `json:"deleted_at"`
+	DeletedBy         ctype.NullUUID `json:"deleted_by"`
+	DeletedByUsername string         `json:"deleted_by_username"`
+	PurgeAt           time.Time      `json:"purge_at"`
+}
+
+// ListTrash returns the records in the trash, most recently deleted first,
+// optionally only those of one kind
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	orgID := uuid.MustParse(claims.OrgID)
+	kind := r.URL.Query().Get("kind")
+	if kind != "" && !service.IsValidTrashKind(kind) {
+		http.Error(w, "Invalid kind, expected object, fact, task, funnel, tag or list", http.StatusBadRequest)
+		return
+	}
+	params := pagination.NewParams(r)
+	if err := params.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	retention, err := h.Trash.Retention(r.Context(), orgID)
+	if err != nil {
+		http.Error(w, "Failed to get trash retention", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.q(r.Context()).ListTrash(r.Context(), database.ListTrashParams{
+		OrgID:  orgID,
+		Kind:   sql.NullString{String: kind, Valid: kind != ""},
+		Limit:  params.PageSize,
+		Offset: params.GetOffset(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to list trash", http.StatusInternalServerError)
+		return
+	}
+	result := pagination.PaginatedResult[TrashItem]{
+		Items:      make([]TrashItem, len(rows)),
+		TotalCount: int64(0),
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+	}
+	for i, item := range rows {
+		result.TotalCount = item.TotalCount
+		result.Items[i] = TrashItem{
+			Kind:              item.Kind,
+			ID:                item.ID,
+			Title:             item.Title,
+			DeletedAt:         item.DeletedAt,
+			DeletedBy:         ctype.NullUUID{NullUUID: item.DeletedBy},
+			DeletedByUsername: item.DeletedByUsername,
+			PurgeAt:           item.DeletedAt.Add(retention),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Restore takes a record out of the trash
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	if !service.IsValidTrashKind(kind) {
+		http.Error(w, "Invalid kind, expected object, fact, task, funnel, tag or list", http.StatusBadRequest)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	err = h.Trash.Restore(r.Context(), uuid.MustParse(claims.OrgID), kind, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Not found in the trash", http.StatusNotFound)
+		return
+	}
+	if err == service.ErrTrashConflict {
+		http.Error(w, "A "+kind+" with the same name exists, rename it first", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to restore from the trash", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/google/uuid"
+)
+
+// TestImportLeavesPending checks the import worker sees the task the request
+// created and takes it to completed
+func TestImportLeavesPending(t *testing.T) {
+	db, queries, router, auth := newTestRouter(t)
+	tn := newTenant(t, auth, "org-import")
+	var typeID uuid.UUID
+	tn.seed(t, db, queries, func(ctx context.Context, q *database.Queries) error {
+		objType, err := q.CreateObjectType(ctx, database.CreateObjectTypeParams{Name: "person", Description: "", Fields: json.RawMessage(`{"email":"string"}`), CreatorID: tn.creatorID, Icon: ""})
+		typeID = objType.ID
+		return err
+	})
+
+	body := fmt.Sprintf(`{"obj_type_id":%q,"file_name":"people.csv","rows":[
+		{"id_string":"ada","name":"Ada","values":{"email":"ada@example.com"},"fact":{"text":"Imported Ada"}}]}`, typeID)
+	rec := tn.do(router, http.MethodPost, "/import", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	var status struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		rec := tn.do(router, http.MethodGet, "/import/status?task_id="+created.TaskID, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status of the task = %d: %s", rec.Code, rec.Body)
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		if status.Status == "completed" || status.Status == "failed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the import is still %s", status.Status)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if status.Status != "completed" {
+		t.Errorf("the import %s: %s", status.Status, status.ErrorMessage)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImpersonationAllows(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		readOnly bool
+		allowed  bool
+	}{
+		{"GET", "/objects", true, true},
+		{"POST", "/objects", true, false},
+		{"PUT", "/objects/1", true, false},
+		{"POST", "/setting/object-types/1/advance", true, true},
+		{"POST", "/setting/object-types/1/advance/", true, true},
+		{"POST", "/setting/object-types/1/2/advance", true, false},
+		{"PUT", "/setting/object-types/1/advance", true, false},
+		{"POST", "/objects/merge/preview", true, true},
+		{"POST", "/objects/merge", true, false},
+		{"POST", "/external/objects", true, true},
+		{"POST", "/external/facts", true, false},
+		{"POST", "/objects", false, true},
+		{"POST", "/auth/logout", true, true},
+		{"POST", "/auth/password", false, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		err := impersonationAllows(&Claims{ReadOnly: tt.readOnly}, r)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s %s with read-only %v: allowed = %v, want %v", tt.method, tt.path, tt.readOnly, allowed, tt.allowed)
+		}
+	}
+}
@@ -2,63 +2,140 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/apikey"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Define key for context
 var UserClaimsKey = "user_claims"
 
-// Claims holds the JWT claims
-// TODO: should remove Name, OrgName, Profile from Claims so user can access to latest value once changed
+// APIKeyHeader carries an org API key as an alternative to a JWT
+const APIKeyHeader = "X-API-Key"
+
+// Claims holds the JWT claims. Display data such as name and profile is not
+// carried in the token, clients read the latest value from /auth/me
 type Claims struct {
 	CreatorID string `json:"creator_id"`
-	Name      string `json:"name"`
 	OrgID     string `json:"org_id"`
-	OrgName	 string `json:"org_name"`
 	Role      string `json:"role"`
-	Profile	 json.RawMessage `json:"profile"`
+	// ImpersonatorID is the admin viewing the app as CreatorID, it is empty
+	// for regular logins
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	ReadOnly       bool   `json:"read_only,omitempty"`
 	jwt.RegisteredClaims
+
+	// impersonationID is looked up for the token, requests are logged under it
+	impersonationID uuid.UUID
 }
 
-// Permission middleware handles JWT authentication and authorization
-func Permission(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get the JWT token from the Authorization header
-		tokenString := r.Header.Get("Authorization")
-		if tokenString == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		// Remove "Bearer " prefix if present
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
-		}
-
-		// Parse and validate the token
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
+var errUnauthorized = errors.New("Unauthorized")
+
+// Permission middleware authenticates the request with either a JWT in the
+// Authorization header or an API key in the X-API-Key header
+func Permission(db *database.Queries) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Nested route groups apply the middleware again, authenticate only once
+			if _, ok := r.Context().Value(UserClaimsKey).(*Claims); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var claims *Claims
+			var err error
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				claims, err = claimsFromAPIKey(r.Context(), db, key)
+			} else {
+				claims, err = claimsFromJWT(r.Context(), db, r.Header.Get("Authorization"))
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			// Add claims to the request context
+			ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
+			if claims.ImpersonatorID != "" {
+				impersonated(db, claims, next).ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
+	}
+}
 
-		if err != nil || !token.Valid {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
+func claimsFromJWT(ctx context.Context, db *database.Queries, tokenString string) (*Claims, error) {
+	if tokenString == "" {
+		return nil, errUnauthorized
+	}
 
-		// Check if the token has expired
-		if time.Now().After(claims.ExpiresAt.Time) {
-			http.Error(w, "Token has expired", http.StatusUnauthorized)
-			return
-		}
+	// Remove "Bearer " prefix if present
+	if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
+		tokenString = tokenString[7:]
+	}
 
-		// Add claims to the request context
-		ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
-		next.ServeHTTP(w, r.WithContext(ctx))
+	// Parse and validate the token
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET")), nil
 	})
-}
\ No newline at end of file
+
+	if err != nil || !token.Valid {
+		return nil, errUnauthorized
+	}
+
+	// Check if the token has expired
+	if claims.ExpiresAt == nil || time.Now().After(claims.ExpiresAt.Time) {
+		return nil, errors.New("Token has expired")
+	}
+
+	// Every login token is backed by a creator_session row, the session must not
+	// be revoked and its creator must still be active
+	if claims.ID == "" {
+		return nil, errors.New("Session not found, please login again")
+	}
+	if claims.ImpersonatorID != "" {
+		return impersonationClaims(ctx, db, claims)
+	}
+	session, err := db.GetActiveSessionByJti(ctx, claims.ID)
+	if err != nil {
+		return nil, errors.New("Session has been revoked")
+	}
+	if session.CreatorID.String() != claims.CreatorID {
+		return nil, errUnauthorized
+	}
+	// Role changes take effect without a new login
+	claims.Role = session.Role
+	return claims, nil
+}
+
+func claimsFromAPIKey(ctx context.Context, db *database.Queries, key string) (*Claims, error) {
+	prefix, err := apikey.Prefix(key)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	// Revoked, expired keys and keys of inactive creators are filtered by the query
+	row, err := db.GetActiveAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	if !apikey.Compare(row.KeyHash, key) {
+		return nil, errUnauthorized
+	}
+	if err := db.TouchAPIKey(ctx, row.ID); err != nil {
+		log.Printf("Error updating api key last used time: %v", err)
+	}
+	return &Claims{
+		CreatorID: row.CreatorID.String(),
+		OrgID:     row.OrgID.String(),
+		Role:      row.Role,
+	}, nil
+}
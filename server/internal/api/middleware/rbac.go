@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/google/uuid"
+)
+
+// Roles a creator can have
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+	RoleRobot  = "robot"
+)
+
+var Roles = []string{RoleAdmin, RoleMember, RoleViewer, RoleRobot}
+
+// Permissions lists every action a route can require, as "resource:action"
+var Permissions = []string{
+	"object:read", "object:write", "object:delete", "object:merge", "object:import",
+	"object_type:read", "object_type:write", "object_type:delete",
+	"fact:read", "fact:write", "fact:delete",
+	"fact_kind:write", "fact_kind:delete",
+	"task:read", "task:read_all", "task:write", "task:delete",
+	"funnel:read", "funnel:write", "funnel:delete",
+	"tag:read", "tag:write", "tag:delete",
+	"list:read", "list:write", "list:delete",
+	"attachment:read", "attachment:write", "attachment:delete",
+	"comment:write", "comment:moderate",
+	"automation:read", "automation:write", "automation:delete",
+	"metrics:read",
+	"audit:read",
+	"trash:read", "trash:restore",
+}
+
+// DefaultRolePermissions is the permission matrix used unless an org overrides
+// it. Admins are always granted every permission and cannot be customised.
+var DefaultRolePermissions = map[string][]string{
+	RoleMember: {
+		"object:read", "object:write", "object:delete", "object:merge", "object:import",
+		"object_type:read", "object_type:write",
+		"fact:read", "fact:write", "fact:delete",
+		"fact_kind:write",
+		"task:read", "task:write", "task:delete",
+		"funnel:read", "funnel:write",
+		"tag:read", "tag:write",
+		"list:read", "list:write", "list:delete",
+		"attachment:read", "attachment:write", "attachment:delete",
+		"comment:write",
+		"automation:read",
+		"metrics:read",
+		"trash:read", "trash:restore",
+	},
+	RoleViewer: {
+		"object:read", "object_type:read", "fact:read", "task:read",
+		"funnel:read", "tag:read", "list:read", "attachment:read", "automation:read", "metrics:read",
+	},
+	RoleRobot: {
+		"object:read", "object:write", "object_type:read",
+		"fact:read", "fact:write",
+		"task:read", "task:write",
+		"funnel:read", "tag:read",
+		"attachment:read", "attachment:write",
+	},
+}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDefaultPermission reports whether the role holds the permission in the
+// default matrix
+func HasDefaultPermission(role string, permission string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	for _, p := range DefaultRolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorizer checks the role of the authenticated creator against the
+// permission matrix and the overrides of its org
+type Authorizer struct {
+	db *database.Queries
+}
+
+func NewAuthorizer(db *database.Queries) *Authorizer {
+	return &Authorizer{db: db}
+}
+
+// RequirePermission must run after Permission, it answers 403 when the role of
+// the creator does not hold the permission
+func (a *Authorizer) RequirePermission(permission string) func(next http.Handler) http.Handler {
+	if !IsValidPermission(permission) {
+		panic("unknown permission " + permission)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(UserClaimsKey).(*Claims)
+			if !ok {
+				http.Error(w, errUnauthorized.Error(), http.StatusUnauthorized)
+				return
+			}
+			allowed, err := a.Can(r.Context(), claims, permission)
+			if err != nil {
+				log.Printf("Error checking permission %s: %v", permission, err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Can resolves a permission for the claims, org overrides win over the default
+func (a *Authorizer) Can(ctx context.Context, claims *Claims, permission string) (bool, error) {
+	if claims.Role == RoleAdmin {
+		return true, nil
+	}
+	orgID, err := uuid.Parse(claims.OrgID)
+	if err != nil {
+		return false, nil
+	}
+	granted, err := a.db.GetRolePermission(ctx, database.GetRolePermissionParams{
+		OrgID:      orgID,
+		Role:       claims.Role,
+		Permission: permission,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return HasDefaultPermission(claims.Role, permission), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return granted, nil
+}
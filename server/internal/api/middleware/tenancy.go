@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/crea8r/muninn/server/internal/database"
+)
+
+// Define key for the request scoped queries
+var QueriesKey = "queries"
+
+// Define key for what runs once the request transaction ends
+var txHooksKey = "txHooks"
+
+// txHooks are the functions to run after the request transaction committed
+// or rolled back, for side effects outside the database such as the blob
+// store
+type txHooks struct {
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+// OrgScope must run after Permission. The rest of the request runs in one
+// transaction where app.org_id is the org of the claims, so the row level
+// security policies only return and accept rows of that org. app.creator_id
+// names the actor of the audit events written by the transaction. The
+// transaction is committed right before a response below 400 is written and
+// rolled back otherwise.
+func OrgScope(db *sql.DB, queries *database.Queries) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Nested route groups apply the middleware again, scope only once
+			if _, ok := r.Context().Value(QueriesKey).(*database.Queries); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims, ok := r.Context().Value(UserClaimsKey).(*Claims)
+			if !ok {
+				http.Error(w, errUnauthorized.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			tx, err := db.BeginTx(r.Context(), nil)
+			if err != nil {
+				log.Printf("Error starting request transaction: %v", err)
+				http.Error(w, "Failed to start transaction", http.StatusInternalServerError)
+				return
+			}
+			// Rolling back a committed transaction is a no-op
+			defer tx.Rollback()
+
+			if err := SetOrgID(r.Context(), tx, claims.OrgID); err != nil {
+				log.Printf("Error setting org of request transaction: %v", err)
+				http.Error(w, "Failed to start transaction", http.StatusInternalServerError)
+				return
+			}
+			if err := SetActor(r.Context(), tx, claims.CreatorID, claims.ImpersonatorID); err != nil {
+				log.Printf("Error setting actor of request transaction: %v", err)
+				http.Error(w, "Failed to start transaction", http.StatusInternalServerError)
+				return
+			}
+
+			tw := &txResponseWriter{ResponseWriter: w, tx: tx, hooks: &txHooks{}}
+			ctx := context.WithValue(r.Context(), QueriesKey, queries.WithTx(tx))
+			ctx = context.WithValue(ctx, txHooksKey, tw.hooks)
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			// Handlers that write nothing answer 200
+			tw.finish(http.StatusOK)
+		})
+	}
+}
+
+// InOrg runs fn in a transaction scoped to the org, as OrgScope does for a
+// request, for work outside one such as background tasks. Without it the row
+// level security policies hide every row. The transaction commits when fn
+// returns nil, its changes have no actor.
+func InOrg(ctx context.Context, db *sql.DB, queries *database.Queries, orgID string, fn func(ctx context.Context) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rolling back a committed transaction is a no-op
+	defer tx.Rollback()
+	if err := SetOrgID(ctx, tx, orgID); err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	scoped := context.WithValue(ctx, QueriesKey, queries.WithTx(tx))
+	scoped = context.WithValue(scoped, txHooksKey, hooks)
+	err = fn(scoped)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		runHooks(hooks.afterRollback)
+		return err
+	}
+	runHooks(hooks.afterCommit)
+	return nil
+}
+
+// SetOrgID scopes the row level security policies of tx to the org until tx
+// ends
+func SetOrgID(ctx context.Context, tx *sql.Tx, orgID string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('app.org_id', $1, true)", orgID)
+	return err
+}
+
+// SetActor records who makes the changes of tx in its audit events until tx
+// ends. impersonatorID is empty unless an admin impersonates the creator.
+func SetActor(ctx context.Context, tx *sql.Tx, creatorID string, impersonatorID string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('app.creator_id', $1, true), set_config('app.impersonator_id', $2, true)",
+		creatorID, impersonatorID)
+	return err
+}
+
+// Queries returns the queries bound to the transaction of the request. Code
+// running outside a request, such as background tasks, gets fallback.
+func Queries(ctx context.Context, fallback *database.Queries) *database.Queries {
+	if q, ok := ctx.Value(QueriesKey).(*database.Queries); ok {
+		return q
+	}
+	return fallback
+}
+
+// AfterCommit runs fn once the request transaction of ctx committed, never if
+// it rolls back. Outside a request fn runs right away, the changes are made.
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(txHooksKey).(*txHooks); ok {
+		hooks.afterCommit = append(hooks.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// AfterRollback runs fn if the request transaction of ctx rolls back, to undo
+// what the transaction can not. Outside a request there is nothing to undo.
+func AfterRollback(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(txHooksKey).(*txHooks); ok {
+		hooks.afterRollback = append(hooks.afterRollback, fn)
+	}
+}
+
+// txResponseWriter ends the transaction before the status line goes out, so a
+// failed commit can still be reported to the client
+type txResponseWriter struct {
+	http.ResponseWriter
+	tx       *sql.Tx
+	hooks    *txHooks
+	finished bool
+	failed   bool
+}
+
+func (tw *txResponseWriter) WriteHeader(statusCode int) {
+	if tw.finish(statusCode) {
+		tw.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (tw *txResponseWriter) Write(b []byte) (int, error) {
+	// The body of a response whose commit failed is dropped
+	if !tw.finish(http.StatusOK) {
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// finish commits or rolls back the transaction once. It reports false when the
+// commit failed and a 500 was written instead of statusCode.
+func (tw *txResponseWriter) finish(statusCode int) bool {
+	if tw.finished {
+		return !tw.failed
+	}
+	tw.finished = true
+	if statusCode >= http.StatusBadRequest {
+		if err := tw.tx.Rollback(); err != nil {
+			log.Printf("Error rolling back request transaction: %v", err)
+		}
+		runHooks(tw.hooks.afterRollback)
+		return true
+	}
+	if err := tw.tx.Commit(); err != nil {
+		log.Printf("Error committing request transaction: %v", err)
+		runHooks(tw.hooks.afterRollback)
+		http.Error(tw.ResponseWriter, "Failed to commit transaction", http.StatusInternalServerError)
+		tw.failed = true
+		return false
+	}
+	runHooks(tw.hooks.afterCommit)
+	return true
+}
+
+func runHooks(hooks []func()) {
+	for _, fn := range hooks {
+		fn()
+	}
+}
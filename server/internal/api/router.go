@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 
 	"github.com/crea8r/muninn/server/internal/api/handlers"
@@ -12,11 +13,14 @@ import (
 	"github.com/crea8r/muninn/server/internal/features/auth"
 	"github.com/crea8r/muninn/server/internal/models"
 	"github.com/crea8r/muninn/server/internal/service"
+	"github.com/crea8r/muninn/server/pkg/blobstore"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/cors"
 )
 
-func SetupRouter(queries *database.Queries, db *sql.DB) *chi.Mux {
+func SetupRouter(queries *database.Queries, db *sql.DB, mail mailer.Mailer, sso *oidc.Provider, blobs blobstore.BlobStore, limits blobstore.Limits) *chi.Mux {
 	debug := os.Getenv("DEBUG_SQL") == "true"
 	fmt.Println("DEBUG_SQL: ", debug)
 	r := chi.NewRouter()
@@ -25,7 +29,7 @@ func SetupRouter(queries *database.Queries, db *sql.DB) *chi.Mux {
 		AllowedOrigins:   []string{"*"}, // Allow all origins
 		//[]string{"http://localhost:3000", "https://yourdomain.com"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", middleware.APIKeyHeader},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
@@ -37,21 +41,28 @@ func SetupRouter(queries *database.Queries, db *sql.DB) *chi.Mux {
 
 	tagHandler := handlers.NewTagHandler(queries)
 	objectTypeHandler := handlers.NewObjectTypeHandler(queries)
+	relationTypeHandler := handlers.NewRelationTypeHandler(queries)
 	funnelHandler := handlers.NewFunnelHandler(queries)
 	objectModel := models.NewObjectModel(queries)
-	objectHandler := handlers.NewObjectHandler(objectModel, queries)
 	objStepHandler := handlers.NewObjStepHandler(objectModel)
-	factHandler := handlers.NewFactHandler(queries)
+	factHandler := handlers.NewFactHandler(queries, service.NewFactService(queries))
 	taskHandler := handlers.NewTaskHandler(queries)
 	feedHandler := handlers.NewFeedHandler(queries)
 	summarizeHandler := handlers.NewSummarizeHandler(queries)
 	listHandler := handlers.NewListHandler(queries)
 	importHandler := handlers.NewImportTaskHandler(db)
 	mergeHandler := handlers.NewMergeObjectsHandler(db)
+	duplicateHandler := handlers.NewDuplicateHandler(queries, service.NewDuplicateService(queries, db), mergeHandler)
 	metricsService := service.NewMetricsService(queries)
 	metricsHandler := handlers.NewMetricsHandler(metricsService)
 	externalHandler := handlers.NewExternalHandler(db, queries)
 	automationHandler := handlers.NewAutomationHandler(queries)
+	auditHandler := handlers.NewAuditHandler(queries)
+	graphHandler := handlers.NewGraphHandler(service.NewGraphService(queries))
+	trashHandler := handlers.NewTrashHandler(queries, service.NewTrashService(queries, db))
+	attachmentService := service.NewAttachmentService(queries, blobs, limits)
+	attachmentHandler := handlers.NewAttachmentHandler(queries, attachmentService)
+	objectHandler := handlers.NewObjectHandler(objectModel, queries, attachmentService)
 	wrapWithFeed := func(handler http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			rw := middleware.NewResponseWriter(w)
@@ -65,155 +76,255 @@ func SetupRouter(queries *database.Queries, db *sql.DB) *chi.Mux {
 		}
 	}
 
+	permission := middleware.Permission(queries)
+	orgScope := middleware.OrgScope(db, queries)
+	authz := middleware.NewAuthorizer(queries)
+	can := authz.RequirePermission
+	commentHandler := handlers.NewCommentHandler(service.NewCommentService(queries), authz)
+
 	// Public routes
-	authHandler := *auth.NewHandler(queries)
+	authHandler := *auth.NewHandler(queries, db, mail, sso)
 	authHandler.RegisterRoutes(r, wrapWithFeed)
 
 	r.Get("/stats",handlers.HealthCheck(queries))
 
+	// The local blob store serves the signed download URLs it hands out
+	if local, ok := blobs.(*blobstore.LocalStore); ok {
+		if u, err := url.Parse(local.PublicURL); err == nil && u.Path != "" {
+			r.Handle(u.Path+"/*", http.StripPrefix(u.Path, local))
+		}
+	}
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Permission)
+		r.Use(permission)
+		r.Use(orgScope)
 		
 		r.Route("/metrics", func(r chi.Router) {
-			r.Use(middleware.Permission)
+			r.Use(permission)
+			r.Use(can("metrics:read"))
 			r.Get("/creator/{creatorId}", metricsHandler.GetCreatorMetrics)
 			r.Get("/team", metricsHandler.GetTeamMetrics)
 		})
 
 		r.Route("/setting/tags", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Post("/", tagHandler.CreateTag)
-			r.Get("/", tagHandler.ListTags)
-			r.Put("/{id}", tagHandler.UpdateTag)
-			r.Get("/{id}", tagHandler.GetTag)
-			r.Get("/ids", tagHandler.GetTags)
-			r.Delete("/{id}", tagHandler.DeleteTag)
+			r.Use(permission)
+			r.With(can("tag:write")).Post("/", tagHandler.CreateTag)
+			r.With(can("tag:read")).Get("/", tagHandler.ListTags)
+			r.With(can("tag:write")).Put("/{id}", tagHandler.UpdateTag)
+			r.With(can("tag:read")).Get("/{id}", tagHandler.GetTag)
+			r.With(can("tag:read")).Get("/ids", tagHandler.GetTags)
+			r.With(can("tag:delete")).Delete("/{id}", tagHandler.DeleteTag)
 		})
 
 		r.Route("/setting/object-types", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Post("/", wrapWithFeed(objectTypeHandler.CreateObjectType))
-			r.Get("/", objectTypeHandler.ListObjectTypes)
-			r.Put("/{id}", objectTypeHandler.UpdateObjectType)
-			r.Delete("/{id}", objectTypeHandler.DeleteObjectType)
-			r.Post("/{typeID}/advance", objectHandler.ListObjectsByTypeWithAdvancedFilter)
+			r.Use(permission)
+			r.With(can("object_type:write")).Post("/", wrapWithFeed(objectTypeHandler.CreateObjectType))
+			r.With(can("object_type:read")).Get("/", objectTypeHandler.ListObjectTypes)
+			r.With(can("object_type:write")).Put("/{id}", objectTypeHandler.UpdateObjectType)
+			r.With(can("object_type:delete")).Delete("/{id}", objectTypeHandler.DeleteObjectType)
+			r.With(can("object:read")).Post("/{typeID}/advance", objectHandler.ListObjectsByTypeWithAdvancedFilter)
+		})
+
+		r.Route("/setting/relation-types", func(r chi.Router) {
+			r.Use(permission)
+			r.With(can("object_type:write")).Post("/", relationTypeHandler.CreateRelationType)
+			r.With(can("object_type:read")).Get("/", relationTypeHandler.ListRelationTypes)
+			r.With(can("object_type:write")).Put("/{id}", relationTypeHandler.UpdateRelationType)
+			r.With(can("object_type:delete")).Delete("/{id}", relationTypeHandler.DeleteRelationType)
 		})
 
 		r.Route("/setting/funnels", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Post("/", wrapWithFeed(funnelHandler.CreateFunnel))
-			r.Get("/", funnelHandler.ListFunnels)
-			r.Get("/{id}", funnelHandler.GetFunnel)
-			r.Put("/{id}", funnelHandler.UpdateFunnel)
-			r.Delete("/{id}", funnelHandler.DeleteFunnel)
-			r.Get("/{id}/view", funnelHandler.GetFunnelView)
+			r.Use(permission)
+			r.With(can("funnel:write")).Post("/", wrapWithFeed(funnelHandler.CreateFunnel))
+			r.With(can("funnel:read")).Get("/", funnelHandler.ListFunnels)
+			r.With(can("funnel:read")).Get("/{id}", funnelHandler.GetFunnel)
+			r.With(can("funnel:write")).Put("/{id}", funnelHandler.UpdateFunnel)
+			r.With(can("funnel:delete")).Delete("/{id}", funnelHandler.DeleteFunnel)
+			r.With(can("funnel:read")).Get("/{id}/view", funnelHandler.GetFunnelView)
 		})
 			
 		r.Route("/objects", func(r chi.Router) {
-			r.Use(middleware.Permission)
+			r.Use(permission)
 			// Object routes
-			r.Post("/", wrapWithFeed(objectHandler.Create))
-			r.Get("/", objectHandler.List)
-			r.Get("/{id}", objectHandler.GetDetails)
-			r.Put("/{id}", wrapWithFeed(objectHandler.Update))
-			r.Delete("/{id}", objectHandler.Delete)
+			r.With(can("object:write")).Post("/", wrapWithFeed(objectHandler.Create))
+			r.With(can("object:read")).Get("/", objectHandler.List)
+			r.With(can("object:read")).Get("/{id}", objectHandler.GetDetails)
+			r.With(can("object:read")).Get("/{id}/history", objectHandler.History)
+			r.With(can("object:write")).Put("/{id}", wrapWithFeed(objectHandler.Update))
+			r.With(can("object:delete")).Delete("/{id}", objectHandler.Delete)
+			r.With(can("object:write"), can("attachment:read")).Put("/{id}/photo", objectHandler.SetPhoto)
 			// Tag routes
-			r.Post("/{id}/tags", objectHandler.AddTag)
-			r.Delete("/{id}/tags/{tagId}", objectHandler.RemoveTag)
+			r.With(can("object:write")).Post("/{id}/tags", objectHandler.AddTag)
+			r.With(can("object:write")).Delete("/{id}/tags/{tagId}", objectHandler.RemoveTag)
 
 			// Object type value routes
-			r.Post("/{id}/type-values", wrapWithFeed(objectHandler.AddObjectTypeValue))
-			r.Put("/{id}/type-values/{typeValueId}", objectHandler.UpdateObjectTypeValue)
-			r.Delete("/{id}/type-values/{typeValueId}", objectHandler.RemoveObjectTypeValue)
+			r.With(can("object:write")).Post("/{id}/type-values", wrapWithFeed(objectHandler.AddObjectTypeValue))
+			r.With(can("object:write")).Put("/{id}/type-values/{typeValueId}", objectHandler.UpdateObjectTypeValue)
+			r.With(can("object:write")).Delete("/{id}/type-values/{typeValueId}", objectHandler.RemoveObjectTypeValue)
+			r.With(can("object:write")).Post("/{id}/type-values/{typeValueId}/restore", objectHandler.RestoreObjectTypeValue)
+
+			// Relationship routes
+			r.With(can("object:write")).Post("/{id}/relations", objectHandler.AddRelation)
+			r.With(can("object:write")).Put("/{id}/relations/{relationId}", objectHandler.UpdateRelation)
+			r.With(can("object:write")).Delete("/{id}/relations/{relationId}", objectHandler.RemoveRelation)
+
+			// Comment routes
+			r.With(can("object:read")).Get("/{id}/comments", commentHandler.ListObjectComments)
+			r.With(can("object:read"), can("comment:write")).Post("/{id}/comments", commentHandler.CreateObjectComment)
 
 			// Object step routes
-			r.Post("/steps", wrapWithFeed(objStepHandler.Create))
-			r.Delete("/steps/{id}", objStepHandler.SoftDelete)
-			r.Delete("/steps/{id}/force", objStepHandler.HardDelete)
-			r.Put("/steps/{id}/sub-status", objStepHandler.UpdateSubStatus)
+			r.With(can("object:write")).Post("/steps", wrapWithFeed(objStepHandler.Create))
+			r.With(can("object:write")).Delete("/steps/{id}", objStepHandler.SoftDelete)
+			r.With(can("object:delete")).Delete("/steps/{id}/force", objStepHandler.HardDelete)
+			r.With(can("object:write")).Put("/steps/{id}/sub-status", objStepHandler.UpdateSubStatus)
 
 			// Object Advanced routes
-			r.Get("/advanced", advancedObjectHandler.ListObjects)
+			r.With(can("object:read")).Get("/advanced", advancedObjectHandler.ListObjects)
 
 			// Merge objects
-			r.Post("/merge", wrapWithFeed(mergeHandler.MergeObjects))
+			r.With(can("object:merge")).Post("/merge", wrapWithFeed(mergeHandler.MergeObjects))
+			r.With(can("object:read")).Post("/merge/preview", mergeHandler.PreviewMerge)
+			r.With(can("object:read")).Get("/merge/history", mergeHandler.ListHistory)
+			r.With(can("object:merge")).Post("/merge/{historyId}/undo", mergeHandler.UndoMerge)
+
+			// Possible duplicates found by the duplicate detector
+			r.With(can("object:read")).Get("/duplicates", duplicateHandler.ListSuggestions)
+			r.With(can("object:merge")).Post("/duplicates/scan", duplicateHandler.Scan)
+			r.With(can("object:merge")).Post("/duplicates/{id}/accept", wrapWithFeed(duplicateHandler.AcceptSuggestion))
+			r.With(can("object:merge")).Post("/duplicates/{id}/dismiss", duplicateHandler.DismissSuggestion)
+		})
+
+		// The network of objects, as nodes and edges
+		r.Route("/graph", func(r chi.Router) {
+			r.Use(permission)
+			r.With(can("object:read")).Get("/limits", graphHandler.Limits)
+			r.With(can("object:read")).Get("/path", graphHandler.ShortestPath)
+			r.With(can("object:read")).Get("/objects/{id}/neighborhood", graphHandler.Neighborhood)
+			r.With(can("object:read"), can("fact:read")).Get("/objects/{id}/shared-facts", graphHandler.SharedFacts)
+		})
+
+		// Soft-deleted records, until the task runner purges them
+		r.Route("/trash", func(r chi.Router) {
+			r.Use(permission)
+			r.With(can("trash:read")).Get("/", trashHandler.ListTrash)
+			r.With(can("trash:restore")).Post("/{kind}/{id}/restore", trashHandler.Restore)
+		})
+
+		// Files linked to objects, facts and tasks
+		r.Route("/attachments", func(r chi.Router) {
+			r.Use(permission)
+			r.With(can("attachment:read")).Get("/", attachmentHandler.List)
+			r.With(can("attachment:write")).Post("/", attachmentHandler.Upload)
+			r.With(can("attachment:read")).Get("/limits", attachmentHandler.Limits)
+			r.With(can("attachment:read")).Get("/{id}", attachmentHandler.Get)
+			r.With(can("attachment:read")).Get("/{id}/url", attachmentHandler.DownloadURL)
+			r.With(can("attachment:delete")).Delete("/{id}", attachmentHandler.Delete)
+			r.With(can("attachment:write")).Post("/{id}/links", attachmentHandler.AddLink)
+			r.With(can("attachment:write")).Delete("/{id}/links/{kind}/{targetId}", attachmentHandler.RemoveLink)
 		})
 		
 		r.Route("/facts", func(r chi.Router) {
-			r.Post("/", factHandler.Create)
-			r.Get("/", factHandler.List)
+			r.With(can("fact:write")).Post("/", factHandler.Create)
+			r.With(can("fact:read")).Get("/", factHandler.List)
+			// Kinds of fact, the built-in ones cannot be changed
+			r.With(can("fact:read")).Get("/kinds", factHandler.ListKinds)
+			r.With(can("fact_kind:write")).Post("/kinds", factHandler.CreateKind)
+			r.With(can("fact_kind:write")).Put("/kinds/{name}", factHandler.UpdateKind)
+			r.With(can("fact_kind:delete")).Delete("/kinds/{name}", factHandler.DeleteKind)
 			r.Route("/{id}", func(r chi.Router) {
-				r.Put("/", factHandler.Update)
-				r.Delete("/", factHandler.Delete)
+				r.With(can("fact:write")).Put("/", factHandler.Update)
+				r.With(can("fact:delete")).Delete("/", factHandler.Delete)
+				r.With(can("fact:read")).Get("/revisions", factHandler.ListRevisions)
+				r.With(can("fact:write")).Post("/revisions/{revision}/revert", factHandler.Revert)
 			})
 		})
 
 		r.Route("/tasks", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			// Only admin can access this route; later implement permission check
-			// r.Get("/", taskHandler.ListAllTasksInOrg)
-			r.Get("/", taskHandler.ListWithFilter)
-			r.Post("/", wrapWithFeed(taskHandler.Create))
-			r.Get("/object/{objectID}", taskHandler.ListByObjectID)
+			r.Use(permission)
+			r.With(can("task:read_all")).Get("/all", taskHandler.ListAllTasksInOrg)
+			r.With(can("task:read")).Get("/", taskHandler.ListWithFilter)
+			r.With(can("task:write")).Post("/", wrapWithFeed(taskHandler.Create))
+			r.With(can("task:read")).Get("/object/{objectID}", taskHandler.ListByObjectID)
 			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", taskHandler.GetByID)
-				r.Put("/", wrapWithFeed(taskHandler.Update))
-				r.Delete("/", taskHandler.Delete)
+				r.With(can("task:read")).Get("/", taskHandler.GetByID)
+				r.With(can("task:write")).Put("/", wrapWithFeed(taskHandler.Update))
+				r.With(can("task:delete")).Delete("/", taskHandler.Delete)
+				r.With(can("task:read")).Get("/comments", commentHandler.ListTaskComments)
+				r.With(can("task:read"), can("comment:write")).Post("/comments", commentHandler.CreateTaskComment)
 			})
 		})
 
+		// Discussions about objects and tasks, created under /objects and /tasks
+		r.Route("/comments", func(r chi.Router) {
+			r.Use(permission)
+			r.Use(can("comment:write"))
+			r.Put("/{id}", commentHandler.Update)
+			r.Delete("/{id}", commentHandler.Delete)
+			r.Post("/{id}/reactions", commentHandler.AddReaction)
+			r.Delete("/{id}/reactions/{emoji}", commentHandler.RemoveReaction)
+		})
+
 		r.Route("/lists", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Post("/", listHandler.CreateList)
-			r.Get("/", listHandler.ListListsByOrgID)
-			r.Put("/{id}", listHandler.UpdateList)
-			r.Delete("/{id}", listHandler.DeleteList)
+			r.Use(permission)
+			r.With(can("list:write")).Post("/", listHandler.CreateList)
+			r.With(can("list:read")).Get("/", listHandler.ListListsByOrgID)
+			r.With(can("list:write")).Put("/{id}", listHandler.UpdateList)
+			r.With(can("list:delete")).Delete("/{id}", listHandler.DeleteList)
 			// create "creator_list" for a list
-			r.Post("/{id}/creator",listHandler.CreateCreatorList)
+			r.With(can("list:write")).Post("/{id}/creator",listHandler.CreateCreatorList)
 			// id of creator_list
-			r.Put("/creator/{id}", listHandler.UpdateCreatorList)
-			r.Delete("/creator/{id}", listHandler.DeleteCreatorList)
-			r.Get("/creator/", listHandler.ListCreatorListsByCreatorID)
-			r.Get("/creator/detail/{id}", listHandler.GetCreateListByID)
+			r.With(can("list:write")).Put("/creator/{id}", listHandler.UpdateCreatorList)
+			r.With(can("list:write")).Delete("/creator/{id}", listHandler.DeleteCreatorList)
+			r.With(can("list:read")).Get("/creator/", listHandler.ListCreatorListsByCreatorID)
+			r.With(can("list:read")).Get("/creator/detail/{id}", listHandler.GetCreateListByID)
 		})
 
 		r.Route("/import", func(r chi.Router) {
-			r.Use(middleware.Permission)
+			r.Use(permission)
+			r.Use(can("object:import"))
 			r.Post("/", importHandler.CreateImportTask)
 			r.Get("/status", importHandler.GetImportTaskStatus)
 			r.Get("/history", importHandler.GetImportHistory)
 	})
 
 		r.Route("/feeds", func(r chi.Router) {
-			r.Use(middleware.Permission)
+			r.Use(permission)
+			r.Use(can("fact:read"))
 			// r.Get("/", feedHandler.ListFeeds)
 			// change to fact since feed logic is not clear
 			r.Get("/", factHandler.List)
+			r.Get("/notifications", feedHandler.ListFeeds)
 			r.Post("/seen", feedHandler.MarkFeedsAsSeen)
 		})
 
 		r.Route("/summarize", func(r chi.Router) {
-			r.Use(middleware.Permission)
+			r.Use(permission)
+			r.Use(can("metrics:read"))
 			r.Get("/personal", summarizeHandler.PersonalSummarize);
 		})
 	
 		r.Route("/external", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Post("/facts", externalHandler.CreateFact)
-			r.Post("/type-values", externalHandler.UpsertObjectTypeValue)
-			r.Post("/tag-object", externalHandler.TagObject)
-			r.Post("/objects", externalHandler.ListObjectsWithNormalizedData)
+			r.Use(permission)
+			r.With(can("fact:write")).Post("/facts", externalHandler.CreateFact)
+			r.With(can("object:write")).Post("/type-values", externalHandler.UpsertObjectTypeValue)
+			r.With(can("object:write")).Post("/tag-object", externalHandler.TagObject)
+			r.With(can("object:read")).Post("/objects", externalHandler.ListObjectsWithNormalizedData)
 		});
 
+		r.Route("/audit", func(r chi.Router) {
+			r.Use(permission)
+			r.With(can("audit:read")).Get("/", auditHandler.ListEvents)
+		})
+
 		r.Route("/automations", func(r chi.Router) {
-			r.Use(middleware.Permission)
-			r.Get("/", automationHandler.ListActions)
-			r.Post("/", automationHandler.CreateAction)
+			r.Use(permission)
+			r.With(can("automation:read")).Get("/", automationHandler.ListActions)
+			r.With(can("automation:write")).Post("/", automationHandler.CreateAction)
 			r.Route("/{actionId}", func(r chi.Router) {
-				r.Get("/executions", automationHandler.GetExecutionLogs)
-				r.Put("/", automationHandler.UpdateAction)
-				r.Delete("/", automationHandler.DeleteAction)
+				r.With(can("automation:read")).Get("/executions", automationHandler.GetExecutionLogs)
+				r.With(can("automation:write")).Put("/", automationHandler.UpdateAction)
+				r.With(can("automation:delete")).Delete("/", automationHandler.DeleteAction)
 			})
 		})
 	})
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	authservice "github.com/crea8r/muninn/server/internal/features/auth/service"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/crea8r/muninn/server/pkg/blobstore"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/google/uuid"
+)
+
+// newTestRouter returns the router on a migrated test database and the auth
+// service to sign up orgs with
+func newTestRouter(t *testing.T) (*sql.DB, *database.Queries, http.Handler, *authservice.Service) {
+	t.Helper()
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	queries := database.New(db)
+	mail := mailer.NewWriterMailer(io.Discard)
+	blobs, err := blobstore.NewLocalStore(t.TempDir(), "http://files.test/blobs/", []byte("test secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := SetupRouter(queries, db, mail, nil, blobs, blobstore.Limits{MaxSize: blobstore.DefaultMaxSize, Types: blobstore.DefaultTypes})
+	return db, queries, router, authservice.NewService(queries, db, mail, nil)
+}
+
+// tenant is an org with its admin logged in
+type tenant struct {
+	orgID     uuid.UUID
+	creatorID uuid.UUID
+	token     string
+}
+
+func newTenant(t *testing.T, auth *authservice.Service, name string) tenant {
+	t.Helper()
+	ctx := context.Background()
+	creator, err := auth.SignUp(ctx, name, name+"-admin", "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, err := auth.Login(ctx, name+"-admin", "correct horse battery", authservice.ClientInfo{UserAgent: "test", IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.AccessToken == "" {
+		t.Fatalf("login of %s asked for %s", name, login.ChallengePurpose)
+	}
+	return tenant{orgID: creator.OrgID, creatorID: creator.ID, token: login.AccessToken}
+}
+
+// seed runs fn in a transaction scoped to the org of the tenant
+func (tn tenant) seed(t *testing.T, db *sql.DB, queries *database.Queries, fn func(ctx context.Context, q *database.Queries) error) {
+	t.Helper()
+	err := middleware.InOrg(context.Background(), db, queries, tn.orgID.String(), func(ctx context.Context) error {
+		return fn(ctx, middleware.Queries(ctx, queries))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// snapshot returns the text of the single value query selects for id, read as
+// the org of the tenant
+func (tn tenant) snapshot(t *testing.T, db *sql.DB, query string, id uuid.UUID) string {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := middleware.SetOrgID(ctx, tx, tn.orgID.String()); err != nil {
+		t.Fatal(err)
+	}
+	var value string
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&value); err != nil {
+		t.Fatalf("%s with %s: %v", query, id, err)
+	}
+	return value
+}
+
+func (tn tenant) do(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+tn.token)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// seeded are the rows a tenant owns
+type seeded struct {
+	obj, tag, objType, typeValue, relType, relation  uuid.UUID
+	fact, task, funnel, step, spareFunnel, spareStep uuid.UUID
+	objStep, list, creatorList                       uuid.UUID
+}
+
+func seedTenant(t *testing.T, db *sql.DB, queries *database.Queries, tn tenant) seeded {
+	t.Helper()
+	var s seeded
+	tn.seed(t, db, queries, func(ctx context.Context, q *database.Queries) error {
+		obj, err := q.CreateObject(ctx, database.CreateObjectParams{Name: "Ada", Description: "", IDString: "ada-" + tn.orgID.String(), CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		other, err := q.CreateObject(ctx, database.CreateObjectParams{Name: "Grace", Description: "", IDString: "grace-" + tn.orgID.String(), CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		tag, err := q.CreateTag(ctx, database.CreateTagParams{Name: "vip", Description: "", ColorSchema: json.RawMessage(`{}`), OrgID: tn.orgID})
+		if err != nil {
+			return err
+		}
+		if _, err := q.AddTagToObject(ctx, database.AddTagToObjectParams{ObjID: obj.ID, TagID: tag.ID, OrgID: tn.orgID}); err != nil {
+			return err
+		}
+		objType, err := q.CreateObjectType(ctx, database.CreateObjectTypeParams{Name: "person", Description: "", Fields: json.RawMessage(`{"email":"string"}`), CreatorID: tn.creatorID, Icon: ""})
+		if err != nil {
+			return err
+		}
+		typeValue, err := q.AddObjectTypeValue(ctx, database.AddObjectTypeValueParams{ObjID: obj.ID, TypeID: objType.ID, Column3: json.RawMessage(`{"email":"ada@example.com"}`), OrgID: tn.orgID})
+		if err != nil {
+			return err
+		}
+		relType, err := q.CreateObjRelationType(ctx, database.CreateObjRelationTypeParams{OrgID: tn.orgID, Name: "knows", InverseName: "known by", Directional: true, Description: "", CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		relation, err := q.CreateObjRelation(ctx, database.CreateObjRelationParams{OrgID: tn.orgID, Attributes: json.RawMessage(`{}`), CreatorID: tn.creatorID, TypeID: relType.ID, FromObjID: obj.ID, ToObjID: other.ID})
+		if err != nil {
+			return err
+		}
+		fact, err := q.CreateFact(ctx, database.CreateFactParams{Text: "Met Ada", HappenedAt: sql.NullTime{Time: time.Now(), Valid: true}, Location: "", CreatorID: tn.creatorID, Kind: "note"})
+		if err != nil {
+			return err
+		}
+		task, err := q.CreateTask(ctx, database.CreateTaskParams{Content: "Call Ada", Status: "todo", CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		funnel, err := q.CreateFunnel(ctx, database.CreateFunnelParams{ID: uuid.New(), Name: "sales", Description: "", CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		step, err := q.CreateStep(ctx, database.CreateStepParams{ID: uuid.New(), FunnelID: funnel.ID, Name: "lead", StepOrder: 1})
+		if err != nil {
+			return err
+		}
+		// Without objects, so deleting it is only refused for its org
+		spareStep, err := q.CreateStep(ctx, database.CreateStepParams{ID: uuid.New(), FunnelID: funnel.ID, Name: "won", StepOrder: 2})
+		if err != nil {
+			return err
+		}
+		spareFunnel, err := q.CreateFunnel(ctx, database.CreateFunnelParams{ID: uuid.New(), Name: "hiring", Description: "", CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		objStep, err := q.CreateObjStep(ctx, database.CreateObjStepParams{ObjID: obj.ID, StepID: step.ID, CreatorID: tn.creatorID, OrgID: tn.orgID})
+		if err != nil {
+			return err
+		}
+		list, err := q.CreateList(ctx, database.CreateListParams{Name: "leads", Description: "", FilterSetting: json.RawMessage(`{}`), CreatorID: tn.creatorID})
+		if err != nil {
+			return err
+		}
+		creatorList, err := q.CreateCreatorList(ctx, database.CreateCreatorListParams{CreatorID: tn.creatorID, ListID: list.ID, OrgID: tn.orgID})
+		if err != nil {
+			return err
+		}
+		s = seeded{
+			obj: obj.ID, tag: tag.ID, objType: objType.ID, typeValue: typeValue.ID, relType: relType.ID, relation: relation.ID,
+			fact: fact.ID, task: task.ID, funnel: funnel.ID, step: step.ID, spareFunnel: spareFunnel.ID, spareStep: spareStep.ID,
+			objStep: objStep.ID, list: list.ID, creatorList: creatorList.ID,
+		}
+		return nil
+	})
+	return s
+}
+
+func row(table string) string {
+	return "SELECT to_jsonb(t)::text FROM " + table + " t WHERE t.id = $1"
+}
+
+const (
+	tagsOf       = "SELECT coalesce(jsonb_agg(tag_id ORDER BY tag_id), '[]')::text FROM obj_tag WHERE obj_id = $1"
+	typeValuesOf = "SELECT coalesce(jsonb_agg(id ORDER BY id), '[]')::text FROM obj_type_value WHERE obj_id = $1"
+	relationsOf  = "SELECT coalesce(jsonb_agg(id ORDER BY id), '[]')::text FROM obj_relation WHERE from_obj_id = $1 OR to_obj_id = $1"
+	stepsOf      = "SELECT coalesce(jsonb_agg(id ORDER BY id), '[]')::text FROM obj_step WHERE obj_id = $1"
+	creatorsOf   = "SELECT coalesce(jsonb_agg(id ORDER BY id), '[]')::text FROM creator_list WHERE list_id = $1"
+)
+
+// TestCrossTenantMutations has the admin of org A change rows of org B, or
+// attach rows of org B to its own, through every org checked route. Each
+// must answer 404 and leave the rows as they were.
+func TestCrossTenantMutations(t *testing.T) {
+	db, queries, router, auth := newTestRouter(t)
+	a := newTenant(t, auth, "org-a")
+	b := newTenant(t, auth, "org-b")
+	own := seedTenant(t, db, queries, a)
+	other := seedTenant(t, db, queries, b)
+
+	id := func(u uuid.UUID) string { return u.String() }
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		owner  tenant
+		check  string
+		id     uuid.UUID
+	}{
+		{"update object", "PUT", "/objects/" + id(other.obj), `{"name":"x","description":"x","idString":"x","aliases":[]}`, b, row("obj"), other.obj},
+		{"delete object", "DELETE", "/objects/" + id(other.obj), ``, b, row("obj"), other.obj},
+		{"clear object photo", "PUT", "/objects/" + id(other.obj) + "/photo", `{"attachmentId":null}`, b, row("obj"), other.obj},
+		{"tag object", "POST", "/objects/" + id(other.obj) + "/tags", `{"tagId":"` + id(own.tag) + `"}`, b, tagsOf, other.obj},
+		{"tag with tag of other org", "POST", "/objects/" + id(own.obj) + "/tags", `{"tagId":"` + id(other.tag) + `"}`, a, tagsOf, own.obj},
+		{"untag object", "DELETE", "/objects/" + id(other.obj) + "/tags/" + id(other.tag), ``, b, tagsOf, other.obj},
+		{"add type value", "POST", "/objects/" + id(other.obj) + "/type-values", `{"typeId":"` + id(own.objType) + `","values":{}}`, b, typeValuesOf, other.obj},
+		{"add type of other org", "POST", "/objects/" + id(own.obj) + "/type-values", `{"typeId":"` + id(other.objType) + `","values":{}}`, a, typeValuesOf, own.obj},
+		{"update type value", "PUT", "/objects/" + id(other.obj) + "/type-values/" + id(other.typeValue), `{"type_values":{"email":"x"}}`, b, row("obj_type_value"), other.typeValue},
+		{"remove type value", "DELETE", "/objects/" + id(other.obj) + "/type-values/" + id(other.typeValue), ``, b, row("obj_type_value"), other.typeValue},
+		{"restore type value", "POST", "/objects/" + id(other.obj) + "/type-values/" + id(other.typeValue) + "/restore", `{"at":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `"}`, b, row("obj_type_value"), other.typeValue},
+		{"add relation", "POST", "/objects/" + id(other.obj) + "/relations", `{"typeId":"` + id(own.relType) + `","objectId":"` + id(own.obj) + `"}`, b, relationsOf, other.obj},
+		{"update relation", "PUT", "/objects/" + id(other.obj) + "/relations/" + id(other.relation), `{"attributes":{"since":"2020"}}`, b, row("obj_relation"), other.relation},
+		{"remove relation", "DELETE", "/objects/" + id(other.obj) + "/relations/" + id(other.relation), ``, b, row("obj_relation"), other.relation},
+		{"add object to step", "POST", "/objects/steps", `{"objId":"` + id(other.obj) + `","stepId":"` + id(own.step) + `"}`, b, stepsOf, other.obj},
+		{"add to step of other org", "POST", "/objects/steps", `{"objId":"` + id(own.obj) + `","stepId":"` + id(other.step) + `"}`, a, stepsOf, own.obj},
+		{"soft delete object step", "DELETE", "/objects/steps/" + id(other.objStep), ``, b, row("obj_step"), other.objStep},
+		{"hard delete object step", "DELETE", "/objects/steps/" + id(other.objStep) + "/force", ``, b, row("obj_step"), other.objStep},
+		{"update sub status", "PUT", "/objects/steps/" + id(other.objStep) + "/sub-status", `{"subStatus":1}`, b, row("obj_step"), other.objStep},
+		{"update fact", "PUT", "/facts/" + id(other.fact), `{"text":"x","location":"x"}`, b, row("fact"), other.fact},
+		{"delete fact", "DELETE", "/facts/" + id(other.fact), ``, b, row("fact"), other.fact},
+		{"revert fact", "POST", "/facts/" + id(other.fact) + "/revisions/1/revert", ``, b, row("fact"), other.fact},
+		{"update task", "PUT", "/tasks/" + id(other.task), `{"content":"x","status":"done"}`, b, row("task"), other.task},
+		{"delete task", "DELETE", "/tasks/" + id(other.task), ``, b, row("task"), other.task},
+		{"update funnel", "PUT", "/setting/funnels/" + id(other.funnel), `{"id":"` + id(other.funnel) + `","name":"x"}`, b, row("funnel"), other.funnel},
+		{"delete funnel", "DELETE", "/setting/funnels/" + id(other.spareFunnel), ``, b, row("funnel"), other.spareFunnel},
+		{"update step of other org", "PUT", "/setting/funnels/" + id(own.funnel), `{"id":"` + id(own.funnel) + `","name":"sales","steps_update":[{"id":"` + id(other.spareStep) + `","name":"x","step_order":2}]}`, b, row("step"), other.spareStep},
+		{"delete step of other org", "PUT", "/setting/funnels/" + id(own.funnel), `{"id":"` + id(own.funnel) + `","name":"sales","steps_delete":["` + id(other.spareStep) + `"]}`, b, row("step"), other.spareStep},
+		{"update list", "PUT", "/lists/" + id(other.list), `{"name":"x","description":"x","filterSetting":{}}`, b, row("list"), other.list},
+		{"delete list", "DELETE", "/lists/" + id(other.list), ``, b, row("list"), other.list},
+		{"add list to creator", "POST", "/lists/" + id(other.list) + "/creator", ``, b, creatorsOf, other.list},
+		{"update creator list", "PUT", "/lists/creator/" + id(other.creatorList), `{"params":{}}`, b, row("creator_list"), other.creatorList},
+		{"delete creator list", "DELETE", "/lists/creator/" + id(other.creatorList), ``, b, row("creator_list"), other.creatorList},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.owner.snapshot(t, db, tt.check, tt.id)
+			rec := a.do(router, tt.method, tt.path, tt.body)
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404: %s", rec.Code, rec.Body)
+			}
+			if after := tt.owner.snapshot(t, db, tt.check, tt.id); after != before {
+				t.Errorf("row changed\nbefore: %s\nafter:  %s", before, after)
+			}
+		})
+	}
+}
@@ -24,6 +24,21 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.acceptOrgInviteStmt, err = db.PrepareContext(ctx, acceptOrgInvite); err != nil {
+		return nil, fmt.Errorf("error preparing query AcceptOrgInvite: %w", err)
+	}
+	if q.addCommentMentionsStmt, err = db.PrepareContext(ctx, addCommentMentions); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCommentMentions: %w", err)
+	}
+	if q.addCommentReactionStmt, err = db.PrepareContext(ctx, addCommentReaction); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCommentReaction: %w", err)
+	}
+	if q.addFactAttendeesStmt, err = db.PrepareContext(ctx, addFactAttendees); err != nil {
+		return nil, fmt.Errorf("error preparing query AddFactAttendees: %w", err)
+	}
+	if q.addMentionedObjectsToFactStmt, err = db.PrepareContext(ctx, addMentionedObjectsToFact); err != nil {
+		return nil, fmt.Errorf("error preparing query AddMentionedObjectsToFact: %w", err)
+	}
 	if q.addObjectTypeValueStmt, err = db.PrepareContext(ctx, addObjectTypeValue); err != nil {
 		return nil, fmt.Errorf("error preparing query AddObjectTypeValue: %w", err)
 	}
@@ -39,6 +54,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.addTagToObjectStmt, err = db.PrepareContext(ctx, addTagToObject); err != nil {
 		return nil, fmt.Errorf("error preparing query AddTagToObject: %w", err)
 	}
+	if q.clearLoginFailuresStmt, err = db.PrepareContext(ctx, clearLoginFailures); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearLoginFailures: %w", err)
+	}
+	if q.clearLoginFailuresByIPStmt, err = db.PrepareContext(ctx, clearLoginFailuresByIP); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearLoginFailuresByIP: %w", err)
+	}
+	if q.clearStaleDuplicateSuggestionsStmt, err = db.PrepareContext(ctx, clearStaleDuplicateSuggestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearStaleDuplicateSuggestions: %w", err)
+	}
 	if q.completeImportTaskStmt, err = db.PrepareContext(ctx, completeImportTask); err != nil {
 		return nil, fmt.Errorf("error preparing query CompleteImportTask: %w", err)
 	}
@@ -60,6 +84,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countListsByOrgIDStmt, err = db.PrepareContext(ctx, countListsByOrgID); err != nil {
 		return nil, fmt.Errorf("error preparing query CountListsByOrgID: %w", err)
 	}
+	if q.countLoginFailuresByIPStmt, err = db.PrepareContext(ctx, countLoginFailuresByIP); err != nil {
+		return nil, fmt.Errorf("error preparing query CountLoginFailuresByIP: %w", err)
+	}
+	if q.countLoginFailuresByUsernameStmt, err = db.PrepareContext(ctx, countLoginFailuresByUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query CountLoginFailuresByUsername: %w", err)
+	}
+	if q.countObjectMergeMovesStmt, err = db.PrepareContext(ctx, countObjectMergeMoves); err != nil {
+		return nil, fmt.Errorf("error preparing query CountObjectMergeMoves: %w", err)
+	}
 	if q.countObjectTypesStmt, err = db.PrepareContext(ctx, countObjectTypes); err != nil {
 		return nil, fmt.Errorf("error preparing query CountObjectTypes: %w", err)
 	}
@@ -81,6 +114,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countOngoingTaskStmt, err = db.PrepareContext(ctx, countOngoingTask); err != nil {
 		return nil, fmt.Errorf("error preparing query CountOngoingTask: %w", err)
 	}
+	if q.countPurgedMergeSourcesStmt, err = db.PrepareContext(ctx, countPurgedMergeSources); err != nil {
+		return nil, fmt.Errorf("error preparing query CountPurgedMergeSources: %w", err)
+	}
 	if q.countTagsStmt, err = db.PrepareContext(ctx, countTags); err != nil {
 		return nil, fmt.Errorf("error preparing query CountTags: %w", err)
 	}
@@ -96,33 +132,72 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countUnseenFeedStmt, err = db.PrepareContext(ctx, countUnseenFeed); err != nil {
 		return nil, fmt.Errorf("error preparing query CountUnseenFeed: %w", err)
 	}
+	if q.createAPIKeyStmt, err = db.PrepareContext(ctx, createAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAPIKey: %w", err)
+	}
 	if q.createActionExecutionStmt, err = db.PrepareContext(ctx, createActionExecution); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateActionExecution: %w", err)
 	}
+	if q.createAttachmentStmt, err = db.PrepareContext(ctx, createAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAttachment: %w", err)
+	}
 	if q.createAutomatedActionStmt, err = db.PrepareContext(ctx, createAutomatedAction); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAutomatedAction: %w", err)
 	}
+	if q.createCommentStmt, err = db.PrepareContext(ctx, createComment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateComment: %w", err)
+	}
 	if q.createCreatorStmt, err = db.PrepareContext(ctx, createCreator); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateCreator: %w", err)
 	}
+	if q.createCreatorIdentityStmt, err = db.PrepareContext(ctx, createCreatorIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCreatorIdentity: %w", err)
+	}
 	if q.createCreatorListStmt, err = db.PrepareContext(ctx, createCreatorList); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateCreatorList: %w", err)
 	}
+	if q.createCreatorSessionStmt, err = db.PrepareContext(ctx, createCreatorSession); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCreatorSession: %w", err)
+	}
+	if q.createCreatorTokenStmt, err = db.PrepareContext(ctx, createCreatorToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCreatorToken: %w", err)
+	}
 	if q.createFactStmt, err = db.PrepareContext(ctx, createFact); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateFact: %w", err)
 	}
+	if q.createFactKindStmt, err = db.PrepareContext(ctx, createFactKind); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateFactKind: %w", err)
+	}
 	if q.createFeedStmt, err = db.PrepareContext(ctx, createFeed); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateFeed: %w", err)
 	}
 	if q.createFunnelStmt, err = db.PrepareContext(ctx, createFunnel); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateFunnel: %w", err)
 	}
+	if q.createImpersonationStmt, err = db.PrepareContext(ctx, createImpersonation); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateImpersonation: %w", err)
+	}
 	if q.createImportTaskStmt, err = db.PrepareContext(ctx, createImportTask); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateImportTask: %w", err)
 	}
 	if q.createListStmt, err = db.PrepareContext(ctx, createList); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateList: %w", err)
 	}
+	if q.createLoginChallengeStmt, err = db.PrepareContext(ctx, createLoginChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateLoginChallenge: %w", err)
+	}
+	if q.createLoginLockoutStmt, err = db.PrepareContext(ctx, createLoginLockout); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateLoginLockout: %w", err)
+	}
+	if q.createOIDCStateStmt, err = db.PrepareContext(ctx, createOIDCState); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOIDCState: %w", err)
+	}
+	if q.createObjRelationStmt, err = db.PrepareContext(ctx, createObjRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateObjRelation: %w", err)
+	}
+	if q.createObjRelationTypeStmt, err = db.PrepareContext(ctx, createObjRelationType); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateObjRelationType: %w", err)
+	}
 	if q.createObjStepStmt, err = db.PrepareContext(ctx, createObjStep); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObjStep: %w", err)
 	}
@@ -132,9 +207,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createObjectTypeStmt, err = db.PrepareContext(ctx, createObjectType); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObjectType: %w", err)
 	}
+	if q.createOrgInviteStmt, err = db.PrepareContext(ctx, createOrgInvite); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOrgInvite: %w", err)
+	}
 	if q.createOrganizationStmt, err = db.PrepareContext(ctx, createOrganization); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateOrganization: %w", err)
 	}
+	if q.createRecoveryCodeStmt, err = db.PrepareContext(ctx, createRecoveryCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRecoveryCode: %w", err)
+	}
+	if q.createRefreshTokenStmt, err = db.PrepareContext(ctx, createRefreshToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRefreshToken: %w", err)
+	}
+	if q.createSSODomainStmt, err = db.PrepareContext(ctx, createSSODomain); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSSODomain: %w", err)
+	}
 	if q.createStepStmt, err = db.PrepareContext(ctx, createStep); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateStep: %w", err)
 	}
@@ -147,30 +234,63 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteActionOldExecutionsStmt, err = db.PrepareContext(ctx, deleteActionOldExecutions); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteActionOldExecutions: %w", err)
 	}
+	if q.deleteAttachmentStmt, err = db.PrepareContext(ctx, deleteAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAttachment: %w", err)
+	}
 	if q.deleteAutomatedActionStmt, err = db.PrepareContext(ctx, deleteAutomatedAction); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAutomatedAction: %w", err)
 	}
+	if q.deleteCommentStmt, err = db.PrepareContext(ctx, deleteComment); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteComment: %w", err)
+	}
 	if q.deleteCreatorStmt, err = db.PrepareContext(ctx, deleteCreator); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteCreator: %w", err)
 	}
 	if q.deleteCreatorListStmt, err = db.PrepareContext(ctx, deleteCreatorList); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteCreatorList: %w", err)
 	}
+	if q.deleteCreatorTOTPStmt, err = db.PrepareContext(ctx, deleteCreatorTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCreatorTOTP: %w", err)
+	}
+	if q.deleteExpiredOIDCStatesStmt, err = db.PrepareContext(ctx, deleteExpiredOIDCStates); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredOIDCStates: %w", err)
+	}
 	if q.deleteFactStmt, err = db.PrepareContext(ctx, deleteFact); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFact: %w", err)
 	}
+	if q.deleteFactAttendeesStmt, err = db.PrepareContext(ctx, deleteFactAttendees); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFactAttendees: %w", err)
+	}
+	if q.deleteFactKindStmt, err = db.PrepareContext(ctx, deleteFactKind); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFactKind: %w", err)
+	}
 	if q.deleteFunnelStmt, err = db.PrepareContext(ctx, deleteFunnel); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFunnel: %w", err)
 	}
 	if q.deleteListStmt, err = db.PrepareContext(ctx, deleteList); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteList: %w", err)
 	}
+	if q.deleteObjRelationStmt, err = db.PrepareContext(ctx, deleteObjRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteObjRelation: %w", err)
+	}
+	if q.deleteObjRelationTypeStmt, err = db.PrepareContext(ctx, deleteObjRelationType); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteObjRelationType: %w", err)
+	}
 	if q.deleteObjectStmt, err = db.PrepareContext(ctx, deleteObject); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObject: %w", err)
 	}
 	if q.deleteObjectTypeStmt, err = db.PrepareContext(ctx, deleteObjectType); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObjectType: %w", err)
 	}
+	if q.deleteRecoveryCodesStmt, err = db.PrepareContext(ctx, deleteRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRecoveryCodes: %w", err)
+	}
+	if q.deleteSSODomainStmt, err = db.PrepareContext(ctx, deleteSSODomain); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSSODomain: %w", err)
+	}
+	if q.deleteStaleCommentMentionsStmt, err = db.PrepareContext(ctx, deleteStaleCommentMentions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteStaleCommentMentions: %w", err)
+	}
 	if q.deleteStepStmt, err = db.PrepareContext(ctx, deleteStep); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteStep: %w", err)
 	}
@@ -180,18 +300,57 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteTaskStmt, err = db.PrepareContext(ctx, deleteTask); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteTask: %w", err)
 	}
+	if q.deleteUnusedCreatorTokensStmt, err = db.PrepareContext(ctx, deleteUnusedCreatorTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUnusedCreatorTokens: %w", err)
+	}
+	if q.enableCreatorTOTPStmt, err = db.PrepareContext(ctx, enableCreatorTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query EnableCreatorTOTP: %w", err)
+	}
+	if q.endImpersonationStmt, err = db.PrepareContext(ctx, endImpersonation); err != nil {
+		return nil, fmt.Errorf("error preparing query EndImpersonation: %w", err)
+	}
+	if q.endImpersonationByJtiStmt, err = db.PrepareContext(ctx, endImpersonationByJti); err != nil {
+		return nil, fmt.Errorf("error preparing query EndImpersonationByJti: %w", err)
+	}
 	if q.findObjectByAliasOrIDStringStmt, err = db.PrepareContext(ctx, findObjectByAliasOrIDString); err != nil {
 		return nil, fmt.Errorf("error preparing query FindObjectByAliasOrIDString: %w", err)
 	}
 	if q.findTagByNormalizedNameStmt, err = db.PrepareContext(ctx, findTagByNormalizedName); err != nil {
 		return nil, fmt.Errorf("error preparing query FindTagByNormalizedName: %w", err)
 	}
+	if q.getActiveAPIKeyByPrefixStmt, err = db.PrepareContext(ctx, getActiveAPIKeyByPrefix); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveAPIKeyByPrefix: %w", err)
+	}
+	if q.getActiveImpersonationByJtiStmt, err = db.PrepareContext(ctx, getActiveImpersonationByJti); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveImpersonationByJti: %w", err)
+	}
+	if q.getActiveLoginChallengeStmt, err = db.PrepareContext(ctx, getActiveLoginChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveLoginChallenge: %w", err)
+	}
+	if q.getActiveLoginLockoutStmt, err = db.PrepareContext(ctx, getActiveLoginLockout); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveLoginLockout: %w", err)
+	}
+	if q.getActiveOrgInviteByHashStmt, err = db.PrepareContext(ctx, getActiveOrgInviteByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveOrgInviteByHash: %w", err)
+	}
+	if q.getActiveSessionByJtiStmt, err = db.PrepareContext(ctx, getActiveSessionByJti); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveSessionByJti: %w", err)
+	}
+	if q.getAttachmentStmt, err = db.PrepareContext(ctx, getAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAttachment: %w", err)
+	}
 	if q.getAutomatedActionStmt, err = db.PrepareContext(ctx, getAutomatedAction); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAutomatedAction: %w", err)
 	}
+	if q.getCommentStmt, err = db.PrepareContext(ctx, getComment); err != nil {
+		return nil, fmt.Errorf("error preparing query GetComment: %w", err)
+	}
 	if q.getCreatorByIDStmt, err = db.PrepareContext(ctx, getCreatorByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetCreatorByID: %w", err)
 	}
+	if q.getCreatorByIdentityStmt, err = db.PrepareContext(ctx, getCreatorByIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCreatorByIdentity: %w", err)
+	}
 	if q.getCreatorByUsernameStmt, err = db.PrepareContext(ctx, getCreatorByUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query GetCreatorByUsername: %w", err)
 	}
@@ -201,9 +360,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getCreatorListByIDStmt, err = db.PrepareContext(ctx, getCreatorListByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetCreatorListByID: %w", err)
 	}
+	if q.getCreatorTOTPStmt, err = db.PrepareContext(ctx, getCreatorTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCreatorTOTP: %w", err)
+	}
+	if q.getDuplicateScanStmt, err = db.PrepareContext(ctx, getDuplicateScan); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDuplicateScan: %w", err)
+	}
+	if q.getDuplicateSuggestionStmt, err = db.PrepareContext(ctx, getDuplicateSuggestion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDuplicateSuggestion: %w", err)
+	}
 	if q.getFactByIDStmt, err = db.PrepareContext(ctx, getFactByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFactByID: %w", err)
 	}
+	if q.getFactKindStmt, err = db.PrepareContext(ctx, getFactKind); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFactKind: %w", err)
+	}
+	if q.getFactRevisionStmt, err = db.PrepareContext(ctx, getFactRevision); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFactRevision: %w", err)
+	}
 	if q.getFeedStmt, err = db.PrepareContext(ctx, getFeed); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFeed: %w", err)
 	}
@@ -231,6 +405,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getObjectDetailsStmt, err = db.PrepareContext(ctx, getObjectDetails); err != nil {
 		return nil, fmt.Errorf("error preparing query GetObjectDetails: %w", err)
 	}
+	if q.getObjectHistoryStateStmt, err = db.PrepareContext(ctx, getObjectHistoryState); err != nil {
+		return nil, fmt.Errorf("error preparing query GetObjectHistoryState: %w", err)
+	}
+	if q.getObjectMergeHistoryStmt, err = db.PrepareContext(ctx, getObjectMergeHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetObjectMergeHistory: %w", err)
+	}
 	if q.getObjectTypeByIDStmt, err = db.PrepareContext(ctx, getObjectTypeByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetObjectTypeByID: %w", err)
 	}
@@ -249,6 +429,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPendingActionsStmt, err = db.PrepareContext(ctx, getPendingActions); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPendingActions: %w", err)
 	}
+	if q.getRefreshTokenByHashStmt, err = db.PrepareContext(ctx, getRefreshTokenByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRefreshTokenByHash: %w", err)
+	}
+	if q.getRolePermissionStmt, err = db.PrepareContext(ctx, getRolePermission); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRolePermission: %w", err)
+	}
+	if q.getSSODomainStmt, err = db.PrepareContext(ctx, getSSODomain); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSSODomain: %w", err)
+	}
 	if q.getStepStmt, err = db.PrepareContext(ctx, getStep); err != nil {
 		return nil, fmt.Errorf("error preparing query GetStep: %w", err)
 	}
@@ -261,30 +450,126 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTaskByIDStmt, err = db.PrepareContext(ctx, getTaskByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTaskByID: %w", err)
 	}
+	if q.graphNeighborhoodStmt, err = db.PrepareContext(ctx, graphNeighborhood); err != nil {
+		return nil, fmt.Errorf("error preparing query GraphNeighborhood: %w", err)
+	}
+	if q.graphSharedFactsStmt, err = db.PrepareContext(ctx, graphSharedFacts); err != nil {
+		return nil, fmt.Errorf("error preparing query GraphSharedFacts: %w", err)
+	}
+	if q.graphShortestPathStmt, err = db.PrepareContext(ctx, graphShortestPath); err != nil {
+		return nil, fmt.Errorf("error preparing query GraphShortestPath: %w", err)
+	}
 	if q.hardDeleteObjStepStmt, err = db.PrepareContext(ctx, hardDeleteObjStep); err != nil {
 		return nil, fmt.Errorf("error preparing query HardDeleteObjStep: %w", err)
 	}
 	if q.healthCheckStmt, err = db.PrepareContext(ctx, healthCheck); err != nil {
 		return nil, fmt.Errorf("error preparing query HealthCheck: %w", err)
 	}
+	if q.isEmailVerifiedStmt, err = db.PrepareContext(ctx, isEmailVerified); err != nil {
+		return nil, fmt.Errorf("error preparing query IsEmailVerified: %w", err)
+	}
+	if q.linkFactAttachmentStmt, err = db.PrepareContext(ctx, linkFactAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query LinkFactAttachment: %w", err)
+	}
+	if q.linkObjectAttachmentStmt, err = db.PrepareContext(ctx, linkObjectAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query LinkObjectAttachment: %w", err)
+	}
+	if q.linkTaskAttachmentStmt, err = db.PrepareContext(ctx, linkTaskAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query LinkTaskAttachment: %w", err)
+	}
+	if q.listAPIKeysByOrgIDStmt, err = db.PrepareContext(ctx, listAPIKeysByOrgID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAPIKeysByOrgID: %w", err)
+	}
 	if q.listActionExecutionsStmt, err = db.PrepareContext(ctx, listActionExecutions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListActionExecutions: %w", err)
 	}
+	if q.listActiveSessionsByCreatorIDStmt, err = db.PrepareContext(ctx, listActiveSessionsByCreatorID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListActiveSessionsByCreatorID: %w", err)
+	}
+	if q.listAttachmentLinksStmt, err = db.PrepareContext(ctx, listAttachmentLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAttachmentLinks: %w", err)
+	}
+	if q.listAttachmentsStmt, err = db.PrepareContext(ctx, listAttachments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAttachments: %w", err)
+	}
+	if q.listAuditEventsStmt, err = db.PrepareContext(ctx, listAuditEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditEvents: %w", err)
+	}
 	if q.listAutomatedActionsStmt, err = db.PrepareContext(ctx, listAutomatedActions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAutomatedActions: %w", err)
 	}
+	if q.listCommentsStmt, err = db.PrepareContext(ctx, listComments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListComments: %w", err)
+	}
 	if q.listCreatorListsByCreatorIDStmt, err = db.PrepareContext(ctx, listCreatorListsByCreatorID); err != nil {
 		return nil, fmt.Errorf("error preparing query ListCreatorListsByCreatorID: %w", err)
 	}
+	if q.listCreatorsByVerifiedEmailStmt, err = db.PrepareContext(ctx, listCreatorsByVerifiedEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCreatorsByVerifiedEmail: %w", err)
+	}
+	if q.listDuplicateScansStmt, err = db.PrepareContext(ctx, listDuplicateScans); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDuplicateScans: %w", err)
+	}
+	if q.listDuplicateSuggestionsStmt, err = db.PrepareContext(ctx, listDuplicateSuggestions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDuplicateSuggestions: %w", err)
+	}
+	if q.listFactKindsStmt, err = db.PrepareContext(ctx, listFactKinds); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFactKinds: %w", err)
+	}
+	if q.listFactRevisionsStmt, err = db.PrepareContext(ctx, listFactRevisions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFactRevisions: %w", err)
+	}
 	if q.listFactsByOrgIDStmt, err = db.PrepareContext(ctx, listFactsByOrgID); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFactsByOrgID: %w", err)
 	}
 	if q.listFunnelsStmt, err = db.PrepareContext(ctx, listFunnels); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFunnels: %w", err)
 	}
+	if q.listGraphEdgesStmt, err = db.PrepareContext(ctx, listGraphEdges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGraphEdges: %w", err)
+	}
+	if q.listGraphNodesStmt, err = db.PrepareContext(ctx, listGraphNodes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGraphNodes: %w", err)
+	}
+	if q.listImpersonationRequestsStmt, err = db.PrepareContext(ctx, listImpersonationRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query ListImpersonationRequests: %w", err)
+	}
+	if q.listImpersonationsByOrgIDStmt, err = db.PrepareContext(ctx, listImpersonationsByOrgID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListImpersonationsByOrgID: %w", err)
+	}
 	if q.listListsByOrgIDStmt, err = db.PrepareContext(ctx, listListsByOrgID); err != nil {
 		return nil, fmt.Errorf("error preparing query ListListsByOrgID: %w", err)
 	}
+	if q.listLoginLockoutsByOrgIDStmt, err = db.PrepareContext(ctx, listLoginLockoutsByOrgID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLoginLockoutsByOrgID: %w", err)
+	}
+	if q.listMentionableCreatorsStmt, err = db.PrepareContext(ctx, listMentionableCreators); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMentionableCreators: %w", err)
+	}
+	if q.listMergeConflictEventsStmt, err = db.PrepareContext(ctx, listMergeConflictEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMergeConflictEvents: %w", err)
+	}
+	if q.listObjRelationTypesStmt, err = db.PrepareContext(ctx, listObjRelationTypes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjRelationTypes: %w", err)
+	}
+	if q.listObjectHistoryStmt, err = db.PrepareContext(ctx, listObjectHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectHistory: %w", err)
+	}
+	if q.listObjectHistoryEventsStmt, err = db.PrepareContext(ctx, listObjectHistoryEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectHistoryEvents: %w", err)
+	}
+	if q.listObjectMergeHistoryStmt, err = db.PrepareContext(ctx, listObjectMergeHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectMergeHistory: %w", err)
+	}
+	if q.listObjectStepsForMergeStmt, err = db.PrepareContext(ctx, listObjectStepsForMerge); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectStepsForMerge: %w", err)
+	}
+	if q.listObjectTypeValueHistoryStatesStmt, err = db.PrepareContext(ctx, listObjectTypeValueHistoryStates); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectTypeValueHistoryStates: %w", err)
+	}
+	if q.listObjectTypeValuesForMergeStmt, err = db.PrepareContext(ctx, listObjectTypeValuesForMerge); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectTypeValuesForMerge: %w", err)
+	}
 	if q.listObjectTypesStmt, err = db.PrepareContext(ctx, listObjectTypes); err != nil {
 		return nil, fmt.Errorf("error preparing query ListObjectTypes: %w", err)
 	}
@@ -300,12 +585,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listObjectsByTypeWithAdvancedFilterStmt, err = db.PrepareContext(ctx, listObjectsByTypeWithAdvancedFilter); err != nil {
 		return nil, fmt.Errorf("error preparing query ListObjectsByTypeWithAdvancedFilter: %w", err)
 	}
+	if q.listObjectsForMergeStmt, err = db.PrepareContext(ctx, listObjectsForMerge); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectsForMerge: %w", err)
+	}
 	if q.listObjectsWithNormalizedDataStmt, err = db.PrepareContext(ctx, listObjectsWithNormalizedData); err != nil {
 		return nil, fmt.Errorf("error preparing query ListObjectsWithNormalizedData: %w", err)
 	}
 	if q.listOrgMembersStmt, err = db.PrepareContext(ctx, listOrgMembers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListOrgMembers: %w", err)
 	}
+	if q.listOrgProfilesStmt, err = db.PrepareContext(ctx, listOrgProfiles); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOrgProfiles: %w", err)
+	}
+	if q.listPendingOrgInvitesStmt, err = db.PrepareContext(ctx, listPendingOrgInvites); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPendingOrgInvites: %w", err)
+	}
+	if q.listRolePermissionsByOrgIDStmt, err = db.PrepareContext(ctx, listRolePermissionsByOrgID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListRolePermissionsByOrgID: %w", err)
+	}
+	if q.listSSODomainsByOrgIDStmt, err = db.PrepareContext(ctx, listSSODomainsByOrgID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSSODomainsByOrgID: %w", err)
+	}
 	if q.listStepsByFunnelStmt, err = db.PrepareContext(ctx, listStepsByFunnel); err != nil {
 		return nil, fmt.Errorf("error preparing query ListStepsByFunnel: %w", err)
 	}
@@ -321,12 +621,51 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listTasksWithFilterStmt, err = db.PrepareContext(ctx, listTasksWithFilter); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTasksWithFilter: %w", err)
 	}
+	if q.listTrashStmt, err = db.PrepareContext(ctx, listTrash); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTrash: %w", err)
+	}
+	if q.listUnusedRecoveryCodesStmt, err = db.PrepareContext(ctx, listUnusedRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnusedRecoveryCodes: %w", err)
+	}
+	if q.logImpersonationRequestStmt, err = db.PrepareContext(ctx, logImpersonationRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query LogImpersonationRequest: %w", err)
+	}
 	if q.markFeedAsSeenStmt, err = db.PrepareContext(ctx, markFeedAsSeen); err != nil {
 		return nil, fmt.Errorf("error preparing query MarkFeedAsSeen: %w", err)
 	}
+	if q.markLoginChallengeUsedStmt, err = db.PrepareContext(ctx, markLoginChallengeUsed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkLoginChallengeUsed: %w", err)
+	}
+	if q.markObjectMergeUndoneStmt, err = db.PrepareContext(ctx, markObjectMergeUndone); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkObjectMergeUndone: %w", err)
+	}
+	if q.markRefreshTokenUsedStmt, err = db.PrepareContext(ctx, markRefreshTokenUsed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkRefreshTokenUsed: %w", err)
+	}
 	if q.mergeObjectsStmt, err = db.PrepareContext(ctx, mergeObjects); err != nil {
 		return nil, fmt.Errorf("error preparing query MergeObjects: %w", err)
 	}
+	if q.moveMergedLinksBackStmt, err = db.PrepareContext(ctx, moveMergedLinksBack); err != nil {
+		return nil, fmt.Errorf("error preparing query MoveMergedLinksBack: %w", err)
+	}
+	if q.purgeTrashStmt, err = db.PrepareContext(ctx, purgeTrash); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeTrash: %w", err)
+	}
+	if q.recordLoginFailureStmt, err = db.PrepareContext(ctx, recordLoginFailure); err != nil {
+		return nil, fmt.Errorf("error preparing query RecordLoginFailure: %w", err)
+	}
+	if q.refreshDuplicateSuggestionsStmt, err = db.PrepareContext(ctx, refreshDuplicateSuggestions); err != nil {
+		return nil, fmt.Errorf("error preparing query RefreshDuplicateSuggestions: %w", err)
+	}
+	if q.removeCommentReactionStmt, err = db.PrepareContext(ctx, removeCommentReaction); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveCommentReaction: %w", err)
+	}
+	if q.removeMentionedObjectsFromFactStmt, err = db.PrepareContext(ctx, removeMentionedObjectsFromFact); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveMentionedObjectsFromFact: %w", err)
+	}
+	if q.removeMergedTagsStmt, err = db.PrepareContext(ctx, removeMergedTags); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveMergedTags: %w", err)
+	}
 	if q.removeObjectTypeValueStmt, err = db.PrepareContext(ctx, removeObjectTypeValue); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveObjectTypeValue: %w", err)
 	}
@@ -339,12 +678,96 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.removeTagFromObjectStmt, err = db.PrepareContext(ctx, removeTagFromObject); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveTagFromObject: %w", err)
 	}
+	if q.replaceRolePermissionsStmt, err = db.PrepareContext(ctx, replaceRolePermissions); err != nil {
+		return nil, fmt.Errorf("error preparing query ReplaceRolePermissions: %w", err)
+	}
+	if q.resolveDuplicateSuggestionStmt, err = db.PrepareContext(ctx, resolveDuplicateSuggestion); err != nil {
+		return nil, fmt.Errorf("error preparing query ResolveDuplicateSuggestion: %w", err)
+	}
+	if q.restoreMergeTargetStmt, err = db.PrepareContext(ctx, restoreMergeTarget); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreMergeTarget: %w", err)
+	}
+	if q.restoreMergedFactTextStmt, err = db.PrepareContext(ctx, restoreMergedFactText); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreMergedFactText: %w", err)
+	}
+	if q.restoreMergedObjectStmt, err = db.PrepareContext(ctx, restoreMergedObject); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreMergedObject: %w", err)
+	}
+	if q.restoreMergedRelationStmt, err = db.PrepareContext(ctx, restoreMergedRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreMergedRelation: %w", err)
+	}
+	if q.restoreMergedTaskTextStmt, err = db.PrepareContext(ctx, restoreMergedTaskText); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreMergedTaskText: %w", err)
+	}
+	if q.restoreTrashedFactStmt, err = db.PrepareContext(ctx, restoreTrashedFact); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedFact: %w", err)
+	}
+	if q.restoreTrashedFunnelStmt, err = db.PrepareContext(ctx, restoreTrashedFunnel); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedFunnel: %w", err)
+	}
+	if q.restoreTrashedListStmt, err = db.PrepareContext(ctx, restoreTrashedList); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedList: %w", err)
+	}
+	if q.restoreTrashedObjectStmt, err = db.PrepareContext(ctx, restoreTrashedObject); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedObject: %w", err)
+	}
+	if q.restoreTrashedTagStmt, err = db.PrepareContext(ctx, restoreTrashedTag); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedTag: %w", err)
+	}
+	if q.restoreTrashedTaskStmt, err = db.PrepareContext(ctx, restoreTrashedTask); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTrashedTask: %w", err)
+	}
+	if q.revertFactStmt, err = db.PrepareContext(ctx, revertFact); err != nil {
+		return nil, fmt.Errorf("error preparing query RevertFact: %w", err)
+	}
+	if q.revokeAPIKeyStmt, err = db.PrepareContext(ctx, revokeAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAPIKey: %w", err)
+	}
+	if q.revokeCreatorSessionsStmt, err = db.PrepareContext(ctx, revokeCreatorSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeCreatorSessions: %w", err)
+	}
+	if q.revokeOrgInviteStmt, err = db.PrepareContext(ctx, revokeOrgInvite); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeOrgInvite: %w", err)
+	}
+	if q.revokeSessionByIDStmt, err = db.PrepareContext(ctx, revokeSessionByID); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeSessionByID: %w", err)
+	}
+	if q.revokeSessionByJtiStmt, err = db.PrepareContext(ctx, revokeSessionByJti); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeSessionByJti: %w", err)
+	}
+	if q.setDuplicateScanStmt, err = db.PrepareContext(ctx, setDuplicateScan); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDuplicateScan: %w", err)
+	}
+	if q.setObjectPhotoStmt, err = db.PrepareContext(ctx, setObjectPhoto); err != nil {
+		return nil, fmt.Errorf("error preparing query SetObjectPhoto: %w", err)
+	}
+	if q.snapshotObjectMergeStmt, err = db.PrepareContext(ctx, snapshotObjectMerge); err != nil {
+		return nil, fmt.Errorf("error preparing query SnapshotObjectMerge: %w", err)
+	}
 	if q.softDeleteObjStepStmt, err = db.PrepareContext(ctx, softDeleteObjStep); err != nil {
 		return nil, fmt.Errorf("error preparing query SoftDeleteObjStep: %w", err)
 	}
 	if q.syncObjectAliasesStmt, err = db.PrepareContext(ctx, syncObjectAliases); err != nil {
 		return nil, fmt.Errorf("error preparing query SyncObjectAliases: %w", err)
 	}
+	if q.touchAPIKeyStmt, err = db.PrepareContext(ctx, touchAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchAPIKey: %w", err)
+	}
+	if q.touchCreatorIdentityStmt, err = db.PrepareContext(ctx, touchCreatorIdentity); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchCreatorIdentity: %w", err)
+	}
+	if q.unlinkFactAttachmentStmt, err = db.PrepareContext(ctx, unlinkFactAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlinkFactAttachment: %w", err)
+	}
+	if q.unlinkObjectAttachmentStmt, err = db.PrepareContext(ctx, unlinkObjectAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlinkObjectAttachment: %w", err)
+	}
+	if q.unlinkTaskAttachmentStmt, err = db.PrepareContext(ctx, unlinkTaskAttachment); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlinkTaskAttachment: %w", err)
+	}
+	if q.unlockLoginLockoutsStmt, err = db.PrepareContext(ctx, unlockLoginLockouts); err != nil {
+		return nil, fmt.Errorf("error preparing query UnlockLoginLockouts: %w", err)
+	}
 	if q.updateActionExecutionStmt, err = db.PrepareContext(ctx, updateActionExecution); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateActionExecution: %w", err)
 	}
@@ -354,6 +777,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateAutomatedActionStmt, err = db.PrepareContext(ctx, updateAutomatedAction); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAutomatedAction: %w", err)
 	}
+	if q.updateCommentStmt, err = db.PrepareContext(ctx, updateComment); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateComment: %w", err)
+	}
 	if q.updateCreatorListStmt, err = db.PrepareContext(ctx, updateCreatorList); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateCreatorList: %w", err)
 	}
@@ -369,6 +795,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateFactStmt, err = db.PrepareContext(ctx, updateFact); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateFact: %w", err)
 	}
+	if q.updateFactKindStmt, err = db.PrepareContext(ctx, updateFactKind); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateFactKind: %w", err)
+	}
 	if q.updateFunnelStmt, err = db.PrepareContext(ctx, updateFunnel); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateFunnel: %w", err)
 	}
@@ -384,6 +813,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateListStmt, err = db.PrepareContext(ctx, updateList); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateList: %w", err)
 	}
+	if q.updateObjRelationStmt, err = db.PrepareContext(ctx, updateObjRelation); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateObjRelation: %w", err)
+	}
+	if q.updateObjRelationTypeStmt, err = db.PrepareContext(ctx, updateObjRelationType); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateObjRelationType: %w", err)
+	}
 	if q.updateObjStepStmt, err = db.PrepareContext(ctx, updateObjStep); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateObjStep: %w", err)
 	}
@@ -414,6 +849,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.upsertObjectTypeValueStmt, err = db.PrepareContext(ctx, upsertObjectTypeValue); err != nil {
 		return nil, fmt.Errorf("error preparing query UpsertObjectTypeValue: %w", err)
 	}
+	if q.upsertPendingCreatorTOTPStmt, err = db.PrepareContext(ctx, upsertPendingCreatorTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertPendingCreatorTOTP: %w", err)
+	}
+	if q.useCreatorTOTPStepStmt, err = db.PrepareContext(ctx, useCreatorTOTPStep); err != nil {
+		return nil, fmt.Errorf("error preparing query UseCreatorTOTPStep: %w", err)
+	}
+	if q.useCreatorTokenStmt, err = db.PrepareContext(ctx, useCreatorToken); err != nil {
+		return nil, fmt.Errorf("error preparing query UseCreatorToken: %w", err)
+	}
+	if q.useOIDCStateStmt, err = db.PrepareContext(ctx, useOIDCState); err != nil {
+		return nil, fmt.Errorf("error preparing query UseOIDCState: %w", err)
+	}
+	if q.useRecoveryCodeStmt, err = db.PrepareContext(ctx, useRecoveryCode); err != nil {
+		return nil, fmt.Errorf("error preparing query UseRecoveryCode: %w", err)
+	}
+	if q.usernameExistsStmt, err = db.PrepareContext(ctx, usernameExists); err != nil {
+		return nil, fmt.Errorf("error preparing query UsernameExists: %w", err)
+	}
 	if q.validateMergeObjectsStmt, err = db.PrepareContext(ctx, validateMergeObjects); err != nil {
 		return nil, fmt.Errorf("error preparing query ValidateMergeObjects: %w", err)
 	}
@@ -422,6 +875,31 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.acceptOrgInviteStmt != nil {
+		if cerr := q.acceptOrgInviteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing acceptOrgInviteStmt: %w", cerr)
+		}
+	}
+	if q.addCommentMentionsStmt != nil {
+		if cerr := q.addCommentMentionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCommentMentionsStmt: %w", cerr)
+		}
+	}
+	if q.addCommentReactionStmt != nil {
+		if cerr := q.addCommentReactionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCommentReactionStmt: %w", cerr)
+		}
+	}
+	if q.addFactAttendeesStmt != nil {
+		if cerr := q.addFactAttendeesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addFactAttendeesStmt: %w", cerr)
+		}
+	}
+	if q.addMentionedObjectsToFactStmt != nil {
+		if cerr := q.addMentionedObjectsToFactStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addMentionedObjectsToFactStmt: %w", cerr)
+		}
+	}
 	if q.addObjectTypeValueStmt != nil {
 		if cerr := q.addObjectTypeValueStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addObjectTypeValueStmt: %w", cerr)
@@ -447,6 +925,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing addTagToObjectStmt: %w", cerr)
 		}
 	}
+	if q.clearLoginFailuresStmt != nil {
+		if cerr := q.clearLoginFailuresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearLoginFailuresStmt: %w", cerr)
+		}
+	}
+	if q.clearLoginFailuresByIPStmt != nil {
+		if cerr := q.clearLoginFailuresByIPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearLoginFailuresByIPStmt: %w", cerr)
+		}
+	}
+	if q.clearStaleDuplicateSuggestionsStmt != nil {
+		if cerr := q.clearStaleDuplicateSuggestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearStaleDuplicateSuggestionsStmt: %w", cerr)
+		}
+	}
 	if q.completeImportTaskStmt != nil {
 		if cerr := q.completeImportTaskStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing completeImportTaskStmt: %w", cerr)
@@ -482,6 +975,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countListsByOrgIDStmt: %w", cerr)
 		}
 	}
+	if q.countLoginFailuresByIPStmt != nil {
+		if cerr := q.countLoginFailuresByIPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countLoginFailuresByIPStmt: %w", cerr)
+		}
+	}
+	if q.countLoginFailuresByUsernameStmt != nil {
+		if cerr := q.countLoginFailuresByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countLoginFailuresByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.countObjectMergeMovesStmt != nil {
+		if cerr := q.countObjectMergeMovesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countObjectMergeMovesStmt: %w", cerr)
+		}
+	}
 	if q.countObjectTypesStmt != nil {
 		if cerr := q.countObjectTypesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countObjectTypesStmt: %w", cerr)
@@ -517,6 +1025,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countOngoingTaskStmt: %w", cerr)
 		}
 	}
+	if q.countPurgedMergeSourcesStmt != nil {
+		if cerr := q.countPurgedMergeSourcesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countPurgedMergeSourcesStmt: %w", cerr)
+		}
+	}
 	if q.countTagsStmt != nil {
 		if cerr := q.countTagsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countTagsStmt: %w", cerr)
@@ -542,31 +1055,66 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countUnseenFeedStmt: %w", cerr)
 		}
 	}
+	if q.createAPIKeyStmt != nil {
+		if cerr := q.createAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAPIKeyStmt: %w", cerr)
+		}
+	}
 	if q.createActionExecutionStmt != nil {
 		if cerr := q.createActionExecutionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createActionExecutionStmt: %w", cerr)
 		}
 	}
+	if q.createAttachmentStmt != nil {
+		if cerr := q.createAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAttachmentStmt: %w", cerr)
+		}
+	}
 	if q.createAutomatedActionStmt != nil {
 		if cerr := q.createAutomatedActionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAutomatedActionStmt: %w", cerr)
 		}
 	}
+	if q.createCommentStmt != nil {
+		if cerr := q.createCommentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCommentStmt: %w", cerr)
+		}
+	}
 	if q.createCreatorStmt != nil {
 		if cerr := q.createCreatorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createCreatorStmt: %w", cerr)
 		}
 	}
+	if q.createCreatorIdentityStmt != nil {
+		if cerr := q.createCreatorIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCreatorIdentityStmt: %w", cerr)
+		}
+	}
 	if q.createCreatorListStmt != nil {
 		if cerr := q.createCreatorListStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createCreatorListStmt: %w", cerr)
 		}
 	}
+	if q.createCreatorSessionStmt != nil {
+		if cerr := q.createCreatorSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCreatorSessionStmt: %w", cerr)
+		}
+	}
+	if q.createCreatorTokenStmt != nil {
+		if cerr := q.createCreatorTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCreatorTokenStmt: %w", cerr)
+		}
+	}
 	if q.createFactStmt != nil {
 		if cerr := q.createFactStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createFactStmt: %w", cerr)
 		}
 	}
+	if q.createFactKindStmt != nil {
+		if cerr := q.createFactKindStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createFactKindStmt: %w", cerr)
+		}
+	}
 	if q.createFeedStmt != nil {
 		if cerr := q.createFeedStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createFeedStmt: %w", cerr)
@@ -577,6 +1125,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createFunnelStmt: %w", cerr)
 		}
 	}
+	if q.createImpersonationStmt != nil {
+		if cerr := q.createImpersonationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createImpersonationStmt: %w", cerr)
+		}
+	}
 	if q.createImportTaskStmt != nil {
 		if cerr := q.createImportTaskStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createImportTaskStmt: %w", cerr)
@@ -587,6 +1140,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createListStmt: %w", cerr)
 		}
 	}
+	if q.createLoginChallengeStmt != nil {
+		if cerr := q.createLoginChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createLoginChallengeStmt: %w", cerr)
+		}
+	}
+	if q.createLoginLockoutStmt != nil {
+		if cerr := q.createLoginLockoutStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createLoginLockoutStmt: %w", cerr)
+		}
+	}
+	if q.createOIDCStateStmt != nil {
+		if cerr := q.createOIDCStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOIDCStateStmt: %w", cerr)
+		}
+	}
+	if q.createObjRelationStmt != nil {
+		if cerr := q.createObjRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createObjRelationStmt: %w", cerr)
+		}
+	}
+	if q.createObjRelationTypeStmt != nil {
+		if cerr := q.createObjRelationTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createObjRelationTypeStmt: %w", cerr)
+		}
+	}
 	if q.createObjStepStmt != nil {
 		if cerr := q.createObjStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createObjStepStmt: %w", cerr)
@@ -602,11 +1180,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createObjectTypeStmt: %w", cerr)
 		}
 	}
+	if q.createOrgInviteStmt != nil {
+		if cerr := q.createOrgInviteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOrgInviteStmt: %w", cerr)
+		}
+	}
 	if q.createOrganizationStmt != nil {
 		if cerr := q.createOrganizationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createOrganizationStmt: %w", cerr)
 		}
 	}
+	if q.createRecoveryCodeStmt != nil {
+		if cerr := q.createRecoveryCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRecoveryCodeStmt: %w", cerr)
+		}
+	}
+	if q.createRefreshTokenStmt != nil {
+		if cerr := q.createRefreshTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRefreshTokenStmt: %w", cerr)
+		}
+	}
+	if q.createSSODomainStmt != nil {
+		if cerr := q.createSSODomainStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSSODomainStmt: %w", cerr)
+		}
+	}
 	if q.createStepStmt != nil {
 		if cerr := q.createStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createStepStmt: %w", cerr)
@@ -627,11 +1225,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteActionOldExecutionsStmt: %w", cerr)
 		}
 	}
+	if q.deleteAttachmentStmt != nil {
+		if cerr := q.deleteAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAttachmentStmt: %w", cerr)
+		}
+	}
 	if q.deleteAutomatedActionStmt != nil {
 		if cerr := q.deleteAutomatedActionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteAutomatedActionStmt: %w", cerr)
 		}
 	}
+	if q.deleteCommentStmt != nil {
+		if cerr := q.deleteCommentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCommentStmt: %w", cerr)
+		}
+	}
 	if q.deleteCreatorStmt != nil {
 		if cerr := q.deleteCreatorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteCreatorStmt: %w", cerr)
@@ -642,11 +1250,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteCreatorListStmt: %w", cerr)
 		}
 	}
+	if q.deleteCreatorTOTPStmt != nil {
+		if cerr := q.deleteCreatorTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCreatorTOTPStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredOIDCStatesStmt != nil {
+		if cerr := q.deleteExpiredOIDCStatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredOIDCStatesStmt: %w", cerr)
+		}
+	}
 	if q.deleteFactStmt != nil {
 		if cerr := q.deleteFactStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFactStmt: %w", cerr)
 		}
 	}
+	if q.deleteFactAttendeesStmt != nil {
+		if cerr := q.deleteFactAttendeesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFactAttendeesStmt: %w", cerr)
+		}
+	}
+	if q.deleteFactKindStmt != nil {
+		if cerr := q.deleteFactKindStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFactKindStmt: %w", cerr)
+		}
+	}
 	if q.deleteFunnelStmt != nil {
 		if cerr := q.deleteFunnelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFunnelStmt: %w", cerr)
@@ -657,6 +1285,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteListStmt: %w", cerr)
 		}
 	}
+	if q.deleteObjRelationStmt != nil {
+		if cerr := q.deleteObjRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteObjRelationStmt: %w", cerr)
+		}
+	}
+	if q.deleteObjRelationTypeStmt != nil {
+		if cerr := q.deleteObjRelationTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteObjRelationTypeStmt: %w", cerr)
+		}
+	}
 	if q.deleteObjectStmt != nil {
 		if cerr := q.deleteObjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteObjectStmt: %w", cerr)
@@ -667,6 +1305,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteObjectTypeStmt: %w", cerr)
 		}
 	}
+	if q.deleteRecoveryCodesStmt != nil {
+		if cerr := q.deleteRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRecoveryCodesStmt: %w", cerr)
+		}
+	}
+	if q.deleteSSODomainStmt != nil {
+		if cerr := q.deleteSSODomainStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSSODomainStmt: %w", cerr)
+		}
+	}
+	if q.deleteStaleCommentMentionsStmt != nil {
+		if cerr := q.deleteStaleCommentMentionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteStaleCommentMentionsStmt: %w", cerr)
+		}
+	}
 	if q.deleteStepStmt != nil {
 		if cerr := q.deleteStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteStepStmt: %w", cerr)
@@ -682,6 +1335,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteTaskStmt: %w", cerr)
 		}
 	}
+	if q.deleteUnusedCreatorTokensStmt != nil {
+		if cerr := q.deleteUnusedCreatorTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUnusedCreatorTokensStmt: %w", cerr)
+		}
+	}
+	if q.enableCreatorTOTPStmt != nil {
+		if cerr := q.enableCreatorTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enableCreatorTOTPStmt: %w", cerr)
+		}
+	}
+	if q.endImpersonationStmt != nil {
+		if cerr := q.endImpersonationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing endImpersonationStmt: %w", cerr)
+		}
+	}
+	if q.endImpersonationByJtiStmt != nil {
+		if cerr := q.endImpersonationByJtiStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing endImpersonationByJtiStmt: %w", cerr)
+		}
+	}
 	if q.findObjectByAliasOrIDStringStmt != nil {
 		if cerr := q.findObjectByAliasOrIDStringStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing findObjectByAliasOrIDStringStmt: %w", cerr)
@@ -692,16 +1365,61 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing findTagByNormalizedNameStmt: %w", cerr)
 		}
 	}
+	if q.getActiveAPIKeyByPrefixStmt != nil {
+		if cerr := q.getActiveAPIKeyByPrefixStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveAPIKeyByPrefixStmt: %w", cerr)
+		}
+	}
+	if q.getActiveImpersonationByJtiStmt != nil {
+		if cerr := q.getActiveImpersonationByJtiStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveImpersonationByJtiStmt: %w", cerr)
+		}
+	}
+	if q.getActiveLoginChallengeStmt != nil {
+		if cerr := q.getActiveLoginChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveLoginChallengeStmt: %w", cerr)
+		}
+	}
+	if q.getActiveLoginLockoutStmt != nil {
+		if cerr := q.getActiveLoginLockoutStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveLoginLockoutStmt: %w", cerr)
+		}
+	}
+	if q.getActiveOrgInviteByHashStmt != nil {
+		if cerr := q.getActiveOrgInviteByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveOrgInviteByHashStmt: %w", cerr)
+		}
+	}
+	if q.getActiveSessionByJtiStmt != nil {
+		if cerr := q.getActiveSessionByJtiStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveSessionByJtiStmt: %w", cerr)
+		}
+	}
+	if q.getAttachmentStmt != nil {
+		if cerr := q.getAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAttachmentStmt: %w", cerr)
+		}
+	}
 	if q.getAutomatedActionStmt != nil {
 		if cerr := q.getAutomatedActionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAutomatedActionStmt: %w", cerr)
 		}
 	}
+	if q.getCommentStmt != nil {
+		if cerr := q.getCommentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCommentStmt: %w", cerr)
+		}
+	}
 	if q.getCreatorByIDStmt != nil {
 		if cerr := q.getCreatorByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getCreatorByIDStmt: %w", cerr)
 		}
 	}
+	if q.getCreatorByIdentityStmt != nil {
+		if cerr := q.getCreatorByIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCreatorByIdentityStmt: %w", cerr)
+		}
+	}
 	if q.getCreatorByUsernameStmt != nil {
 		if cerr := q.getCreatorByUsernameStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getCreatorByUsernameStmt: %w", cerr)
@@ -717,11 +1435,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getCreatorListByIDStmt: %w", cerr)
 		}
 	}
+	if q.getCreatorTOTPStmt != nil {
+		if cerr := q.getCreatorTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCreatorTOTPStmt: %w", cerr)
+		}
+	}
+	if q.getDuplicateScanStmt != nil {
+		if cerr := q.getDuplicateScanStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDuplicateScanStmt: %w", cerr)
+		}
+	}
+	if q.getDuplicateSuggestionStmt != nil {
+		if cerr := q.getDuplicateSuggestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDuplicateSuggestionStmt: %w", cerr)
+		}
+	}
 	if q.getFactByIDStmt != nil {
 		if cerr := q.getFactByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFactByIDStmt: %w", cerr)
 		}
 	}
+	if q.getFactKindStmt != nil {
+		if cerr := q.getFactKindStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFactKindStmt: %w", cerr)
+		}
+	}
+	if q.getFactRevisionStmt != nil {
+		if cerr := q.getFactRevisionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFactRevisionStmt: %w", cerr)
+		}
+	}
 	if q.getFeedStmt != nil {
 		if cerr := q.getFeedStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFeedStmt: %w", cerr)
@@ -767,6 +1510,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getObjectDetailsStmt: %w", cerr)
 		}
 	}
+	if q.getObjectHistoryStateStmt != nil {
+		if cerr := q.getObjectHistoryStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getObjectHistoryStateStmt: %w", cerr)
+		}
+	}
+	if q.getObjectMergeHistoryStmt != nil {
+		if cerr := q.getObjectMergeHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getObjectMergeHistoryStmt: %w", cerr)
+		}
+	}
 	if q.getObjectTypeByIDStmt != nil {
 		if cerr := q.getObjectTypeByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getObjectTypeByIDStmt: %w", cerr)
@@ -797,6 +1550,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPendingActionsStmt: %w", cerr)
 		}
 	}
+	if q.getRefreshTokenByHashStmt != nil {
+		if cerr := q.getRefreshTokenByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRefreshTokenByHashStmt: %w", cerr)
+		}
+	}
+	if q.getRolePermissionStmt != nil {
+		if cerr := q.getRolePermissionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRolePermissionStmt: %w", cerr)
+		}
+	}
+	if q.getSSODomainStmt != nil {
+		if cerr := q.getSSODomainStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSSODomainStmt: %w", cerr)
+		}
+	}
 	if q.getStepStmt != nil {
 		if cerr := q.getStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getStepStmt: %w", cerr)
@@ -817,6 +1585,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTaskByIDStmt: %w", cerr)
 		}
 	}
+	if q.graphNeighborhoodStmt != nil {
+		if cerr := q.graphNeighborhoodStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing graphNeighborhoodStmt: %w", cerr)
+		}
+	}
+	if q.graphSharedFactsStmt != nil {
+		if cerr := q.graphSharedFactsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing graphSharedFactsStmt: %w", cerr)
+		}
+	}
+	if q.graphShortestPathStmt != nil {
+		if cerr := q.graphShortestPathStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing graphShortestPathStmt: %w", cerr)
+		}
+	}
 	if q.hardDeleteObjStepStmt != nil {
 		if cerr := q.hardDeleteObjStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing hardDeleteObjStepStmt: %w", cerr)
@@ -827,21 +1610,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing healthCheckStmt: %w", cerr)
 		}
 	}
+	if q.isEmailVerifiedStmt != nil {
+		if cerr := q.isEmailVerifiedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing isEmailVerifiedStmt: %w", cerr)
+		}
+	}
+	if q.linkFactAttachmentStmt != nil {
+		if cerr := q.linkFactAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing linkFactAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.linkObjectAttachmentStmt != nil {
+		if cerr := q.linkObjectAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing linkObjectAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.linkTaskAttachmentStmt != nil {
+		if cerr := q.linkTaskAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing linkTaskAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.listAPIKeysByOrgIDStmt != nil {
+		if cerr := q.listAPIKeysByOrgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAPIKeysByOrgIDStmt: %w", cerr)
+		}
+	}
 	if q.listActionExecutionsStmt != nil {
 		if cerr := q.listActionExecutionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listActionExecutionsStmt: %w", cerr)
 		}
 	}
+	if q.listActiveSessionsByCreatorIDStmt != nil {
+		if cerr := q.listActiveSessionsByCreatorIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listActiveSessionsByCreatorIDStmt: %w", cerr)
+		}
+	}
+	if q.listAttachmentLinksStmt != nil {
+		if cerr := q.listAttachmentLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAttachmentLinksStmt: %w", cerr)
+		}
+	}
+	if q.listAttachmentsStmt != nil {
+		if cerr := q.listAttachmentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAttachmentsStmt: %w", cerr)
+		}
+	}
+	if q.listAuditEventsStmt != nil {
+		if cerr := q.listAuditEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditEventsStmt: %w", cerr)
+		}
+	}
 	if q.listAutomatedActionsStmt != nil {
 		if cerr := q.listAutomatedActionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAutomatedActionsStmt: %w", cerr)
 		}
 	}
+	if q.listCommentsStmt != nil {
+		if cerr := q.listCommentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCommentsStmt: %w", cerr)
+		}
+	}
 	if q.listCreatorListsByCreatorIDStmt != nil {
 		if cerr := q.listCreatorListsByCreatorIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listCreatorListsByCreatorIDStmt: %w", cerr)
 		}
 	}
+	if q.listCreatorsByVerifiedEmailStmt != nil {
+		if cerr := q.listCreatorsByVerifiedEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCreatorsByVerifiedEmailStmt: %w", cerr)
+		}
+	}
+	if q.listDuplicateScansStmt != nil {
+		if cerr := q.listDuplicateScansStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDuplicateScansStmt: %w", cerr)
+		}
+	}
+	if q.listDuplicateSuggestionsStmt != nil {
+		if cerr := q.listDuplicateSuggestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDuplicateSuggestionsStmt: %w", cerr)
+		}
+	}
+	if q.listFactKindsStmt != nil {
+		if cerr := q.listFactKindsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFactKindsStmt: %w", cerr)
+		}
+	}
+	if q.listFactRevisionsStmt != nil {
+		if cerr := q.listFactRevisionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFactRevisionsStmt: %w", cerr)
+		}
+	}
 	if q.listFactsByOrgIDStmt != nil {
 		if cerr := q.listFactsByOrgIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listFactsByOrgIDStmt: %w", cerr)
@@ -852,11 +1710,81 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listFunnelsStmt: %w", cerr)
 		}
 	}
+	if q.listGraphEdgesStmt != nil {
+		if cerr := q.listGraphEdgesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGraphEdgesStmt: %w", cerr)
+		}
+	}
+	if q.listGraphNodesStmt != nil {
+		if cerr := q.listGraphNodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGraphNodesStmt: %w", cerr)
+		}
+	}
+	if q.listImpersonationRequestsStmt != nil {
+		if cerr := q.listImpersonationRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listImpersonationRequestsStmt: %w", cerr)
+		}
+	}
+	if q.listImpersonationsByOrgIDStmt != nil {
+		if cerr := q.listImpersonationsByOrgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listImpersonationsByOrgIDStmt: %w", cerr)
+		}
+	}
 	if q.listListsByOrgIDStmt != nil {
 		if cerr := q.listListsByOrgIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listListsByOrgIDStmt: %w", cerr)
 		}
 	}
+	if q.listLoginLockoutsByOrgIDStmt != nil {
+		if cerr := q.listLoginLockoutsByOrgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLoginLockoutsByOrgIDStmt: %w", cerr)
+		}
+	}
+	if q.listMentionableCreatorsStmt != nil {
+		if cerr := q.listMentionableCreatorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMentionableCreatorsStmt: %w", cerr)
+		}
+	}
+	if q.listMergeConflictEventsStmt != nil {
+		if cerr := q.listMergeConflictEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMergeConflictEventsStmt: %w", cerr)
+		}
+	}
+	if q.listObjRelationTypesStmt != nil {
+		if cerr := q.listObjRelationTypesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjRelationTypesStmt: %w", cerr)
+		}
+	}
+	if q.listObjectHistoryStmt != nil {
+		if cerr := q.listObjectHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectHistoryStmt: %w", cerr)
+		}
+	}
+	if q.listObjectHistoryEventsStmt != nil {
+		if cerr := q.listObjectHistoryEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectHistoryEventsStmt: %w", cerr)
+		}
+	}
+	if q.listObjectMergeHistoryStmt != nil {
+		if cerr := q.listObjectMergeHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectMergeHistoryStmt: %w", cerr)
+		}
+	}
+	if q.listObjectStepsForMergeStmt != nil {
+		if cerr := q.listObjectStepsForMergeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectStepsForMergeStmt: %w", cerr)
+		}
+	}
+	if q.listObjectTypeValueHistoryStatesStmt != nil {
+		if cerr := q.listObjectTypeValueHistoryStatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectTypeValueHistoryStatesStmt: %w", cerr)
+		}
+	}
+	if q.listObjectTypeValuesForMergeStmt != nil {
+		if cerr := q.listObjectTypeValuesForMergeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectTypeValuesForMergeStmt: %w", cerr)
+		}
+	}
 	if q.listObjectTypesStmt != nil {
 		if cerr := q.listObjectTypesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listObjectTypesStmt: %w", cerr)
@@ -882,6 +1810,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listObjectsByTypeWithAdvancedFilterStmt: %w", cerr)
 		}
 	}
+	if q.listObjectsForMergeStmt != nil {
+		if cerr := q.listObjectsForMergeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectsForMergeStmt: %w", cerr)
+		}
+	}
 	if q.listObjectsWithNormalizedDataStmt != nil {
 		if cerr := q.listObjectsWithNormalizedDataStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listObjectsWithNormalizedDataStmt: %w", cerr)
@@ -892,6 +1825,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listOrgMembersStmt: %w", cerr)
 		}
 	}
+	if q.listOrgProfilesStmt != nil {
+		if cerr := q.listOrgProfilesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOrgProfilesStmt: %w", cerr)
+		}
+	}
+	if q.listPendingOrgInvitesStmt != nil {
+		if cerr := q.listPendingOrgInvitesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPendingOrgInvitesStmt: %w", cerr)
+		}
+	}
+	if q.listRolePermissionsByOrgIDStmt != nil {
+		if cerr := q.listRolePermissionsByOrgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listRolePermissionsByOrgIDStmt: %w", cerr)
+		}
+	}
+	if q.listSSODomainsByOrgIDStmt != nil {
+		if cerr := q.listSSODomainsByOrgIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSSODomainsByOrgIDStmt: %w", cerr)
+		}
+	}
 	if q.listStepsByFunnelStmt != nil {
 		if cerr := q.listStepsByFunnelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listStepsByFunnelStmt: %w", cerr)
@@ -917,16 +1870,81 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listTasksWithFilterStmt: %w", cerr)
 		}
 	}
+	if q.listTrashStmt != nil {
+		if cerr := q.listTrashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTrashStmt: %w", cerr)
+		}
+	}
+	if q.listUnusedRecoveryCodesStmt != nil {
+		if cerr := q.listUnusedRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnusedRecoveryCodesStmt: %w", cerr)
+		}
+	}
+	if q.logImpersonationRequestStmt != nil {
+		if cerr := q.logImpersonationRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing logImpersonationRequestStmt: %w", cerr)
+		}
+	}
 	if q.markFeedAsSeenStmt != nil {
 		if cerr := q.markFeedAsSeenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing markFeedAsSeenStmt: %w", cerr)
 		}
 	}
+	if q.markLoginChallengeUsedStmt != nil {
+		if cerr := q.markLoginChallengeUsedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markLoginChallengeUsedStmt: %w", cerr)
+		}
+	}
+	if q.markObjectMergeUndoneStmt != nil {
+		if cerr := q.markObjectMergeUndoneStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markObjectMergeUndoneStmt: %w", cerr)
+		}
+	}
+	if q.markRefreshTokenUsedStmt != nil {
+		if cerr := q.markRefreshTokenUsedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markRefreshTokenUsedStmt: %w", cerr)
+		}
+	}
 	if q.mergeObjectsStmt != nil {
 		if cerr := q.mergeObjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing mergeObjectsStmt: %w", cerr)
 		}
 	}
+	if q.moveMergedLinksBackStmt != nil {
+		if cerr := q.moveMergedLinksBackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing moveMergedLinksBackStmt: %w", cerr)
+		}
+	}
+	if q.purgeTrashStmt != nil {
+		if cerr := q.purgeTrashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeTrashStmt: %w", cerr)
+		}
+	}
+	if q.recordLoginFailureStmt != nil {
+		if cerr := q.recordLoginFailureStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recordLoginFailureStmt: %w", cerr)
+		}
+	}
+	if q.refreshDuplicateSuggestionsStmt != nil {
+		if cerr := q.refreshDuplicateSuggestionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing refreshDuplicateSuggestionsStmt: %w", cerr)
+		}
+	}
+	if q.removeCommentReactionStmt != nil {
+		if cerr := q.removeCommentReactionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeCommentReactionStmt: %w", cerr)
+		}
+	}
+	if q.removeMentionedObjectsFromFactStmt != nil {
+		if cerr := q.removeMentionedObjectsFromFactStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeMentionedObjectsFromFactStmt: %w", cerr)
+		}
+	}
+	if q.removeMergedTagsStmt != nil {
+		if cerr := q.removeMergedTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeMergedTagsStmt: %w", cerr)
+		}
+	}
 	if q.removeObjectTypeValueStmt != nil {
 		if cerr := q.removeObjectTypeValueStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing removeObjectTypeValueStmt: %w", cerr)
@@ -947,6 +1965,116 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing removeTagFromObjectStmt: %w", cerr)
 		}
 	}
+	if q.replaceRolePermissionsStmt != nil {
+		if cerr := q.replaceRolePermissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing replaceRolePermissionsStmt: %w", cerr)
+		}
+	}
+	if q.resolveDuplicateSuggestionStmt != nil {
+		if cerr := q.resolveDuplicateSuggestionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resolveDuplicateSuggestionStmt: %w", cerr)
+		}
+	}
+	if q.restoreMergeTargetStmt != nil {
+		if cerr := q.restoreMergeTargetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreMergeTargetStmt: %w", cerr)
+		}
+	}
+	if q.restoreMergedFactTextStmt != nil {
+		if cerr := q.restoreMergedFactTextStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreMergedFactTextStmt: %w", cerr)
+		}
+	}
+	if q.restoreMergedObjectStmt != nil {
+		if cerr := q.restoreMergedObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreMergedObjectStmt: %w", cerr)
+		}
+	}
+	if q.restoreMergedRelationStmt != nil {
+		if cerr := q.restoreMergedRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreMergedRelationStmt: %w", cerr)
+		}
+	}
+	if q.restoreMergedTaskTextStmt != nil {
+		if cerr := q.restoreMergedTaskTextStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreMergedTaskTextStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedFactStmt != nil {
+		if cerr := q.restoreTrashedFactStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedFactStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedFunnelStmt != nil {
+		if cerr := q.restoreTrashedFunnelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedFunnelStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedListStmt != nil {
+		if cerr := q.restoreTrashedListStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedListStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedObjectStmt != nil {
+		if cerr := q.restoreTrashedObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedObjectStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedTagStmt != nil {
+		if cerr := q.restoreTrashedTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedTagStmt: %w", cerr)
+		}
+	}
+	if q.restoreTrashedTaskStmt != nil {
+		if cerr := q.restoreTrashedTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTrashedTaskStmt: %w", cerr)
+		}
+	}
+	if q.revertFactStmt != nil {
+		if cerr := q.revertFactStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revertFactStmt: %w", cerr)
+		}
+	}
+	if q.revokeAPIKeyStmt != nil {
+		if cerr := q.revokeAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.revokeCreatorSessionsStmt != nil {
+		if cerr := q.revokeCreatorSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeCreatorSessionsStmt: %w", cerr)
+		}
+	}
+	if q.revokeOrgInviteStmt != nil {
+		if cerr := q.revokeOrgInviteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeOrgInviteStmt: %w", cerr)
+		}
+	}
+	if q.revokeSessionByIDStmt != nil {
+		if cerr := q.revokeSessionByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeSessionByIDStmt: %w", cerr)
+		}
+	}
+	if q.revokeSessionByJtiStmt != nil {
+		if cerr := q.revokeSessionByJtiStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeSessionByJtiStmt: %w", cerr)
+		}
+	}
+	if q.setDuplicateScanStmt != nil {
+		if cerr := q.setDuplicateScanStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDuplicateScanStmt: %w", cerr)
+		}
+	}
+	if q.setObjectPhotoStmt != nil {
+		if cerr := q.setObjectPhotoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setObjectPhotoStmt: %w", cerr)
+		}
+	}
+	if q.snapshotObjectMergeStmt != nil {
+		if cerr := q.snapshotObjectMergeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing snapshotObjectMergeStmt: %w", cerr)
+		}
+	}
 	if q.softDeleteObjStepStmt != nil {
 		if cerr := q.softDeleteObjStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing softDeleteObjStepStmt: %w", cerr)
@@ -957,6 +2085,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing syncObjectAliasesStmt: %w", cerr)
 		}
 	}
+	if q.touchAPIKeyStmt != nil {
+		if cerr := q.touchAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.touchCreatorIdentityStmt != nil {
+		if cerr := q.touchCreatorIdentityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchCreatorIdentityStmt: %w", cerr)
+		}
+	}
+	if q.unlinkFactAttachmentStmt != nil {
+		if cerr := q.unlinkFactAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlinkFactAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.unlinkObjectAttachmentStmt != nil {
+		if cerr := q.unlinkObjectAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlinkObjectAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.unlinkTaskAttachmentStmt != nil {
+		if cerr := q.unlinkTaskAttachmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlinkTaskAttachmentStmt: %w", cerr)
+		}
+	}
+	if q.unlockLoginLockoutsStmt != nil {
+		if cerr := q.unlockLoginLockoutsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unlockLoginLockoutsStmt: %w", cerr)
+		}
+	}
 	if q.updateActionExecutionStmt != nil {
 		if cerr := q.updateActionExecutionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateActionExecutionStmt: %w", cerr)
@@ -972,6 +2130,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateAutomatedActionStmt: %w", cerr)
 		}
 	}
+	if q.updateCommentStmt != nil {
+		if cerr := q.updateCommentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateCommentStmt: %w", cerr)
+		}
+	}
 	if q.updateCreatorListStmt != nil {
 		if cerr := q.updateCreatorListStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateCreatorListStmt: %w", cerr)
@@ -997,6 +2160,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateFactStmt: %w", cerr)
 		}
 	}
+	if q.updateFactKindStmt != nil {
+		if cerr := q.updateFactKindStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateFactKindStmt: %w", cerr)
+		}
+	}
 	if q.updateFunnelStmt != nil {
 		if cerr := q.updateFunnelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateFunnelStmt: %w", cerr)
@@ -1022,6 +2190,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateListStmt: %w", cerr)
 		}
 	}
+	if q.updateObjRelationStmt != nil {
+		if cerr := q.updateObjRelationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateObjRelationStmt: %w", cerr)
+		}
+	}
+	if q.updateObjRelationTypeStmt != nil {
+		if cerr := q.updateObjRelationTypeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateObjRelationTypeStmt: %w", cerr)
+		}
+	}
 	if q.updateObjStepStmt != nil {
 		if cerr := q.updateObjStepStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateObjStepStmt: %w", cerr)
@@ -1072,6 +2250,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing upsertObjectTypeValueStmt: %w", cerr)
 		}
 	}
+	if q.upsertPendingCreatorTOTPStmt != nil {
+		if cerr := q.upsertPendingCreatorTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertPendingCreatorTOTPStmt: %w", cerr)
+		}
+	}
+	if q.useCreatorTOTPStepStmt != nil {
+		if cerr := q.useCreatorTOTPStepStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useCreatorTOTPStepStmt: %w", cerr)
+		}
+	}
+	if q.useCreatorTokenStmt != nil {
+		if cerr := q.useCreatorTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useCreatorTokenStmt: %w", cerr)
+		}
+	}
+	if q.useOIDCStateStmt != nil {
+		if cerr := q.useOIDCStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useOIDCStateStmt: %w", cerr)
+		}
+	}
+	if q.useRecoveryCodeStmt != nil {
+		if cerr := q.useRecoveryCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useRecoveryCodeStmt: %w", cerr)
+		}
+	}
+	if q.usernameExistsStmt != nil {
+		if cerr := q.usernameExistsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing usernameExistsStmt: %w", cerr)
+		}
+	}
 	if q.validateMergeObjectsStmt != nil {
 		if cerr := q.validateMergeObjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing validateMergeObjectsStmt: %w", cerr)
@@ -1116,11 +2324,19 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                       DBTX
 	tx                                       *sql.Tx
+	acceptOrgInviteStmt                      *sql.Stmt
+	addCommentMentionsStmt                   *sql.Stmt
+	addCommentReactionStmt                   *sql.Stmt
+	addFactAttendeesStmt                     *sql.Stmt
+	addMentionedObjectsToFactStmt            *sql.Stmt
 	addObjectTypeValueStmt                   *sql.Stmt
 	addObjectsToFactStmt                     *sql.Stmt
 	addObjectsToTaskStmt                     *sql.Stmt
 	addTagAndStepToFilteredObjectsStmt       *sql.Stmt
 	addTagToObjectStmt                       *sql.Stmt
+	clearLoginFailuresStmt                   *sql.Stmt
+	clearLoginFailuresByIPStmt               *sql.Stmt
+	clearStaleDuplicateSuggestionsStmt       *sql.Stmt
 	completeImportTaskStmt                   *sql.Stmt
 	countActionExecutionsStmt                *sql.Stmt
 	countAutomatedActionsStmt                *sql.Stmt
@@ -1128,6 +2344,9 @@ type Queries struct {
 	countFunnelsStmt                         *sql.Stmt
 	countImportTasksStmt                     *sql.Stmt
 	countListsByOrgIDStmt                    *sql.Stmt
+	countLoginFailuresByIPStmt               *sql.Stmt
+	countLoginFailuresByUsernameStmt         *sql.Stmt
+	countObjectMergeMovesStmt                *sql.Stmt
 	countObjectTypesStmt                     *sql.Stmt
 	countObjectsAdvancedStmt                 *sql.Stmt
 	countObjectsAfterCreatedAtStmt           *sql.Stmt
@@ -1135,47 +2354,94 @@ type Queries struct {
 	countObjectsByTypeWithAdvancedFilterStmt *sql.Stmt
 	countObjectsForStepStmt                  *sql.Stmt
 	countOngoingTaskStmt                     *sql.Stmt
+	countPurgedMergeSourcesStmt              *sql.Stmt
 	countTagsStmt                            *sql.Stmt
 	countTasksByObjectIDStmt                 *sql.Stmt
 	countTasksByOrgIDStmt                    *sql.Stmt
 	countTasksWithFilterStmt                 *sql.Stmt
 	countUnseenFeedStmt                      *sql.Stmt
+	createAPIKeyStmt                         *sql.Stmt
 	createActionExecutionStmt                *sql.Stmt
+	createAttachmentStmt                     *sql.Stmt
 	createAutomatedActionStmt                *sql.Stmt
+	createCommentStmt                        *sql.Stmt
 	createCreatorStmt                        *sql.Stmt
+	createCreatorIdentityStmt                *sql.Stmt
 	createCreatorListStmt                    *sql.Stmt
+	createCreatorSessionStmt                 *sql.Stmt
+	createCreatorTokenStmt                   *sql.Stmt
 	createFactStmt                           *sql.Stmt
+	createFactKindStmt                       *sql.Stmt
 	createFeedStmt                           *sql.Stmt
 	createFunnelStmt                         *sql.Stmt
+	createImpersonationStmt                  *sql.Stmt
 	createImportTaskStmt                     *sql.Stmt
 	createListStmt                           *sql.Stmt
+	createLoginChallengeStmt                 *sql.Stmt
+	createLoginLockoutStmt                   *sql.Stmt
+	createOIDCStateStmt                      *sql.Stmt
+	createObjRelationStmt                    *sql.Stmt
+	createObjRelationTypeStmt                *sql.Stmt
 	createObjStepStmt                        *sql.Stmt
 	createObjectStmt                         *sql.Stmt
 	createObjectTypeStmt                     *sql.Stmt
+	createOrgInviteStmt                      *sql.Stmt
 	createOrganizationStmt                   *sql.Stmt
+	createRecoveryCodeStmt                   *sql.Stmt
+	createRefreshTokenStmt                   *sql.Stmt
+	createSSODomainStmt                      *sql.Stmt
 	createStepStmt                           *sql.Stmt
 	createTagStmt                            *sql.Stmt
 	createTaskStmt                           *sql.Stmt
 	deleteActionOldExecutionsStmt            *sql.Stmt
+	deleteAttachmentStmt                     *sql.Stmt
 	deleteAutomatedActionStmt                *sql.Stmt
+	deleteCommentStmt                        *sql.Stmt
 	deleteCreatorStmt                        *sql.Stmt
 	deleteCreatorListStmt                    *sql.Stmt
+	deleteCreatorTOTPStmt                    *sql.Stmt
+	deleteExpiredOIDCStatesStmt              *sql.Stmt
 	deleteFactStmt                           *sql.Stmt
+	deleteFactAttendeesStmt                  *sql.Stmt
+	deleteFactKindStmt                       *sql.Stmt
 	deleteFunnelStmt                         *sql.Stmt
 	deleteListStmt                           *sql.Stmt
+	deleteObjRelationStmt                    *sql.Stmt
+	deleteObjRelationTypeStmt                *sql.Stmt
 	deleteObjectStmt                         *sql.Stmt
 	deleteObjectTypeStmt                     *sql.Stmt
+	deleteRecoveryCodesStmt                  *sql.Stmt
+	deleteSSODomainStmt                      *sql.Stmt
+	deleteStaleCommentMentionsStmt           *sql.Stmt
 	deleteStepStmt                           *sql.Stmt
 	deleteTagStmt                            *sql.Stmt
 	deleteTaskStmt                           *sql.Stmt
+	deleteUnusedCreatorTokensStmt            *sql.Stmt
+	enableCreatorTOTPStmt                    *sql.Stmt
+	endImpersonationStmt                     *sql.Stmt
+	endImpersonationByJtiStmt                *sql.Stmt
 	findObjectByAliasOrIDStringStmt          *sql.Stmt
 	findTagByNormalizedNameStmt              *sql.Stmt
+	getActiveAPIKeyByPrefixStmt              *sql.Stmt
+	getActiveImpersonationByJtiStmt          *sql.Stmt
+	getActiveLoginChallengeStmt              *sql.Stmt
+	getActiveLoginLockoutStmt                *sql.Stmt
+	getActiveOrgInviteByHashStmt             *sql.Stmt
+	getActiveSessionByJtiStmt                *sql.Stmt
+	getAttachmentStmt                        *sql.Stmt
 	getAutomatedActionStmt                   *sql.Stmt
+	getCommentStmt                           *sql.Stmt
 	getCreatorByIDStmt                       *sql.Stmt
+	getCreatorByIdentityStmt                 *sql.Stmt
 	getCreatorByUsernameStmt                 *sql.Stmt
 	getCreatorDailyActivityStmt              *sql.Stmt
 	getCreatorListByIDStmt                   *sql.Stmt
+	getCreatorTOTPStmt                       *sql.Stmt
+	getDuplicateScanStmt                     *sql.Stmt
+	getDuplicateSuggestionStmt               *sql.Stmt
 	getFactByIDStmt                          *sql.Stmt
+	getFactKindStmt                          *sql.Stmt
+	getFactRevisionStmt                      *sql.Stmt
 	getFeedStmt                              *sql.Stmt
 	getFunnelStmt                            *sql.Stmt
 	getImportTaskStmt                        *sql.Stmt
@@ -1185,57 +2451,144 @@ type Queries struct {
 	getObjStepStmt                           *sql.Stmt
 	getObjectByIDStringStmt                  *sql.Stmt
 	getObjectDetailsStmt                     *sql.Stmt
+	getObjectHistoryStateStmt                *sql.Stmt
+	getObjectMergeHistoryStmt                *sql.Stmt
 	getObjectTypeByIDStmt                    *sql.Stmt
 	getObjectTypeValueStmt                   *sql.Stmt
 	getObjectsForStepStmt                    *sql.Stmt
 	getOngoingImportTaskStmt                 *sql.Stmt
 	getOrgDetailsStmt                        *sql.Stmt
 	getPendingActionsStmt                    *sql.Stmt
+	getRefreshTokenByHashStmt                *sql.Stmt
+	getRolePermissionStmt                    *sql.Stmt
+	getSSODomainStmt                         *sql.Stmt
 	getStepStmt                              *sql.Stmt
 	getTagByIDStmt                           *sql.Stmt
 	getTagsByIDsStmt                         *sql.Stmt
 	getTaskByIDStmt                          *sql.Stmt
+	graphNeighborhoodStmt                    *sql.Stmt
+	graphSharedFactsStmt                     *sql.Stmt
+	graphShortestPathStmt                    *sql.Stmt
 	hardDeleteObjStepStmt                    *sql.Stmt
 	healthCheckStmt                          *sql.Stmt
+	isEmailVerifiedStmt                      *sql.Stmt
+	linkFactAttachmentStmt                   *sql.Stmt
+	linkObjectAttachmentStmt                 *sql.Stmt
+	linkTaskAttachmentStmt                   *sql.Stmt
+	listAPIKeysByOrgIDStmt                   *sql.Stmt
 	listActionExecutionsStmt                 *sql.Stmt
+	listActiveSessionsByCreatorIDStmt        *sql.Stmt
+	listAttachmentLinksStmt                  *sql.Stmt
+	listAttachmentsStmt                      *sql.Stmt
+	listAuditEventsStmt                      *sql.Stmt
 	listAutomatedActionsStmt                 *sql.Stmt
+	listCommentsStmt                         *sql.Stmt
 	listCreatorListsByCreatorIDStmt          *sql.Stmt
+	listCreatorsByVerifiedEmailStmt          *sql.Stmt
+	listDuplicateScansStmt                   *sql.Stmt
+	listDuplicateSuggestionsStmt             *sql.Stmt
+	listFactKindsStmt                        *sql.Stmt
+	listFactRevisionsStmt                    *sql.Stmt
 	listFactsByOrgIDStmt                     *sql.Stmt
 	listFunnelsStmt                          *sql.Stmt
+	listGraphEdgesStmt                       *sql.Stmt
+	listGraphNodesStmt                       *sql.Stmt
+	listImpersonationRequestsStmt            *sql.Stmt
+	listImpersonationsByOrgIDStmt            *sql.Stmt
 	listListsByOrgIDStmt                     *sql.Stmt
+	listLoginLockoutsByOrgIDStmt             *sql.Stmt
+	listMentionableCreatorsStmt              *sql.Stmt
+	listMergeConflictEventsStmt              *sql.Stmt
+	listObjRelationTypesStmt                 *sql.Stmt
+	listObjectHistoryStmt                    *sql.Stmt
+	listObjectHistoryEventsStmt              *sql.Stmt
+	listObjectMergeHistoryStmt               *sql.Stmt
+	listObjectStepsForMergeStmt              *sql.Stmt
+	listObjectTypeValueHistoryStatesStmt     *sql.Stmt
+	listObjectTypeValuesForMergeStmt         *sql.Stmt
 	listObjectTypesStmt                      *sql.Stmt
 	listObjectsAdvancedStmt                  *sql.Stmt
 	listObjectsByOrgIDStmt                   *sql.Stmt
 	listObjectsByTaskIDStmt                  *sql.Stmt
 	listObjectsByTypeWithAdvancedFilterStmt  *sql.Stmt
+	listObjectsForMergeStmt                  *sql.Stmt
 	listObjectsWithNormalizedDataStmt        *sql.Stmt
 	listOrgMembersStmt                       *sql.Stmt
+	listOrgProfilesStmt                      *sql.Stmt
+	listPendingOrgInvitesStmt                *sql.Stmt
+	listRolePermissionsByOrgIDStmt           *sql.Stmt
+	listSSODomainsByOrgIDStmt                *sql.Stmt
 	listStepsByFunnelStmt                    *sql.Stmt
 	listTagsStmt                             *sql.Stmt
 	listTasksByObjectIDStmt                  *sql.Stmt
 	listTasksByOrgIDStmt                     *sql.Stmt
 	listTasksWithFilterStmt                  *sql.Stmt
+	listTrashStmt                            *sql.Stmt
+	listUnusedRecoveryCodesStmt              *sql.Stmt
+	logImpersonationRequestStmt              *sql.Stmt
 	markFeedAsSeenStmt                       *sql.Stmt
+	markLoginChallengeUsedStmt               *sql.Stmt
+	markObjectMergeUndoneStmt                *sql.Stmt
+	markRefreshTokenUsedStmt                 *sql.Stmt
 	mergeObjectsStmt                         *sql.Stmt
+	moveMergedLinksBackStmt                  *sql.Stmt
+	purgeTrashStmt                           *sql.Stmt
+	recordLoginFailureStmt                   *sql.Stmt
+	refreshDuplicateSuggestionsStmt          *sql.Stmt
+	removeCommentReactionStmt                *sql.Stmt
+	removeMentionedObjectsFromFactStmt       *sql.Stmt
+	removeMergedTagsStmt                     *sql.Stmt
 	removeObjectTypeValueStmt                *sql.Stmt
 	removeObjectsFromFactStmt                *sql.Stmt
 	removeObjectsFromTaskStmt                *sql.Stmt
 	removeTagFromObjectStmt                  *sql.Stmt
+	replaceRolePermissionsStmt               *sql.Stmt
+	resolveDuplicateSuggestionStmt           *sql.Stmt
+	restoreMergeTargetStmt                   *sql.Stmt
+	restoreMergedFactTextStmt                *sql.Stmt
+	restoreMergedObjectStmt                  *sql.Stmt
+	restoreMergedRelationStmt                *sql.Stmt
+	restoreMergedTaskTextStmt                *sql.Stmt
+	restoreTrashedFactStmt                   *sql.Stmt
+	restoreTrashedFunnelStmt                 *sql.Stmt
+	restoreTrashedListStmt                   *sql.Stmt
+	restoreTrashedObjectStmt                 *sql.Stmt
+	restoreTrashedTagStmt                    *sql.Stmt
+	restoreTrashedTaskStmt                   *sql.Stmt
+	revertFactStmt                           *sql.Stmt
+	revokeAPIKeyStmt                         *sql.Stmt
+	revokeCreatorSessionsStmt                *sql.Stmt
+	revokeOrgInviteStmt                      *sql.Stmt
+	revokeSessionByIDStmt                    *sql.Stmt
+	revokeSessionByJtiStmt                   *sql.Stmt
+	setDuplicateScanStmt                     *sql.Stmt
+	setObjectPhotoStmt                       *sql.Stmt
+	snapshotObjectMergeStmt                  *sql.Stmt
 	softDeleteObjStepStmt                    *sql.Stmt
 	syncObjectAliasesStmt                    *sql.Stmt
+	touchAPIKeyStmt                          *sql.Stmt
+	touchCreatorIdentityStmt                 *sql.Stmt
+	unlinkFactAttachmentStmt                 *sql.Stmt
+	unlinkObjectAttachmentStmt               *sql.Stmt
+	unlinkTaskAttachmentStmt                 *sql.Stmt
+	unlockLoginLockoutsStmt                  *sql.Stmt
 	updateActionExecutionStmt                *sql.Stmt
 	updateActionLastRunStmt                  *sql.Stmt
 	updateAutomatedActionStmt                *sql.Stmt
+	updateCommentStmt                        *sql.Stmt
 	updateCreatorListStmt                    *sql.Stmt
 	updateCreatorPasswordStmt                *sql.Stmt
 	updateCreatorProfileStmt                 *sql.Stmt
 	updateCreatorRoleAndStatusStmt           *sql.Stmt
 	updateFactStmt                           *sql.Stmt
+	updateFactKindStmt                       *sql.Stmt
 	updateFunnelStmt                         *sql.Stmt
 	updateImportTaskErrorStmt                *sql.Stmt
 	updateImportTaskProgressStmt             *sql.Stmt
 	updateImportTaskStatusStmt               *sql.Stmt
 	updateListStmt                           *sql.Stmt
+	updateObjRelationStmt                    *sql.Stmt
+	updateObjRelationTypeStmt                *sql.Stmt
 	updateObjStepStmt                        *sql.Stmt
 	updateObjStepSubStatusStmt               *sql.Stmt
 	updateObjectStmt                         *sql.Stmt
@@ -1246,6 +2599,12 @@ type Queries struct {
 	updateTagStmt                            *sql.Stmt
 	updateTaskStmt                           *sql.Stmt
 	upsertObjectTypeValueStmt                *sql.Stmt
+	upsertPendingCreatorTOTPStmt             *sql.Stmt
+	useCreatorTOTPStepStmt                   *sql.Stmt
+	useCreatorTokenStmt                      *sql.Stmt
+	useOIDCStateStmt                         *sql.Stmt
+	useRecoveryCodeStmt                      *sql.Stmt
+	usernameExistsStmt                       *sql.Stmt
 	validateMergeObjectsStmt                 *sql.Stmt
 }
 
@@ -1253,11 +2612,19 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                       tx,
 		tx:                                       tx,
+		acceptOrgInviteStmt:                      q.acceptOrgInviteStmt,
+		addCommentMentionsStmt:                   q.addCommentMentionsStmt,
+		addCommentReactionStmt:                   q.addCommentReactionStmt,
+		addFactAttendeesStmt:                     q.addFactAttendeesStmt,
+		addMentionedObjectsToFactStmt:            q.addMentionedObjectsToFactStmt,
 		addObjectTypeValueStmt:                   q.addObjectTypeValueStmt,
 		addObjectsToFactStmt:                     q.addObjectsToFactStmt,
 		addObjectsToTaskStmt:                     q.addObjectsToTaskStmt,
 		addTagAndStepToFilteredObjectsStmt:       q.addTagAndStepToFilteredObjectsStmt,
 		addTagToObjectStmt:                       q.addTagToObjectStmt,
+		clearLoginFailuresStmt:                   q.clearLoginFailuresStmt,
+		clearLoginFailuresByIPStmt:               q.clearLoginFailuresByIPStmt,
+		clearStaleDuplicateSuggestionsStmt:       q.clearStaleDuplicateSuggestionsStmt,
 		completeImportTaskStmt:                   q.completeImportTaskStmt,
 		countActionExecutionsStmt:                q.countActionExecutionsStmt,
 		countAutomatedActionsStmt:                q.countAutomatedActionsStmt,
@@ -1265,6 +2632,9 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		countFunnelsStmt:                         q.countFunnelsStmt,
 		countImportTasksStmt:                     q.countImportTasksStmt,
 		countListsByOrgIDStmt:                    q.countListsByOrgIDStmt,
+		countLoginFailuresByIPStmt:               q.countLoginFailuresByIPStmt,
+		countLoginFailuresByUsernameStmt:         q.countLoginFailuresByUsernameStmt,
+		countObjectMergeMovesStmt:                q.countObjectMergeMovesStmt,
 		countObjectTypesStmt:                     q.countObjectTypesStmt,
 		countObjectsAdvancedStmt:                 q.countObjectsAdvancedStmt,
 		countObjectsAfterCreatedAtStmt:           q.countObjectsAfterCreatedAtStmt,
@@ -1272,47 +2642,94 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		countObjectsByTypeWithAdvancedFilterStmt: q.countObjectsByTypeWithAdvancedFilterStmt,
 		countObjectsForStepStmt:                  q.countObjectsForStepStmt,
 		countOngoingTaskStmt:                     q.countOngoingTaskStmt,
+		countPurgedMergeSourcesStmt:              q.countPurgedMergeSourcesStmt,
 		countTagsStmt:                            q.countTagsStmt,
 		countTasksByObjectIDStmt:                 q.countTasksByObjectIDStmt,
 		countTasksByOrgIDStmt:                    q.countTasksByOrgIDStmt,
 		countTasksWithFilterStmt:                 q.countTasksWithFilterStmt,
 		countUnseenFeedStmt:                      q.countUnseenFeedStmt,
+		createAPIKeyStmt:                         q.createAPIKeyStmt,
 		createActionExecutionStmt:                q.createActionExecutionStmt,
+		createAttachmentStmt:                     q.createAttachmentStmt,
 		createAutomatedActionStmt:                q.createAutomatedActionStmt,
+		createCommentStmt:                        q.createCommentStmt,
 		createCreatorStmt:                        q.createCreatorStmt,
+		createCreatorIdentityStmt:                q.createCreatorIdentityStmt,
 		createCreatorListStmt:                    q.createCreatorListStmt,
+		createCreatorSessionStmt:                 q.createCreatorSessionStmt,
+		createCreatorTokenStmt:                   q.createCreatorTokenStmt,
 		createFactStmt:                           q.createFactStmt,
+		createFactKindStmt:                       q.createFactKindStmt,
 		createFeedStmt:                           q.createFeedStmt,
 		createFunnelStmt:                         q.createFunnelStmt,
+		createImpersonationStmt:                  q.createImpersonationStmt,
 		createImportTaskStmt:                     q.createImportTaskStmt,
 		createListStmt:                           q.createListStmt,
+		createLoginChallengeStmt:                 q.createLoginChallengeStmt,
+		createLoginLockoutStmt:                   q.createLoginLockoutStmt,
+		createOIDCStateStmt:                      q.createOIDCStateStmt,
+		createObjRelationStmt:                    q.createObjRelationStmt,
+		createObjRelationTypeStmt:                q.createObjRelationTypeStmt,
 		createObjStepStmt:                        q.createObjStepStmt,
 		createObjectStmt:                         q.createObjectStmt,
 		createObjectTypeStmt:                     q.createObjectTypeStmt,
+		createOrgInviteStmt:                      q.createOrgInviteStmt,
 		createOrganizationStmt:                   q.createOrganizationStmt,
+		createRecoveryCodeStmt:                   q.createRecoveryCodeStmt,
+		createRefreshTokenStmt:                   q.createRefreshTokenStmt,
+		createSSODomainStmt:                      q.createSSODomainStmt,
 		createStepStmt:                           q.createStepStmt,
 		createTagStmt:                            q.createTagStmt,
 		createTaskStmt:                           q.createTaskStmt,
 		deleteActionOldExecutionsStmt:            q.deleteActionOldExecutionsStmt,
+		deleteAttachmentStmt:                     q.deleteAttachmentStmt,
 		deleteAutomatedActionStmt:                q.deleteAutomatedActionStmt,
+		deleteCommentStmt:                        q.deleteCommentStmt,
 		deleteCreatorStmt:                        q.deleteCreatorStmt,
 		deleteCreatorListStmt:                    q.deleteCreatorListStmt,
+		deleteCreatorTOTPStmt:                    q.deleteCreatorTOTPStmt,
+		deleteExpiredOIDCStatesStmt:              q.deleteExpiredOIDCStatesStmt,
 		deleteFactStmt:                           q.deleteFactStmt,
+		deleteFactAttendeesStmt:                  q.deleteFactAttendeesStmt,
+		deleteFactKindStmt:                       q.deleteFactKindStmt,
 		deleteFunnelStmt:                         q.deleteFunnelStmt,
 		deleteListStmt:                           q.deleteListStmt,
+		deleteObjRelationStmt:                    q.deleteObjRelationStmt,
+		deleteObjRelationTypeStmt:                q.deleteObjRelationTypeStmt,
 		deleteObjectStmt:                         q.deleteObjectStmt,
 		deleteObjectTypeStmt:                     q.deleteObjectTypeStmt,
+		deleteRecoveryCodesStmt:                  q.deleteRecoveryCodesStmt,
+		deleteSSODomainStmt:                      q.deleteSSODomainStmt,
+		deleteStaleCommentMentionsStmt:           q.deleteStaleCommentMentionsStmt,
 		deleteStepStmt:                           q.deleteStepStmt,
 		deleteTagStmt:                            q.deleteTagStmt,
 		deleteTaskStmt:                           q.deleteTaskStmt,
+		deleteUnusedCreatorTokensStmt:            q.deleteUnusedCreatorTokensStmt,
+		enableCreatorTOTPStmt:                    q.enableCreatorTOTPStmt,
+		endImpersonationStmt:                     q.endImpersonationStmt,
+		endImpersonationByJtiStmt:                q.endImpersonationByJtiStmt,
 		findObjectByAliasOrIDStringStmt:          q.findObjectByAliasOrIDStringStmt,
 		findTagByNormalizedNameStmt:              q.findTagByNormalizedNameStmt,
+		getActiveAPIKeyByPrefixStmt:              q.getActiveAPIKeyByPrefixStmt,
+		getActiveImpersonationByJtiStmt:          q.getActiveImpersonationByJtiStmt,
+		getActiveLoginChallengeStmt:              q.getActiveLoginChallengeStmt,
+		getActiveLoginLockoutStmt:                q.getActiveLoginLockoutStmt,
+		getActiveOrgInviteByHashStmt:             q.getActiveOrgInviteByHashStmt,
+		getActiveSessionByJtiStmt:                q.getActiveSessionByJtiStmt,
+		getAttachmentStmt:                        q.getAttachmentStmt,
 		getAutomatedActionStmt:                   q.getAutomatedActionStmt,
+		getCommentStmt:                           q.getCommentStmt,
 		getCreatorByIDStmt:                       q.getCreatorByIDStmt,
+		getCreatorByIdentityStmt:                 q.getCreatorByIdentityStmt,
 		getCreatorByUsernameStmt:                 q.getCreatorByUsernameStmt,
 		getCreatorDailyActivityStmt:              q.getCreatorDailyActivityStmt,
 		getCreatorListByIDStmt:                   q.getCreatorListByIDStmt,
+		getCreatorTOTPStmt:                       q.getCreatorTOTPStmt,
+		getDuplicateScanStmt:                     q.getDuplicateScanStmt,
+		getDuplicateSuggestionStmt:               q.getDuplicateSuggestionStmt,
 		getFactByIDStmt:                          q.getFactByIDStmt,
+		getFactKindStmt:                          q.getFactKindStmt,
+		getFactRevisionStmt:                      q.getFactRevisionStmt,
 		getFeedStmt:                              q.getFeedStmt,
 		getFunnelStmt:                            q.getFunnelStmt,
 		getImportTaskStmt:                        q.getImportTaskStmt,
@@ -1322,57 +2739,144 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getObjStepStmt:                           q.getObjStepStmt,
 		getObjectByIDStringStmt:                  q.getObjectByIDStringStmt,
 		getObjectDetailsStmt:                     q.getObjectDetailsStmt,
+		getObjectHistoryStateStmt:                q.getObjectHistoryStateStmt,
+		getObjectMergeHistoryStmt:                q.getObjectMergeHistoryStmt,
 		getObjectTypeByIDStmt:                    q.getObjectTypeByIDStmt,
 		getObjectTypeValueStmt:                   q.getObjectTypeValueStmt,
 		getObjectsForStepStmt:                    q.getObjectsForStepStmt,
 		getOngoingImportTaskStmt:                 q.getOngoingImportTaskStmt,
 		getOrgDetailsStmt:                        q.getOrgDetailsStmt,
 		getPendingActionsStmt:                    q.getPendingActionsStmt,
+		getRefreshTokenByHashStmt:                q.getRefreshTokenByHashStmt,
+		getRolePermissionStmt:                    q.getRolePermissionStmt,
+		getSSODomainStmt:                         q.getSSODomainStmt,
 		getStepStmt:                              q.getStepStmt,
 		getTagByIDStmt:                           q.getTagByIDStmt,
 		getTagsByIDsStmt:                         q.getTagsByIDsStmt,
 		getTaskByIDStmt:                          q.getTaskByIDStmt,
+		graphNeighborhoodStmt:                    q.graphNeighborhoodStmt,
+		graphSharedFactsStmt:                     q.graphSharedFactsStmt,
+		graphShortestPathStmt:                    q.graphShortestPathStmt,
 		hardDeleteObjStepStmt:                    q.hardDeleteObjStepStmt,
 		healthCheckStmt:                          q.healthCheckStmt,
+		isEmailVerifiedStmt:                      q.isEmailVerifiedStmt,
+		linkFactAttachmentStmt:                   q.linkFactAttachmentStmt,
+		linkObjectAttachmentStmt:                 q.linkObjectAttachmentStmt,
+		linkTaskAttachmentStmt:                   q.linkTaskAttachmentStmt,
+		listAPIKeysByOrgIDStmt:                   q.listAPIKeysByOrgIDStmt,
 		listActionExecutionsStmt:                 q.listActionExecutionsStmt,
+		listActiveSessionsByCreatorIDStmt:        q.listActiveSessionsByCreatorIDStmt,
+		listAttachmentLinksStmt:                  q.listAttachmentLinksStmt,
+		listAttachmentsStmt:                      q.listAttachmentsStmt,
+		listAuditEventsStmt:                      q.listAuditEventsStmt,
 		listAutomatedActionsStmt:                 q.listAutomatedActionsStmt,
+		listCommentsStmt:                         q.listCommentsStmt,
 		listCreatorListsByCreatorIDStmt:          q.listCreatorListsByCreatorIDStmt,
+		listCreatorsByVerifiedEmailStmt:          q.listCreatorsByVerifiedEmailStmt,
+		listDuplicateScansStmt:                   q.listDuplicateScansStmt,
+		listDuplicateSuggestionsStmt:             q.listDuplicateSuggestionsStmt,
+		listFactKindsStmt:                        q.listFactKindsStmt,
+		listFactRevisionsStmt:                    q.listFactRevisionsStmt,
 		listFactsByOrgIDStmt:                     q.listFactsByOrgIDStmt,
 		listFunnelsStmt:                          q.listFunnelsStmt,
+		listGraphEdgesStmt:                       q.listGraphEdgesStmt,
+		listGraphNodesStmt:                       q.listGraphNodesStmt,
+		listImpersonationRequestsStmt:            q.listImpersonationRequestsStmt,
+		listImpersonationsByOrgIDStmt:            q.listImpersonationsByOrgIDStmt,
 		listListsByOrgIDStmt:                     q.listListsByOrgIDStmt,
+		listLoginLockoutsByOrgIDStmt:             q.listLoginLockoutsByOrgIDStmt,
+		listMentionableCreatorsStmt:              q.listMentionableCreatorsStmt,
+		listMergeConflictEventsStmt:              q.listMergeConflictEventsStmt,
+		listObjRelationTypesStmt:                 q.listObjRelationTypesStmt,
+		listObjectHistoryStmt:                    q.listObjectHistoryStmt,
+		listObjectHistoryEventsStmt:              q.listObjectHistoryEventsStmt,
+		listObjectMergeHistoryStmt:               q.listObjectMergeHistoryStmt,
+		listObjectStepsForMergeStmt:              q.listObjectStepsForMergeStmt,
+		listObjectTypeValueHistoryStatesStmt:     q.listObjectTypeValueHistoryStatesStmt,
+		listObjectTypeValuesForMergeStmt:         q.listObjectTypeValuesForMergeStmt,
 		listObjectTypesStmt:                      q.listObjectTypesStmt,
 		listObjectsAdvancedStmt:                  q.listObjectsAdvancedStmt,
 		listObjectsByOrgIDStmt:                   q.listObjectsByOrgIDStmt,
 		listObjectsByTaskIDStmt:                  q.listObjectsByTaskIDStmt,
 		listObjectsByTypeWithAdvancedFilterStmt:  q.listObjectsByTypeWithAdvancedFilterStmt,
+		listObjectsForMergeStmt:                  q.listObjectsForMergeStmt,
 		listObjectsWithNormalizedDataStmt:        q.listObjectsWithNormalizedDataStmt,
 		listOrgMembersStmt:                       q.listOrgMembersStmt,
+		listOrgProfilesStmt:                      q.listOrgProfilesStmt,
+		listPendingOrgInvitesStmt:                q.listPendingOrgInvitesStmt,
+		listRolePermissionsByOrgIDStmt:           q.listRolePermissionsByOrgIDStmt,
+		listSSODomainsByOrgIDStmt:                q.listSSODomainsByOrgIDStmt,
 		listStepsByFunnelStmt:                    q.listStepsByFunnelStmt,
 		listTagsStmt:                             q.listTagsStmt,
 		listTasksByObjectIDStmt:                  q.listTasksByObjectIDStmt,
 		listTasksByOrgIDStmt:                     q.listTasksByOrgIDStmt,
 		listTasksWithFilterStmt:                  q.listTasksWithFilterStmt,
+		listTrashStmt:                            q.listTrashStmt,
+		listUnusedRecoveryCodesStmt:              q.listUnusedRecoveryCodesStmt,
+		logImpersonationRequestStmt:              q.logImpersonationRequestStmt,
 		markFeedAsSeenStmt:                       q.markFeedAsSeenStmt,
+		markLoginChallengeUsedStmt:               q.markLoginChallengeUsedStmt,
+		markObjectMergeUndoneStmt:                q.markObjectMergeUndoneStmt,
+		markRefreshTokenUsedStmt:                 q.markRefreshTokenUsedStmt,
 		mergeObjectsStmt:                         q.mergeObjectsStmt,
+		moveMergedLinksBackStmt:                  q.moveMergedLinksBackStmt,
+		purgeTrashStmt:                           q.purgeTrashStmt,
+		recordLoginFailureStmt:                   q.recordLoginFailureStmt,
+		refreshDuplicateSuggestionsStmt:          q.refreshDuplicateSuggestionsStmt,
+		removeCommentReactionStmt:                q.removeCommentReactionStmt,
+		removeMentionedObjectsFromFactStmt:       q.removeMentionedObjectsFromFactStmt,
+		removeMergedTagsStmt:                     q.removeMergedTagsStmt,
 		removeObjectTypeValueStmt:                q.removeObjectTypeValueStmt,
 		removeObjectsFromFactStmt:                q.removeObjectsFromFactStmt,
 		removeObjectsFromTaskStmt:                q.removeObjectsFromTaskStmt,
 		removeTagFromObjectStmt:                  q.removeTagFromObjectStmt,
+		replaceRolePermissionsStmt:               q.replaceRolePermissionsStmt,
+		resolveDuplicateSuggestionStmt:           q.resolveDuplicateSuggestionStmt,
+		restoreMergeTargetStmt:                   q.restoreMergeTargetStmt,
+		restoreMergedFactTextStmt:                q.restoreMergedFactTextStmt,
+		restoreMergedObjectStmt:                  q.restoreMergedObjectStmt,
+		restoreMergedRelationStmt:                q.restoreMergedRelationStmt,
+		restoreMergedTaskTextStmt:                q.restoreMergedTaskTextStmt,
+		restoreTrashedFactStmt:                   q.restoreTrashedFactStmt,
+		restoreTrashedFunnelStmt:                 q.restoreTrashedFunnelStmt,
+		restoreTrashedListStmt:                   q.restoreTrashedListStmt,
+		restoreTrashedObjectStmt:                 q.restoreTrashedObjectStmt,
+		restoreTrashedTagStmt:                    q.restoreTrashedTagStmt,
+		restoreTrashedTaskStmt:                   q.restoreTrashedTaskStmt,
+		revertFactStmt:                           q.revertFactStmt,
+		revokeAPIKeyStmt:                         q.revokeAPIKeyStmt,
+		revokeCreatorSessionsStmt:                q.revokeCreatorSessionsStmt,
+		revokeOrgInviteStmt:                      q.revokeOrgInviteStmt,
+		revokeSessionByIDStmt:                    q.revokeSessionByIDStmt,
+		revokeSessionByJtiStmt:                   q.revokeSessionByJtiStmt,
+		setDuplicateScanStmt:                     q.setDuplicateScanStmt,
+		setObjectPhotoStmt:                       q.setObjectPhotoStmt,
+		snapshotObjectMergeStmt:                  q.snapshotObjectMergeStmt,
 		softDeleteObjStepStmt:                    q.softDeleteObjStepStmt,
 		syncObjectAliasesStmt:                    q.syncObjectAliasesStmt,
+		touchAPIKeyStmt:                          q.touchAPIKeyStmt,
+		touchCreatorIdentityStmt:                 q.touchCreatorIdentityStmt,
+		unlinkFactAttachmentStmt:                 q.unlinkFactAttachmentStmt,
+		unlinkObjectAttachmentStmt:               q.unlinkObjectAttachmentStmt,
+		unlinkTaskAttachmentStmt:                 q.unlinkTaskAttachmentStmt,
+		unlockLoginLockoutsStmt:                  q.unlockLoginLockoutsStmt,
 		updateActionExecutionStmt:                q.updateActionExecutionStmt,
 		updateActionLastRunStmt:                  q.updateActionLastRunStmt,
 		updateAutomatedActionStmt:                q.updateAutomatedActionStmt,
+		updateCommentStmt:                        q.updateCommentStmt,
 		updateCreatorListStmt:                    q.updateCreatorListStmt,
 		updateCreatorPasswordStmt:                q.updateCreatorPasswordStmt,
 		updateCreatorProfileStmt:                 q.updateCreatorProfileStmt,
 		updateCreatorRoleAndStatusStmt:           q.updateCreatorRoleAndStatusStmt,
 		updateFactStmt:                           q.updateFactStmt,
+		updateFactKindStmt:                       q.updateFactKindStmt,
 		updateFunnelStmt:                         q.updateFunnelStmt,
 		updateImportTaskErrorStmt:                q.updateImportTaskErrorStmt,
 		updateImportTaskProgressStmt:             q.updateImportTaskProgressStmt,
 		updateImportTaskStatusStmt:               q.updateImportTaskStatusStmt,
 		updateListStmt:                           q.updateListStmt,
+		updateObjRelationStmt:                    q.updateObjRelationStmt,
+		updateObjRelationTypeStmt:                q.updateObjRelationTypeStmt,
 		updateObjStepStmt:                        q.updateObjStepStmt,
 		updateObjStepSubStatusStmt:               q.updateObjStepSubStatusStmt,
 		updateObjectStmt:                         q.updateObjectStmt,
@@ -1383,6 +2887,12 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		updateTagStmt:                            q.updateTagStmt,
 		updateTaskStmt:                           q.updateTaskStmt,
 		upsertObjectTypeValueStmt:                q.upsertObjectTypeValueStmt,
+		upsertPendingCreatorTOTPStmt:             q.upsertPendingCreatorTOTPStmt,
+		useCreatorTOTPStepStmt:                   q.useCreatorTOTPStepStmt,
+		useCreatorTokenStmt:                      q.useCreatorTokenStmt,
+		useOIDCStateStmt:                         q.useOIDCStateStmt,
+		useRecoveryCodeStmt:                      q.useRecoveryCodeStmt,
+		usernameExistsStmt:                       q.usernameExistsStmt,
 		validateMergeObjectsStmt:                 q.validateMergeObjectsStmt,
 	}
 }
@@ -31,10 +31,9 @@ func (q *Queries) CountObjectsAfterCreatedAt(ctx context.Context, createdAt time
 }
 
 const findObjectByAliasOrIDString = `-- name: FindObjectByAliasOrIDString :one
-SELECT obj.id, obj.name, obj.photo, obj.description, obj.id_string, obj.creator_id, obj.created_at, obj.deleted_at, obj.aliases FROM obj
-JOIN creator c ON o.creator_id = c.id
-WHERE c.org_id = $2 AND 
-id_string = $1 OR $1 = ANY(aliases)
+SELECT obj.id, obj.name, obj.photo, obj.description, obj.id_string, obj.creator_id, obj.created_at, obj.deleted_at, obj.aliases, obj.org_id, obj.photo_attachment_id FROM obj
+WHERE obj.org_id = $2
+AND (id_string = $1 OR $1 = ANY(aliases))
 AND deleted_at IS NULL
 ORDER BY (id_string = $1) DESC
 LIMIT 1
@@ -58,6 +57,8 @@ func (q *Queries) FindObjectByAliasOrIDString(ctx context.Context, arg FindObjec
 		&i.CreatedAt,
 		&i.DeletedAt,
 		pq.Array(&i.Aliases),
+		&i.OrgID,
+		&i.PhotoAttachmentID,
 	)
 	return i, err
 }
@@ -184,15 +184,21 @@ func (q *Queries) GetImportTaskHistory(ctx context.Context, arg GetImportTaskHis
 }
 
 const getObjectByIDString = `-- name: GetObjectByIDString :one
-SELECT id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases FROM obj
-WHERE id_string = $1 OR $1 = ANY(aliases)
+SELECT id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases, org_id, photo_attachment_id FROM obj
+WHERE org_id = $2
+AND (id_string = $1 OR $1 = ANY(aliases))
 AND deleted_at IS NULL
 ORDER BY (id_string = $1) DESC
 LIMIT 1
 `
 
-func (q *Queries) GetObjectByIDString(ctx context.Context, idString string) (Obj, error) {
-	row := q.queryRow(ctx, q.getObjectByIDStringStmt, getObjectByIDString, idString)
+type GetObjectByIDStringParams struct {
+	IDString string    `json:"id_string"`
+	OrgID    uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetObjectByIDString(ctx context.Context, arg GetObjectByIDStringParams) (Obj, error) {
+	row := q.queryRow(ctx, q.getObjectByIDStringStmt, getObjectByIDString, arg.IDString, arg.OrgID)
 	var i Obj
 	err := row.Scan(
 		&i.ID,
@@ -204,6 +210,8 @@ func (q *Queries) GetObjectByIDString(ctx context.Context, idString string) (Obj
 		&i.CreatedAt,
 		&i.DeletedAt,
 		pq.Array(&i.Aliases),
+		&i.OrgID,
+		&i.PhotoAttachmentID,
 	)
 	return i, err
 }
@@ -30,17 +30,22 @@ func (q *Queries) CountListsByOrgID(ctx context.Context, orgID uuid.UUID) (int64
 
 const createCreatorList = `-- name: CreateCreatorList :one
 INSERT INTO creator_list (creator_id, list_id, params)
-VALUES ($1, $2, '{}')
+SELECT $1, $2, '{}'
+WHERE EXISTS (
+  SELECT 1 FROM list l
+  WHERE l.id = $2 AND l.org_id = $3 AND l.deleted_at IS NULL
+)
 RETURNING id, creator_id, list_id, params, created_at, last_updated
 `
 
 type CreateCreatorListParams struct {
 	CreatorID uuid.UUID `json:"creator_id"`
 	ListID    uuid.UUID `json:"list_id"`
+	OrgID     uuid.UUID `json:"org_id"`
 }
 
 func (q *Queries) CreateCreatorList(ctx context.Context, arg CreateCreatorListParams) (CreatorList, error) {
-	row := q.queryRow(ctx, q.createCreatorListStmt, createCreatorList, arg.CreatorID, arg.ListID)
+	row := q.queryRow(ctx, q.createCreatorListStmt, createCreatorList, arg.CreatorID, arg.ListID, arg.OrgID)
 	var i CreatorList
 	err := row.Scan(
 		&i.ID,
@@ -56,7 +61,7 @@ func (q *Queries) CreateCreatorList(ctx context.Context, arg CreateCreatorListPa
 const createList = `-- name: CreateList :one
 INSERT INTO list (name, description, filter_setting, creator_id)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, description, filter_setting, creator_id, created_at, last_updated, deleted_at
+RETURNING id, name, description, filter_setting, creator_id, created_at, last_updated, deleted_at, org_id
 `
 
 type CreateListParams struct {
@@ -83,23 +88,33 @@ func (q *Queries) CreateList(ctx context.Context, arg CreateListParams) (List, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
 
-const deleteCreatorList = `-- name: DeleteCreatorList :exec
+const deleteCreatorList = `-- name: DeleteCreatorList :execrows
 DELETE FROM creator_list
-WHERE id=$1
+WHERE id=$1 AND creator_id=$2
 `
 
-func (q *Queries) DeleteCreatorList(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteCreatorListStmt, deleteCreatorList, id)
-	return err
+type DeleteCreatorListParams struct {
+	ID        uuid.UUID `json:"id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+}
+
+func (q *Queries) DeleteCreatorList(ctx context.Context, arg DeleteCreatorListParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteCreatorListStmt, deleteCreatorList, arg.ID, arg.CreatorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteList = `-- name: DeleteList :exec
-DELETE FROM list
-WHERE list.id = $1
+const deleteList = `-- name: DeleteList :execrows
+UPDATE list
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE list.id = $1 AND list.org_id = $2 AND list.deleted_at IS NULL
 AND NOT EXISTS (
   SELECT 1
   FROM creator_list
@@ -107,18 +122,31 @@ AND NOT EXISTS (
 )
 `
 
-func (q *Queries) DeleteList(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteListStmt, deleteList, id)
-	return err
+type DeleteListParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteList(ctx context.Context, arg DeleteListParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteListStmt, deleteList, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getCreatorListByID = `-- name: GetCreatorListByID :one
 SELECT cl.id, cl.creator_id, cl.list_id, cl.params, cl.created_at, cl.last_updated, l.name as list_name, l.description as list_description, l.filter_setting as list_filter_setting
 FROM creator_list cl
 JOIN list l ON cl.list_id = l.id
-WHERE cl.id = $1 AND l.deleted_at IS NULL
+WHERE cl.id = $1 AND cl.creator_id = $2 AND l.deleted_at IS NULL
 `
 
+type GetCreatorListByIDParams struct {
+	ID        uuid.UUID `json:"id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+}
+
 type GetCreatorListByIDRow struct {
 	ID                uuid.UUID       `json:"id"`
 	CreatorID         uuid.UUID       `json:"creator_id"`
@@ -131,8 +159,8 @@ type GetCreatorListByIDRow struct {
 	ListFilterSetting json.RawMessage `json:"list_filter_setting"`
 }
 
-func (q *Queries) GetCreatorListByID(ctx context.Context, id uuid.UUID) (GetCreatorListByIDRow, error) {
-	row := q.queryRow(ctx, q.getCreatorListByIDStmt, getCreatorListByID, id)
+func (q *Queries) GetCreatorListByID(ctx context.Context, arg GetCreatorListByIDParams) (GetCreatorListByIDRow, error) {
+	row := q.queryRow(ctx, q.getCreatorListByIDStmt, getCreatorListByID, arg.ID, arg.CreatorID)
 	var i GetCreatorListByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -151,11 +179,17 @@ func (q *Queries) GetCreatorListByID(ctx context.Context, id uuid.UUID) (GetCrea
 const getListByID = `-- name: GetListByID :one
 SELECT l.id
 FROM list l
-WHERE l.id = $1 AND l.deleted_at IS NULL
+WHERE l.id = $1 AND l.org_id = $2 AND l.deleted_at IS NULL
 `
 
-func (q *Queries) GetListByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
-	row := q.queryRow(ctx, q.getListByIDStmt, getListByID, id)
+type GetListByIDParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetListByID(ctx context.Context, arg GetListByIDParams) (uuid.UUID, error) {
+	row := q.queryRow(ctx, q.getListByIDStmt, getListByID, arg.ID, arg.OrgID)
+	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
@@ -214,7 +248,7 @@ func (q *Queries) ListCreatorListsByCreatorID(ctx context.Context, creatorID uui
 }
 
 const listListsByOrgID = `-- name: ListListsByOrgID :many
-SELECT l.id, l.name, l.description, l.filter_setting, l.creator_id, l.created_at, l.last_updated, l.deleted_at, c.username as creator_name
+SELECT l.id, l.name, l.description, l.filter_setting, l.creator_id, l.created_at, l.last_updated, l.deleted_at, l.org_id, c.username as creator_name
 FROM list l
 JOIN creator c ON l.creator_id = c.id
 WHERE c.org_id = $1 AND l.deleted_at IS NULL
@@ -237,6 +271,7 @@ type ListListsByOrgIDRow struct {
 	CreatedAt     time.Time       `json:"created_at"`
 	LastUpdated   time.Time       `json:"last_updated"`
 	DeletedAt     sql.NullTime    `json:"deleted_at"`
+	OrgID         uuid.UUID       `json:"org_id"`
 	CreatorName   string          `json:"creator_name"`
 }
 
@@ -258,6 +293,7 @@ func (q *Queries) ListListsByOrgID(ctx context.Context, arg ListListsByOrgIDPara
 			&i.CreatedAt,
 			&i.LastUpdated,
 			&i.DeletedAt,
+			&i.OrgID,
 			&i.CreatorName,
 		); err != nil {
 			return nil, err
@@ -276,17 +312,18 @@ func (q *Queries) ListListsByOrgID(ctx context.Context, arg ListListsByOrgIDPara
 const updateCreatorList = `-- name: UpdateCreatorList :one
 UPDATE creator_list
 SET params = $2, last_updated = CURRENT_TIMESTAMP
-WHERE id=$1
+WHERE id=$1 AND creator_id=$3
 RETURNING id, creator_id, list_id, params, created_at, last_updated
 `
 
 type UpdateCreatorListParams struct {
-	ID     uuid.UUID       `json:"id"`
-	Params json.RawMessage `json:"params"`
+	ID        uuid.UUID       `json:"id"`
+	Params    json.RawMessage `json:"params"`
+	CreatorID uuid.UUID       `json:"creator_id"`
 }
 
 func (q *Queries) UpdateCreatorList(ctx context.Context, arg UpdateCreatorListParams) (CreatorList, error) {
-	row := q.queryRow(ctx, q.updateCreatorListStmt, updateCreatorList, arg.ID, arg.Params)
+	row := q.queryRow(ctx, q.updateCreatorListStmt, updateCreatorList, arg.ID, arg.Params, arg.CreatorID)
 	var i CreatorList
 	err := row.Scan(
 		&i.ID,
@@ -302,8 +339,8 @@ func (q *Queries) UpdateCreatorList(ctx context.Context, arg UpdateCreatorListPa
 const updateList = `-- name: UpdateList :one
 UPDATE list
 SET name = $2, description = $3, filter_setting = $4, last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, description, filter_setting, creator_id, created_at, last_updated, deleted_at
+WHERE id = $1 AND org_id = $5 AND deleted_at IS NULL
+RETURNING id, name, description, filter_setting, creator_id, created_at, last_updated, deleted_at, org_id
 `
 
 type UpdateListParams struct {
@@ -311,6 +348,7 @@ type UpdateListParams struct {
 	Name          string          `json:"name"`
 	Description   string          `json:"description"`
 	FilterSetting json.RawMessage `json:"filter_setting"`
+	OrgID         uuid.UUID       `json:"org_id"`
 }
 
 func (q *Queries) UpdateList(ctx context.Context, arg UpdateListParams) (List, error) {
@@ -319,6 +357,7 @@ func (q *Queries) UpdateList(ctx context.Context, arg UpdateListParams) (List, e
 		arg.Name,
 		arg.Description,
 		arg.FilterSetting,
+		arg.OrgID,
 	)
 	var i List
 	err := row.Scan(
@@ -330,6 +369,7 @@ func (q *Queries) UpdateList(ctx context.Context, arg UpdateListParams) (List, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
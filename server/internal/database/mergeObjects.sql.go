@@ -7,12 +7,435 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/sqlc-dev/pqtype"
 )
 
-const mergeObjects = `-- name: MergeObjects :exec
+const countObjectMergeMoves = `-- name: CountObjectMergeMoves :one
+SELECT
+    (SELECT COUNT(DISTINCT lf.fact_id)
+     FROM obj_fact lf
+     JOIN obj o ON o.id = lf.obj_id
+     WHERE lf.obj_id = ANY($1::uuid[]) AND o.org_id = $2
+    )::int AS facts,
+    (SELECT COUNT(DISTINCT lt.task_id)
+     FROM obj_task lt
+     JOIN obj o ON o.id = lt.obj_id
+     WHERE lt.obj_id = ANY($1::uuid[]) AND o.org_id = $2
+    )::int AS tasks,
+    (SELECT COUNT(DISTINCT lg.tag_id)
+     FROM obj_tag lg
+     JOIN obj o ON o.id = lg.obj_id
+     WHERE lg.obj_id = ANY($1::uuid[]) AND o.org_id = $2
+     AND NOT EXISTS (
+        SELECT 1 FROM obj_tag t
+        WHERE t.obj_id = $3 AND t.tag_id = lg.tag_id
+     )
+    )::int AS tags,
+    (SELECT COUNT(*)
+     FROM obj_step ls
+     JOIN obj o ON o.id = ls.obj_id
+     WHERE ls.obj_id = ANY($1::uuid[]) AND o.org_id = $2
+     AND ls.deleted_at IS NULL
+     AND NOT EXISTS (
+        SELECT 1 FROM obj_step t
+        WHERE t.obj_id = $3 AND t.step_id = ls.step_id AND t.deleted_at IS NULL
+     )
+    )::int AS steps,
+    (SELECT COUNT(*)
+     FROM obj_relation r
+     WHERE (r.from_obj_id = ANY($1::uuid[]) OR r.to_obj_id = ANY($1::uuid[]))
+     AND r.org_id = $2
+     AND r.deleted_at IS NULL
+     -- Relationships among the merged objects are dropped
+     AND NOT (r.from_obj_id = ANY(array_append($1::uuid[], $3))
+        AND r.to_obj_id = ANY(array_append($1::uuid[], $3)))
+    )::int AS relations
+`
+
+type CountObjectMergeMovesParams struct {
+	SourceObjectIds []uuid.UUID `json:"source_object_ids"`
+	OrgID           uuid.UUID   `json:"org_id"`
+	TargetObjectID  uuid.UUID   `json:"target_object_id"`
+}
+
+type CountObjectMergeMovesRow struct {
+	Facts     int32 `json:"facts"`
+	Tasks     int32 `json:"tasks"`
+	Tags      int32 `json:"tags"`
+	Steps     int32 `json:"steps"`
+	Relations int32 `json:"relations"`
+}
+
+// What MergeObjects would move from the sources to the target
+func (q *Queries) CountObjectMergeMoves(ctx context.Context, arg CountObjectMergeMovesParams) (CountObjectMergeMovesRow, error) {
+	row := q.queryRow(ctx, q.countObjectMergeMovesStmt, countObjectMergeMoves, pq.Array(arg.SourceObjectIds), arg.OrgID, arg.TargetObjectID)
+	var i CountObjectMergeMovesRow
+	err := row.Scan(
+		&i.Facts,
+		&i.Tasks,
+		&i.Tags,
+		&i.Steps,
+		&i.Relations,
+	)
+	return i, err
+}
+
+const countPurgedMergeSources = `-- name: CountPurgedMergeSources :one
+SELECT COUNT(*)::int AS purged
+FROM unnest($1::uuid[]) s (id)
+WHERE NOT EXISTS (SELECT 1 FROM obj o WHERE o.id = s.id)
+`
+
+// Sources of a merge that were purged from the trash since
+func (q *Queries) CountPurgedMergeSources(ctx context.Context, sourceObjectIds []uuid.UUID) (int32, error) {
+	row := q.queryRow(ctx, q.countPurgedMergeSourcesStmt, countPurgedMergeSources, pq.Array(sourceObjectIds))
+	var purged int32
+	err := row.Scan(&purged)
+	return purged, err
+}
+
+const getObjectMergeHistory = `-- name: GetObjectMergeHistory :one
+SELECT id, target_object_id, source_object_ids, merged_at, creator_id, created_at, org_id, snapshot, undone_at, undone_by FROM object_merge_history
+WHERE id = $1 AND org_id = $2
+FOR UPDATE
+`
+
+type GetObjectMergeHistoryParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetObjectMergeHistory(ctx context.Context, arg GetObjectMergeHistoryParams) (ObjectMergeHistory, error) {
+	row := q.queryRow(ctx, q.getObjectMergeHistoryStmt, getObjectMergeHistory, arg.ID, arg.OrgID)
+	var i ObjectMergeHistory
+	err := row.Scan(
+		&i.ID,
+		&i.TargetObjectID,
+		pq.Array(&i.SourceObjectIds),
+		&i.MergedAt,
+		&i.CreatorID,
+		&i.CreatedAt,
+		&i.OrgID,
+		&i.Snapshot,
+		&i.UndoneAt,
+		&i.UndoneBy,
+	)
+	return i, err
+}
+
+const listMergeConflictEvents = `-- name: ListMergeConflictEvents :many
+SELECT e.entity_type, e.entity_id, e.action, e.before, e.after, e.created_at,
+  COALESCE(a.username, '')::text AS actor_username
+FROM audit_event e
+LEFT JOIN creator a ON e.actor_id = a.id
+WHERE e.org_id = $1
+  AND e.entity_id = ANY($2::uuid[])
+  AND e.entity_type IN ('obj', 'obj_type_value', 'fact', 'task', 'obj_relation')
+  AND e.created_at > $3
+ORDER BY e.seq
+`
+
+type ListMergeConflictEventsParams struct {
+	OrgID     uuid.UUID   `json:"org_id"`
+	EntityIds []uuid.UUID `json:"entity_ids"`
+	MergedAt  time.Time   `json:"merged_at"`
+}
+
+type ListMergeConflictEventsRow struct {
+	EntityType    string                `json:"entity_type"`
+	EntityID      uuid.UUID             `json:"entity_id"`
+	Action        string                `json:"action"`
+	Before        pqtype.NullRawMessage `json:"before"`
+	After         pqtype.NullRawMessage `json:"after"`
+	CreatedAt     time.Time             `json:"created_at"`
+	ActorUsername string                `json:"actor_username"`
+}
+
+// Changes made after a merge to what undoing it would set back
+func (q *Queries) ListMergeConflictEvents(ctx context.Context, arg ListMergeConflictEventsParams) ([]ListMergeConflictEventsRow, error) {
+	rows, err := q.query(ctx, q.listMergeConflictEventsStmt, listMergeConflictEvents, arg.OrgID, pq.Array(arg.EntityIds), arg.MergedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMergeConflictEventsRow
+	for rows.Next() {
+		var i ListMergeConflictEventsRow
+		if err := rows.Scan(
+			&i.EntityType,
+			&i.EntityID,
+			&i.Action,
+			&i.Before,
+			&i.After,
+			&i.CreatedAt,
+			&i.ActorUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listObjectMergeHistory = `-- name: ListObjectMergeHistory :many
+SELECT h.id, h.target_object_id, h.source_object_ids, h.merged_at, h.creator_id,
+  COALESCE(c.username, '')::text AS creator_username, h.undone_at, h.undone_by,
+  (h.snapshot IS NOT NULL AND h.undone_at IS NULL AND NOT EXISTS (
+    SELECT 1 FROM unnest(h.source_object_ids) s (id)
+    WHERE NOT EXISTS (SELECT 1 FROM obj o WHERE o.id = s.id)
+  ))::boolean AS undoable
+FROM object_merge_history h
+LEFT JOIN creator c ON h.creator_id = c.id
+WHERE h.org_id = $1
+  AND ($2::uuid IS NULL
+    OR h.target_object_id = $2
+    OR $2 = ANY(h.source_object_ids))
+ORDER BY h.merged_at DESC
+LIMIT $3
+`
+
+type ListObjectMergeHistoryParams struct {
+	OrgID    uuid.UUID     `json:"org_id"`
+	ObjectID uuid.NullUUID `json:"object_id"`
+	Limit    int32         `json:"limit"`
+}
+
+type ListObjectMergeHistoryRow struct {
+	ID              uuid.UUID     `json:"id"`
+	TargetObjectID  uuid.UUID     `json:"target_object_id"`
+	SourceObjectIds []uuid.UUID   `json:"source_object_ids"`
+	MergedAt        time.Time     `json:"merged_at"`
+	CreatorID       uuid.UUID     `json:"creator_id"`
+	CreatorUsername string        `json:"creator_username"`
+	UndoneAt        sql.NullTime  `json:"undone_at"`
+	UndoneBy        uuid.NullUUID `json:"undone_by"`
+	Undoable        bool          `json:"undoable"`
+}
+
+func (q *Queries) ListObjectMergeHistory(ctx context.Context, arg ListObjectMergeHistoryParams) ([]ListObjectMergeHistoryRow, error) {
+	rows, err := q.query(ctx, q.listObjectMergeHistoryStmt, listObjectMergeHistory, arg.OrgID, arg.ObjectID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListObjectMergeHistoryRow
+	for rows.Next() {
+		var i ListObjectMergeHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TargetObjectID,
+			pq.Array(&i.SourceObjectIds),
+			&i.MergedAt,
+			&i.CreatorID,
+			&i.CreatorUsername,
+			&i.UndoneAt,
+			&i.UndoneBy,
+			&i.Undoable,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listObjectStepsForMerge = `-- name: ListObjectStepsForMerge :many
+SELECT os.obj_id, s.id AS step_id, s.name AS step_name, f.id AS funnel_id, f.name AS funnel_name
+FROM obj_step os
+JOIN step s ON s.id = os.step_id
+JOIN funnel f ON f.id = s.funnel_id
+JOIN obj o ON o.id = os.obj_id
+WHERE os.obj_id = ANY($1::uuid[])
+  AND o.org_id = $2
+  AND os.deleted_at IS NULL
+  AND f.deleted_at IS NULL
+ORDER BY f.name, f.id, s.step_order
+`
+
+type ListObjectStepsForMergeParams struct {
+	ObjectIds []uuid.UUID `json:"object_ids"`
+	OrgID     uuid.UUID   `json:"org_id"`
+}
+
+type ListObjectStepsForMergeRow struct {
+	ObjID      uuid.UUID `json:"obj_id"`
+	StepID     uuid.UUID `json:"step_id"`
+	StepName   string    `json:"step_name"`
+	FunnelID   uuid.UUID `json:"funnel_id"`
+	FunnelName string    `json:"funnel_name"`
+}
+
+func (q *Queries) ListObjectStepsForMerge(ctx context.Context, arg ListObjectStepsForMergeParams) ([]ListObjectStepsForMergeRow, error) {
+	rows, err := q.query(ctx, q.listObjectStepsForMergeStmt, listObjectStepsForMerge, pq.Array(arg.ObjectIds), arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListObjectStepsForMergeRow
+	for rows.Next() {
+		var i ListObjectStepsForMergeRow
+		if err := rows.Scan(
+			&i.ObjID,
+			&i.StepID,
+			&i.StepName,
+			&i.FunnelID,
+			&i.FunnelName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listObjectTypeValuesForMerge = `-- name: ListObjectTypeValuesForMerge :many
+SELECT otv.obj_id, otv.type_id, ot.name AS type_name, otv.type_values
+FROM obj_type_value otv
+JOIN obj_type ot ON ot.id = otv.type_id
+JOIN obj o ON o.id = otv.obj_id
+WHERE otv.obj_id = ANY($1::uuid[])
+  AND o.org_id = $2
+  AND otv.deleted_at IS NULL
+ORDER BY ot.name, ot.id
+`
+
+type ListObjectTypeValuesForMergeParams struct {
+	ObjectIds []uuid.UUID `json:"object_ids"`
+	OrgID     uuid.UUID   `json:"org_id"`
+}
+
+type ListObjectTypeValuesForMergeRow struct {
+	ObjID      uuid.UUID       `json:"obj_id"`
+	TypeID     uuid.UUID       `json:"type_id"`
+	TypeName   string          `json:"type_name"`
+	TypeValues json.RawMessage `json:"type_values"`
+}
+
+func (q *Queries) ListObjectTypeValuesForMerge(ctx context.Context, arg ListObjectTypeValuesForMergeParams) ([]ListObjectTypeValuesForMergeRow, error) {
+	rows, err := q.query(ctx, q.listObjectTypeValuesForMergeStmt, listObjectTypeValuesForMerge, pq.Array(arg.ObjectIds), arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListObjectTypeValuesForMergeRow
+	for rows.Next() {
+		var i ListObjectTypeValuesForMergeRow
+		if err := rows.Scan(
+			&i.ObjID,
+			&i.TypeID,
+			&i.TypeName,
+			&i.TypeValues,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listObjectsForMerge = `-- name: ListObjectsForMerge :many
+SELECT o.id, o.name, o.description, o.id_string, o.aliases
+FROM obj o
+WHERE o.id = ANY($1::uuid[])
+  AND o.org_id = $2
+  AND o.deleted_at IS NULL
+`
+
+type ListObjectsForMergeParams struct {
+	ObjectIds []uuid.UUID `json:"object_ids"`
+	OrgID     uuid.UUID   `json:"org_id"`
+}
+
+type ListObjectsForMergeRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IDString    string    `json:"id_string"`
+	Aliases     []string  `json:"aliases"`
+}
+
+func (q *Queries) ListObjectsForMerge(ctx context.Context, arg ListObjectsForMergeParams) ([]ListObjectsForMergeRow, error) {
+	rows, err := q.query(ctx, q.listObjectsForMergeStmt, listObjectsForMerge, pq.Array(arg.ObjectIds), arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListObjectsForMergeRow
+	for rows.Next() {
+		var i ListObjectsForMergeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.IDString,
+			pq.Array(&i.Aliases),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markObjectMergeUndone = `-- name: MarkObjectMergeUndone :execrows
+UPDATE object_merge_history
+SET undone_at = CURRENT_TIMESTAMP,
+    undone_by = $2
+WHERE id = $1 AND org_id = $3
+  AND undone_at IS NULL
+`
+
+type MarkObjectMergeUndoneParams struct {
+	ID       uuid.UUID     `json:"id"`
+	UndoneBy uuid.NullUUID `json:"undone_by"`
+	OrgID    uuid.UUID     `json:"org_id"`
+}
+
+func (q *Queries) MarkObjectMergeUndone(ctx context.Context, arg MarkObjectMergeUndoneParams) (int64, error) {
+	result, err := q.exec(ctx, q.markObjectMergeUndoneStmt, markObjectMergeUndone, arg.ID, arg.UndoneBy, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const mergeObjects = `-- name: MergeObjects :one
 WITH target_org AS (
     -- Get organization ID for the target object once
     SELECT c.org_id
@@ -179,6 +602,27 @@ update_task_text AS (
     )
     RETURNING 1
 ),
+update_relations AS (
+    UPDATE obj_relation r
+    SET from_obj_id = CASE WHEN r.from_obj_id = ANY($2::uuid[]) THEN $1 ELSE r.from_obj_id END,
+        to_obj_id = CASE WHEN r.to_obj_id = ANY($2::uuid[]) THEN $1 ELSE r.to_obj_id END,
+        last_updated = CURRENT_TIMESTAMP
+    WHERE (r.from_obj_id = ANY($2::uuid[]) OR r.to_obj_id = ANY($2::uuid[]))
+    AND NOT (r.from_obj_id = ANY(array_append($2::uuid[], $1)) AND r.to_obj_id = ANY(array_append($2::uuid[], $1)))
+    AND r.deleted_at IS NULL
+    AND r.org_id IN (SELECT org_id FROM target_org)
+    RETURNING 1
+),
+delete_inner_relations AS (
+    UPDATE obj_relation r
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE (r.from_obj_id = ANY($2::uuid[]) OR r.to_obj_id = ANY($2::uuid[]))
+    AND r.from_obj_id = ANY(array_append($2::uuid[], $1))
+    AND r.to_obj_id = ANY(array_append($2::uuid[], $1))
+    AND r.deleted_at IS NULL
+    AND r.org_id IN (SELECT org_id FROM target_org)
+    RETURNING 1
+),
 mark_deleted AS (
     UPDATE obj o
     SET deleted_at = CURRENT_TIMESTAMP,
@@ -197,16 +641,19 @@ INSERT INTO object_merge_history (
     target_object_id,
     source_object_ids,
     merged_at,
-    creator_id
+    creator_id,
+    org_id,
+    snapshot
 )
-VALUES ($1, $2, CURRENT_TIMESTAMP, $3)
+VALUES ($1, $2, CURRENT_TIMESTAMP, $3, (SELECT org_id FROM target_org), $4)
 RETURNING id
 `
 
 type MergeObjectsParams struct {
-	TargetObjectID  uuid.UUID   `json:"target_object_id"`
-	SourceObjectIds []uuid.UUID `json:"source_object_ids"`
-	CreatorID       uuid.UUID   `json:"creator_id"`
+	TargetObjectID  uuid.UUID             `json:"target_object_id"`
+	SourceObjectIds []uuid.UUID           `json:"source_object_ids"`
+	CreatorID       uuid.UUID             `json:"creator_id"`
+	Snapshot        pqtype.NullRawMessage `json:"snapshot"`
 }
 
 // Update fact references
@@ -216,11 +663,293 @@ type MergeObjectsParams struct {
 // Update type values references
 // Update fact text
 // Update task text
+// Move relationships of the sources to the target
+// Relationships among the merged objects would relate the target to itself
 // Mark source objects as deleted
 // Create merge history record
-func (q *Queries) MergeObjects(ctx context.Context, arg MergeObjectsParams) error {
-	_, err := q.exec(ctx, q.mergeObjectsStmt, mergeObjects, arg.TargetObjectID, pq.Array(arg.SourceObjectIds), arg.CreatorID)
-	return err
+func (q *Queries) MergeObjects(ctx context.Context, arg MergeObjectsParams) (uuid.UUID, error) {
+	row := q.queryRow(ctx, q.mergeObjectsStmt, mergeObjects,
+		arg.TargetObjectID,
+		pq.Array(arg.SourceObjectIds),
+		arg.CreatorID,
+		arg.Snapshot,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const moveMergedLinksBack = `-- name: MoveMergedLinksBack :one
+WITH target AS (
+    SELECT o.id FROM obj o
+    WHERE o.id = $1 AND o.org_id = $2
+),
+moved_facts AS (
+    UPDATE obj_fact
+    SET obj_id = $3
+    WHERE obj_id IN (SELECT id FROM target)
+    AND fact_id = ANY($4::uuid[])
+    RETURNING 1
+),
+moved_tasks AS (
+    UPDATE obj_task
+    SET obj_id = $3
+    WHERE obj_id IN (SELECT id FROM target)
+    AND task_id = ANY($5::uuid[])
+    RETURNING 1
+),
+moved_steps AS (
+    UPDATE obj_step
+    SET obj_id = $3,
+        last_updated = CURRENT_TIMESTAMP
+    WHERE obj_id IN (SELECT id FROM target)
+    AND id = ANY($6::uuid[])
+    RETURNING 1
+)
+SELECT
+    (SELECT COUNT(*) FROM moved_facts)::int AS facts,
+    (SELECT COUNT(*) FROM moved_tasks)::int AS tasks,
+    (SELECT COUNT(*) FROM moved_steps)::int AS steps
+`
+
+type MoveMergedLinksBackParams struct {
+	TargetObjectID uuid.UUID   `json:"target_object_id"`
+	OrgID          uuid.UUID   `json:"org_id"`
+	SourceObjectID uuid.UUID   `json:"source_object_id"`
+	FactIds        []uuid.UUID `json:"fact_ids"`
+	TaskIds        []uuid.UUID `json:"task_ids"`
+	StepIds        []uuid.UUID `json:"step_ids"`
+}
+
+type MoveMergedLinksBackRow struct {
+	Facts int32 `json:"facts"`
+	Tasks int32 `json:"tasks"`
+	Steps int32 `json:"steps"`
+}
+
+func (q *Queries) MoveMergedLinksBack(ctx context.Context, arg MoveMergedLinksBackParams) (MoveMergedLinksBackRow, error) {
+	row := q.queryRow(ctx, q.moveMergedLinksBackStmt, moveMergedLinksBack,
+		arg.TargetObjectID,
+		arg.OrgID,
+		arg.SourceObjectID,
+		pq.Array(arg.FactIds),
+		pq.Array(arg.TaskIds),
+		pq.Array(arg.StepIds),
+	)
+	var i MoveMergedLinksBackRow
+	err := row.Scan(&i.Facts, &i.Tasks, &i.Steps)
+	return i, err
+}
+
+const removeMergedTags = `-- name: RemoveMergedTags :execrows
+DELETE FROM obj_tag
+WHERE obj_id = $1
+  AND tag_id = ANY($2::uuid[])
+  AND EXISTS (
+    SELECT 1 FROM obj o
+    WHERE o.id = obj_tag.obj_id AND o.org_id = $3
+  )
+`
+
+type RemoveMergedTagsParams struct {
+	TargetObjectID uuid.UUID   `json:"target_object_id"`
+	TagIds         []uuid.UUID `json:"tag_ids"`
+	OrgID          uuid.UUID   `json:"org_id"`
+}
+
+func (q *Queries) RemoveMergedTags(ctx context.Context, arg RemoveMergedTagsParams) (int64, error) {
+	result, err := q.exec(ctx, q.removeMergedTagsStmt, removeMergedTags, arg.TargetObjectID, pq.Array(arg.TagIds), arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreMergeTarget = `-- name: RestoreMergeTarget :execrows
+UPDATE obj
+SET name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    id_string = COALESCE($3, id_string),
+    aliases = COALESCE($4::text[], aliases)
+WHERE id = $5
+  AND org_id = $6
+`
+
+type RestoreMergeTargetParams struct {
+	Name        sql.NullString `json:"name"`
+	Description sql.NullString `json:"description"`
+	IDString    sql.NullString `json:"id_string"`
+	Aliases     []string       `json:"aliases"`
+	ID          uuid.UUID      `json:"id"`
+	OrgID       uuid.UUID      `json:"org_id"`
+}
+
+// Sets back the fields of the target of a merge, null fields are kept
+func (q *Queries) RestoreMergeTarget(ctx context.Context, arg RestoreMergeTargetParams) (int64, error) {
+	result, err := q.exec(ctx, q.restoreMergeTargetStmt, restoreMergeTarget,
+		arg.Name,
+		arg.Description,
+		arg.IDString,
+		pq.Array(arg.Aliases),
+		arg.ID,
+		arg.OrgID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreMergedFactText = `-- name: RestoreMergedFactText :execrows
+UPDATE fact
+SET text = $2
+WHERE id = $1 AND org_id = $3
+`
+
+type RestoreMergedFactTextParams struct {
+	ID    uuid.UUID `json:"id"`
+	Text  string    `json:"text"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) RestoreMergedFactText(ctx context.Context, arg RestoreMergedFactTextParams) (int64, error) {
+	result, err := q.exec(ctx, q.restoreMergedFactTextStmt, restoreMergedFactText, arg.ID, arg.Text, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreMergedObject = `-- name: RestoreMergedObject :execrows
+UPDATE obj
+SET deleted_at = NULL,
+    id_string = COALESCE($1, id_string)
+WHERE id = $2
+  AND org_id = $3
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreMergedObjectParams struct {
+	IDString sql.NullString `json:"id_string"`
+	ID       uuid.UUID      `json:"id"`
+	OrgID    uuid.UUID      `json:"org_id"`
+}
+
+func (q *Queries) RestoreMergedObject(ctx context.Context, arg RestoreMergedObjectParams) (int64, error) {
+	result, err := q.exec(ctx, q.restoreMergedObjectStmt, restoreMergedObject, arg.IDString, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreMergedTaskText = `-- name: RestoreMergedTaskText :execrows
+UPDATE task
+SET content = $2
+WHERE id = $1 AND org_id = $3
+`
+
+type RestoreMergedTaskTextParams struct {
+	ID      uuid.UUID `json:"id"`
+	Content string    `json:"content"`
+	OrgID   uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) RestoreMergedTaskText(ctx context.Context, arg RestoreMergedTaskTextParams) (int64, error) {
+	result, err := q.exec(ctx, q.restoreMergedTaskTextStmt, restoreMergedTaskText, arg.ID, arg.Content, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const snapshotObjectMerge = `-- name: SnapshotObjectMerge :one
+SELECT jsonb_build_object(
+    'target', (
+        SELECT audit_row(to_jsonb(o)) FROM obj o
+        WHERE o.id = $1 AND o.org_id = $2
+    ),
+    'sources', COALESCE((
+        SELECT jsonb_agg(audit_row(to_jsonb(o))) FROM obj o
+        WHERE o.id = ANY($3::uuid[]) AND o.org_id = $2
+    ), '[]'),
+    'type_values', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', otv.id, 'obj_id', otv.obj_id, 'type_id', otv.type_id, 'type_values', otv.type_values))
+        FROM obj_type_value otv
+        JOIN obj o ON o.id = otv.obj_id
+        WHERE (otv.obj_id = $1 OR otv.obj_id = ANY($3::uuid[]))
+        AND o.org_id = $2
+        AND otv.deleted_at IS NULL
+    ), '[]'),
+    'facts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lf.obj_id, 'id', lf.fact_id))
+        FROM obj_fact lf
+        JOIN obj o ON o.id = lf.obj_id
+        WHERE lf.obj_id = ANY($3::uuid[]) AND o.org_id = $2
+    ), '[]'),
+    'tasks', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lt.obj_id, 'id', lt.task_id))
+        FROM obj_task lt
+        JOIN obj o ON o.id = lt.obj_id
+        WHERE lt.obj_id = ANY($3::uuid[]) AND o.org_id = $2
+    ), '[]'),
+    'steps', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', ls.obj_id, 'id', ls.id))
+        FROM obj_step ls
+        JOIN obj o ON o.id = ls.obj_id
+        WHERE ls.obj_id = ANY($3::uuid[]) AND o.org_id = $2
+        AND ls.deleted_at IS NULL
+    ), '[]'),
+    'tags', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lg.obj_id, 'id', lg.tag_id))
+        FROM obj_tag lg
+        JOIN obj o ON o.id = lg.obj_id
+        WHERE (lg.obj_id = $1 OR lg.obj_id = ANY($3::uuid[]))
+        AND o.org_id = $2
+    ), '[]'),
+    'relations', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', r.id, 'from_obj_id', r.from_obj_id, 'to_obj_id', r.to_obj_id))
+        FROM obj_relation r
+        WHERE (r.from_obj_id = ANY($3::uuid[]) OR r.to_obj_id = ANY($3::uuid[]))
+        AND r.org_id = $2
+        AND r.deleted_at IS NULL
+    ), '[]'),
+    'fact_texts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', f.id, 'text', f.text))
+        FROM fact f
+        WHERE f.org_id = $2
+        AND f.deleted_at IS NULL
+        AND f.text LIKE ANY(
+            SELECT '%' || source_id::text || '%'
+            FROM unnest($3::uuid[]) AS source_id
+        )
+    ), '[]'),
+    'task_texts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', t.id, 'text', t.content))
+        FROM task t
+        WHERE t.org_id = $2
+        AND t.deleted_at IS NULL
+        AND t.content LIKE ANY(
+            SELECT '%' || source_id::text || '%'
+            FROM unnest($3::uuid[]) AS source_id
+        )
+    ), '[]')
+)::jsonb AS snapshot
+`
+
+type SnapshotObjectMergeParams struct {
+	TargetObjectID  uuid.UUID   `json:"target_object_id"`
+	OrgID           uuid.UUID   `json:"org_id"`
+	SourceObjectIds []uuid.UUID `json:"source_object_ids"`
+}
+
+// What merging the sources into the target is about to change, so the merge
+// can be undone. Objects are in the shape of their audit events.
+func (q *Queries) SnapshotObjectMerge(ctx context.Context, arg SnapshotObjectMergeParams) (json.RawMessage, error) {
+	row := q.queryRow(ctx, q.snapshotObjectMergeStmt, snapshotObjectMerge, arg.TargetObjectID, arg.OrgID, pq.Array(arg.SourceObjectIds))
+	var snapshot json.RawMessage
+	err := row.Scan(&snapshot)
+	return snapshot, err
 }
 
 const validateMergeObjects = `-- name: ValidateMergeObjects :one
@@ -13,6 +13,46 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID    `json:"id"`
+	OrgID      uuid.UUID    `json:"org_id"`
+	CreatorID  uuid.UUID    `json:"creator_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	KeyHash    string       `json:"key_hash"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	CreatedBy  uuid.UUID    `json:"created_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	StorageKey  string    `json:"storage_key"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Sha256      string    `json:"sha256"`
+	CreatorID   uuid.UUID `json:"creator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID             uuid.UUID             `json:"id"`
+	OrgID          uuid.UUID             `json:"org_id"`
+	ActorID        uuid.NullUUID         `json:"actor_id"`
+	ImpersonatorID uuid.NullUUID         `json:"impersonator_id"`
+	EntityType     string                `json:"entity_type"`
+	EntityID       uuid.UUID             `json:"entity_id"`
+	Action         string                `json:"action"`
+	Before         pqtype.NullRawMessage `json:"before"`
+	After          pqtype.NullRawMessage `json:"after"`
+	CreatedAt      time.Time             `json:"created_at"`
+	Seq            int64                 `json:"seq"`
+}
+
 type AutomatedAction struct {
 	ID           uuid.UUID       `json:"id"`
 	OrgID        uuid.UUID       `json:"org_id"`
@@ -39,6 +79,32 @@ type AutomatedActionExecution struct {
 	ExecutionLog    pqtype.NullRawMessage `json:"execution_log"`
 }
 
+type Comment struct {
+	ID          uuid.UUID     `json:"id"`
+	OrgID       uuid.UUID     `json:"org_id"`
+	ObjID       uuid.NullUUID `json:"obj_id"`
+	TaskID      uuid.NullUUID `json:"task_id"`
+	ParentID    uuid.NullUUID `json:"parent_id"`
+	Text        string        `json:"text"`
+	CreatorID   uuid.UUID     `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	LastUpdated time.Time     `json:"last_updated"`
+	EditedAt    sql.NullTime  `json:"edited_at"`
+	DeletedAt   sql.NullTime  `json:"deleted_at"`
+}
+
+type CommentMention struct {
+	CommentID uuid.UUID `json:"comment_id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+}
+
+type CommentReaction struct {
+	CommentID uuid.UUID `json:"comment_id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Creator struct {
 	ID        uuid.UUID       `json:"id"`
 	Username  string          `json:"username"`
@@ -51,6 +117,16 @@ type Creator struct {
 	DeletedAt sql.NullTime    `json:"deleted_at"`
 }
 
+type CreatorIdentity struct {
+	ID          uuid.UUID `json:"id"`
+	CreatorID   uuid.UUID `json:"creator_id"`
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type CreatorList struct {
 	ID          uuid.UUID       `json:"id"`
 	CreatorID   uuid.UUID       `json:"creator_id"`
@@ -60,23 +136,115 @@ type CreatorList struct {
 	LastUpdated time.Time       `json:"last_updated"`
 }
 
+type CreatorRecoveryCode struct {
+	ID        uuid.UUID    `json:"id"`
+	CreatorID uuid.UUID    `json:"creator_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
 type CreatorSession struct {
-	ID        uuid.UUID `json:"id"`
-	CreatorID uuid.UUID `json:"creator_id"`
-	Jwt       string    `json:"jwt"`
-	ExpiredAt time.Time `json:"expired_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID    `json:"id"`
+	CreatorID uuid.UUID    `json:"creator_id"`
+	Jti       string       `json:"jti"`
+	ExpiredAt time.Time    `json:"expired_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UserAgent string       `json:"user_agent"`
+	Ip        string       `json:"ip"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+type CreatorToken struct {
+	ID        uuid.UUID    `json:"id"`
+	CreatorID uuid.UUID    `json:"creator_id"`
+	Purpose   string       `json:"purpose"`
+	TokenHash string       `json:"token_hash"`
+	Email     string       `json:"email"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type CreatorTotp struct {
+	CreatorID    uuid.UUID    `json:"creator_id"`
+	Secret       string       `json:"secret"`
+	EnabledAt    sql.NullTime `json:"enabled_at"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type DuplicateScan struct {
+	OrgID     uuid.UUID `json:"org_id"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+type DuplicateSuggestion struct {
+	ID             uuid.UUID       `json:"id"`
+	OrgID          uuid.UUID       `json:"org_id"`
+	ObjAID         uuid.UUID       `json:"obj_a_id"`
+	ObjBID         uuid.UUID       `json:"obj_b_id"`
+	Score          float32         `json:"score"`
+	Signals        json.RawMessage `json:"signals"`
+	Status         string          `json:"status"`
+	ResolvedBy     uuid.NullUUID   `json:"resolved_by"`
+	ResolvedAt     sql.NullTime    `json:"resolved_at"`
+	MergeHistoryID uuid.NullUUID   `json:"merge_history_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
 type Fact struct {
-	ID          uuid.UUID    `json:"id"`
-	Text        string       `json:"text"`
-	HappenedAt  sql.NullTime `json:"happened_at"`
-	Location    string       `json:"location"`
-	CreatorID   uuid.UUID    `json:"creator_id"`
-	CreatedAt   time.Time    `json:"created_at"`
-	LastUpdated time.Time    `json:"last_updated"`
-	DeletedAt   sql.NullTime `json:"deleted_at"`
+	ID              uuid.UUID      `json:"id"`
+	Text            string         `json:"text"`
+	HappenedAt      sql.NullTime   `json:"happened_at"`
+	Location        string         `json:"location"`
+	CreatorID       uuid.UUID      `json:"creator_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	LastUpdated     time.Time      `json:"last_updated"`
+	DeletedAt       sql.NullTime   `json:"deleted_at"`
+	OrgID           uuid.UUID      `json:"org_id"`
+	Kind            string         `json:"kind"`
+	DurationMinutes sql.NullInt32  `json:"duration_minutes"`
+	Direction       sql.NullString `json:"direction"`
+	Outcome         string         `json:"outcome"`
+}
+
+type FactAttachment struct {
+	FactID       uuid.UUID `json:"fact_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
+}
+
+type FactAttendee struct {
+	FactID    uuid.UUID     `json:"fact_id"`
+	ObjID     uuid.NullUUID `json:"obj_id"`
+	CreatorID uuid.NullUUID `json:"creator_id"`
+}
+
+type FactKind struct {
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Fields      []string  `json:"fields"`
+	CreatorID   uuid.UUID `json:"creator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FactRevision struct {
+	ID              uuid.UUID      `json:"id"`
+	FactID          uuid.UUID      `json:"fact_id"`
+	OrgID           uuid.UUID      `json:"org_id"`
+	Revision        int32          `json:"revision"`
+	Text            string         `json:"text"`
+	HappenedAt      sql.NullTime   `json:"happened_at"`
+	Location        string         `json:"location"`
+	Kind            string         `json:"kind"`
+	DurationMinutes sql.NullInt32  `json:"duration_minutes"`
+	Direction       sql.NullString `json:"direction"`
+	Outcome         string         `json:"outcome"`
+	EditorID        uuid.NullUUID  `json:"editor_id"`
+	CreatedAt       time.Time      `json:"created_at"`
 }
 
 type Feed struct {
@@ -95,6 +263,30 @@ type Funnel struct {
 	CreatorID   uuid.UUID    `json:"creator_id"`
 	CreatedAt   time.Time    `json:"created_at"`
 	DeletedAt   sql.NullTime `json:"deleted_at"`
+	OrgID       uuid.UUID    `json:"org_id"`
+}
+
+type Impersonation struct {
+	ID        uuid.UUID    `json:"id"`
+	Jti       string       `json:"jti"`
+	OrgID     uuid.UUID    `json:"org_id"`
+	AdminID   uuid.UUID    `json:"admin_id"`
+	CreatorID uuid.UUID    `json:"creator_id"`
+	Reason    string       `json:"reason"`
+	ReadOnly  bool         `json:"read_only"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	EndedAt   sql.NullTime `json:"ended_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ImpersonationRequest struct {
+	ID              uuid.UUID `json:"id"`
+	ImpersonationID uuid.UUID `json:"impersonation_id"`
+	Method          string    `json:"method"`
+	Path            string    `json:"path"`
+	Status          int32     `json:"status"`
+	Ip              string    `json:"ip"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 type ImportTask struct {
@@ -122,23 +314,90 @@ type List struct {
 	CreatedAt     time.Time       `json:"created_at"`
 	LastUpdated   time.Time       `json:"last_updated"`
 	DeletedAt     sql.NullTime    `json:"deleted_at"`
+	OrgID         uuid.UUID       `json:"org_id"`
+}
+
+type LoginChallenge struct {
+	ID        uuid.UUID    `json:"id"`
+	CreatorID uuid.UUID    `json:"creator_id"`
+	Purpose   string       `json:"purpose"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type LoginFailure struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Ip        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginLockout struct {
+	ID          uuid.UUID     `json:"id"`
+	Scope       string        `json:"scope"`
+	Username    string        `json:"username"`
+	Ip          string        `json:"ip"`
+	CreatorID   uuid.NullUUID `json:"creator_id"`
+	Failures    int32         `json:"failures"`
+	LockedUntil time.Time     `json:"locked_until"`
+	UnlockedAt  sql.NullTime  `json:"unlocked_at"`
+	UnlockedBy  uuid.NullUUID `json:"unlocked_by"`
+	CreatedAt   time.Time     `json:"created_at"`
 }
 
 type Obj struct {
+	ID                uuid.UUID     `json:"id"`
+	Name              string        `json:"name"`
+	Photo             string        `json:"photo"`
+	Description       string        `json:"description"`
+	IDString          string        `json:"id_string"`
+	CreatorID         uuid.UUID     `json:"creator_id"`
+	CreatedAt         time.Time     `json:"created_at"`
+	DeletedAt         sql.NullTime  `json:"deleted_at"`
+	Aliases           []string      `json:"aliases"`
+	OrgID             uuid.UUID     `json:"org_id"`
+	PhotoAttachmentID uuid.NullUUID `json:"photo_attachment_id"`
+}
+
+type ObjAttachment struct {
+	ObjID        uuid.UUID `json:"obj_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
+}
+
+type ObjFact struct {
+	ObjID     uuid.UUID `json:"obj_id"`
+	FactID    uuid.UUID `json:"fact_id"`
+	Mentioned bool      `json:"mentioned"`
+}
+
+type ObjRelation struct {
+	ID          uuid.UUID       `json:"id"`
+	OrgID       uuid.UUID       `json:"org_id"`
+	TypeID      uuid.UUID       `json:"type_id"`
+	FromObjID   uuid.UUID       `json:"from_obj_id"`
+	ToObjID     uuid.UUID       `json:"to_obj_id"`
+	Attributes  json.RawMessage `json:"attributes"`
+	StartsAt    sql.NullTime    `json:"starts_at"`
+	EndsAt      sql.NullTime    `json:"ends_at"`
+	CreatorID   uuid.UUID       `json:"creator_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	LastUpdated time.Time       `json:"last_updated"`
+	DeletedAt   sql.NullTime    `json:"deleted_at"`
+}
+
+type ObjRelationType struct {
 	ID          uuid.UUID    `json:"id"`
+	OrgID       uuid.UUID    `json:"org_id"`
 	Name        string       `json:"name"`
-	Photo       string       `json:"photo"`
+	InverseName string       `json:"inverse_name"`
+	Directional bool         `json:"directional"`
 	Description string       `json:"description"`
-	IDString    string       `json:"id_string"`
 	CreatorID   uuid.UUID    `json:"creator_id"`
 	CreatedAt   time.Time    `json:"created_at"`
+	LastUpdated time.Time    `json:"last_updated"`
 	DeletedAt   sql.NullTime `json:"deleted_at"`
-	Aliases     []string     `json:"aliases"`
-}
-
-type ObjFact struct {
-	ObjID  uuid.UUID `json:"obj_id"`
-	FactID uuid.UUID `json:"fact_id"`
 }
 
 type ObjStep struct {
@@ -172,6 +431,7 @@ type ObjType struct {
 	CreatedAt    time.Time       `json:"created_at"`
 	DeletedAt    sql.NullTime    `json:"deleted_at"`
 	FieldsSearch interface{}     `json:"fields_search"`
+	OrgID        uuid.UUID       `json:"org_id"`
 }
 
 // This table has full-text search capabilities on its JSON data
@@ -188,12 +448,26 @@ type ObjTypeValue struct {
 }
 
 type ObjectMergeHistory struct {
-	ID              uuid.UUID   `json:"id"`
-	TargetObjectID  uuid.UUID   `json:"target_object_id"`
-	SourceObjectIds []uuid.UUID `json:"source_object_ids"`
-	MergedAt        time.Time   `json:"merged_at"`
-	CreatorID       uuid.UUID   `json:"creator_id"`
-	CreatedAt       time.Time   `json:"created_at"`
+	ID              uuid.UUID             `json:"id"`
+	TargetObjectID  uuid.UUID             `json:"target_object_id"`
+	SourceObjectIds []uuid.UUID           `json:"source_object_ids"`
+	MergedAt        time.Time             `json:"merged_at"`
+	CreatorID       uuid.UUID             `json:"creator_id"`
+	CreatedAt       time.Time             `json:"created_at"`
+	OrgID           uuid.UUID             `json:"org_id"`
+	Snapshot        pqtype.NullRawMessage `json:"snapshot"`
+	UndoneAt        sql.NullTime          `json:"undone_at"`
+	UndoneBy        uuid.NullUUID         `json:"undone_by"`
+}
+
+type OidcState struct {
+	ID           uuid.UUID    `json:"id"`
+	StateHash    string       `json:"state_hash"`
+	Nonce        string       `json:"nonce"`
+	CodeVerifier string       `json:"code_verifier"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	UsedAt       sql.NullTime `json:"used_at"`
+	CreatedAt    time.Time    `json:"created_at"`
 }
 
 type Org struct {
@@ -204,6 +478,47 @@ type Org struct {
 	DeletedAt sql.NullTime    `json:"deleted_at"`
 }
 
+type OrgInvite struct {
+	ID         uuid.UUID       `json:"id"`
+	OrgID      uuid.UUID       `json:"org_id"`
+	Email      string          `json:"email"`
+	Role       string          `json:"role"`
+	Profile    json.RawMessage `json:"profile"`
+	TokenHash  string          `json:"token_hash"`
+	InvitedBy  uuid.UUID       `json:"invited_by"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	AcceptedAt sql.NullTime    `json:"accepted_at"`
+	AcceptedBy uuid.NullUUID   `json:"accepted_by"`
+	RevokedAt  sql.NullTime    `json:"revoked_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type OrgSsoDomain struct {
+	Domain    string        `json:"domain"`
+	OrgID     uuid.UUID     `json:"org_id"`
+	Role      string        `json:"role"`
+	CreatedBy uuid.NullUUID `json:"created_by"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID    `json:"id"`
+	SessionID uuid.UUID    `json:"session_id"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type RolePermission struct {
+	OrgID      uuid.UUID `json:"org_id"`
+	Role       string    `json:"role"`
+	Permission string    `json:"permission"`
+	Granted    bool      `json:"granted"`
+	UpdatedBy  uuid.UUID `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 type Step struct {
 	ID          uuid.UUID    `json:"id"`
 	FunnelID    uuid.UUID    `json:"funnel_id"`
@@ -239,4 +554,10 @@ type Task struct {
 	CreatedAt   time.Time     `json:"created_at"`
 	LastUpdated time.Time     `json:"last_updated"`
 	DeletedAt   sql.NullTime  `json:"deleted_at"`
+	OrgID       uuid.UUID     `json:"org_id"`
+}
+
+type TaskAttachment struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
 }
@@ -72,6 +72,16 @@ filtered_objects AS (
         ($4::uuid[] IS NULL OR od.tag_ids && $4) AND
         -- Filter by object types
         ($5::uuid[] IS NULL OR od.type_ids && $5) AND
+        -- Filter by relationships of the given types, with the given objects
+        (($10::uuid[] IS NULL AND $11::uuid[] IS NULL) OR EXISTS (
+            SELECT 1
+            FROM obj_relation r
+            JOIN obj other ON other.id = CASE WHEN r.from_obj_id = od.id THEN r.to_obj_id ELSE r.from_obj_id END
+            WHERE (r.from_obj_id = od.id OR r.to_obj_id = od.id)
+              AND r.deleted_at IS NULL AND other.deleted_at IS NULL
+              AND ($10::uuid[] IS NULL OR r.type_id = ANY($10::uuid[]))
+              AND ($11::uuid[] IS NULL OR other.id = ANY($11::uuid[]))
+        )) AND
         -- Filter by type value criteria 1 with LIKE
         ((COALESCE($6::jsonb, 'null'::jsonb) = 'null'::jsonb) OR
          EXISTS (
@@ -166,15 +176,17 @@ SELECT
 `
 
 type CountObjectsAdvancedParams struct {
-	OrgID   uuid.UUID       `json:"org_id"`
-	Column2 interface{}     `json:"column_2"`
-	Column3 []uuid.UUID     `json:"column_3"`
-	Column4 []uuid.UUID     `json:"column_4"`
-	Column5 []uuid.UUID     `json:"column_5"`
-	Column6 json.RawMessage `json:"column_6"`
-	Column7 json.RawMessage `json:"column_7"`
-	Column8 json.RawMessage `json:"column_8"`
-	Column9 []int32         `json:"column_9"`
+	OrgID    uuid.UUID       `json:"org_id"`
+	Column2  interface{}     `json:"column_2"`
+	Column3  []uuid.UUID     `json:"column_3"`
+	Column4  []uuid.UUID     `json:"column_4"`
+	Column5  []uuid.UUID     `json:"column_5"`
+	Column6  json.RawMessage `json:"column_6"`
+	Column7  json.RawMessage `json:"column_7"`
+	Column8  json.RawMessage `json:"column_8"`
+	Column9  []int32         `json:"column_9"`
+	Column10 []uuid.UUID     `json:"column_10"`
+	Column11 []uuid.UUID     `json:"column_11"`
 }
 
 func (q *Queries) CountObjectsAdvanced(ctx context.Context, arg CountObjectsAdvancedParams) (json.RawMessage, error) {
@@ -188,6 +200,8 @@ func (q *Queries) CountObjectsAdvanced(ctx context.Context, arg CountObjectsAdva
 		arg.Column7,
 		arg.Column8,
 		pq.Array(arg.Column9),
+		pq.Array(arg.Column10),
+		pq.Array(arg.Column11),
 	)
 	var jsonb_build_object json.RawMessage
 	err := row.Scan(&jsonb_build_object)
@@ -102,6 +102,16 @@ filtered_objects AS (
         ($4::uuid[] IS NULL OR od.tag_ids && $4) AND
         -- Filter by object types if array is provided
         ($5::uuid[] IS NULL OR od.type_ids && $5) AND
+        -- Filter by relationships of the given types, with the given objects
+        (($15::uuid[] IS NULL AND $16::uuid[] IS NULL) OR EXISTS (
+            SELECT 1
+            FROM obj_relation r
+            JOIN obj other ON other.id = CASE WHEN r.from_obj_id = od.id THEN r.to_obj_id ELSE r.from_obj_id END
+            WHERE (r.from_obj_id = od.id OR r.to_obj_id = od.id)
+              AND r.deleted_at IS NULL AND other.deleted_at IS NULL
+              AND ($15::uuid[] IS NULL OR r.type_id = ANY($15::uuid[]))
+              AND ($16::uuid[] IS NULL OR other.id = ANY($16::uuid[]))
+        )) AND
         -- Filter by type value criteria 1 with LIKE
         ((COALESCE($6::jsonb, 'null'::jsonb) = 'null'::jsonb) OR 
          EXISTS (
@@ -335,6 +345,8 @@ type ListObjectsAdvancedParams struct {
 	Limit    int32           `json:"limit"`
 	Offset   int32           `json:"offset"`
 	Column14 []int32         `json:"column_14"`
+	Column15 []uuid.UUID     `json:"column_15"`
+	Column16 []uuid.UUID     `json:"column_16"`
 }
 
 type ListObjectsAdvancedRow struct {
@@ -370,6 +382,8 @@ func (q *Queries) ListObjectsAdvanced(ctx context.Context, arg ListObjectsAdvanc
 		arg.Limit,
 		arg.Offset,
 		pq.Array(arg.Column14),
+		pq.Array(arg.Column15),
+		pq.Array(arg.Column16),
 	)
 	if err != nil {
 		return nil, err
@@ -13,7 +13,20 @@ import (
 )
 
 type Querier interface {
+	AcceptOrgInvite(ctx context.Context, arg AcceptOrgInviteParams) (Creator, error)
+	// Only active creators of the org can be mentioned. Returns the creators
+	// that were not mentioned before.
+	AddCommentMentions(ctx context.Context, arg AddCommentMentionsParams) ([]uuid.UUID, error)
+	// Reacting twice with the same emoji is a no-op
+	AddCommentReaction(ctx context.Context, arg AddCommentReactionParams) (int64, error)
+	// Objects and creators outside the org of the fact are left out
+	AddFactAttendees(ctx context.Context, arg AddFactAttendeesParams) error
+	// Links the objects the text of the fact mentions, those already linked keep
+	// their link as it is
+	AddMentionedObjectsToFact(ctx context.Context, arg AddMentionedObjectsToFactParams) error
 	AddObjectTypeValue(ctx context.Context, arg AddObjectTypeValueParams) (ObjTypeValue, error)
+	// Links by hand, which also keeps an object the text mentioned linked once
+	// the mention is gone
 	AddObjectsToFact(ctx context.Context, arg AddObjectsToFactParams) error
 	AddObjectsToTask(ctx context.Context, arg AddObjectsToTaskParams) error
 	// First find the first step of the funnel if funnel_id is provided
@@ -22,7 +35,13 @@ type Querier interface {
 	// Insert step relations if funnel_id is provided
 	// Return affected object IDs and what was done to them
 	AddTagAndStepToFilteredObjects(ctx context.Context, arg AddTagAndStepToFilteredObjectsParams) ([]AddTagAndStepToFilteredObjectsRow, error)
-	AddTagToObject(ctx context.Context, arg AddTagToObjectParams) error
+	// Tags the object unless it already is. No row is returned when the object or
+	// the tag is not of the org.
+	AddTagToObject(ctx context.Context, arg AddTagToObjectParams) (uuid.UUID, error)
+	ClearLoginFailures(ctx context.Context, arg ClearLoginFailuresParams) error
+	ClearLoginFailuresByIP(ctx context.Context, ip string) error
+	// Pending pairs with an object deleted or merged away are no question anymore
+	ClearStaleDuplicateSuggestions(ctx context.Context, orgID uuid.UUID) (int64, error)
 	CompleteImportTask(ctx context.Context, arg CompleteImportTaskParams) (ImportTask, error)
 	CountActionExecutions(ctx context.Context, actionID uuid.UUID) (int64, error)
 	CountAutomatedActions(ctx context.Context, arg CountAutomatedActionsParams) (int64, error)
@@ -30,6 +49,10 @@ type Querier interface {
 	CountFunnels(ctx context.Context, arg CountFunnelsParams) (int64, error)
 	CountImportTasks(ctx context.Context, orgID uuid.UUID) (int64, error)
 	CountListsByOrgID(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CountLoginFailuresByIP(ctx context.Context, arg CountLoginFailuresByIPParams) (int32, error)
+	CountLoginFailuresByUsername(ctx context.Context, arg CountLoginFailuresByUsernameParams) (CountLoginFailuresByUsernameRow, error)
+	// What MergeObjects would move from the sources to the target
+	CountObjectMergeMoves(ctx context.Context, arg CountObjectMergeMovesParams) (CountObjectMergeMovesRow, error)
 	CountObjectTypes(ctx context.Context, arg CountObjectTypesParams) (int64, error)
 	CountObjectsAdvanced(ctx context.Context, arg CountObjectsAdvancedParams) (json.RawMessage, error)
 	CountObjectsAfterCreatedAt(ctx context.Context, createdAt time.Time) (int64, error)
@@ -37,95 +60,243 @@ type Querier interface {
 	CountObjectsByTypeWithAdvancedFilter(ctx context.Context, arg CountObjectsByTypeWithAdvancedFilterParams) (int64, error)
 	CountObjectsForStep(ctx context.Context, arg CountObjectsForStepParams) (int64, error)
 	CountOngoingTask(ctx context.Context, assignedID uuid.NullUUID) (int64, error)
+	// Sources of a merge that were purged from the trash since
+	CountPurgedMergeSources(ctx context.Context, sourceObjectIds []uuid.UUID) (int32, error)
 	CountTags(ctx context.Context, arg CountTagsParams) (int64, error)
 	CountTasksByObjectID(ctx context.Context, arg CountTasksByObjectIDParams) (int64, error)
 	CountTasksByOrgID(ctx context.Context, arg CountTasksByOrgIDParams) (int64, error)
 	CountTasksWithFilter(ctx context.Context, arg CountTasksWithFilterParams) (int64, error)
 	CountUnseenFeed(ctx context.Context, creatorID uuid.UUID) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateActionExecution(ctx context.Context, actionID uuid.UUID) (AutomatedActionExecution, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
 	CreateAutomatedAction(ctx context.Context, arg CreateAutomatedActionParams) (AutomatedAction, error)
+	// The object or task and the parent must belong to the org, and a reply
+	// must be about the object or task of its parent
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
 	CreateCreator(ctx context.Context, arg CreateCreatorParams) (Creator, error)
+	CreateCreatorIdentity(ctx context.Context, arg CreateCreatorIdentityParams) (CreatorIdentity, error)
 	CreateCreatorList(ctx context.Context, arg CreateCreatorListParams) (CreatorList, error)
+	CreateCreatorSession(ctx context.Context, arg CreateCreatorSessionParams) (CreatorSession, error)
+	CreateCreatorToken(ctx context.Context, arg CreateCreatorTokenParams) (CreatorToken, error)
 	// Add these new queries to your existing queries.sql file
 	CreateFact(ctx context.Context, arg CreateFactParams) (Fact, error)
+	CreateFactKind(ctx context.Context, arg CreateFactKindParams) (FactKind, error)
 	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
 	CreateFunnel(ctx context.Context, arg CreateFunnelParams) (Funnel, error)
+	CreateImpersonation(ctx context.Context, arg CreateImpersonationParams) (Impersonation, error)
 	CreateImportTask(ctx context.Context, arg CreateImportTaskParams) (ImportTask, error)
 	CreateList(ctx context.Context, arg CreateListParams) (List, error)
+	CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error)
+	CreateLoginLockout(ctx context.Context, arg CreateLoginLockoutParams) (LoginLockout, error)
+	CreateOIDCState(ctx context.Context, arg CreateOIDCStateParams) error
+	// Both objects and the type must belong to the org
+	CreateObjRelation(ctx context.Context, arg CreateObjRelationParams) (ObjRelation, error)
+	CreateObjRelationType(ctx context.Context, arg CreateObjRelationTypeParams) (ObjRelationType, error)
 	CreateObjStep(ctx context.Context, arg CreateObjStepParams) (CreateObjStepRow, error)
 	CreateObject(ctx context.Context, arg CreateObjectParams) (Obj, error)
 	CreateObjectType(ctx context.Context, arg CreateObjectTypeParams) (ObjType, error)
+	CreateOrgInvite(ctx context.Context, arg CreateOrgInviteParams) (OrgInvite, error)
 	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Org, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSSODomain(ctx context.Context, arg CreateSSODomainParams) (OrgSsoDomain, error)
 	CreateStep(ctx context.Context, arg CreateStepParams) (Step, error)
 	// Setting/Tag section
 	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	// Existing queries...
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
 	DeleteActionOldExecutions(ctx context.Context, startedAt time.Time) error
+	// Returns the storage key so the content can be deleted from the blob store
+	DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) (string, error)
 	DeleteAutomatedAction(ctx context.Context, id uuid.UUID) error
+	DeleteComment(ctx context.Context, arg DeleteCommentParams) (int64, error)
 	DeleteCreator(ctx context.Context, id uuid.UUID) error
-	DeleteCreatorList(ctx context.Context, id uuid.UUID) error
-	DeleteFact(ctx context.Context, id uuid.UUID) error
-	DeleteFunnel(ctx context.Context, id uuid.UUID) error
-	DeleteList(ctx context.Context, id uuid.UUID) error
-	DeleteObject(ctx context.Context, id uuid.UUID) error
-	DeleteObjectType(ctx context.Context, id uuid.UUID) (int64, error)
-	DeleteStep(ctx context.Context, id uuid.UUID) error
-	DeleteTag(ctx context.Context, id uuid.UUID) (int64, error)
-	DeleteTask(ctx context.Context, id uuid.UUID) error
+	DeleteCreatorList(ctx context.Context, arg DeleteCreatorListParams) (int64, error)
+	DeleteCreatorTOTP(ctx context.Context, creatorID uuid.UUID) (int64, error)
+	DeleteExpiredOIDCStates(ctx context.Context, expiresAt time.Time) error
+	DeleteFact(ctx context.Context, arg DeleteFactParams) (int64, error)
+	DeleteFactAttendees(ctx context.Context, factID uuid.UUID) error
+	// A kind is only deleted while no fact is of it
+	DeleteFactKind(ctx context.Context, arg DeleteFactKindParams) (int64, error)
+	DeleteFunnel(ctx context.Context, arg DeleteFunnelParams) (int64, error)
+	DeleteList(ctx context.Context, arg DeleteListParams) (int64, error)
+	DeleteObjRelation(ctx context.Context, arg DeleteObjRelationParams) (int64, error)
+	// Types still in use are kept
+	DeleteObjRelationType(ctx context.Context, arg DeleteObjRelationTypeParams) (int64, error)
+	// The funnel steps and relationships of the object go to the trash with it,
+	// at the same time, so restoring the object can tell them apart
+	DeleteObject(ctx context.Context, arg DeleteObjectParams) (int64, error)
+	DeleteObjectType(ctx context.Context, arg DeleteObjectTypeParams) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, creatorID uuid.UUID) error
+	DeleteSSODomain(ctx context.Context, arg DeleteSSODomainParams) (int64, error)
+	// Removes the mentions an edit took out of the comment
+	DeleteStaleCommentMentions(ctx context.Context, arg DeleteStaleCommentMentionsParams) error
+	DeleteStep(ctx context.Context, arg DeleteStepParams) (int64, error)
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
+	DeleteTask(ctx context.Context, arg DeleteTaskParams) (int64, error)
+	DeleteUnusedCreatorTokens(ctx context.Context, arg DeleteUnusedCreatorTokensParams) error
+	EnableCreatorTOTP(ctx context.Context, creatorID uuid.UUID) (int64, error)
+	EndImpersonation(ctx context.Context, arg EndImpersonationParams) (int64, error)
+	EndImpersonationByJti(ctx context.Context, jti string) (int64, error)
 	FindObjectByAliasOrIDString(ctx context.Context, arg FindObjectByAliasOrIDStringParams) (Obj, error)
 	FindTagByNormalizedName(ctx context.Context, arg FindTagByNormalizedNameParams) (Tag, error)
+	GetActiveAPIKeyByPrefix(ctx context.Context, prefix string) (GetActiveAPIKeyByPrefixRow, error)
+	GetActiveImpersonationByJti(ctx context.Context, jti string) (GetActiveImpersonationByJtiRow, error)
+	GetActiveLoginChallenge(ctx context.Context, arg GetActiveLoginChallengeParams) (GetActiveLoginChallengeRow, error)
+	GetActiveLoginLockout(ctx context.Context, arg GetActiveLoginLockoutParams) (LoginLockout, error)
+	GetActiveOrgInviteByHash(ctx context.Context, tokenHash string) (GetActiveOrgInviteByHashRow, error)
+	GetActiveSessionByJti(ctx context.Context, jti string) (GetActiveSessionByJtiRow, error)
+	GetAttachment(ctx context.Context, arg GetAttachmentParams) (GetAttachmentRow, error)
 	GetAutomatedAction(ctx context.Context, id uuid.UUID) (AutomatedAction, error)
+	GetComment(ctx context.Context, arg GetCommentParams) (Comment, error)
 	GetCreatorByID(ctx context.Context, id uuid.UUID) (Creator, error)
+	GetCreatorByIdentity(ctx context.Context, arg GetCreatorByIdentityParams) (GetCreatorByIdentityRow, error)
 	GetCreatorByUsername(ctx context.Context, arg GetCreatorByUsernameParams) (GetCreatorByUsernameRow, error)
 	GetCreatorDailyActivity(ctx context.Context, creatorID uuid.UUID) ([]GetCreatorDailyActivityRow, error)
-	GetCreatorListByID(ctx context.Context, id uuid.UUID) (GetCreatorListByIDRow, error)
-	GetFactByID(ctx context.Context, id uuid.UUID) (GetFactByIDRow, error)
+	GetCreatorListByID(ctx context.Context, arg GetCreatorListByIDParams) (GetCreatorListByIDRow, error)
+	GetCreatorTOTP(ctx context.Context, creatorID uuid.UUID) (CreatorTotp, error)
+	GetDuplicateScan(ctx context.Context, orgID uuid.UUID) (DuplicateScan, error)
+	// Locks the suggestion so it is resolved once
+	GetDuplicateSuggestion(ctx context.Context, arg GetDuplicateSuggestionParams) (GetDuplicateSuggestionRow, error)
+	GetFactByID(ctx context.Context, arg GetFactByIDParams) (GetFactByIDRow, error)
+	GetFactKind(ctx context.Context, arg GetFactKindParams) (FactKind, error)
+	GetFactRevision(ctx context.Context, arg GetFactRevisionParams) (FactRevision, error)
 	GetFeed(ctx context.Context, creatorID uuid.UUID) ([]Feed, error)
-	GetFunnel(ctx context.Context, id uuid.UUID) (GetFunnelRow, error)
+	GetFunnel(ctx context.Context, arg GetFunnelParams) (GetFunnelRow, error)
 	GetImportTask(ctx context.Context, id uuid.UUID) (ImportTask, error)
 	GetImportTaskHistory(ctx context.Context, arg GetImportTaskHistoryParams) ([]ImportTask, error)
 	GetLatestExecution(ctx context.Context, actionID uuid.UUID) (AutomatedActionExecution, error)
-	GetListByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
-	GetObjStep(ctx context.Context, id uuid.UUID) (ObjStep, error)
-	GetObjectByIDString(ctx context.Context, idString string) (Obj, error)
+	GetListByID(ctx context.Context, arg GetListByIDParams) (uuid.UUID, error)
+	GetObjStep(ctx context.Context, arg GetObjStepParams) (ObjStep, error)
+	GetObjectByIDString(ctx context.Context, arg GetObjectByIDStringParams) (Obj, error)
 	GetObjectDetails(ctx context.Context, arg GetObjectDetailsParams) (GetObjectDetailsRow, error)
-	GetObjectTypeByID(ctx context.Context, id uuid.UUID) (ObjType, error)
+	// The object in the shape of its audit events, deleted or not
+	GetObjectHistoryState(ctx context.Context, arg GetObjectHistoryStateParams) (json.RawMessage, error)
+	GetObjectMergeHistory(ctx context.Context, arg GetObjectMergeHistoryParams) (ObjectMergeHistory, error)
+	GetObjectTypeByID(ctx context.Context, arg GetObjectTypeByIDParams) (ObjType, error)
 	GetObjectTypeValue(ctx context.Context, arg GetObjectTypeValueParams) (ObjTypeValue, error)
 	GetObjectsForStep(ctx context.Context, arg GetObjectsForStepParams) ([]GetObjectsForStepRow, error)
 	GetOngoingImportTask(ctx context.Context, orgID uuid.UUID) (ImportTask, error)
 	GetOrgDetails(ctx context.Context, id uuid.UUID) (Org, error)
 	GetPendingActions(ctx context.Context) ([]AutomatedAction, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (GetRefreshTokenByHashRow, error)
+	GetRolePermission(ctx context.Context, arg GetRolePermissionParams) (bool, error)
+	GetSSODomain(ctx context.Context, domain string) (OrgSsoDomain, error)
 	GetStep(ctx context.Context, id uuid.UUID) (GetStepRow, error)
 	GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error)
 	GetTagsByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]Tag, error)
-	GetTaskByID(ctx context.Context, id uuid.UUID) (GetTaskByIDRow, error)
-	HardDeleteObjStep(ctx context.Context, id uuid.UUID) error
+	GetTaskByID(ctx context.Context, arg GetTaskByIDParams) (GetTaskByIDRow, error)
+	// Walks the relationships of the org breadth first from the object, up to
+	// max_depth hops in either direction, and returns the objects reached with
+	// their distance, nearest first. Only relationships of relation_type_ids are
+	// followed when given, and only objects with a type value of obj_type_ids
+	// are returned besides the object itself. The walk keeps one row per object
+	// and depth rather than one per path, so it stays small on dense networks.
+	GraphNeighborhood(ctx context.Context, arg GraphNeighborhoodParams) ([]GraphNeighborhoodRow, error)
+	// Objects that share facts with the object, those sharing the most first.
+	// A fact about several objects, such as a meeting, links each of them.
+	GraphSharedFacts(ctx context.Context, arg GraphSharedFactsParams) ([]GraphSharedFactsRow, error)
+	// Finds a shortest chain of relationships between two objects, at most
+	// max_depth long. The walk gives every object its distance from the start,
+	// then the path steps back from the end to an object one hop closer until it
+	// reaches the start. Returns the objects of the path in order, each with the
+	// relationship to the next one, or no rows when the objects are not
+	// connected.
+	GraphShortestPath(ctx context.Context, arg GraphShortestPathParams) ([]GraphShortestPathRow, error)
+	HardDeleteObjStep(ctx context.Context, arg HardDeleteObjStepParams) (int64, error)
 	HealthCheck(ctx context.Context) (int32, error)
+	IsEmailVerified(ctx context.Context, arg IsEmailVerifiedParams) (bool, error)
+	LinkFactAttachment(ctx context.Context, arg LinkFactAttachmentParams) (int64, error)
+	// The object and the attachment must belong to the org, linking twice is a
+	// no-op
+	LinkObjectAttachment(ctx context.Context, arg LinkObjectAttachmentParams) (int64, error)
+	LinkTaskAttachment(ctx context.Context, arg LinkTaskAttachmentParams) (int64, error)
+	ListAPIKeysByOrgID(ctx context.Context, orgID uuid.UUID) ([]ListAPIKeysByOrgIDRow, error)
 	ListActionExecutions(ctx context.Context, arg ListActionExecutionsParams) ([]AutomatedActionExecution, error)
+	ListActiveSessionsByCreatorID(ctx context.Context, creatorID uuid.UUID) ([]ListActiveSessionsByCreatorIDRow, error)
+	// The objects, facts and tasks an attachment is linked to, leaving out those
+	// in the trash
+	ListAttachmentLinks(ctx context.Context, attachmentID uuid.UUID) ([]ListAttachmentLinksRow, error)
+	// The attachments of the org, newest first, optionally only those linked to
+	// an object, a fact or a task
+	ListAttachments(ctx context.Context, arg ListAttachmentsParams) ([]ListAttachmentsRow, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]ListAuditEventsRow, error)
 	ListAutomatedActions(ctx context.Context, arg ListAutomatedActionsParams) ([]AutomatedAction, error)
+	// The discussion about an object or a task, oldest first, or the comment of
+	// id. A deleted comment is kept without its text while it has replies, so
+	// the thread holds.
+	ListComments(ctx context.Context, arg ListCommentsParams) ([]ListCommentsRow, error)
 	ListCreatorListsByCreatorID(ctx context.Context, creatorID uuid.UUID) ([]ListCreatorListsByCreatorIDRow, error)
+	ListCreatorsByVerifiedEmail(ctx context.Context, lower string) ([]ListCreatorsByVerifiedEmailRow, error)
+	// Every org with the time the detector last scanned it, NULL if never
+	ListDuplicateScans(ctx context.Context) ([]ListDuplicateScansRow, error)
+	ListDuplicateSuggestions(ctx context.Context, arg ListDuplicateSuggestionsParams) ([]ListDuplicateSuggestionsRow, error)
+	ListFactKinds(ctx context.Context, orgID uuid.UUID) ([]FactKind, error)
+	// Newest first
+	ListFactRevisions(ctx context.Context, arg ListFactRevisionsParams) ([]ListFactRevisionsRow, error)
 	ListFactsByOrgID(ctx context.Context, arg ListFactsByOrgIDParams) ([]ListFactsByOrgIDRow, error)
 	ListFunnels(ctx context.Context, arg ListFunnelsParams) ([]ListFunnelsRow, error)
+	// Relationships with both objects among ids
+	ListGraphEdges(ctx context.Context, arg ListGraphEdgesParams) ([]ListGraphEdgesRow, error)
+	ListGraphNodes(ctx context.Context, arg ListGraphNodesParams) ([]ListGraphNodesRow, error)
+	ListImpersonationRequests(ctx context.Context, arg ListImpersonationRequestsParams) ([]ListImpersonationRequestsRow, error)
+	ListImpersonationsByOrgID(ctx context.Context, arg ListImpersonationsByOrgIDParams) ([]ListImpersonationsByOrgIDRow, error)
 	ListListsByOrgID(ctx context.Context, arg ListListsByOrgIDParams) ([]ListListsByOrgIDRow, error)
+	ListLoginLockoutsByOrgID(ctx context.Context, arg ListLoginLockoutsByOrgIDParams) ([]LoginLockout, error)
+	// The creators among creator_ids that can be mentioned in the org
+	ListMentionableCreators(ctx context.Context, arg ListMentionableCreatorsParams) ([]uuid.UUID, error)
+	// Changes made after a merge to what undoing it would set back
+	ListMergeConflictEvents(ctx context.Context, arg ListMergeConflictEventsParams) ([]ListMergeConflictEventsRow, error)
+	ListObjRelationTypes(ctx context.Context, orgID uuid.UUID) ([]ListObjRelationTypesRow, error)
+	// A page of the changes of the object and of its type values while they
+	// belonged to it, newest first. Type values moved by a merge show in the
+	// history of both objects. An update that does not move a type value carries
+	// neither obj_id nor type_id, its owner is the one before the next move or
+	// delete, or the current one.
+	ListObjectHistory(ctx context.Context, arg ListObjectHistoryParams) ([]ListObjectHistoryRow, error)
+	// Changes of the object and of every type value that belonged to it after the
+	// time, newest first, to revert them. Type values are found by their create,
+	// move and delete events, their updates only carry the changed columns.
+	ListObjectHistoryEvents(ctx context.Context, arg ListObjectHistoryEventsParams) ([]ListObjectHistoryEventsRow, error)
+	ListObjectMergeHistory(ctx context.Context, arg ListObjectMergeHistoryParams) ([]ListObjectMergeHistoryRow, error)
+	ListObjectStepsForMerge(ctx context.Context, arg ListObjectStepsForMergeParams) ([]ListObjectStepsForMergeRow, error)
+	// The type values in the shape of their audit events, of the object or with
+	// one of the ids
+	ListObjectTypeValueHistoryStates(ctx context.Context, arg ListObjectTypeValueHistoryStatesParams) ([]json.RawMessage, error)
+	ListObjectTypeValuesForMerge(ctx context.Context, arg ListObjectTypeValuesForMergeParams) ([]ListObjectTypeValuesForMergeRow, error)
 	ListObjectTypes(ctx context.Context, arg ListObjectTypesParams) ([]ListObjectTypesRow, error)
 	ListObjectsAdvanced(ctx context.Context, arg ListObjectsAdvancedParams) ([]ListObjectsAdvancedRow, error)
 	ListObjectsByOrgID(ctx context.Context, arg ListObjectsByOrgIDParams) ([]ListObjectsByOrgIDRow, error)
 	ListObjectsByTaskID(ctx context.Context, taskID uuid.UUID) ([]ListObjectsByTaskIDRow, error)
 	ListObjectsByTypeWithAdvancedFilter(ctx context.Context, arg ListObjectsByTypeWithAdvancedFilterParams) ([]ListObjectsByTypeWithAdvancedFilterRow, error)
+	ListObjectsForMerge(ctx context.Context, arg ListObjectsForMergeParams) ([]ListObjectsForMergeRow, error)
 	// Main query to transform and aggregate object data
 	// First level: Get all keys for each object
 	// Second level: Aggregate values by key
 	// Third level: Create contact data object
 	ListObjectsWithNormalizedData(ctx context.Context, arg ListObjectsWithNormalizedDataParams) ([]ListObjectsWithNormalizedDataRow, error)
 	ListOrgMembers(ctx context.Context, arg ListOrgMembersParams) ([]ListOrgMembersRow, error)
+	ListOrgProfiles(ctx context.Context) ([]ListOrgProfilesRow, error)
+	ListPendingOrgInvites(ctx context.Context, orgID uuid.UUID) ([]ListPendingOrgInvitesRow, error)
+	ListRolePermissionsByOrgID(ctx context.Context, orgID uuid.UUID) ([]RolePermission, error)
+	ListSSODomainsByOrgID(ctx context.Context, orgID uuid.UUID) ([]OrgSsoDomain, error)
 	ListStepsByFunnel(ctx context.Context, funnelID uuid.UUID) ([]ListStepsByFunnelRow, error)
 	ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error)
 	ListTasksByObjectID(ctx context.Context, arg ListTasksByObjectIDParams) ([]ListTasksByObjectIDRow, error)
 	ListTasksByOrgID(ctx context.Context, arg ListTasksByOrgIDParams) ([]ListTasksByOrgIDRow, error)
 	// Add this new query to your existing queries.sql file
 	ListTasksWithFilter(ctx context.Context, arg ListTasksWithFilterParams) ([]ListTasksWithFilterRow, error)
+	// Everything the org has in the trash, most recently deleted first, with the
+	// member who deleted it according to the audit log. Objects merged into
+	// another are left out, undoing the merge brings them back.
+	ListTrash(ctx context.Context, arg ListTrashParams) ([]ListTrashRow, error)
+	ListUnusedRecoveryCodes(ctx context.Context, creatorID uuid.UUID) ([]ListUnusedRecoveryCodesRow, error)
+	LogImpersonationRequest(ctx context.Context, arg LogImpersonationRequestParams) error
 	MarkFeedAsSeen(ctx context.Context, dollar_1 []uuid.UUID) error
+	MarkLoginChallengeUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkObjectMergeUndone(ctx context.Context, arg MarkObjectMergeUndoneParams) (int64, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (int64, error)
 	// Update fact references
 	// Update task references
 	// Copy tags
@@ -133,31 +304,94 @@ type Querier interface {
 	// Update type values references
 	// Update fact text
 	// Update task text
+	// Move relationships of the sources to the target
+	// Relationships among the merged objects would relate the target to itself
 	// Mark source objects as deleted
 	// Create merge history record
-	MergeObjects(ctx context.Context, arg MergeObjectsParams) error
-	RemoveObjectTypeValue(ctx context.Context, arg RemoveObjectTypeValueParams) error
+	MergeObjects(ctx context.Context, arg MergeObjectsParams) (uuid.UUID, error)
+	MoveMergedLinksBack(ctx context.Context, arg MoveMergedLinksBackParams) (MoveMergedLinksBackRow, error)
+	// Deletes for good what the org put in the trash before the cutoff. Links,
+	// funnel steps, relationships and merges of the deleted rows go with them.
+	PurgeTrash(ctx context.Context, arg PurgeTrashParams) (PurgeTrashRow, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) error
+	// Scores every pair of objects of the org where at least one of them changed
+	// since the last scan, and stores the pairs scoring min_score or more. The
+	// signals are shared contacts, shared aliases or id strings and similar
+	// names. Independent signals add up as 1 - (1 - w1)(1 - w2)... A name weighs
+	// at most 0.5, below min_score, so a name alone never makes a pair. Pairs
+	// already accepted or dismissed are left alone.
+	RefreshDuplicateSuggestions(ctx context.Context, arg RefreshDuplicateSuggestionsParams) (int64, error)
+	RemoveCommentReaction(ctx context.Context, arg RemoveCommentReactionParams) (int64, error)
+	// Unlinks the objects among obj_ids that only a mention linked, except those
+	// among kept_ids
+	RemoveMentionedObjectsFromFact(ctx context.Context, arg RemoveMentionedObjectsFromFactParams) error
+	RemoveMergedTags(ctx context.Context, arg RemoveMergedTagsParams) (int64, error)
+	RemoveObjectTypeValue(ctx context.Context, arg RemoveObjectTypeValueParams) (int64, error)
 	RemoveObjectsFromFact(ctx context.Context, arg RemoveObjectsFromFactParams) error
 	RemoveObjectsFromTask(ctx context.Context, arg RemoveObjectsFromTaskParams) error
-	RemoveTagFromObject(ctx context.Context, arg RemoveTagFromObjectParams) error
+	// Untags the object. No row is returned when the object is not of the org.
+	RemoveTagFromObject(ctx context.Context, arg RemoveTagFromObjectParams) (uuid.UUID, error)
+	ReplaceRolePermissions(ctx context.Context, arg ReplaceRolePermissionsParams) error
+	ResolveDuplicateSuggestion(ctx context.Context, arg ResolveDuplicateSuggestionParams) (int64, error)
+	// Sets back the fields of the target of a merge, null fields are kept
+	RestoreMergeTarget(ctx context.Context, arg RestoreMergeTargetParams) (int64, error)
+	RestoreMergedFactText(ctx context.Context, arg RestoreMergedFactTextParams) (int64, error)
+	RestoreMergedObject(ctx context.Context, arg RestoreMergedObjectParams) (int64, error)
+	// Moves a relationship back to the objects it had before a merge
+	RestoreMergedRelation(ctx context.Context, arg RestoreMergedRelationParams) (int64, error)
+	RestoreMergedTaskText(ctx context.Context, arg RestoreMergedTaskTextParams) (int64, error)
+	RestoreTrashedFact(ctx context.Context, arg RestoreTrashedFactParams) (int64, error)
+	RestoreTrashedFunnel(ctx context.Context, arg RestoreTrashedFunnelParams) (int64, error)
+	RestoreTrashedList(ctx context.Context, arg RestoreTrashedListParams) (int64, error)
+	// Also brings back the funnel steps and relationships that went to the trash
+	// with the object. Objects merged into another are restored by undoing the
+	// merge.
+	RestoreTrashedObject(ctx context.Context, arg RestoreTrashedObjectParams) (int64, error)
+	RestoreTrashedTag(ctx context.Context, arg RestoreTrashedTagParams) (int64, error)
+	RestoreTrashedTask(ctx context.Context, arg RestoreTrashedTaskParams) (int64, error)
+	// Sets the fact back to a revision, which the trigger records as a new one
+	RevertFact(ctx context.Context, arg RevertFactParams) (Fact, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeCreatorSessions(ctx context.Context, creatorID uuid.UUID) (int64, error)
+	RevokeOrgInvite(ctx context.Context, arg RevokeOrgInviteParams) (int64, error)
+	RevokeSessionByID(ctx context.Context, id uuid.UUID) error
+	RevokeSessionByJti(ctx context.Context, arg RevokeSessionByJtiParams) (int64, error)
+	SetDuplicateScan(ctx context.Context, arg SetDuplicateScanParams) error
+	// A NULL photo_attachment_id clears the photo
+	SetObjectPhoto(ctx context.Context, arg SetObjectPhotoParams) (int64, error)
+	// What merging the sources into the target is about to change, so the merge
+	// can be undone. Objects are in the shape of their audit events.
+	SnapshotObjectMerge(ctx context.Context, arg SnapshotObjectMergeParams) (json.RawMessage, error)
 	// Ensure we only get one row
-	SoftDeleteObjStep(ctx context.Context, id uuid.UUID) error
+	SoftDeleteObjStep(ctx context.Context, arg SoftDeleteObjStepParams) (int64, error)
 	SyncObjectAliases(ctx context.Context, arg SyncObjectAliasesParams) (SyncObjectAliasesRow, error)
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+	TouchCreatorIdentity(ctx context.Context, arg TouchCreatorIdentityParams) error
+	UnlinkFactAttachment(ctx context.Context, arg UnlinkFactAttachmentParams) (int64, error)
+	UnlinkObjectAttachment(ctx context.Context, arg UnlinkObjectAttachmentParams) (int64, error)
+	UnlinkTaskAttachment(ctx context.Context, arg UnlinkTaskAttachmentParams) (int64, error)
+	UnlockLoginLockouts(ctx context.Context, arg UnlockLoginLockoutsParams) (int64, error)
 	UpdateActionExecution(ctx context.Context, arg UpdateActionExecutionParams) (AutomatedActionExecution, error)
 	UpdateActionLastRun(ctx context.Context, id uuid.UUID) error
 	UpdateAutomatedAction(ctx context.Context, arg UpdateAutomatedActionParams) (AutomatedAction, error)
+	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error)
 	UpdateCreatorList(ctx context.Context, arg UpdateCreatorListParams) (CreatorList, error)
 	UpdateCreatorPassword(ctx context.Context, arg UpdateCreatorPasswordParams) error
 	UpdateCreatorProfile(ctx context.Context, arg UpdateCreatorProfileParams) (Creator, error)
 	UpdateCreatorRoleAndStatus(ctx context.Context, arg UpdateCreatorRoleAndStatusParams) (Creator, error)
 	UpdateFact(ctx context.Context, arg UpdateFactParams) (Fact, error)
+	UpdateFactKind(ctx context.Context, arg UpdateFactKindParams) (FactKind, error)
 	UpdateFunnel(ctx context.Context, arg UpdateFunnelParams) (Funnel, error)
 	UpdateImportTaskError(ctx context.Context, arg UpdateImportTaskErrorParams) (ImportTask, error)
 	UpdateImportTaskProgress(ctx context.Context, arg UpdateImportTaskProgressParams) (ImportTask, error)
 	UpdateImportTaskStatus(ctx context.Context, arg UpdateImportTaskStatusParams) (ImportTask, error)
 	UpdateList(ctx context.Context, arg UpdateListParams) (List, error)
+	// Only the attributes and dates of a relationship change, the objects and
+	// the type make another relationship
+	UpdateObjRelation(ctx context.Context, arg UpdateObjRelationParams) (ObjRelation, error)
+	UpdateObjRelationType(ctx context.Context, arg UpdateObjRelationTypeParams) (ObjRelationType, error)
 	UpdateObjStep(ctx context.Context, arg UpdateObjStepParams) error
-	UpdateObjStepSubStatus(ctx context.Context, arg UpdateObjStepSubStatusParams) error
+	UpdateObjStepSubStatus(ctx context.Context, arg UpdateObjStepSubStatusParams) (int64, error)
 	UpdateObject(ctx context.Context, arg UpdateObjectParams) (Obj, error)
 	UpdateObjectType(ctx context.Context, arg UpdateObjectTypeParams) (ObjType, error)
 	UpdateObjectTypeValue(ctx context.Context, arg UpdateObjectTypeValueParams) (ObjTypeValue, error)
@@ -166,6 +400,12 @@ type Querier interface {
 	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
 	UpsertObjectTypeValue(ctx context.Context, arg UpsertObjectTypeValueParams) (ObjTypeValue, error)
+	UpsertPendingCreatorTOTP(ctx context.Context, arg UpsertPendingCreatorTOTPParams) (CreatorTotp, error)
+	UseCreatorTOTPStep(ctx context.Context, arg UseCreatorTOTPStepParams) (int64, error)
+	UseCreatorToken(ctx context.Context, arg UseCreatorTokenParams) (CreatorToken, error)
+	UseOIDCState(ctx context.Context, stateHash string) (UseOIDCStateRow, error)
+	UseRecoveryCode(ctx context.Context, id uuid.UUID) (int64, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
 	ValidateMergeObjects(ctx context.Context, arg ValidateMergeObjectsParams) (ValidateMergeObjectsRow, error)
 }
 
@@ -15,6 +15,31 @@ import (
 	"github.com/lib/pq"
 )
 
+const addMentionedObjectsToFact = `-- name: AddMentionedObjectsToFact :exec
+INSERT INTO obj_fact (obj_id, fact_id, mentioned)
+SELECT o.id, $1, true
+FROM obj o
+WHERE o.id = ANY($2::uuid[]) AND o.org_id = $3
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = $1 AND f.org_id = $3
+)
+ON CONFLICT (obj_id, fact_id) DO NOTHING
+`
+
+type AddMentionedObjectsToFactParams struct {
+	FactID uuid.UUID   `json:"fact_id"`
+	ObjIds []uuid.UUID `json:"obj_ids"`
+	OrgID  uuid.UUID   `json:"org_id"`
+}
+
+// Links the objects the text of the fact mentions, those already linked keep
+// their link as it is
+func (q *Queries) AddMentionedObjectsToFact(ctx context.Context, arg AddMentionedObjectsToFactParams) error {
+	_, err := q.exec(ctx, q.addMentionedObjectsToFactStmt, addMentionedObjectsToFact, arg.FactID, pq.Array(arg.ObjIds), arg.OrgID)
+	return err
+}
+
 const addObjectTypeValue = `-- name: AddObjectTypeValue :one
 WITH org_check AS (
   SELECT
@@ -31,6 +56,7 @@ WITH org_check AS (
   WHERE
     c_obj.org_id = c_ot.org_id              -- Ensure both creators belong to the same org
     AND o.id = $1                           -- obj_id parameter
+    AND c_obj.org_id = $4                   -- org of the caller
 )
 INSERT INTO obj_type_value (obj_id, type_id, type_values)
 SELECT $1, $2, $3::jsonb
@@ -43,10 +69,16 @@ type AddObjectTypeValueParams struct {
 	ObjID   uuid.UUID       `json:"obj_id"`
 	TypeID  uuid.UUID       `json:"type_id"`
 	Column3 json.RawMessage `json:"column_3"`
+	OrgID   uuid.UUID       `json:"org_id"`
 }
 
 func (q *Queries) AddObjectTypeValue(ctx context.Context, arg AddObjectTypeValueParams) (ObjTypeValue, error) {
-	row := q.queryRow(ctx, q.addObjectTypeValueStmt, addObjectTypeValue, arg.ObjID, arg.TypeID, arg.Column3)
+	row := q.queryRow(ctx, q.addObjectTypeValueStmt, addObjectTypeValue,
+		arg.ObjID,
+		arg.TypeID,
+		arg.Column3,
+		arg.OrgID,
+	)
 	var i ObjTypeValue
 	err := row.Scan(
 		&i.ID,
@@ -63,16 +95,16 @@ func (q *Queries) AddObjectTypeValue(ctx context.Context, arg AddObjectTypeValue
 
 const addObjectsToFact = `-- name: AddObjectsToFact :exec
 INSERT INTO obj_fact (obj_id, fact_id)
-SELECT unnest($1::uuid[]), $2
-WHERE EXISTS (
+SELECT o.id, $2
+FROM obj o
+WHERE o.id = ANY($1::uuid[]) AND o.org_id = $3
+AND EXISTS (
   SELECT 1 FROM fact f
   JOIN creator c ON f.creator_id = c.id
   WHERE f.id = $2 AND c.org_id = $3
 )
-AND NOT EXISTS (
-  SELECT 1 FROM obj_fact
-  WHERE obj_id = ANY($1::uuid[]) AND fact_id = $2
-)
+ON CONFLICT (obj_id, fact_id) DO UPDATE SET mentioned = false
+WHERE obj_fact.mentioned
 `
 
 type AddObjectsToFactParams struct {
@@ -81,6 +113,8 @@ type AddObjectsToFactParams struct {
 	OrgID   uuid.UUID   `json:"org_id"`
 }
 
+// Links by hand, which also keeps an object the text mentioned linked once
+// the mention is gone
 func (q *Queries) AddObjectsToFact(ctx context.Context, arg AddObjectsToFactParams) error {
 	_, err := q.exec(ctx, q.addObjectsToFactStmt, addObjectsToFact, pq.Array(arg.Column1), arg.FactID, arg.OrgID)
 	return err
@@ -88,8 +122,10 @@ func (q *Queries) AddObjectsToFact(ctx context.Context, arg AddObjectsToFactPara
 
 const addObjectsToTask = `-- name: AddObjectsToTask :exec
 INSERT INTO obj_task (obj_id, task_id)
-SELECT unnest($1::uuid[]), $2
-WHERE EXISTS (
+SELECT o.id, $2
+FROM obj o
+WHERE o.id = ANY($1::uuid[]) AND o.org_id = $3
+AND EXISTS (
   SELECT 1 FROM task t
   JOIN creator c ON t.creator_id = c.id
   WHERE t.id = $2 AND c.org_id = $3
@@ -111,14 +147,19 @@ func (q *Queries) AddObjectsToTask(ctx context.Context, arg AddObjectsToTaskPara
 	return err
 }
 
-const addTagToObject = `-- name: AddTagToObject :exec
-INSERT INTO obj_tag (obj_id, tag_id)
-SELECT $1, $2
-FROM obj o
-JOIN creator c ON o.creator_id = c.id
-JOIN tag t ON t.org_id = c.org_id
-WHERE o.id = $1 AND t.id = $2 AND c.org_id = $3
-ON CONFLICT DO NOTHING
+const addTagToObject = `-- name: AddTagToObject :one
+WITH target AS (
+  SELECT o.id AS obj_id, t.id AS tag_id
+  FROM obj o
+  JOIN creator c ON o.creator_id = c.id
+  JOIN tag t ON t.org_id = c.org_id
+  WHERE o.id = $1 AND t.id = $2 AND c.org_id = $3 AND t.deleted_at IS NULL
+), added AS (
+  INSERT INTO obj_tag (obj_id, tag_id)
+  SELECT obj_id, tag_id FROM target
+  ON CONFLICT DO NOTHING
+)
+SELECT obj_id FROM target
 `
 
 type AddTagToObjectParams struct {
@@ -127,9 +168,13 @@ type AddTagToObjectParams struct {
 	OrgID uuid.UUID `json:"org_id"`
 }
 
-func (q *Queries) AddTagToObject(ctx context.Context, arg AddTagToObjectParams) error {
-	_, err := q.exec(ctx, q.addTagToObjectStmt, addTagToObject, arg.ObjID, arg.TagID, arg.OrgID)
-	return err
+// Tags the object unless it already is. No row is returned when the object or
+// the tag is not of the org.
+func (q *Queries) AddTagToObject(ctx context.Context, arg AddTagToObjectParams) (uuid.UUID, error) {
+	row := q.queryRow(ctx, q.addTagToObjectStmt, addTagToObject, arg.ObjID, arg.TagID, arg.OrgID)
+	var obj_id uuid.UUID
+	err := row.Scan(&obj_id)
+	return obj_id, err
 }
 
 const countFactsByOrgID = `-- name: CountFactsByOrgID :one
@@ -142,15 +187,17 @@ WHERE
     c.org_id = $1 
     AND f.deleted_at IS NULL
     AND ($2::text = '' OR f.text ILIKE '%' || $2 || '%')
+    AND ($3::text = '' OR f.kind = $3)
 `
 
 type CountFactsByOrgIDParams struct {
 	OrgID   uuid.UUID `json:"org_id"`
 	Column2 string    `json:"column_2"`
+	Column3 string    `json:"column_3"`
 }
 
 func (q *Queries) CountFactsByOrgID(ctx context.Context, arg CountFactsByOrgIDParams) (int64, error) {
-	row := q.queryRow(ctx, q.countFactsByOrgIDStmt, countFactsByOrgID, arg.OrgID, arg.Column2)
+	row := q.queryRow(ctx, q.countFactsByOrgIDStmt, countFactsByOrgID, arg.OrgID, arg.Column2, arg.Column3)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -159,8 +206,7 @@ func (q *Queries) CountFactsByOrgID(ctx context.Context, arg CountFactsByOrgIDPa
 const countFunnels = `-- name: CountFunnels :one
 SELECT COUNT(*)
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE c.org_id = $1 AND f.deleted_at IS NULL
+WHERE f.org_id = $1 AND f.deleted_at IS NULL
 AND ($2::text = '' OR (
   f.name ILIKE '%' || $2 || '%' OR
   f.description ILIKE '%' || $2 || '%' OR
@@ -417,16 +463,20 @@ func (q *Queries) CreateCreator(ctx context.Context, arg CreateCreatorParams) (C
 
 const createFact = `-- name: CreateFact :one
 
-INSERT INTO fact (text, happened_at, location, creator_id)
-VALUES ($1, $2, $3, $4)
-RETURNING id, text, happened_at, location, creator_id, created_at, last_updated, deleted_at
+INSERT INTO fact (text, happened_at, location, creator_id, kind, duration_minutes, direction, outcome)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, text, happened_at, location, creator_id, created_at, last_updated, deleted_at, org_id, kind, duration_minutes, direction, outcome
 `
 
 type CreateFactParams struct {
-	Text       string       `json:"text"`
-	HappenedAt sql.NullTime `json:"happened_at"`
-	Location   string       `json:"location"`
-	CreatorID  uuid.UUID    `json:"creator_id"`
+	Text            string         `json:"text"`
+	HappenedAt      sql.NullTime   `json:"happened_at"`
+	Location        string         `json:"location"`
+	CreatorID       uuid.UUID      `json:"creator_id"`
+	Kind            string         `json:"kind"`
+	DurationMinutes sql.NullInt32  `json:"duration_minutes"`
+	Direction       sql.NullString `json:"direction"`
+	Outcome         string         `json:"outcome"`
 }
 
 // Add these new queries to your existing queries.sql file
@@ -436,6 +486,10 @@ func (q *Queries) CreateFact(ctx context.Context, arg CreateFactParams) (Fact, e
 		arg.HappenedAt,
 		arg.Location,
 		arg.CreatorID,
+		arg.Kind,
+		arg.DurationMinutes,
+		arg.Direction,
+		arg.Outcome,
 	)
 	var i Fact
 	err := row.Scan(
@@ -447,6 +501,11 @@ func (q *Queries) CreateFact(ctx context.Context, arg CreateFactParams) (Fact, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
+		&i.Kind,
+		&i.DurationMinutes,
+		&i.Direction,
+		&i.Outcome,
 	)
 	return i, err
 }
@@ -480,7 +539,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 const createFunnel = `-- name: CreateFunnel :one
 INSERT INTO funnel (id, name, description, creator_id)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, description, creator_id, created_at, deleted_at
+RETURNING id, name, description, creator_id, created_at, deleted_at, org_id
 `
 
 type CreateFunnelParams struct {
@@ -505,21 +564,32 @@ func (q *Queries) CreateFunnel(ctx context.Context, arg CreateFunnelParams) (Fun
 		&i.CreatorID,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
 
 const createObjStep = `-- name: CreateObjStep :one
-WITH existing_step AS (
+WITH org_check AS (
+  -- Both the object and the funnel of the step must belong to the org
+  SELECT 1
+  FROM obj o, step s
+  JOIN funnel f ON f.id = s.funnel_id
+  WHERE o.id = $1 AND o.org_id = $4
+    AND s.id = $2 AND f.org_id = $4
+),
+existing_step AS (
   -- First check if the step already exists
   SELECT id, obj_id, step_id, creator_id, sub_status, created_at, last_updated, deleted_at FROM obj_step
   WHERE obj_step.obj_id = $1 AND obj_step.step_id = $2 AND obj_step.deleted_at IS NULL
+    AND EXISTS (SELECT 1 FROM org_check)
 ),
 new_step AS (
   -- Try to insert if it doesn't exist
   INSERT INTO obj_step (obj_id, step_id, creator_id)
   SELECT $1, $2, $3
   WHERE NOT EXISTS (SELECT 1 FROM existing_step)
+    AND EXISTS (SELECT 1 FROM org_check)
   RETURNING id, obj_id, step_id, creator_id, sub_status, created_at, last_updated, deleted_at
 ),
 update_old_steps AS (
@@ -527,6 +597,7 @@ update_old_steps AS (
   UPDATE obj_step
   SET deleted_at = CURRENT_TIMESTAMP
   WHERE obj_id = $1
+    AND EXISTS (SELECT 1 FROM org_check)
     AND step_id IN (
       SELECT id
       FROM step
@@ -549,6 +620,7 @@ type CreateObjStepParams struct {
 	ObjID     uuid.UUID `json:"obj_id"`
 	StepID    uuid.UUID `json:"step_id"`
 	CreatorID uuid.UUID `json:"creator_id"`
+	OrgID     uuid.UUID `json:"org_id"`
 }
 
 type CreateObjStepRow struct {
@@ -563,7 +635,12 @@ type CreateObjStepRow struct {
 }
 
 func (q *Queries) CreateObjStep(ctx context.Context, arg CreateObjStepParams) (CreateObjStepRow, error) {
-	row := q.queryRow(ctx, q.createObjStepStmt, createObjStep, arg.ObjID, arg.StepID, arg.CreatorID)
+	row := q.queryRow(ctx, q.createObjStepStmt, createObjStep,
+		arg.ObjID,
+		arg.StepID,
+		arg.CreatorID,
+		arg.OrgID,
+	)
 	var i CreateObjStepRow
 	err := row.Scan(
 		&i.ID,
@@ -581,7 +658,7 @@ func (q *Queries) CreateObjStep(ctx context.Context, arg CreateObjStepParams) (C
 const createObject = `-- name: CreateObject :one
 INSERT INTO obj (name, description, id_string, creator_id)
 VALUES ($1, $2, $3, $4)
-RETURNING id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases
+RETURNING id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases, org_id, photo_attachment_id
 `
 
 type CreateObjectParams struct {
@@ -609,6 +686,8 @@ func (q *Queries) CreateObject(ctx context.Context, arg CreateObjectParams) (Obj
 		&i.CreatedAt,
 		&i.DeletedAt,
 		pq.Array(&i.Aliases),
+		&i.OrgID,
+		&i.PhotoAttachmentID,
 	)
 	return i, err
 }
@@ -616,7 +695,7 @@ func (q *Queries) CreateObject(ctx context.Context, arg CreateObjectParams) (Obj
 const createObjectType = `-- name: CreateObjectType :one
 INSERT INTO obj_type (name, description, fields, creator_id, icon)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search
+RETURNING id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search, org_id
 `
 
 type CreateObjectTypeParams struct {
@@ -646,6 +725,7 @@ func (q *Queries) CreateObjectType(ctx context.Context, arg CreateObjectTypePara
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.FieldsSearch,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -720,7 +800,7 @@ const createTask = `-- name: CreateTask :one
 
 INSERT INTO task (content, deadline, remind_at, status, creator_id, assigned_id, parent_id)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, content, deadline, remind_at, status, creator_id, assigned_id, parent_id, created_at, last_updated, deleted_at
+RETURNING id, content, deadline, remind_at, status, creator_id, assigned_id, parent_id, created_at, last_updated, deleted_at, org_id
 `
 
 type CreateTaskParams struct {
@@ -757,6 +837,7 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -770,21 +851,29 @@ func (q *Queries) DeleteCreator(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
-const deleteFact = `-- name: DeleteFact :exec
+const deleteFact = `-- name: DeleteFact :execrows
 UPDATE fact
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteFact(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteFactStmt, deleteFact, id)
-	return err
+type DeleteFactParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteFact(ctx context.Context, arg DeleteFactParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteFactStmt, deleteFact, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteFunnel = `-- name: DeleteFunnel :exec
+const deleteFunnel = `-- name: DeleteFunnel :execrows
 UPDATE funnel
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE funnel.id = $1 AND deleted_at IS NULL
+WHERE funnel.id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_step os
     JOIN step s ON s.id = os.step_id
@@ -793,60 +882,111 @@ WHERE funnel.id = $1 AND deleted_at IS NULL
   )
 `
 
-func (q *Queries) DeleteFunnel(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteFunnelStmt, deleteFunnel, id)
-	return err
+type DeleteFunnelParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteFunnel(ctx context.Context, arg DeleteFunnelParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteFunnelStmt, deleteFunnel, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteObject = `-- name: DeleteObject :exec
-UPDATE obj SET deleted_at = NOW() WHERE id = $1
+const deleteObject = `-- name: DeleteObject :execrows
+WITH trashed_steps AS (
+    UPDATE obj_step
+    SET deleted_at = NOW()
+    WHERE obj_step.obj_id = $1 AND obj_step.deleted_at IS NULL
+      AND EXISTS (SELECT 1 FROM obj o WHERE o.id = $1 AND o.org_id = $2 AND o.deleted_at IS NULL)
+),
+trashed_relations AS (
+    UPDATE obj_relation
+    SET deleted_at = NOW()
+    WHERE (obj_relation.from_obj_id = $1 OR obj_relation.to_obj_id = $1)
+      AND obj_relation.org_id = $2 AND obj_relation.deleted_at IS NULL
+)
+UPDATE obj SET deleted_at = NOW() WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteObject(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteObjectStmt, deleteObject, id)
-	return err
+type DeleteObjectParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+// The funnel steps and relationships of the object go to the trash with it,
+// at the same time, so restoring the object can tell them apart
+func (q *Queries) DeleteObject(ctx context.Context, arg DeleteObjectParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteObjectStmt, deleteObject, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteObjectType = `-- name: DeleteObjectType :execrows
 UPDATE obj_type
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE obj_type.id = $1 AND deleted_at IS NULL
+WHERE obj_type.id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_type_value WHERE type_id = $1
   )
 `
 
-func (q *Queries) DeleteObjectType(ctx context.Context, id uuid.UUID) (int64, error) {
-	result, err := q.exec(ctx, q.deleteObjectTypeStmt, deleteObjectType, id)
+type DeleteObjectTypeParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteObjectType(ctx context.Context, arg DeleteObjectTypeParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteObjectTypeStmt, deleteObjectType, arg.ID, arg.OrgID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-const deleteStep = `-- name: DeleteStep :exec
+const deleteStep = `-- name: DeleteStep :execrows
 UPDATE step
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE step.id = $1 AND deleted_at IS NULL
+WHERE step.id = $1 AND funnel_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_step os WHERE os.step_id = $1
   )
 `
 
-func (q *Queries) DeleteStep(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteStepStmt, deleteStep, id)
-	return err
+type DeleteStepParams struct {
+	ID       uuid.UUID `json:"id"`
+	FunnelID uuid.UUID `json:"funnel_id"`
+}
+
+func (q *Queries) DeleteStep(ctx context.Context, arg DeleteStepParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteStepStmt, deleteStep, arg.ID, arg.FunnelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteTask = `-- name: DeleteTask :exec
+const deleteTask = `-- name: DeleteTask :execrows
 UPDATE task
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteTask(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.deleteTaskStmt, deleteTask, id)
-	return err
+type DeleteTaskParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteTask(ctx context.Context, arg DeleteTaskParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTaskStmt, deleteTask, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getCreatorByID = `-- name: GetCreatorByID :one
@@ -913,26 +1053,36 @@ func (q *Queries) GetCreatorByUsername(ctx context.Context, arg GetCreatorByUser
 }
 
 const getFactByID = `-- name: GetFactByID :one
-SELECT f.id, f.text, f.happened_at, f.location, f.creator_id, f.created_at, f.last_updated, f.deleted_at, c.username as creator_name
+SELECT f.id, f.text, f.happened_at, f.location, f.creator_id, f.created_at, f.last_updated, f.deleted_at, f.org_id, f.kind, f.duration_minutes, f.direction, f.outcome, c.username as creator_name
 FROM fact f
 JOIN creator c ON f.creator_id = c.id
-WHERE f.id = $1 AND f.deleted_at IS NULL
+WHERE f.id = $1 AND f.org_id = $2 AND f.deleted_at IS NULL
 `
 
-type GetFactByIDRow struct {
-	ID          uuid.UUID    `json:"id"`
-	Text        string       `json:"text"`
-	HappenedAt  sql.NullTime `json:"happened_at"`
-	Location    string       `json:"location"`
-	CreatorID   uuid.UUID    `json:"creator_id"`
-	CreatedAt   time.Time    `json:"created_at"`
-	LastUpdated time.Time    `json:"last_updated"`
-	DeletedAt   sql.NullTime `json:"deleted_at"`
-	CreatorName string       `json:"creator_name"`
+type GetFactByIDParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
 }
 
-func (q *Queries) GetFactByID(ctx context.Context, id uuid.UUID) (GetFactByIDRow, error) {
-	row := q.queryRow(ctx, q.getFactByIDStmt, getFactByID, id)
+type GetFactByIDRow struct {
+	ID              uuid.UUID      `json:"id"`
+	Text            string         `json:"text"`
+	HappenedAt      sql.NullTime   `json:"happened_at"`
+	Location        string         `json:"location"`
+	CreatorID       uuid.UUID      `json:"creator_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	LastUpdated     time.Time      `json:"last_updated"`
+	DeletedAt       sql.NullTime   `json:"deleted_at"`
+	OrgID           uuid.UUID      `json:"org_id"`
+	Kind            string         `json:"kind"`
+	DurationMinutes sql.NullInt32  `json:"duration_minutes"`
+	Direction       sql.NullString `json:"direction"`
+	Outcome         string         `json:"outcome"`
+	CreatorName     string         `json:"creator_name"`
+}
+
+func (q *Queries) GetFactByID(ctx context.Context, arg GetFactByIDParams) (GetFactByIDRow, error) {
+	row := q.queryRow(ctx, q.getFactByIDStmt, getFactByID, arg.ID, arg.OrgID)
 	var i GetFactByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -943,6 +1093,11 @@ func (q *Queries) GetFactByID(ctx context.Context, id uuid.UUID) (GetFactByIDRow
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
+		&i.Kind,
+		&i.DurationMinutes,
+		&i.Direction,
+		&i.Outcome,
 		&i.CreatorName,
 	)
 	return i, err
@@ -986,15 +1141,19 @@ func (q *Queries) GetFeed(ctx context.Context, creatorID uuid.UUID) ([]Feed, err
 }
 
 const getFunnel = `-- name: GetFunnel :one
-SELECT f.id, f.name, f.description, f.creator_id, f.created_at, f.deleted_at, c.org_id,
+SELECT f.id, f.name, f.description, f.creator_id, f.created_at, f.deleted_at, f.org_id,
   (SELECT COUNT(*) FROM obj_step os
   JOIN step s ON s.id = os.step_id
   WHERE s.funnel_id = f.id) AS object_count
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE f.id = $1 AND f.deleted_at IS NULL
+WHERE f.id = $1 AND f.org_id = $2 AND f.deleted_at IS NULL
 `
 
+type GetFunnelParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
 type GetFunnelRow struct {
 	ID          uuid.UUID    `json:"id"`
 	Name        string       `json:"name"`
@@ -1006,8 +1165,8 @@ type GetFunnelRow struct {
 	ObjectCount int64        `json:"object_count"`
 }
 
-func (q *Queries) GetFunnel(ctx context.Context, id uuid.UUID) (GetFunnelRow, error) {
-	row := q.queryRow(ctx, q.getFunnelStmt, getFunnel, id)
+func (q *Queries) GetFunnel(ctx context.Context, arg GetFunnelParams) (GetFunnelRow, error) {
+	row := q.queryRow(ctx, q.getFunnelStmt, getFunnel, arg.ID, arg.OrgID)
 	var i GetFunnelRow
 	err := row.Scan(
 		&i.ID,
@@ -1025,10 +1184,16 @@ func (q *Queries) GetFunnel(ctx context.Context, id uuid.UUID) (GetFunnelRow, er
 const getObjStep = `-- name: GetObjStep :one
 SELECT id, obj_id, step_id, creator_id, sub_status, created_at, last_updated, deleted_at FROM obj_step
 WHERE id = $1
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2)
 `
 
-func (q *Queries) GetObjStep(ctx context.Context, id uuid.UUID) (ObjStep, error) {
-	row := q.queryRow(ctx, q.getObjStepStmt, getObjStep, id)
+type GetObjStepParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetObjStep(ctx context.Context, arg GetObjStepParams) (ObjStep, error) {
+	row := q.queryRow(ctx, q.getObjStepStmt, getObjStep, arg.ID, arg.OrgID)
 	var i ObjStep
 	err := row.Scan(
 		&i.ID,
@@ -1045,7 +1210,7 @@ func (q *Queries) GetObjStep(ctx context.Context, id uuid.UUID) (ObjStep, error)
 
 const getObjectDetails = `-- name: GetObjectDetails :one
 WITH object_data AS (
-    SELECT o.id, o.name, o.photo, o.description, o.id_string, o.creator_id, o.created_at,
+    SELECT o.id, o.name, o.photo, o.photo_attachment_id, o.description, o.id_string, o.creator_id, o.created_at,
            c.org_id, o.aliases,
            coalesce(
             json_agg(DISTINCT jsonb_build_object('id', t.id, 'name', t.name, 'color_schema', t.color_schema)) FILTER (WHERE t.id IS NOT NULL), '[]') 
@@ -1087,7 +1252,30 @@ WITH object_data AS (
                'location', fact.location,
                'createdAt', fact.created_at
            )) FILTER (WHERE fact.id IS NOT NULL), '[]')
-           AS facts
+           AS facts,
+           -- Relationships read from this object, whichever end it is
+           coalesce((
+               SELECT json_agg(jsonb_build_object(
+                   'id', r.id,
+                   'typeId', rt.id,
+                   'typeName', CASE WHEN rt.directional AND r.to_obj_id = o.id AND rt.inverse_name <> ''
+                                    THEN rt.inverse_name ELSE rt.name END,
+                   'directional', rt.directional,
+                   'direction', CASE WHEN r.from_obj_id = o.id THEN 'outgoing' ELSE 'incoming' END,
+                   'objectId', other.id,
+                   'objectName', other.name,
+                   'attributes', r.attributes,
+                   'startsAt', r.starts_at,
+                   'endsAt', r.ends_at,
+                   'createdAt', r.created_at
+               ) ORDER BY rt.name, other.name)
+               FROM obj_relation r
+               JOIN obj_relation_type rt ON rt.id = r.type_id
+               JOIN obj other ON other.id = CASE WHEN r.from_obj_id = o.id THEN r.to_obj_id ELSE r.from_obj_id END
+               WHERE (r.from_obj_id = o.id OR r.to_obj_id = o.id)
+               AND r.deleted_at IS NULL AND other.deleted_at IS NULL
+           ), '[]')
+           AS relations
     FROM obj o
     JOIN creator c ON o.creator_id = c.id
     LEFT JOIN obj_tag otg ON o.id = otg.obj_id
@@ -1104,7 +1292,7 @@ WITH object_data AS (
     WHERE o.id = $1 AND c.org_id = $2
     GROUP BY o.id, o.name, o.description, o.id_string, o.creator_id, o.created_at, c.org_id, o.aliases
 )
-SELECT id, name, photo, description, id_string, creator_id, created_at, org_id, aliases, tags, type_values, tasks, steps_and_funnels, facts
+SELECT id, name, photo, photo_attachment_id, description, id_string, creator_id, created_at, org_id, aliases, tags, type_values, tasks, steps_and_funnels, facts, relations
 FROM object_data
 `
 
@@ -1114,20 +1302,22 @@ type GetObjectDetailsParams struct {
 }
 
 type GetObjectDetailsRow struct {
-	ID              uuid.UUID   `json:"id"`
-	Name            string      `json:"name"`
-	Photo           string      `json:"photo"`
-	Description     string      `json:"description"`
-	IDString        string      `json:"id_string"`
-	CreatorID       uuid.UUID   `json:"creator_id"`
-	CreatedAt       time.Time   `json:"created_at"`
-	OrgID           uuid.UUID   `json:"org_id"`
-	Aliases         []string    `json:"aliases"`
-	Tags            interface{} `json:"tags"`
-	TypeValues      interface{} `json:"type_values"`
-	Tasks           interface{} `json:"tasks"`
-	StepsAndFunnels interface{} `json:"steps_and_funnels"`
-	Facts           interface{} `json:"facts"`
+	ID                uuid.UUID     `json:"id"`
+	Name              string        `json:"name"`
+	Photo             string        `json:"photo"`
+	PhotoAttachmentID uuid.NullUUID `json:"photo_attachment_id"`
+	Description       string        `json:"description"`
+	IDString          string        `json:"id_string"`
+	CreatorID         uuid.UUID     `json:"creator_id"`
+	CreatedAt         time.Time     `json:"created_at"`
+	OrgID             uuid.UUID     `json:"org_id"`
+	Aliases           []string      `json:"aliases"`
+	Tags              interface{}   `json:"tags"`
+	TypeValues        interface{}   `json:"type_values"`
+	Tasks             interface{}   `json:"tasks"`
+	StepsAndFunnels   interface{}   `json:"steps_and_funnels"`
+	Facts             interface{}   `json:"facts"`
+	Relations         interface{}   `json:"relations"`
 }
 
 func (q *Queries) GetObjectDetails(ctx context.Context, arg GetObjectDetailsParams) (GetObjectDetailsRow, error) {
@@ -1137,6 +1327,7 @@ func (q *Queries) GetObjectDetails(ctx context.Context, arg GetObjectDetailsPara
 		&i.ID,
 		&i.Name,
 		&i.Photo,
+		&i.PhotoAttachmentID,
 		&i.Description,
 		&i.IDString,
 		&i.CreatorID,
@@ -1148,18 +1339,24 @@ func (q *Queries) GetObjectDetails(ctx context.Context, arg GetObjectDetailsPara
 		&i.Tasks,
 		&i.StepsAndFunnels,
 		&i.Facts,
+		&i.Relations,
 	)
 	return i, err
 }
 
 const getObjectTypeByID = `-- name: GetObjectTypeByID :one
-SELECT id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search FROM obj_type
-WHERE id = $1 AND deleted_at IS NULL
+SELECT id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search, org_id FROM obj_type
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetObjectTypeByID(ctx context.Context, id uuid.UUID) (ObjType, error) {
-	row := q.queryRow(ctx, q.getObjectTypeByIDStmt, getObjectTypeByID, id)
+type GetObjectTypeByIDParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetObjectTypeByID(ctx context.Context, arg GetObjectTypeByIDParams) (ObjType, error) {
+	row := q.queryRow(ctx, q.getObjectTypeByIDStmt, getObjectTypeByID, arg.ID, arg.OrgID)
 	var i ObjType
 	err := row.Scan(
 		&i.ID,
@@ -1171,6 +1368,7 @@ func (q *Queries) GetObjectTypeByID(ctx context.Context, id uuid.UUID) (ObjType,
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.FieldsSearch,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -1297,13 +1495,18 @@ func (q *Queries) GetStep(ctx context.Context, id uuid.UUID) (GetStepRow, error)
 }
 
 const getTaskByID = `-- name: GetTaskByID :one
-SELECT t.id, t.content, t.deadline, t.remind_at, t.status, t.creator_id, t.assigned_id, t.parent_id, t.created_at, t.last_updated, t.deleted_at, c.username as creator_name, a.username as assigned_name
+SELECT t.id, t.content, t.deadline, t.remind_at, t.status, t.creator_id, t.assigned_id, t.parent_id, t.created_at, t.last_updated, t.deleted_at, t.org_id, c.username as creator_name, a.username as assigned_name
 FROM task t
 JOIN creator c ON t.creator_id = c.id
 LEFT JOIN creator a ON t.assigned_id = a.id
-WHERE t.id = $1 AND t.deleted_at IS NULL
+WHERE t.id = $1 AND t.org_id = $2 AND t.deleted_at IS NULL
 `
 
+type GetTaskByIDParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
 type GetTaskByIDRow struct {
 	ID           uuid.UUID      `json:"id"`
 	Content      string         `json:"content"`
@@ -1316,12 +1519,13 @@ type GetTaskByIDRow struct {
 	CreatedAt    time.Time      `json:"created_at"`
 	LastUpdated  time.Time      `json:"last_updated"`
 	DeletedAt    sql.NullTime   `json:"deleted_at"`
+	OrgID        uuid.UUID      `json:"org_id"`
 	CreatorName  string         `json:"creator_name"`
 	AssignedName sql.NullString `json:"assigned_name"`
 }
 
-func (q *Queries) GetTaskByID(ctx context.Context, id uuid.UUID) (GetTaskByIDRow, error) {
-	row := q.queryRow(ctx, q.getTaskByIDStmt, getTaskByID, id)
+func (q *Queries) GetTaskByID(ctx context.Context, arg GetTaskByIDParams) (GetTaskByIDRow, error) {
+	row := q.queryRow(ctx, q.getTaskByIDStmt, getTaskByID, arg.ID, arg.OrgID)
 	var i GetTaskByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -1335,20 +1539,30 @@ func (q *Queries) GetTaskByID(ctx context.Context, id uuid.UUID) (GetTaskByIDRow
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
 		&i.CreatorName,
 		&i.AssignedName,
 	)
 	return i, err
 }
 
-const hardDeleteObjStep = `-- name: HardDeleteObjStep :exec
+const hardDeleteObjStep = `-- name: HardDeleteObjStep :execrows
 DELETE FROM obj_step
 WHERE id = $1
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2)
 `
 
-func (q *Queries) HardDeleteObjStep(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.hardDeleteObjStepStmt, hardDeleteObjStep, id)
-	return err
+type HardDeleteObjStepParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) HardDeleteObjStep(ctx context.Context, arg HardDeleteObjStepParams) (int64, error) {
+	result, err := q.exec(ctx, q.hardDeleteObjStepStmt, hardDeleteObjStep, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const listFactsByOrgID = `-- name: ListFactsByOrgID :many
@@ -1360,6 +1574,10 @@ SELECT
     f.creator_id,
     c.username AS creator_name,
     f.created_at,
+    f.kind,
+    f.duration_minutes,
+    f.direction,
+    f.outcome,
     COALESCE(
         json_agg(
             json_build_object(
@@ -1369,7 +1587,22 @@ SELECT
             )
         ) FILTER (WHERE o.id IS NOT NULL),
         '[]'
-    ) AS related_objects
+    ) AS related_objects,
+    (
+        SELECT COALESCE(json_agg(json_build_object(
+            'kind', CASE WHEN fa.obj_id IS NOT NULL THEN 'object' ELSE 'creator' END,
+            'id', COALESCE(fa.obj_id, fa.creator_id),
+            'name', COALESCE(ao.name, ac.username)
+        )), '[]')
+        FROM fact_attendee fa
+        LEFT JOIN obj ao ON ao.id = fa.obj_id
+        LEFT JOIN creator ac ON ac.id = fa.creator_id
+        WHERE fa.fact_id = f.id
+    )::json AS attendees,
+    COALESCE(lr.revision, 1) > 1 AS edited,
+    (CASE WHEN lr.revision > 1 THEN lr.created_at END)::timestamptz AS edited_at,
+    lr.editor_id AS last_editor_id,
+    COALESCE(le.username, '')::text AS last_editor_name
 FROM 
     fact f
 JOIN 
@@ -1378,12 +1611,22 @@ LEFT JOIN
     obj_fact of ON f.id = of.fact_id
 LEFT JOIN 
     obj o ON of.obj_id = o.id
+LEFT JOIN LATERAL (
+    SELECT r.revision, r.editor_id, r.created_at
+    FROM fact_revision r
+    WHERE r.fact_id = f.id
+    ORDER BY r.revision DESC
+    LIMIT 1
+) lr ON true
+LEFT JOIN 
+    creator le ON le.id = lr.editor_id
 WHERE 
     c.org_id = $1 
     AND f.deleted_at IS NULL
     AND ($2::text = '' OR f.text ILIKE '%' || $2 || '%')
+    AND ($5::text = '' OR f.kind = $5)
 GROUP BY 
-    f.id, c.username
+    f.id, c.username, lr.revision, lr.editor_id, lr.created_at, le.username
 ORDER BY 
     f.happened_at DESC
 LIMIT $3 OFFSET $4
@@ -1394,17 +1637,27 @@ type ListFactsByOrgIDParams struct {
 	Column2 string    `json:"column_2"`
 	Limit   int32     `json:"limit"`
 	Offset  int32     `json:"offset"`
+	Column5 string    `json:"column_5"`
 }
 
 type ListFactsByOrgIDRow struct {
-	ID             uuid.UUID    `json:"id"`
-	Text           string       `json:"text"`
-	HappenedAt     sql.NullTime `json:"happened_at"`
-	Location       string       `json:"location"`
-	CreatorID      uuid.UUID    `json:"creator_id"`
-	CreatorName    string       `json:"creator_name"`
-	CreatedAt      time.Time    `json:"created_at"`
-	RelatedObjects interface{}  `json:"related_objects"`
+	ID              uuid.UUID       `json:"id"`
+	Text            string          `json:"text"`
+	HappenedAt      sql.NullTime    `json:"happened_at"`
+	Location        string          `json:"location"`
+	CreatorID       uuid.UUID       `json:"creator_id"`
+	CreatorName     string          `json:"creator_name"`
+	CreatedAt       time.Time       `json:"created_at"`
+	Kind            string          `json:"kind"`
+	DurationMinutes sql.NullInt32   `json:"duration_minutes"`
+	Direction       sql.NullString  `json:"direction"`
+	Outcome         string          `json:"outcome"`
+	RelatedObjects  interface{}     `json:"related_objects"`
+	Attendees       json.RawMessage `json:"attendees"`
+	Edited          bool            `json:"edited"`
+	EditedAt        sql.NullTime    `json:"edited_at"`
+	LastEditorID    uuid.NullUUID   `json:"last_editor_id"`
+	LastEditorName  string          `json:"last_editor_name"`
 }
 
 func (q *Queries) ListFactsByOrgID(ctx context.Context, arg ListFactsByOrgIDParams) ([]ListFactsByOrgIDRow, error) {
@@ -1413,6 +1666,7 @@ func (q *Queries) ListFactsByOrgID(ctx context.Context, arg ListFactsByOrgIDPara
 		arg.Column2,
 		arg.Limit,
 		arg.Offset,
+		arg.Column5,
 	)
 	if err != nil {
 		return nil, err
@@ -1429,7 +1683,16 @@ func (q *Queries) ListFactsByOrgID(ctx context.Context, arg ListFactsByOrgIDPara
 			&i.CreatorID,
 			&i.CreatorName,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.DurationMinutes,
+			&i.Direction,
+			&i.Outcome,
 			&i.RelatedObjects,
+			&i.Attendees,
+			&i.Edited,
+			&i.EditedAt,
+			&i.LastEditorID,
+			&i.LastEditorName,
 		); err != nil {
 			return nil, err
 		}
@@ -1445,13 +1708,12 @@ func (q *Queries) ListFactsByOrgID(ctx context.Context, arg ListFactsByOrgIDPara
 }
 
 const listFunnels = `-- name: ListFunnels :many
-SELECT f.id, f.name, f.description, f.creator_id, f.created_at, f.deleted_at, c.org_id,
+SELECT f.id, f.name, f.description, f.creator_id, f.created_at, f.deleted_at, f.org_id,
     (SELECT COUNT(*) FROM obj_step os
     JOIN step s ON s.id = os.step_id
     WHERE s.funnel_id = f.id) AS object_count
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE c.org_id = $1 AND f.deleted_at IS NULL
+WHERE f.org_id = $1 AND f.deleted_at IS NULL
   AND ($2::text = '' OR (
     f.name ILIKE '%' || $2 || '%' OR
     f.description ILIKE '%' || $2 || '%' OR
@@ -1524,6 +1786,41 @@ func (q *Queries) ListFunnels(ctx context.Context, arg ListFunnelsParams) ([]Lis
 	return items, nil
 }
 
+const listMentionableCreators = `-- name: ListMentionableCreators :many
+SELECT id FROM creator
+WHERE id = ANY($1::uuid[]) AND org_id = $2
+  AND active AND deleted_at IS NULL
+`
+
+type ListMentionableCreatorsParams struct {
+	CreatorIds []uuid.UUID `json:"creator_ids"`
+	OrgID      uuid.UUID   `json:"org_id"`
+}
+
+// The creators among creator_ids that can be mentioned in the org
+func (q *Queries) ListMentionableCreators(ctx context.Context, arg ListMentionableCreatorsParams) ([]uuid.UUID, error) {
+	rows, err := q.query(ctx, q.listMentionableCreatorsStmt, listMentionableCreators, pq.Array(arg.CreatorIds), arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listObjectTypes = `-- name: ListObjectTypes :many
 SELECT o.id, o.name, o.icon, o.description, o.fields, o.created_at
 FROM obj_type o
@@ -1857,7 +2154,7 @@ func (q *Queries) ListTasksByObjectID(ctx context.Context, arg ListTasksByObject
 }
 
 const listTasksByOrgID = `-- name: ListTasksByOrgID :many
-SELECT t.id, t.content, t.deadline, t.remind_at, t.status, t.creator_id, t.assigned_id, t.parent_id, t.created_at, t.last_updated, t.deleted_at, c.username as creator_name, a.username as assigned_name
+SELECT t.id, t.content, t.deadline, t.remind_at, t.status, t.creator_id, t.assigned_id, t.parent_id, t.created_at, t.last_updated, t.deleted_at, t.org_id, c.username as creator_name, a.username as assigned_name
 FROM task t
 JOIN creator c ON t.creator_id = c.id
 LEFT JOIN creator a ON t.assigned_id = a.id
@@ -1890,6 +2187,7 @@ type ListTasksByOrgIDRow struct {
 	CreatedAt    time.Time      `json:"created_at"`
 	LastUpdated  time.Time      `json:"last_updated"`
 	DeletedAt    sql.NullTime   `json:"deleted_at"`
+	OrgID        uuid.UUID      `json:"org_id"`
 	CreatorName  string         `json:"creator_name"`
 	AssignedName sql.NullString `json:"assigned_name"`
 }
@@ -1920,6 +2218,7 @@ func (q *Queries) ListTasksByOrgID(ctx context.Context, arg ListTasksByOrgIDPara
 			&i.CreatedAt,
 			&i.LastUpdated,
 			&i.DeletedAt,
+			&i.OrgID,
 			&i.CreatorName,
 			&i.AssignedName,
 		); err != nil {
@@ -2093,7 +2392,37 @@ func (q *Queries) MarkFeedAsSeen(ctx context.Context, dollar_1 []uuid.UUID) erro
 	return err
 }
 
-const removeObjectTypeValue = `-- name: RemoveObjectTypeValue :exec
+const removeMentionedObjectsFromFact = `-- name: RemoveMentionedObjectsFromFact :exec
+DELETE FROM obj_fact
+WHERE fact_id = $1 AND mentioned
+AND obj_id = ANY($2::uuid[])
+AND NOT obj_id = ANY($3::uuid[])
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = $1 AND f.org_id = $4
+)
+`
+
+type RemoveMentionedObjectsFromFactParams struct {
+	FactID  uuid.UUID   `json:"fact_id"`
+	ObjIds  []uuid.UUID `json:"obj_ids"`
+	KeptIds []uuid.UUID `json:"kept_ids"`
+	OrgID   uuid.UUID   `json:"org_id"`
+}
+
+// Unlinks the objects among obj_ids that only a mention linked, except those
+// among kept_ids
+func (q *Queries) RemoveMentionedObjectsFromFact(ctx context.Context, arg RemoveMentionedObjectsFromFactParams) error {
+	_, err := q.exec(ctx, q.removeMentionedObjectsFromFactStmt, removeMentionedObjectsFromFact,
+		arg.FactID,
+		pq.Array(arg.ObjIds),
+		pq.Array(arg.KeptIds),
+		arg.OrgID,
+	)
+	return err
+}
+
+const removeObjectTypeValue = `-- name: RemoveObjectTypeValue :execrows
 DELETE FROM obj_type_value
 WHERE obj_type_value.id = $1
 AND EXISTS (
@@ -2108,73 +2437,101 @@ type RemoveObjectTypeValueParams struct {
 	OrgID uuid.UUID `json:"org_id"`
 }
 
-func (q *Queries) RemoveObjectTypeValue(ctx context.Context, arg RemoveObjectTypeValueParams) error {
-	_, err := q.exec(ctx, q.removeObjectTypeValueStmt, removeObjectTypeValue, arg.ID, arg.OrgID)
-	return err
+func (q *Queries) RemoveObjectTypeValue(ctx context.Context, arg RemoveObjectTypeValueParams) (int64, error) {
+	result, err := q.exec(ctx, q.removeObjectTypeValueStmt, removeObjectTypeValue, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const removeObjectsFromFact = `-- name: RemoveObjectsFromFact :exec
 DELETE FROM obj_fact
 WHERE fact_id = $1 AND obj_id = ANY($2::uuid[])
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = $1 AND f.org_id = $3
+)
 `
 
 type RemoveObjectsFromFactParams struct {
 	FactID  uuid.UUID   `json:"fact_id"`
 	Column2 []uuid.UUID `json:"column_2"`
+	OrgID   uuid.UUID   `json:"org_id"`
 }
 
 func (q *Queries) RemoveObjectsFromFact(ctx context.Context, arg RemoveObjectsFromFactParams) error {
-	_, err := q.exec(ctx, q.removeObjectsFromFactStmt, removeObjectsFromFact, arg.FactID, pq.Array(arg.Column2))
+	_, err := q.exec(ctx, q.removeObjectsFromFactStmt, removeObjectsFromFact, arg.FactID, pq.Array(arg.Column2), arg.OrgID)
 	return err
 }
 
 const removeObjectsFromTask = `-- name: RemoveObjectsFromTask :exec
 DELETE FROM obj_task
 WHERE task_id = $1 AND obj_id = ANY($2::uuid[])
+AND EXISTS (
+  SELECT 1 FROM task t
+  WHERE t.id = $1 AND t.org_id = $3
+)
 `
 
 type RemoveObjectsFromTaskParams struct {
 	TaskID  uuid.UUID   `json:"task_id"`
 	Column2 []uuid.UUID `json:"column_2"`
+	OrgID   uuid.UUID   `json:"org_id"`
 }
 
 func (q *Queries) RemoveObjectsFromTask(ctx context.Context, arg RemoveObjectsFromTaskParams) error {
-	_, err := q.exec(ctx, q.removeObjectsFromTaskStmt, removeObjectsFromTask, arg.TaskID, pq.Array(arg.Column2))
+	_, err := q.exec(ctx, q.removeObjectsFromTaskStmt, removeObjectsFromTask, arg.TaskID, pq.Array(arg.Column2), arg.OrgID)
 	return err
 }
 
-const removeTagFromObject = `-- name: RemoveTagFromObject :exec
-DELETE FROM obj_tag
-WHERE obj_id = $1 AND tag_id = $2
-AND EXISTS (
-  SELECT 1 FROM obj o
+const removeTagFromObject = `-- name: RemoveTagFromObject :one
+WITH target AS (
+  SELECT o.id AS obj_id
+  FROM obj o
   JOIN creator c ON o.creator_id = c.id
-  WHERE o.id = $1 AND c.org_id = $3
+  WHERE o.id = $1 AND c.org_id = $2
+), removed AS (
+  DELETE FROM obj_tag
+  WHERE obj_id IN (SELECT obj_id FROM target) AND tag_id = $3
 )
+SELECT obj_id FROM target
 `
 
 type RemoveTagFromObjectParams struct {
 	ObjID uuid.UUID `json:"obj_id"`
-	TagID uuid.UUID `json:"tag_id"`
 	OrgID uuid.UUID `json:"org_id"`
+	TagID uuid.UUID `json:"tag_id"`
 }
 
-func (q *Queries) RemoveTagFromObject(ctx context.Context, arg RemoveTagFromObjectParams) error {
-	_, err := q.exec(ctx, q.removeTagFromObjectStmt, removeTagFromObject, arg.ObjID, arg.TagID, arg.OrgID)
-	return err
+// Untags the object. No row is returned when the object is not of the org.
+func (q *Queries) RemoveTagFromObject(ctx context.Context, arg RemoveTagFromObjectParams) (uuid.UUID, error) {
+	row := q.queryRow(ctx, q.removeTagFromObjectStmt, removeTagFromObject, arg.ObjID, arg.OrgID, arg.TagID)
+	var obj_id uuid.UUID
+	err := row.Scan(&obj_id)
+	return obj_id, err
 }
 
-const softDeleteObjStep = `-- name: SoftDeleteObjStep :exec
+const softDeleteObjStep = `-- name: SoftDeleteObjStep :execrows
 
 UPDATE obj_step
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2)
 `
 
+type SoftDeleteObjStepParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
 // Ensure we only get one row
-func (q *Queries) SoftDeleteObjStep(ctx context.Context, id uuid.UUID) error {
-	_, err := q.exec(ctx, q.softDeleteObjStepStmt, softDeleteObjStep, id)
-	return err
+func (q *Queries) SoftDeleteObjStep(ctx context.Context, arg SoftDeleteObjStepParams) (int64, error) {
+	result, err := q.exec(ctx, q.softDeleteObjStepStmt, softDeleteObjStep, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const updateCreatorPassword = `-- name: UpdateCreatorPassword :exec
@@ -2260,16 +2617,22 @@ func (q *Queries) UpdateCreatorRoleAndStatus(ctx context.Context, arg UpdateCrea
 
 const updateFact = `-- name: UpdateFact :one
 UPDATE fact
-SET text = $2, happened_at = $3, location = $4
-WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, text, happened_at, location, creator_id, created_at, last_updated, deleted_at
+SET text = $2, happened_at = $3, location = $4,
+    kind = $6, duration_minutes = $7, direction = $8, outcome = $9
+WHERE id = $1 AND org_id = $5 AND deleted_at IS NULL
+RETURNING id, text, happened_at, location, creator_id, created_at, last_updated, deleted_at, org_id, kind, duration_minutes, direction, outcome
 `
 
 type UpdateFactParams struct {
-	ID         uuid.UUID    `json:"id"`
-	Text       string       `json:"text"`
-	HappenedAt sql.NullTime `json:"happened_at"`
-	Location   string       `json:"location"`
+	ID              uuid.UUID      `json:"id"`
+	Text            string         `json:"text"`
+	HappenedAt      sql.NullTime   `json:"happened_at"`
+	Location        string         `json:"location"`
+	OrgID           uuid.UUID      `json:"org_id"`
+	Kind            string         `json:"kind"`
+	DurationMinutes sql.NullInt32  `json:"duration_minutes"`
+	Direction       sql.NullString `json:"direction"`
+	Outcome         string         `json:"outcome"`
 }
 
 func (q *Queries) UpdateFact(ctx context.Context, arg UpdateFactParams) (Fact, error) {
@@ -2278,6 +2641,11 @@ func (q *Queries) UpdateFact(ctx context.Context, arg UpdateFactParams) (Fact, e
 		arg.Text,
 		arg.HappenedAt,
 		arg.Location,
+		arg.OrgID,
+		arg.Kind,
+		arg.DurationMinutes,
+		arg.Direction,
+		arg.Outcome,
 	)
 	var i Fact
 	err := row.Scan(
@@ -2289,6 +2657,11 @@ func (q *Queries) UpdateFact(ctx context.Context, arg UpdateFactParams) (Fact, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
+		&i.Kind,
+		&i.DurationMinutes,
+		&i.Direction,
+		&i.Outcome,
 	)
 	return i, err
 }
@@ -2296,18 +2669,24 @@ func (q *Queries) UpdateFact(ctx context.Context, arg UpdateFactParams) (Fact, e
 const updateFunnel = `-- name: UpdateFunnel :one
 UPDATE funnel
 SET name = $2, description = $3
-WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, description, creator_id, created_at, deleted_at
+WHERE id = $1 AND org_id = $4 AND deleted_at IS NULL
+RETURNING id, name, description, creator_id, created_at, deleted_at, org_id
 `
 
 type UpdateFunnelParams struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	OrgID       uuid.UUID `json:"org_id"`
 }
 
 func (q *Queries) UpdateFunnel(ctx context.Context, arg UpdateFunnelParams) (Funnel, error) {
-	row := q.queryRow(ctx, q.updateFunnelStmt, updateFunnel, arg.ID, arg.Name, arg.Description)
+	row := q.queryRow(ctx, q.updateFunnelStmt, updateFunnel,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.OrgID,
+	)
 	var i Funnel
 	err := row.Scan(
 		&i.ID,
@@ -2316,6 +2695,7 @@ func (q *Queries) UpdateFunnel(ctx context.Context, arg UpdateFunnelParams) (Fun
 		&i.CreatorID,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -2324,39 +2704,47 @@ const updateObjStep = `-- name: UpdateObjStep :exec
 UPDATE obj_step
 SET step_id = $2
 WHERE step_id = $1
+  AND EXISTS (SELECT 1 FROM step s WHERE s.id = $1 AND s.funnel_id = $3)
+  AND EXISTS (SELECT 1 FROM step s WHERE s.id = $2 AND s.funnel_id = $3)
 `
 
 type UpdateObjStepParams struct {
 	StepID   uuid.UUID `json:"step_id"`
 	StepID_2 uuid.UUID `json:"step_id_2"`
+	FunnelID uuid.UUID `json:"funnel_id"`
 }
 
 func (q *Queries) UpdateObjStep(ctx context.Context, arg UpdateObjStepParams) error {
-	_, err := q.exec(ctx, q.updateObjStepStmt, updateObjStep, arg.StepID, arg.StepID_2)
+	_, err := q.exec(ctx, q.updateObjStepStmt, updateObjStep, arg.StepID, arg.StepID_2, arg.FunnelID)
 	return err
 }
 
-const updateObjStepSubStatus = `-- name: UpdateObjStepSubStatus :exec
+const updateObjStepSubStatus = `-- name: UpdateObjStepSubStatus :execrows
 UPDATE obj_step
 SET sub_status = $2
 WHERE id = $1 AND deleted_at IS NULL
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $3)
 `
 
 type UpdateObjStepSubStatusParams struct {
 	ID        uuid.UUID `json:"id"`
 	SubStatus int32     `json:"sub_status"`
+	OrgID     uuid.UUID `json:"org_id"`
 }
 
-func (q *Queries) UpdateObjStepSubStatus(ctx context.Context, arg UpdateObjStepSubStatusParams) error {
-	_, err := q.exec(ctx, q.updateObjStepSubStatusStmt, updateObjStepSubStatus, arg.ID, arg.SubStatus)
-	return err
+func (q *Queries) UpdateObjStepSubStatus(ctx context.Context, arg UpdateObjStepSubStatusParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateObjStepSubStatusStmt, updateObjStepSubStatus, arg.ID, arg.SubStatus, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const updateObject = `-- name: UpdateObject :one
 UPDATE obj
 SET name = $2, description = $3, id_string = $4, aliases = $5
-WHERE id = $1
-RETURNING id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases
+WHERE id = $1 AND org_id = $6
+RETURNING id, name, photo, description, id_string, creator_id, created_at, deleted_at, aliases, org_id, photo_attachment_id
 `
 
 type UpdateObjectParams struct {
@@ -2365,6 +2753,7 @@ type UpdateObjectParams struct {
 	Description string    `json:"description"`
 	IDString    string    `json:"id_string"`
 	Aliases     []string  `json:"aliases"`
+	OrgID       uuid.UUID `json:"org_id"`
 }
 
 func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) (Obj, error) {
@@ -2374,6 +2763,7 @@ func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) (Obj
 		arg.Description,
 		arg.IDString,
 		pq.Array(arg.Aliases),
+		arg.OrgID,
 	)
 	var i Obj
 	err := row.Scan(
@@ -2386,6 +2776,8 @@ func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) (Obj
 		&i.CreatedAt,
 		&i.DeletedAt,
 		pq.Array(&i.Aliases),
+		&i.OrgID,
+		&i.PhotoAttachmentID,
 	)
 	return i, err
 }
@@ -2393,8 +2785,8 @@ func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) (Obj
 const updateObjectType = `-- name: UpdateObjectType :one
 UPDATE obj_type
 SET name = $2, description = $3, fields = $4, icon = $5
-WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search
+WHERE id = $1 AND org_id = $6 AND deleted_at IS NULL
+RETURNING id, name, icon, description, fields, creator_id, created_at, deleted_at, fields_search, org_id
 `
 
 type UpdateObjectTypeParams struct {
@@ -2403,6 +2795,7 @@ type UpdateObjectTypeParams struct {
 	Description string          `json:"description"`
 	Fields      json.RawMessage `json:"fields"`
 	Icon        string          `json:"icon"`
+	OrgID       uuid.UUID       `json:"org_id"`
 }
 
 func (q *Queries) UpdateObjectType(ctx context.Context, arg UpdateObjectTypeParams) (ObjType, error) {
@@ -2412,6 +2805,7 @@ func (q *Queries) UpdateObjectType(ctx context.Context, arg UpdateObjectTypePara
 		arg.Description,
 		arg.Fields,
 		arg.Icon,
+		arg.OrgID,
 	)
 	var i ObjType
 	err := row.Scan(
@@ -2424,6 +2818,7 @@ func (q *Queries) UpdateObjectType(ctx context.Context, arg UpdateObjectTypePara
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.FieldsSearch,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -2491,7 +2886,7 @@ func (q *Queries) UpdateOrgDetails(ctx context.Context, arg UpdateOrgDetailsPara
 const updateStep = `-- name: UpdateStep :one
 UPDATE step
 SET name = $2, definition = $3, example = $4, action = $5, step_order = $6, last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND funnel_id = $7 AND deleted_at IS NULL
 RETURNING id, funnel_id, name, definition, example, action, step_order, created_at, last_updated, deleted_at
 `
 
@@ -2502,6 +2897,7 @@ type UpdateStepParams struct {
 	Example    string    `json:"example"`
 	Action     string    `json:"action"`
 	StepOrder  int32     `json:"step_order"`
+	FunnelID   uuid.UUID `json:"funnel_id"`
 }
 
 func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, error) {
@@ -2512,6 +2908,7 @@ func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, e
 		arg.Example,
 		arg.Action,
 		arg.StepOrder,
+		arg.FunnelID,
 	)
 	var i Step
 	err := row.Scan(
@@ -2538,8 +2935,8 @@ SET content = $2,
     assigned_id = $6,
     parent_id = $7,
     last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, content, deadline, remind_at, status, creator_id, assigned_id, parent_id, created_at, last_updated, deleted_at
+WHERE id = $1 AND org_id = $8 AND deleted_at IS NULL
+RETURNING id, content, deadline, remind_at, status, creator_id, assigned_id, parent_id, created_at, last_updated, deleted_at, org_id
 `
 
 type UpdateTaskParams struct {
@@ -2550,6 +2947,7 @@ type UpdateTaskParams struct {
 	Status     string        `json:"status"`
 	AssignedID uuid.NullUUID `json:"assigned_id"`
 	ParentID   uuid.NullUUID `json:"parent_id"`
+	OrgID      uuid.UUID     `json:"org_id"`
 }
 
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
@@ -2561,6 +2959,7 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		arg.Status,
 		arg.AssignedID,
 		arg.ParentID,
+		arg.OrgID,
 	)
 	var i Task
 	err := row.Scan(
@@ -2575,6 +2974,7 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		&i.CreatedAt,
 		&i.LastUpdated,
 		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
@@ -0,0 +1,103 @@
+-- name: CreateAttachment :one
+INSERT INTO attachment (id, org_id, storage_key, filename, content_type, size, sha256, creator_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetAttachment :one
+SELECT a.*, COALESCE(c.username, '')::text AS creator_username
+FROM attachment a
+LEFT JOIN creator c ON c.id = a.creator_id
+WHERE a.id = $1 AND a.org_id = $2;
+
+-- name: ListAttachments :many
+-- The attachments of the org, newest first, optionally only those linked to
+-- an object, a fact or a task
+SELECT a.*, COALESCE(c.username, '')::text AS creator_username,
+    COUNT(*) OVER() AS total_count
+FROM attachment a
+LEFT JOIN creator c ON c.id = a.creator_id
+WHERE a.org_id = sqlc.arg('org_id')
+  AND (sqlc.narg('obj_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM obj_attachment oa WHERE oa.attachment_id = a.id AND oa.obj_id = sqlc.narg('obj_id')
+  ))
+  AND (sqlc.narg('fact_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM fact_attachment fa WHERE fa.attachment_id = a.id AND fa.fact_id = sqlc.narg('fact_id')
+  ))
+  AND (sqlc.narg('task_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM task_attachment ta WHERE ta.attachment_id = a.id AND ta.task_id = sqlc.narg('task_id')
+  ))
+ORDER BY a.created_at DESC, a.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListAttachmentLinks :many
+-- The objects, facts and tasks an attachment is linked to, leaving out those
+-- in the trash
+SELECT 'object'::text AS kind, o.id, o.name::text AS title
+FROM obj_attachment oa
+JOIN obj o ON o.id = oa.obj_id
+WHERE oa.attachment_id = $1 AND o.deleted_at IS NULL
+UNION ALL
+SELECT 'fact', f.id, f.text
+FROM fact_attachment fa
+JOIN fact f ON f.id = fa.fact_id
+WHERE fa.attachment_id = $1 AND f.deleted_at IS NULL
+UNION ALL
+SELECT 'task', t.id, t.content
+FROM task_attachment ta
+JOIN task t ON t.id = ta.task_id
+WHERE ta.attachment_id = $1 AND t.deleted_at IS NULL;
+
+-- name: DeleteAttachment :one
+-- Returns the storage key so the content can be deleted from the blob store
+DELETE FROM attachment
+WHERE id = $1 AND org_id = $2
+RETURNING storage_key;
+
+-- name: LinkObjectAttachment :execrows
+-- The object and the attachment must belong to the org, linking twice is a
+-- no-op
+INSERT INTO obj_attachment (obj_id, attachment_id)
+SELECT o.id, a.id
+FROM obj o, attachment a
+WHERE o.id = sqlc.arg('obj_id') AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id')
+ON CONFLICT (obj_id, attachment_id) DO UPDATE SET obj_id = EXCLUDED.obj_id;
+
+-- name: LinkFactAttachment :execrows
+INSERT INTO fact_attachment (fact_id, attachment_id)
+SELECT f.id, a.id
+FROM fact f, attachment a
+WHERE f.id = sqlc.arg('fact_id') AND f.org_id = sqlc.arg('org_id') AND f.deleted_at IS NULL
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id')
+ON CONFLICT (fact_id, attachment_id) DO UPDATE SET fact_id = EXCLUDED.fact_id;
+
+-- name: LinkTaskAttachment :execrows
+INSERT INTO task_attachment (task_id, attachment_id)
+SELECT t.id, a.id
+FROM task t, attachment a
+WHERE t.id = sqlc.arg('task_id') AND t.org_id = sqlc.arg('org_id') AND t.deleted_at IS NULL
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id')
+ON CONFLICT (task_id, attachment_id) DO UPDATE SET task_id = EXCLUDED.task_id;
+
+-- name: UnlinkObjectAttachment :execrows
+DELETE FROM obj_attachment oa
+USING attachment a
+WHERE oa.obj_id = sqlc.arg('obj_id') AND oa.attachment_id = a.id
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id');
+
+-- name: UnlinkFactAttachment :execrows
+DELETE FROM fact_attachment fa
+USING attachment a
+WHERE fa.fact_id = sqlc.arg('fact_id') AND fa.attachment_id = a.id
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id');
+
+-- name: UnlinkTaskAttachment :execrows
+DELETE FROM task_attachment ta
+USING attachment a
+WHERE ta.task_id = sqlc.arg('task_id') AND ta.attachment_id = a.id
+  AND a.id = sqlc.arg('attachment_id') AND a.org_id = sqlc.arg('org_id');
+
+-- name: SetObjectPhoto :execrows
+-- A NULL photo_attachment_id clears the photo
+UPDATE obj SET photo_attachment_id = $1
+WHERE id = $2 AND org_id = $3 AND deleted_at IS NULL;
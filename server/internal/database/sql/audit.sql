@@ -0,0 +1,13 @@
+-- name: ListAuditEvents :many
+SELECT e.id, e.actor_id, COALESCE(a.username, '')::text AS actor_username,
+  e.impersonator_id, e.entity_type, e.entity_id, e.action, e.before, e.after, e.created_at
+FROM audit_event e
+LEFT JOIN creator a ON e.actor_id = a.id
+WHERE e.org_id = sqlc.arg('org_id')
+  AND (sqlc.narg('entity_type')::text IS NULL OR e.entity_type = sqlc.narg('entity_type'))
+  AND (sqlc.narg('entity_id')::uuid IS NULL OR e.entity_id = sqlc.narg('entity_id'))
+  AND (sqlc.narg('actor_id')::uuid IS NULL OR e.actor_id = sqlc.narg('actor_id'))
+  AND (sqlc.narg('from')::timestamptz IS NULL OR e.created_at >= sqlc.narg('from'))
+  AND (sqlc.narg('to')::timestamptz IS NULL OR e.created_at < sqlc.narg('to'))
+ORDER BY e.created_at DESC, e.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,100 @@
+-- name: CreateComment :one
+-- The object or task and the parent must belong to the org, and a reply
+-- must be about the object or task of its parent
+INSERT INTO comment (org_id, obj_id, task_id, parent_id, text, creator_id)
+SELECT sqlc.arg('org_id'), sqlc.narg('obj_id')::uuid, sqlc.narg('task_id')::uuid, sqlc.narg('parent_id')::uuid,
+    sqlc.arg('text'), sqlc.arg('creator_id')
+WHERE (
+    EXISTS (
+      SELECT 1 FROM obj o
+      WHERE o.id = sqlc.narg('obj_id')::uuid AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+    )
+    OR EXISTS (
+      SELECT 1 FROM task t
+      WHERE t.id = sqlc.narg('task_id')::uuid AND t.org_id = sqlc.arg('org_id') AND t.deleted_at IS NULL
+    )
+  )
+  AND (sqlc.narg('parent_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM comment p
+    WHERE p.id = sqlc.narg('parent_id')::uuid AND p.org_id = sqlc.arg('org_id') AND p.deleted_at IS NULL
+      AND p.obj_id IS NOT DISTINCT FROM sqlc.narg('obj_id')::uuid
+      AND p.task_id IS NOT DISTINCT FROM sqlc.narg('task_id')::uuid
+  ))
+RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM comment
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL;
+
+-- name: UpdateComment :one
+UPDATE comment
+SET text = $3, edited_at = CURRENT_TIMESTAMP, last_updated = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteComment :execrows
+UPDATE comment
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL;
+
+-- name: ListComments :many
+-- The discussion about an object or a task, oldest first, or the comment of
+-- id. A deleted comment is kept without its text while it has replies, so
+-- the thread holds.
+SELECT c.id, c.obj_id, c.task_id, c.parent_id,
+    (CASE WHEN c.deleted_at IS NULL THEN c.text ELSE '' END)::text AS text,
+    c.creator_id, COALESCE(cr.username, '')::text AS creator_username,
+    c.created_at, c.edited_at, c.deleted_at,
+    COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('creatorId', m.creator_id, 'username', mc.username) ORDER BY mc.username)
+        FROM comment_mention m
+        JOIN creator mc ON mc.id = m.creator_id
+        WHERE m.comment_id = c.id
+    ), '[]')::jsonb AS mentions,
+    COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('emoji', r.emoji, 'count', r.count, 'creatorIds', r.creator_ids) ORDER BY r.first_at)
+        FROM (
+            SELECT emoji, COUNT(*) AS count, array_agg(creator_id ORDER BY created_at) AS creator_ids, MIN(created_at) AS first_at
+            FROM comment_reaction
+            WHERE comment_id = c.id
+            GROUP BY emoji
+        ) r
+    ), '[]')::jsonb AS reactions
+FROM comment c
+LEFT JOIN creator cr ON cr.id = c.creator_id
+WHERE c.org_id = sqlc.arg('org_id')
+  AND (c.obj_id = sqlc.narg('obj_id')::uuid OR c.task_id = sqlc.narg('task_id')::uuid OR c.id = sqlc.narg('id')::uuid)
+  AND (c.deleted_at IS NULL OR EXISTS (
+    SELECT 1 FROM comment reply WHERE reply.parent_id = c.id AND reply.deleted_at IS NULL
+  ))
+ORDER BY c.created_at, c.id;
+
+-- name: DeleteStaleCommentMentions :exec
+-- Removes the mentions an edit took out of the comment
+DELETE FROM comment_mention
+WHERE comment_id = sqlc.arg('comment_id') AND creator_id <> ALL(sqlc.arg('creator_ids')::uuid[]);
+
+-- name: AddCommentMentions :many
+-- Only active creators of the org can be mentioned. Returns the creators
+-- that were not mentioned before.
+INSERT INTO comment_mention (comment_id, creator_id)
+SELECT sqlc.arg('comment_id'), c.id
+FROM creator c
+WHERE c.id = ANY(sqlc.arg('creator_ids')::uuid[]) AND c.org_id = sqlc.arg('org_id')
+  AND c.active AND c.deleted_at IS NULL
+ON CONFLICT (comment_id, creator_id) DO NOTHING
+RETURNING creator_id;
+
+-- name: AddCommentReaction :execrows
+-- Reacting twice with the same emoji is a no-op
+INSERT INTO comment_reaction (comment_id, creator_id, emoji)
+SELECT c.id, sqlc.arg('creator_id'), sqlc.arg('emoji')
+FROM comment c
+WHERE c.id = sqlc.arg('comment_id') AND c.org_id = sqlc.arg('org_id') AND c.deleted_at IS NULL
+ON CONFLICT (comment_id, creator_id, emoji) DO UPDATE SET emoji = EXCLUDED.emoji;
+
+-- name: RemoveCommentReaction :execrows
+DELETE FROM comment_reaction r
+USING comment c
+WHERE r.comment_id = c.id AND c.id = sqlc.arg('comment_id') AND c.org_id = sqlc.arg('org_id')
+  AND r.creator_id = sqlc.arg('creator_id') AND r.emoji = sqlc.arg('emoji');
@@ -0,0 +1,133 @@
+-- name: RefreshDuplicateSuggestions :execrows
+-- Scores every pair of objects of the org where at least one of them changed
+-- since the last scan, and stores the pairs scoring min_score or more. The
+-- signals are shared contacts, shared aliases or id strings and similar
+-- names. Independent signals add up as 1 - (1 - w1)(1 - w2)... A name weighs
+-- at most 0.5, below min_score, so a name alone never makes a pair. Pairs
+-- already accepted or dismissed are left alone.
+WITH objs AS (
+    SELECT o.id, lower(o.name) AS name, o.id_string, o.aliases,
+        (sqlc.narg('since')::TIMESTAMPTZ IS NULL
+            OR o.created_at > sqlc.narg('since')
+            OR EXISTS (SELECT 1 FROM obj_type_value otv WHERE otv.obj_id = o.id AND otv.last_updated > sqlc.narg('since'))
+            OR EXISTS (SELECT 1 FROM audit_event e WHERE e.entity_type = 'obj' AND e.entity_id = o.id AND e.created_at > sqlc.narg('since'))
+        ) AS changed
+    FROM obj o
+    WHERE o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+),
+contacts AS (
+    SELECT o.id, o.changed,
+        CASE WHEN lower(kv.key) = 'x or twitter' THEN 'twitter' ELSE lower(kv.key) END AS kind,
+        normalize_contact_value(kv.key, kv.value) AS value
+    FROM objs o
+    JOIN obj_type_value otv ON otv.obj_id = o.id AND otv.deleted_at IS NULL
+    CROSS JOIN LATERAL jsonb_each(otv.type_values) kv
+),
+aliases AS (
+    SELECT DISTINCT o.id, o.changed, lower(btrim(a)) AS alias
+    FROM objs o
+    CROSS JOIN LATERAL unnest(array_append(o.aliases, o.id_string)) a
+    WHERE btrim(a) <> ''
+),
+signals AS (
+    SELECT a.id AS a_id, b.id AS b_id, a.kind AS signal, a.value,
+        CASE a.kind WHEN 'email' THEN 0.9 WHEN 'phone' THEN 0.8 ELSE 0.7 END::FLOAT8 AS weight
+    FROM contacts a
+    JOIN contacts b ON b.kind = a.kind AND b.value = a.value AND b.id <> a.id
+    WHERE a.changed AND a.value IS NOT NULL
+    -- Short numbers are extensions or placeholders rather than phone numbers
+    AND (a.kind <> 'phone' OR length(a.value) >= 6)
+    UNION ALL
+    SELECT a.id, b.id, 'alias', a.alias, 0.6::FLOAT8
+    FROM aliases a
+    JOIN aliases b ON b.alias = a.alias AND b.id <> a.id
+    WHERE a.changed
+    UNION ALL
+    -- Both directions of a pair name the same object so the signal counts once
+    SELECT a.id, b.id, 'name', CASE WHEN a.id < b.id THEN a.name ELSE lower(b.name) END,
+        (similarity(a.name, lower(b.name)) * 0.5)::FLOAT8
+    FROM objs a
+    JOIN obj b ON lower(b.name) % a.name AND b.id <> a.id
+        AND b.org_id = sqlc.arg('org_id') AND b.deleted_at IS NULL
+    WHERE a.changed AND similarity(a.name, lower(b.name)) >= sqlc.arg('min_name_similarity')::FLOAT8
+),
+pairs AS (
+    SELECT DISTINCT LEAST(a_id, b_id) AS obj_a_id, GREATEST(a_id, b_id) AS obj_b_id, signal, value, weight
+    FROM signals
+),
+scored AS (
+    SELECT obj_a_id, obj_b_id,
+        (1 - exp(sum(ln(1 - LEAST(weight, 0.99)))))::REAL AS score,
+        jsonb_agg(jsonb_build_object(
+            'signal', signal,
+            'value', value,
+            'weight', round(weight::NUMERIC, 2)
+        ) ORDER BY weight DESC) AS signals
+    FROM pairs
+    GROUP BY obj_a_id, obj_b_id
+)
+INSERT INTO duplicate_suggestion (org_id, obj_a_id, obj_b_id, score, signals)
+SELECT sqlc.arg('org_id'), obj_a_id, obj_b_id, score, signals
+FROM scored
+WHERE score >= sqlc.arg('min_score')::FLOAT8
+ON CONFLICT (obj_a_id, obj_b_id) DO UPDATE
+SET score = EXCLUDED.score,
+    signals = EXCLUDED.signals,
+    updated_at = CURRENT_TIMESTAMP
+WHERE duplicate_suggestion.status = 'pending';
+
+-- name: ClearStaleDuplicateSuggestions :execrows
+-- Pending pairs with an object deleted or merged away are no question anymore
+DELETE FROM duplicate_suggestion s
+WHERE s.org_id = $1 AND s.status = 'pending'
+AND EXISTS (
+    SELECT 1 FROM obj o
+    WHERE o.id IN (s.obj_a_id, s.obj_b_id) AND o.deleted_at IS NOT NULL
+);
+
+-- name: ListDuplicateScans :many
+-- Every org with the time the detector last scanned it, NULL if never
+SELECT org.id AS org_id, s.scanned_at
+FROM org
+LEFT JOIN duplicate_scan s ON s.org_id = org.id
+WHERE org.deleted_at IS NULL;
+
+-- name: GetDuplicateScan :one
+SELECT org_id, scanned_at FROM duplicate_scan WHERE org_id = $1;
+
+-- name: SetDuplicateScan :exec
+INSERT INTO duplicate_scan (org_id, scanned_at)
+VALUES ($1, $2)
+ON CONFLICT (org_id) DO UPDATE SET scanned_at = EXCLUDED.scanned_at;
+
+-- name: ListDuplicateSuggestions :many
+SELECT s.id, s.obj_a_id, a.name AS obj_a_name, a.id_string AS obj_a_id_string,
+    s.obj_b_id, b.name AS obj_b_name, b.id_string AS obj_b_id_string,
+    s.score, s.signals, s.status, s.resolved_by, s.resolved_at, s.merge_history_id,
+    s.created_at, s.updated_at,
+    COUNT(*) OVER() AS total_count
+FROM duplicate_suggestion s
+JOIN obj a ON a.id = s.obj_a_id
+JOIN obj b ON b.id = s.obj_b_id
+WHERE s.org_id = $1 AND s.status = $2
+ORDER BY s.score DESC, s.updated_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: GetDuplicateSuggestion :one
+-- Locks the suggestion so it is resolved once
+SELECT s.id, s.obj_a_id, s.obj_b_id, s.status,
+    a.created_at AS obj_a_created_at, b.created_at AS obj_b_created_at
+FROM duplicate_suggestion s
+JOIN obj a ON a.id = s.obj_a_id
+JOIN obj b ON b.id = s.obj_b_id
+WHERE s.id = $1 AND s.org_id = $2
+FOR UPDATE OF s;
+
+-- name: ResolveDuplicateSuggestion :execrows
+UPDATE duplicate_suggestion
+SET status = $2,
+    resolved_by = $3,
+    resolved_at = CURRENT_TIMESTAMP,
+    merge_history_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $5 AND status = 'pending';
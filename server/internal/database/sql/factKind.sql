@@ -0,0 +1,45 @@
+-- name: ListFactKinds :many
+SELECT * FROM fact_kind
+WHERE org_id = $1
+ORDER BY name;
+
+-- name: GetFactKind :one
+SELECT * FROM fact_kind
+WHERE org_id = $1 AND name = $2;
+
+-- name: CreateFactKind :one
+INSERT INTO fact_kind (org_id, name, description, fields, creator_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateFactKind :one
+UPDATE fact_kind
+SET description = $3, fields = $4
+WHERE org_id = $1 AND name = $2
+RETURNING *;
+
+-- name: DeleteFactKind :execrows
+-- A kind is only deleted while no fact is of it
+DELETE FROM fact_kind k
+WHERE k.org_id = $1 AND k.name = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM fact f
+    WHERE f.org_id = k.org_id AND f.kind = k.name AND f.deleted_at IS NULL
+  );
+
+-- name: DeleteFactAttendees :exec
+DELETE FROM fact_attendee
+WHERE fact_id = $1;
+
+-- name: AddFactAttendees :exec
+-- Objects and creators outside the org of the fact are left out
+INSERT INTO fact_attendee (fact_id, obj_id, creator_id)
+SELECT f.id, o.id, NULL
+FROM fact f
+JOIN obj o ON o.org_id = f.org_id AND o.deleted_at IS NULL
+WHERE f.id = sqlc.arg('fact_id') AND o.id = ANY(sqlc.arg('obj_ids')::uuid[])
+UNION ALL
+SELECT f.id, NULL, c.id
+FROM fact f
+JOIN creator c ON c.org_id = f.org_id AND c.deleted_at IS NULL
+WHERE f.id = sqlc.arg('fact_id') AND c.id = ANY(sqlc.arg('creator_ids')::uuid[]);
@@ -0,0 +1,21 @@
+-- name: ListFactRevisions :many
+-- Newest first
+SELECT r.*, COALESCE(c.username, '')::text AS editor_name
+FROM fact_revision r
+LEFT JOIN creator c ON c.id = r.editor_id
+WHERE r.fact_id = $1 AND r.org_id = $2
+ORDER BY r.revision DESC;
+
+-- name: GetFactRevision :one
+SELECT * FROM fact_revision
+WHERE fact_id = $1 AND org_id = $2 AND revision = $3;
+
+-- name: RevertFact :one
+-- Sets the fact back to a revision, which the trigger records as a new one
+UPDATE fact f
+SET text = r.text, happened_at = r.happened_at, location = r.location, kind = r.kind,
+    duration_minutes = r.duration_minutes, direction = r.direction, outcome = r.outcome
+FROM fact_revision r
+WHERE f.id = $1 AND f.org_id = $2 AND f.deleted_at IS NULL
+  AND r.fact_id = f.id AND r.revision = $3
+RETURNING f.*;
@@ -0,0 +1,124 @@
+-- name: GraphNeighborhood :many
+-- Walks the relationships of the org breadth first from the object, up to
+-- max_depth hops in either direction, and returns the objects reached with
+-- their distance, nearest first. Only relationships of relation_type_ids are
+-- followed when given, and only objects with a type value of obj_type_ids
+-- are returned besides the object itself. The walk keeps one row per object
+-- and depth rather than one per path, so it stays small on dense networks.
+WITH RECURSIVE edge AS (
+    SELECT e.obj_id, e.other_id
+    FROM obj_relation r
+    CROSS JOIN LATERAL (VALUES (r.from_obj_id, r.to_obj_id), (r.to_obj_id, r.from_obj_id)) e (obj_id, other_id)
+    JOIN obj o ON o.id = e.other_id AND o.deleted_at IS NULL
+    WHERE r.org_id = sqlc.arg('org_id') AND r.deleted_at IS NULL
+      AND (sqlc.narg('relation_type_ids')::uuid[] IS NULL OR r.type_id = ANY(sqlc.narg('relation_type_ids')::uuid[]))
+),
+walk (obj_id, depth) AS (
+    SELECT sqlc.arg('obj_id')::uuid, 0
+    UNION
+    SELECT e.other_id, w.depth + 1
+    FROM walk w
+    JOIN edge e ON e.obj_id = w.obj_id
+    WHERE w.depth < sqlc.arg('max_depth')::int
+)
+SELECT o.id, MIN(w.depth)::int AS depth
+FROM walk w
+JOIN obj o ON o.id = w.obj_id AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+WHERE o.id = sqlc.arg('obj_id')
+   OR sqlc.narg('obj_type_ids')::uuid[] IS NULL
+   OR EXISTS (
+    SELECT 1 FROM obj_type_value otv
+    WHERE otv.obj_id = o.id AND otv.deleted_at IS NULL
+      AND otv.type_id = ANY(sqlc.narg('obj_type_ids')::uuid[])
+  )
+GROUP BY o.id
+ORDER BY depth, o.id
+LIMIT sqlc.arg('max_nodes');
+
+-- name: GraphShortestPath :many
+-- Finds a shortest chain of relationships between two objects, at most
+-- max_depth long. The walk gives every object its distance from the start,
+-- then the path steps back from the end to an object one hop closer until it
+-- reaches the start. Returns the objects of the path in order, each with the
+-- relationship to the next one, or no rows when the objects are not
+-- connected.
+WITH RECURSIVE edge AS (
+    SELECT r.id, e.obj_id, e.other_id
+    FROM obj_relation r
+    CROSS JOIN LATERAL (VALUES (r.from_obj_id, r.to_obj_id), (r.to_obj_id, r.from_obj_id)) e (obj_id, other_id)
+    JOIN obj o ON o.id = e.other_id AND o.deleted_at IS NULL
+    WHERE r.org_id = sqlc.arg('org_id') AND r.deleted_at IS NULL
+      AND (sqlc.narg('relation_type_ids')::uuid[] IS NULL OR r.type_id = ANY(sqlc.narg('relation_type_ids')::uuid[]))
+),
+walk (obj_id, depth) AS (
+    SELECT sqlc.arg('from_obj_id')::uuid, 0
+    UNION
+    SELECT e.other_id, w.depth + 1
+    FROM walk w
+    JOIN edge e ON e.obj_id = w.obj_id
+    WHERE w.depth < sqlc.arg('max_depth')::int
+),
+distance AS (
+    SELECT obj_id, MIN(depth) AS depth
+    FROM walk
+    GROUP BY obj_id
+),
+path (obj_id, depth, relation_id) AS (
+    SELECT d.obj_id, d.depth, NULL::uuid
+    FROM distance d
+    WHERE d.obj_id = sqlc.arg('to_obj_id')::uuid
+    UNION ALL
+    SELECT prev.obj_id, p.depth - 1, prev.relation_id
+    FROM path p
+    CROSS JOIN LATERAL (
+        SELECT e.other_id AS obj_id, e.id AS relation_id
+        FROM edge e
+        JOIN distance d ON d.obj_id = e.other_id AND d.depth = p.depth - 1
+        WHERE e.obj_id = p.obj_id
+        ORDER BY e.id
+        LIMIT 1
+    ) prev
+    WHERE p.depth > 0
+)
+SELECT obj_id, depth::int AS depth, relation_id
+FROM path
+ORDER BY depth;
+
+-- name: GraphSharedFacts :many
+-- Objects that share facts with the object, those sharing the most first.
+-- A fact about several objects, such as a meeting, links each of them.
+SELECT other.obj_id AS id,
+    COUNT(*)::int AS shared_facts,
+    MAX(COALESCE(f.happened_at, f.created_at))::TIMESTAMPTZ AS last_shared_at
+FROM obj_fact mine
+JOIN fact f ON f.id = mine.fact_id AND f.deleted_at IS NULL
+JOIN obj_fact other ON other.fact_id = mine.fact_id AND other.obj_id <> mine.obj_id
+JOIN obj o ON o.id = other.obj_id AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+WHERE mine.obj_id = sqlc.arg('obj_id')
+GROUP BY other.obj_id
+HAVING COUNT(*) >= sqlc.arg('min_shared')::int
+ORDER BY shared_facts DESC, last_shared_at DESC, other.obj_id
+LIMIT sqlc.arg('max_nodes');
+
+-- name: ListGraphNodes :many
+SELECT o.id, o.name, o.id_string, o.photo,
+    COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', t.id, 'name', t.name, 'icon', t.icon) ORDER BY t.name)
+        FROM obj_type_value otv
+        JOIN obj_type t ON t.id = otv.type_id
+        WHERE otv.obj_id = o.id AND otv.deleted_at IS NULL
+    ), '[]')::jsonb AS types
+FROM obj o
+WHERE o.id = ANY(sqlc.arg('ids')::uuid[]) AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL;
+
+-- name: ListGraphEdges :many
+-- Relationships with both objects among ids
+SELECT r.id, r.type_id, t.name AS type_name, t.inverse_name, t.directional,
+    r.from_obj_id, r.to_obj_id, r.attributes, r.starts_at, r.ends_at
+FROM obj_relation r
+JOIN obj_relation_type t ON t.id = r.type_id
+WHERE r.org_id = sqlc.arg('org_id') AND r.deleted_at IS NULL
+  AND r.from_obj_id = ANY(sqlc.arg('ids')::uuid[])
+  AND r.to_obj_id = ANY(sqlc.arg('ids')::uuid[])
+  AND (sqlc.narg('relation_type_ids')::uuid[] IS NULL OR r.type_id = ANY(sqlc.narg('relation_type_ids')::uuid[]))
+ORDER BY r.created_at, r.id;
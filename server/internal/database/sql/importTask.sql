@@ -52,7 +52,8 @@ WHERE org_id = $1;
 
 -- name: GetObjectByIDString :one
 SELECT * FROM obj
-WHERE id_string = $1 OR $1 = ANY(aliases)
+WHERE org_id = $2
+AND (id_string = $1 OR $1 = ANY(aliases))
 AND deleted_at IS NULL
 ORDER BY (id_string = $1) DESC
 LIMIT 1;
@@ -5,18 +5,23 @@ RETURNING *;
 
 -- name: CreateCreatorList :one
 INSERT INTO creator_list (creator_id, list_id, params)
-VALUES ($1, $2, '{}')
+SELECT $1, $2, '{}'
+WHERE EXISTS (
+  SELECT 1 FROM list l
+  WHERE l.id = $2 AND l.org_id = $3 AND l.deleted_at IS NULL
+)
 RETURNING *;
 
 -- name: UpdateList :one
 UPDATE list
 SET name = $2, description = $3, filter_setting = $4, last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $5 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteList :exec
-DELETE FROM list
-WHERE list.id = $1
+-- name: DeleteList :execrows
+UPDATE list
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE list.id = $1 AND list.org_id = $2 AND list.deleted_at IS NULL
 AND NOT EXISTS (
   SELECT 1
   FROM creator_list
@@ -26,17 +31,17 @@ AND NOT EXISTS (
 -- name: GetListByID :one
 SELECT l.id
 FROM list l
-WHERE l.id = $1 AND l.deleted_at IS NULL;
+WHERE l.id = $1 AND l.org_id = $2 AND l.deleted_at IS NULL;
 
 -- name: UpdateCreatorList :one
 UPDATE creator_list
 SET params = $2, last_updated = CURRENT_TIMESTAMP
-WHERE id=$1
+WHERE id=$1 AND creator_id=$3
 RETURNING *;
 
--- name: DeleteCreatorList :exec
+-- name: DeleteCreatorList :execrows
 DELETE FROM creator_list
-WHERE id=$1;
+WHERE id=$1 AND creator_id=$2;
 
 -- name: ListListsByOrgID :many
 SELECT l.*, c.username as creator_name
@@ -63,4 +68,4 @@ ORDER BY cl.last_updated DESC;
 SELECT cl.*, l.name as list_name, l.description as list_description, l.filter_setting as list_filter_setting
 FROM creator_list cl
 JOIN list l ON cl.list_id = l.id
-WHERE cl.id = $1 AND l.deleted_at IS NULL;
\ No newline at end of file
+WHERE cl.id = $1 AND cl.creator_id = $2 AND l.deleted_at IS NULL;
\ No newline at end of file
@@ -1,4 +1,4 @@
--- name: MergeObjects :exec
+-- name: MergeObjects :one
 WITH target_org AS (
     -- Get organization ID for the target object once
     SELECT c.org_id
@@ -172,6 +172,29 @@ update_task_text AS (
     )
     RETURNING 1
 ),
+-- Move relationships of the sources to the target
+update_relations AS (
+    UPDATE obj_relation r
+    SET from_obj_id = CASE WHEN r.from_obj_id = ANY($2::uuid[]) THEN $1 ELSE r.from_obj_id END,
+        to_obj_id = CASE WHEN r.to_obj_id = ANY($2::uuid[]) THEN $1 ELSE r.to_obj_id END,
+        last_updated = CURRENT_TIMESTAMP
+    WHERE (r.from_obj_id = ANY($2::uuid[]) OR r.to_obj_id = ANY($2::uuid[]))
+    AND NOT (r.from_obj_id = ANY(array_append($2::uuid[], $1)) AND r.to_obj_id = ANY(array_append($2::uuid[], $1)))
+    AND r.deleted_at IS NULL
+    AND r.org_id IN (SELECT org_id FROM target_org)
+    RETURNING 1
+),
+-- Relationships among the merged objects would relate the target to itself
+delete_inner_relations AS (
+    UPDATE obj_relation r
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE (r.from_obj_id = ANY($2::uuid[]) OR r.to_obj_id = ANY($2::uuid[]))
+    AND r.from_obj_id = ANY(array_append($2::uuid[], $1))
+    AND r.to_obj_id = ANY(array_append($2::uuid[], $1))
+    AND r.deleted_at IS NULL
+    AND r.org_id IN (SELECT org_id FROM target_org)
+    RETURNING 1
+),
 -- Mark source objects as deleted
 mark_deleted AS (
     UPDATE obj o
@@ -192,9 +215,11 @@ INSERT INTO object_merge_history (
     target_object_id,
     source_object_ids,
     merged_at,
-    creator_id
+    creator_id,
+    org_id,
+    snapshot
 )
-VALUES ($1, $2, CURRENT_TIMESTAMP, $3)
+VALUES ($1, $2, CURRENT_TIMESTAMP, $3, (SELECT org_id FROM target_org), $4)
 RETURNING id;
 
 -- name: ValidateMergeObjects :one
@@ -240,4 +265,265 @@ SELECT
     COALESCE(
         (SELECT org_id FROM obj_check), 
         NULL
-    ) as objects_org_id;
\ No newline at end of file
+    ) as objects_org_id;
+
+-- name: SnapshotObjectMerge :one
+-- What merging the sources into the target is about to change, so the merge
+-- can be undone. Objects are in the shape of their audit events.
+SELECT jsonb_build_object(
+    'target', (
+        SELECT audit_row(to_jsonb(o)) FROM obj o
+        WHERE o.id = sqlc.arg('target_object_id') AND o.org_id = sqlc.arg('org_id')
+    ),
+    'sources', COALESCE((
+        SELECT jsonb_agg(audit_row(to_jsonb(o))) FROM obj o
+        WHERE o.id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+    ), '[]'),
+    'type_values', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', otv.id, 'obj_id', otv.obj_id, 'type_id', otv.type_id, 'type_values', otv.type_values))
+        FROM obj_type_value otv
+        JOIN obj o ON o.id = otv.obj_id
+        WHERE (otv.obj_id = sqlc.arg('target_object_id') OR otv.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]))
+        AND o.org_id = sqlc.arg('org_id')
+        AND otv.deleted_at IS NULL
+    ), '[]'),
+    'facts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lf.obj_id, 'id', lf.fact_id))
+        FROM obj_fact lf
+        JOIN obj o ON o.id = lf.obj_id
+        WHERE lf.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+    ), '[]'),
+    'tasks', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lt.obj_id, 'id', lt.task_id))
+        FROM obj_task lt
+        JOIN obj o ON o.id = lt.obj_id
+        WHERE lt.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+    ), '[]'),
+    'steps', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', ls.obj_id, 'id', ls.id))
+        FROM obj_step ls
+        JOIN obj o ON o.id = ls.obj_id
+        WHERE ls.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+        AND ls.deleted_at IS NULL
+    ), '[]'),
+    'tags', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('obj_id', lg.obj_id, 'id', lg.tag_id))
+        FROM obj_tag lg
+        JOIN obj o ON o.id = lg.obj_id
+        WHERE (lg.obj_id = sqlc.arg('target_object_id') OR lg.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]))
+        AND o.org_id = sqlc.arg('org_id')
+    ), '[]'),
+    'relations', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', r.id, 'from_obj_id', r.from_obj_id, 'to_obj_id', r.to_obj_id))
+        FROM obj_relation r
+        WHERE (r.from_obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) OR r.to_obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]))
+        AND r.org_id = sqlc.arg('org_id')
+        AND r.deleted_at IS NULL
+    ), '[]'),
+    'fact_texts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', f.id, 'text', f.text))
+        FROM fact f
+        WHERE f.org_id = sqlc.arg('org_id')
+        AND f.deleted_at IS NULL
+        AND f.text LIKE ANY(
+            SELECT '%' || source_id::text || '%'
+            FROM unnest(sqlc.arg('source_object_ids')::uuid[]) AS source_id
+        )
+    ), '[]'),
+    'task_texts', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', t.id, 'text', t.content))
+        FROM task t
+        WHERE t.org_id = sqlc.arg('org_id')
+        AND t.deleted_at IS NULL
+        AND t.content LIKE ANY(
+            SELECT '%' || source_id::text || '%'
+            FROM unnest(sqlc.arg('source_object_ids')::uuid[]) AS source_id
+        )
+    ), '[]')
+)::jsonb AS snapshot;
+
+-- name: GetObjectMergeHistory :one
+SELECT * FROM object_merge_history
+WHERE id = $1 AND org_id = $2
+FOR UPDATE;
+
+-- name: ListObjectMergeHistory :many
+SELECT h.id, h.target_object_id, h.source_object_ids, h.merged_at, h.creator_id,
+  COALESCE(c.username, '')::text AS creator_username, h.undone_at, h.undone_by,
+  (h.snapshot IS NOT NULL AND h.undone_at IS NULL AND NOT EXISTS (
+    SELECT 1 FROM unnest(h.source_object_ids) s (id)
+    WHERE NOT EXISTS (SELECT 1 FROM obj o WHERE o.id = s.id)
+  ))::boolean AS undoable
+FROM object_merge_history h
+LEFT JOIN creator c ON h.creator_id = c.id
+WHERE h.org_id = sqlc.arg('org_id')
+  AND (sqlc.narg('object_id')::uuid IS NULL
+    OR h.target_object_id = sqlc.narg('object_id')
+    OR sqlc.narg('object_id') = ANY(h.source_object_ids))
+ORDER BY h.merged_at DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountPurgedMergeSources :one
+-- Sources of a merge that were purged from the trash since
+SELECT COUNT(*)::int AS purged
+FROM unnest(sqlc.arg('source_object_ids')::uuid[]) s (id)
+WHERE NOT EXISTS (SELECT 1 FROM obj o WHERE o.id = s.id);
+
+-- name: ListMergeConflictEvents :many
+-- Changes made after a merge to what undoing it would set back
+SELECT e.entity_type, e.entity_id, e.action, e.before, e.after, e.created_at,
+  COALESCE(a.username, '')::text AS actor_username
+FROM audit_event e
+LEFT JOIN creator a ON e.actor_id = a.id
+WHERE e.org_id = sqlc.arg('org_id')
+  AND e.entity_id = ANY(sqlc.arg('entity_ids')::uuid[])
+  AND e.entity_type IN ('obj', 'obj_type_value', 'fact', 'task', 'obj_relation')
+  AND e.created_at > sqlc.arg('merged_at')
+ORDER BY e.seq;
+
+-- name: RestoreMergedObject :execrows
+UPDATE obj
+SET deleted_at = NULL,
+    id_string = COALESCE(sqlc.narg('id_string'), id_string)
+WHERE id = sqlc.arg('id')
+  AND org_id = sqlc.arg('org_id')
+  AND deleted_at IS NOT NULL;
+
+-- name: RestoreMergeTarget :execrows
+-- Sets back the fields of the target of a merge, null fields are kept
+UPDATE obj
+SET name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    id_string = COALESCE(sqlc.narg('id_string'), id_string),
+    aliases = COALESCE(sqlc.narg('aliases')::text[], aliases)
+WHERE id = sqlc.arg('id')
+  AND org_id = sqlc.arg('org_id');
+
+-- name: MoveMergedLinksBack :one
+WITH target AS (
+    SELECT o.id FROM obj o
+    WHERE o.id = sqlc.arg('target_object_id') AND o.org_id = sqlc.arg('org_id')
+),
+moved_facts AS (
+    UPDATE obj_fact
+    SET obj_id = sqlc.arg('source_object_id')
+    WHERE obj_id IN (SELECT id FROM target)
+    AND fact_id = ANY(sqlc.arg('fact_ids')::uuid[])
+    RETURNING 1
+),
+moved_tasks AS (
+    UPDATE obj_task
+    SET obj_id = sqlc.arg('source_object_id')
+    WHERE obj_id IN (SELECT id FROM target)
+    AND task_id = ANY(sqlc.arg('task_ids')::uuid[])
+    RETURNING 1
+),
+moved_steps AS (
+    UPDATE obj_step
+    SET obj_id = sqlc.arg('source_object_id'),
+        last_updated = CURRENT_TIMESTAMP
+    WHERE obj_id IN (SELECT id FROM target)
+    AND id = ANY(sqlc.arg('step_ids')::uuid[])
+    RETURNING 1
+)
+SELECT
+    (SELECT COUNT(*) FROM moved_facts)::int AS facts,
+    (SELECT COUNT(*) FROM moved_tasks)::int AS tasks,
+    (SELECT COUNT(*) FROM moved_steps)::int AS steps;
+
+-- name: RemoveMergedTags :execrows
+DELETE FROM obj_tag
+WHERE obj_id = sqlc.arg('target_object_id')
+  AND tag_id = ANY(sqlc.arg('tag_ids')::uuid[])
+  AND EXISTS (
+    SELECT 1 FROM obj o
+    WHERE o.id = obj_tag.obj_id AND o.org_id = sqlc.arg('org_id')
+  );
+
+-- name: RestoreMergedFactText :execrows
+UPDATE fact
+SET text = $2
+WHERE id = $1 AND org_id = $3;
+
+-- name: RestoreMergedTaskText :execrows
+UPDATE task
+SET content = $2
+WHERE id = $1 AND org_id = $3;
+
+-- name: MarkObjectMergeUndone :execrows
+UPDATE object_merge_history
+SET undone_at = CURRENT_TIMESTAMP,
+    undone_by = $2
+WHERE id = $1 AND org_id = $3
+  AND undone_at IS NULL;
+
+-- name: ListObjectsForMerge :many
+SELECT o.id, o.name, o.description, o.id_string, o.aliases
+FROM obj o
+WHERE o.id = ANY(sqlc.arg('object_ids')::uuid[])
+  AND o.org_id = sqlc.arg('org_id')
+  AND o.deleted_at IS NULL;
+
+-- name: ListObjectTypeValuesForMerge :many
+SELECT otv.obj_id, otv.type_id, ot.name AS type_name, otv.type_values
+FROM obj_type_value otv
+JOIN obj_type ot ON ot.id = otv.type_id
+JOIN obj o ON o.id = otv.obj_id
+WHERE otv.obj_id = ANY(sqlc.arg('object_ids')::uuid[])
+  AND o.org_id = sqlc.arg('org_id')
+  AND otv.deleted_at IS NULL
+ORDER BY ot.name, ot.id;
+
+-- name: ListObjectStepsForMerge :many
+SELECT os.obj_id, s.id AS step_id, s.name AS step_name, f.id AS funnel_id, f.name AS funnel_name
+FROM obj_step os
+JOIN step s ON s.id = os.step_id
+JOIN funnel f ON f.id = s.funnel_id
+JOIN obj o ON o.id = os.obj_id
+WHERE os.obj_id = ANY(sqlc.arg('object_ids')::uuid[])
+  AND o.org_id = sqlc.arg('org_id')
+  AND os.deleted_at IS NULL
+  AND f.deleted_at IS NULL
+ORDER BY f.name, f.id, s.step_order;
+
+-- name: CountObjectMergeMoves :one
+-- What MergeObjects would move from the sources to the target
+SELECT
+    (SELECT COUNT(DISTINCT lf.fact_id)
+     FROM obj_fact lf
+     JOIN obj o ON o.id = lf.obj_id
+     WHERE lf.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+    )::int AS facts,
+    (SELECT COUNT(DISTINCT lt.task_id)
+     FROM obj_task lt
+     JOIN obj o ON o.id = lt.obj_id
+     WHERE lt.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+    )::int AS tasks,
+    (SELECT COUNT(DISTINCT lg.tag_id)
+     FROM obj_tag lg
+     JOIN obj o ON o.id = lg.obj_id
+     WHERE lg.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+     AND NOT EXISTS (
+        SELECT 1 FROM obj_tag t
+        WHERE t.obj_id = sqlc.arg('target_object_id') AND t.tag_id = lg.tag_id
+     )
+    )::int AS tags,
+    (SELECT COUNT(*)
+     FROM obj_step ls
+     JOIN obj o ON o.id = ls.obj_id
+     WHERE ls.obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+     AND ls.deleted_at IS NULL
+     AND NOT EXISTS (
+        SELECT 1 FROM obj_step t
+        WHERE t.obj_id = sqlc.arg('target_object_id') AND t.step_id = ls.step_id AND t.deleted_at IS NULL
+     )
+    )::int AS steps,
+    (SELECT COUNT(*)
+     FROM obj_relation r
+     WHERE (r.from_obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]) OR r.to_obj_id = ANY(sqlc.arg('source_object_ids')::uuid[]))
+     AND r.org_id = sqlc.arg('org_id')
+     AND r.deleted_at IS NULL
+     -- Relationships among the merged objects are dropped
+     AND NOT (r.from_obj_id = ANY(array_append(sqlc.arg('source_object_ids')::uuid[], sqlc.arg('target_object_id')))
+        AND r.to_obj_id = ANY(array_append(sqlc.arg('source_object_ids')::uuid[], sqlc.arg('target_object_id'))))
+    )::int AS relations;
@@ -0,0 +1,68 @@
+-- name: CreateObjRelationType :one
+INSERT INTO obj_relation_type (org_id, name, inverse_name, directional, description, creator_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateObjRelationType :one
+UPDATE obj_relation_type
+SET name = $2, inverse_name = $3, directional = $4, description = $5, last_updated = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $6 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteObjRelationType :execrows
+-- Types still in use are kept
+UPDATE obj_relation_type
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE obj_relation_type.id = $1 AND org_id = $2 AND deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM obj_relation WHERE type_id = $1 AND deleted_at IS NULL
+  );
+
+-- name: ListObjRelationTypes :many
+SELECT t.*,
+  (SELECT COUNT(*) FROM obj_relation r WHERE r.type_id = t.id AND r.deleted_at IS NULL)::int AS relation_count
+FROM obj_relation_type t
+WHERE t.org_id = $1 AND t.deleted_at IS NULL
+ORDER BY lower(t.name);
+
+-- name: CreateObjRelation :one
+-- Both objects and the type must belong to the org
+INSERT INTO obj_relation (org_id, type_id, from_obj_id, to_obj_id, attributes, starts_at, ends_at, creator_id)
+SELECT sqlc.arg('org_id'), t.id, f.id, o.id, sqlc.arg('attributes'), sqlc.narg('starts_at'), sqlc.narg('ends_at'), sqlc.arg('creator_id')
+FROM obj_relation_type t, obj f, obj o
+WHERE t.id = sqlc.arg('type_id') AND t.org_id = sqlc.arg('org_id') AND t.deleted_at IS NULL
+  AND f.id = sqlc.arg('from_obj_id') AND f.org_id = sqlc.arg('org_id') AND f.deleted_at IS NULL
+  AND o.id = sqlc.arg('to_obj_id') AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateObjRelation :one
+-- Only the attributes and dates of a relationship change, the objects and
+-- the type make another relationship
+UPDATE obj_relation
+SET attributes = sqlc.arg('attributes'),
+    starts_at = sqlc.narg('starts_at'),
+    ends_at = sqlc.narg('ends_at'),
+    last_updated = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+  AND (from_obj_id = sqlc.arg('obj_id') OR to_obj_id = sqlc.arg('obj_id'))
+  AND org_id = sqlc.arg('org_id')
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteObjRelation :execrows
+UPDATE obj_relation
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+  AND (from_obj_id = sqlc.arg('obj_id') OR to_obj_id = sqlc.arg('obj_id'))
+  AND org_id = sqlc.arg('org_id')
+  AND deleted_at IS NULL;
+
+-- name: RestoreMergedRelation :execrows
+-- Moves a relationship back to the objects it had before a merge
+UPDATE obj_relation
+SET from_obj_id = sqlc.arg('from_obj_id'),
+    to_obj_id = sqlc.arg('to_obj_id'),
+    deleted_at = NULL,
+    last_updated = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+  AND org_id = sqlc.arg('org_id');
@@ -0,0 +1,80 @@
+-- name: ListObjectHistory :many
+-- A page of the changes of the object and of its type values while they
+-- belonged to it, newest first. Type values moved by a merge show in the
+-- history of both objects. An update that does not move a type value carries
+-- neither obj_id nor type_id, its owner is the one before the next move or
+-- delete, or the current one.
+SELECT e.id, e.actor_id, COALESCE(a.username, '')::text AS actor_username,
+  e.impersonator_id, e.entity_type, e.entity_id, e.action, e.before, e.after, e.created_at,
+  (CASE WHEN e.entity_type = 'obj_type_value' THEN COALESCE(
+    (e.after ->> 'type_id')::uuid,
+    (e.before ->> 'type_id')::uuid,
+    otv.type_id,
+    (SELECT (m.before ->> 'type_id')::uuid FROM audit_event m
+     WHERE m.entity_id = e.entity_id AND m.before ->> 'type_id' IS NOT NULL
+     LIMIT 1)
+  ) END)::uuid AS object_type_id
+FROM audit_event e
+LEFT JOIN creator a ON e.actor_id = a.id
+LEFT JOIN obj_type_value otv ON e.entity_type = 'obj_type_value' AND otv.id = e.entity_id
+WHERE e.org_id = sqlc.arg('org_id')
+  AND (
+    (e.entity_type = 'obj' AND e.entity_id = sqlc.arg('obj_id'))
+    OR (e.entity_type = 'obj_type_value' AND e.entity_id IN (
+      SELECT t.id FROM obj_type_value t WHERE t.obj_id = sqlc.arg('obj_id')
+      UNION
+      SELECT m.entity_id FROM audit_event m
+      WHERE m.entity_type = 'obj_type_value'
+        AND ((m.after ->> 'obj_id')::uuid = sqlc.arg('obj_id') OR (m.before ->> 'obj_id')::uuid = sqlc.arg('obj_id'))
+    ) AND sqlc.arg('obj_id') IN (
+      (e.before ->> 'obj_id')::uuid,
+      (e.after ->> 'obj_id')::uuid,
+      CASE WHEN e.before ->> 'obj_id' IS NULL AND e.after ->> 'obj_id' IS NULL THEN COALESCE(
+        (SELECT (m.before ->> 'obj_id')::uuid FROM audit_event m
+         WHERE m.entity_id = e.entity_id AND m.seq > e.seq AND m.before ->> 'obj_id' IS NOT NULL
+         ORDER BY m.seq
+         LIMIT 1),
+        otv.obj_id
+      ) END
+    ))
+  )
+ORDER BY e.seq DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListObjectHistoryEvents :many
+-- Changes of the object and of every type value that belonged to it after the
+-- time, newest first, to revert them. Type values are found by their create,
+-- move and delete events, their updates only carry the changed columns.
+SELECT e.id, e.actor_id, COALESCE(a.username, '')::text AS actor_username,
+  e.impersonator_id, e.entity_type, e.entity_id, e.action, e.before, e.after, e.created_at
+FROM audit_event e
+LEFT JOIN creator a ON e.actor_id = a.id
+WHERE e.org_id = sqlc.arg('org_id')
+  AND e.created_at > sqlc.arg('since')
+  AND (
+    (e.entity_type = 'obj' AND e.entity_id = sqlc.arg('obj_id'))
+    OR (e.entity_type = 'obj_type_value' AND e.entity_id IN (
+      SELECT otv.id FROM obj_type_value otv WHERE otv.obj_id = sqlc.arg('obj_id')
+      UNION
+      SELECT m.entity_id FROM audit_event m
+      WHERE m.entity_type = 'obj_type_value'
+        AND m.created_at > sqlc.arg('since')
+        AND ((m.after ->> 'obj_id')::uuid = sqlc.arg('obj_id') OR (m.before ->> 'obj_id')::uuid = sqlc.arg('obj_id'))
+    ))
+  )
+ORDER BY e.seq DESC;
+
+-- name: GetObjectHistoryState :one
+-- The object in the shape of its audit events, deleted or not
+SELECT audit_row(to_jsonb(o))::jsonb AS data
+FROM obj o
+WHERE o.id = $1 AND o.org_id = $2;
+
+-- name: ListObjectTypeValueHistoryStates :many
+-- The type values in the shape of their audit events, of the object or with
+-- one of the ids
+SELECT audit_row(to_jsonb(otv))::jsonb AS data
+FROM obj_type_value otv
+JOIN obj o ON o.id = otv.obj_id
+WHERE (otv.obj_id = sqlc.arg('obj_id') OR otv.id = ANY(sqlc.arg('ids')::uuid[]))
+  AND o.org_id = sqlc.arg('org_id');
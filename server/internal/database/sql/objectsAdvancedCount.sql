@@ -57,6 +57,16 @@ filtered_objects AS (
         ($4::uuid[] IS NULL OR od.tag_ids && $4) AND
         -- Filter by object types
         ($5::uuid[] IS NULL OR od.type_ids && $5) AND
+        -- Filter by relationships of the given types, with the given objects
+        (($10::uuid[] IS NULL AND $11::uuid[] IS NULL) OR EXISTS (
+            SELECT 1
+            FROM obj_relation r
+            JOIN obj other ON other.id = CASE WHEN r.from_obj_id = od.id THEN r.to_obj_id ELSE r.from_obj_id END
+            WHERE (r.from_obj_id = od.id OR r.to_obj_id = od.id)
+              AND r.deleted_at IS NULL AND other.deleted_at IS NULL
+              AND ($10::uuid[] IS NULL OR r.type_id = ANY($10::uuid[]))
+              AND ($11::uuid[] IS NULL OR other.id = ANY($11::uuid[]))
+        )) AND
         -- Filter by type value criteria 1 with LIKE
         ((COALESCE($6::jsonb, 'null'::jsonb) = 'null'::jsonb) OR
          EXISTS (
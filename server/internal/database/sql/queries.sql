@@ -27,13 +27,13 @@ RETURNING *;
 -- name: UpdateObjectType :one
 UPDATE obj_type
 SET name = $2, description = $3, fields = $4, icon = $5
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $6 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteObjectType :execrows
 UPDATE obj_type
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE obj_type.id = $1 AND deleted_at IS NULL
+WHERE obj_type.id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_type_value WHERE type_id = $1
   );
@@ -53,7 +53,7 @@ LIMIT $3 OFFSET $4;
 
 -- name: GetObjectTypeByID :one
 SELECT * FROM obj_type
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: CountObjectTypes :one
@@ -73,22 +73,20 @@ VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetFunnel :one
-SELECT f.*, c.org_id,
+SELECT f.*,
   (SELECT COUNT(*) FROM obj_step os
   JOIN step s ON s.id = os.step_id
   WHERE s.funnel_id = f.id) AS object_count
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE f.id = $1 AND f.deleted_at IS NULL;
+WHERE f.id = $1 AND f.org_id = $2 AND f.deleted_at IS NULL;
 
 -- name: ListFunnels :many
-SELECT f.*, c.org_id,
+SELECT f.*,
     (SELECT COUNT(*) FROM obj_step os
     JOIN step s ON s.id = os.step_id
     WHERE s.funnel_id = f.id) AS object_count
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE c.org_id = $1 AND f.deleted_at IS NULL
+WHERE f.org_id = $1 AND f.deleted_at IS NULL
   AND ($2::text = '' OR (
     f.name ILIKE '%' || $2 || '%' OR
     f.description ILIKE '%' || $2 || '%' OR
@@ -108,13 +106,13 @@ LIMIT $3 OFFSET $4;
 -- name: UpdateFunnel :one
 UPDATE funnel
 SET name = $2, description = $3
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $4 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteFunnel :exec
+-- name: DeleteFunnel :execrows
 UPDATE funnel
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE funnel.id = $1 AND deleted_at IS NULL
+WHERE funnel.id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_step os
     JOIN step s ON s.id = os.step_id
@@ -137,13 +135,13 @@ WHERE s.id = $1 AND s.deleted_at IS NULL;
 -- name: UpdateStep :one
 UPDATE step
 SET name = $2, definition = $3, example = $4, action = $5, step_order = $6, last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND funnel_id = $7 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteStep :exec
+-- name: DeleteStep :execrows
 UPDATE step
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE step.id = $1 AND deleted_at IS NULL
+WHERE step.id = $1 AND funnel_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_step os WHERE os.step_id = $1
   );
@@ -158,13 +156,14 @@ ORDER BY s.step_order;
 -- name: UpdateObjStep :exec
 UPDATE obj_step
 SET step_id = $2
-WHERE step_id = $1;
+WHERE step_id = $1
+  AND EXISTS (SELECT 1 FROM step s WHERE s.id = $1 AND s.funnel_id = $3)
+  AND EXISTS (SELECT 1 FROM step s WHERE s.id = $2 AND s.funnel_id = $3);
 
 -- name: CountFunnels :one
 SELECT COUNT(*)
 FROM funnel f
-JOIN creator c ON c.id = f.creator_id
-WHERE c.org_id = $1 AND f.deleted_at IS NULL
+WHERE f.org_id = $1 AND f.deleted_at IS NULL
 AND ($2::text = '' OR (
   f.name ILIKE '%' || $2 || '%' OR
   f.description ILIKE '%' || $2 || '%' OR
@@ -187,15 +186,29 @@ RETURNING *;
 -- name: UpdateObject :one
 UPDATE obj
 SET name = $2, description = $3, id_string = $4, aliases = $5
-WHERE id = $1
+WHERE id = $1 AND org_id = $6
 RETURNING *;
 
--- name: DeleteObject :exec
-UPDATE obj SET deleted_at = NOW() WHERE id = $1;
+-- name: DeleteObject :execrows
+-- The funnel steps and relationships of the object go to the trash with it,
+-- at the same time, so restoring the object can tell them apart
+WITH trashed_steps AS (
+    UPDATE obj_step
+    SET deleted_at = NOW()
+    WHERE obj_step.obj_id = $1 AND obj_step.deleted_at IS NULL
+      AND EXISTS (SELECT 1 FROM obj o WHERE o.id = $1 AND o.org_id = $2 AND o.deleted_at IS NULL)
+),
+trashed_relations AS (
+    UPDATE obj_relation
+    SET deleted_at = NOW()
+    WHERE (obj_relation.from_obj_id = $1 OR obj_relation.to_obj_id = $1)
+      AND obj_relation.org_id = $2 AND obj_relation.deleted_at IS NULL
+)
+UPDATE obj SET deleted_at = NOW() WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL;
 
 -- name: GetObjectDetails :one
 WITH object_data AS (
-    SELECT o.id, o.name, o.photo, o.description, o.id_string, o.creator_id, o.created_at,
+    SELECT o.id, o.name, o.photo, o.photo_attachment_id, o.description, o.id_string, o.creator_id, o.created_at,
            c.org_id, o.aliases,
            coalesce(
             json_agg(DISTINCT jsonb_build_object('id', t.id, 'name', t.name, 'color_schema', t.color_schema)) FILTER (WHERE t.id IS NOT NULL), '[]') 
@@ -237,7 +250,30 @@ WITH object_data AS (
                'location', fact.location,
                'createdAt', fact.created_at
            )) FILTER (WHERE fact.id IS NOT NULL), '[]')
-           AS facts
+           AS facts,
+           -- Relationships read from this object, whichever end it is
+           coalesce((
+               SELECT json_agg(jsonb_build_object(
+                   'id', r.id,
+                   'typeId', rt.id,
+                   'typeName', CASE WHEN rt.directional AND r.to_obj_id = o.id AND rt.inverse_name <> ''
+                                    THEN rt.inverse_name ELSE rt.name END,
+                   'directional', rt.directional,
+                   'direction', CASE WHEN r.from_obj_id = o.id THEN 'outgoing' ELSE 'incoming' END,
+                   'objectId', other.id,
+                   'objectName', other.name,
+                   'attributes', r.attributes,
+                   'startsAt', r.starts_at,
+                   'endsAt', r.ends_at,
+                   'createdAt', r.created_at
+               ) ORDER BY rt.name, other.name)
+               FROM obj_relation r
+               JOIN obj_relation_type rt ON rt.id = r.type_id
+               JOIN obj other ON other.id = CASE WHEN r.from_obj_id = o.id THEN r.to_obj_id ELSE r.from_obj_id END
+               WHERE (r.from_obj_id = o.id OR r.to_obj_id = o.id)
+               AND r.deleted_at IS NULL AND other.deleted_at IS NULL
+           ), '[]')
+           AS relations
     FROM obj o
     JOIN creator c ON o.creator_id = c.id
     LEFT JOIN obj_tag otg ON o.id = otg.obj_id
@@ -257,23 +293,34 @@ WITH object_data AS (
 SELECT *
 FROM object_data;
 
--- name: AddTagToObject :exec
-INSERT INTO obj_tag (obj_id, tag_id)
-SELECT $1, $2
-FROM obj o
-JOIN creator c ON o.creator_id = c.id
-JOIN tag t ON t.org_id = c.org_id
-WHERE o.id = $1 AND t.id = $2 AND c.org_id = $3
-ON CONFLICT DO NOTHING;
+-- name: AddTagToObject :one
+-- Tags the object unless it already is. No row is returned when the object or
+-- the tag is not of the org.
+WITH target AS (
+  SELECT o.id AS obj_id, t.id AS tag_id
+  FROM obj o
+  JOIN creator c ON o.creator_id = c.id
+  JOIN tag t ON t.org_id = c.org_id
+  WHERE o.id = sqlc.arg('obj_id') AND t.id = sqlc.arg('tag_id') AND c.org_id = sqlc.arg('org_id') AND t.deleted_at IS NULL
+), added AS (
+  INSERT INTO obj_tag (obj_id, tag_id)
+  SELECT obj_id, tag_id FROM target
+  ON CONFLICT DO NOTHING
+)
+SELECT obj_id FROM target;
 
--- name: RemoveTagFromObject :exec
-DELETE FROM obj_tag
-WHERE obj_id = $1 AND tag_id = $2
-AND EXISTS (
-  SELECT 1 FROM obj o
+-- name: RemoveTagFromObject :one
+-- Untags the object. No row is returned when the object is not of the org.
+WITH target AS (
+  SELECT o.id AS obj_id
+  FROM obj o
   JOIN creator c ON o.creator_id = c.id
-  WHERE o.id = $1 AND c.org_id = $3
-);
+  WHERE o.id = sqlc.arg('obj_id') AND c.org_id = sqlc.arg('org_id')
+), removed AS (
+  DELETE FROM obj_tag
+  WHERE obj_id IN (SELECT obj_id FROM target) AND tag_id = sqlc.arg('tag_id')
+)
+SELECT obj_id FROM target;
 
 -- name: AddObjectTypeValue :one
 WITH org_check AS (
@@ -291,6 +338,7 @@ WITH org_check AS (
   WHERE
     c_obj.org_id = c_ot.org_id              -- Ensure both creators belong to the same org
     AND o.id = $1                           -- obj_id parameter
+    AND c_obj.org_id = $4                   -- org of the caller
 )
 INSERT INTO obj_type_value (obj_id, type_id, type_values)
 SELECT $1, $2, $3::jsonb
@@ -298,7 +346,7 @@ FROM
   org_check
 RETURNING *;
 
--- name: RemoveObjectTypeValue :exec
+-- name: RemoveObjectTypeValue :execrows
 DELETE FROM obj_type_value
 WHERE obj_type_value.id = $1
 AND EXISTS (
@@ -319,16 +367,26 @@ WHERE obj_type_value.id = $1
 RETURNING *;
 
 -- name: CreateObjStep :one
-WITH existing_step AS (
+WITH org_check AS (
+  -- Both the object and the funnel of the step must belong to the org
+  SELECT 1
+  FROM obj o, step s
+  JOIN funnel f ON f.id = s.funnel_id
+  WHERE o.id = $1 AND o.org_id = $4
+    AND s.id = $2 AND f.org_id = $4
+),
+existing_step AS (
   -- First check if the step already exists
   SELECT * FROM obj_step
   WHERE obj_step.obj_id = $1 AND obj_step.step_id = $2 AND obj_step.deleted_at IS NULL
+    AND EXISTS (SELECT 1 FROM org_check)
 ),
 new_step AS (
   -- Try to insert if it doesn't exist
   INSERT INTO obj_step (obj_id, step_id, creator_id)
   SELECT $1, $2, $3
   WHERE NOT EXISTS (SELECT 1 FROM existing_step)
+    AND EXISTS (SELECT 1 FROM org_check)
   RETURNING *
 ),
 update_old_steps AS (
@@ -336,6 +394,7 @@ update_old_steps AS (
   UPDATE obj_step
   SET deleted_at = CURRENT_TIMESTAMP
   WHERE obj_id = $1
+    AND EXISTS (SELECT 1 FROM org_check)
     AND step_id IN (
       SELECT id
       FROM step
@@ -353,23 +412,27 @@ combined_result AS (
 SELECT * FROM combined_result 
 LIMIT 1; -- Ensure we only get one row
 
--- name: SoftDeleteObjStep :exec
+-- name: SoftDeleteObjStep :execrows
 UPDATE obj_step
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL;
+WHERE id = $1 AND deleted_at IS NULL
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2);
 
--- name: HardDeleteObjStep :exec
+-- name: HardDeleteObjStep :execrows
 DELETE FROM obj_step
-WHERE id = $1;
+WHERE id = $1
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2);
 
 -- name: GetObjStep :one
 SELECT * FROM obj_step
-WHERE id = $1;
+WHERE id = $1
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $2);
 
--- name: UpdateObjStepSubStatus :exec
+-- name: UpdateObjStepSubStatus :execrows
 UPDATE obj_step
 SET sub_status = $2
-WHERE id = $1 AND deleted_at IS NULL;
+WHERE id = $1 AND deleted_at IS NULL
+  AND obj_id IN (SELECT o.id FROM obj o WHERE o.org_id = $3);
 
 -- Existing queries...
 
@@ -387,13 +450,13 @@ SET content = $2,
     assigned_id = $6,
     parent_id = $7,
     last_updated = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $8 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteTask :exec
+-- name: DeleteTask :execrows
 UPDATE task
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL;
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL;
 
 -- name: ListTasksByOrgID :many
 SELECT t.*, c.username as creator_name, a.username as assigned_name
@@ -426,12 +489,14 @@ SELECT t.*, c.username as creator_name, a.username as assigned_name
 FROM task t
 JOIN creator c ON t.creator_id = c.id
 LEFT JOIN creator a ON t.assigned_id = a.id
-WHERE t.id = $1 AND t.deleted_at IS NULL;
+WHERE t.id = $1 AND t.org_id = $2 AND t.deleted_at IS NULL;
 
 -- name: AddObjectsToTask :exec
 INSERT INTO obj_task (obj_id, task_id)
-SELECT unnest($1::uuid[]), $2
-WHERE EXISTS (
+SELECT o.id, $2
+FROM obj o
+WHERE o.id = ANY($1::uuid[]) AND o.org_id = $3
+AND EXISTS (
   SELECT 1 FROM task t
   JOIN creator c ON t.creator_id = c.id
   WHERE t.id = $2 AND c.org_id = $3
@@ -443,7 +508,11 @@ AND NOT EXISTS (
 
 -- name: RemoveObjectsFromTask :exec
 DELETE FROM obj_task
-WHERE task_id = $1 AND obj_id = ANY($2::uuid[]);
+WHERE task_id = $1 AND obj_id = ANY($2::uuid[])
+AND EXISTS (
+  SELECT 1 FROM task t
+  WHERE t.id = $1 AND t.org_id = $3
+);
 
 -- name: ListObjectsByTaskID :many
 SELECT o.id, o.name, o.description
@@ -648,26 +717,27 @@ AND status in ('todo', 'doing');
 -- Add these new queries to your existing queries.sql file
 
 -- name: CreateFact :one
-INSERT INTO fact (text, happened_at, location, creator_id)
-VALUES ($1, $2, $3, $4)
+INSERT INTO fact (text, happened_at, location, creator_id, kind, duration_minutes, direction, outcome)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: UpdateFact :one
 UPDATE fact
-SET text = $2, happened_at = $3, location = $4
-WHERE id = $1 AND deleted_at IS NULL
+SET text = $2, happened_at = $3, location = $4,
+    kind = $6, duration_minutes = $7, direction = $8, outcome = $9
+WHERE id = $1 AND org_id = $5 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteFact :exec
+-- name: DeleteFact :execrows
 UPDATE fact
 SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL;
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL;
 
 -- name: GetFactByID :one
 SELECT f.*, c.username as creator_name
 FROM fact f
 JOIN creator c ON f.creator_id = c.id
-WHERE f.id = $1 AND f.deleted_at IS NULL;
+WHERE f.id = $1 AND f.org_id = $2 AND f.deleted_at IS NULL;
 
 -- name: ListFactsByOrgID :many
 SELECT 
@@ -678,6 +748,10 @@ SELECT
     f.creator_id,
     c.username AS creator_name,
     f.created_at,
+    f.kind,
+    f.duration_minutes,
+    f.direction,
+    f.outcome,
     COALESCE(
         json_agg(
             json_build_object(
@@ -687,7 +761,22 @@ SELECT
             )
         ) FILTER (WHERE o.id IS NOT NULL),
         '[]'
-    ) AS related_objects
+    ) AS related_objects,
+    (
+        SELECT COALESCE(json_agg(json_build_object(
+            'kind', CASE WHEN fa.obj_id IS NOT NULL THEN 'object' ELSE 'creator' END,
+            'id', COALESCE(fa.obj_id, fa.creator_id),
+            'name', COALESCE(ao.name, ac.username)
+        )), '[]')
+        FROM fact_attendee fa
+        LEFT JOIN obj ao ON ao.id = fa.obj_id
+        LEFT JOIN creator ac ON ac.id = fa.creator_id
+        WHERE fa.fact_id = f.id
+    )::json AS attendees,
+    COALESCE(lr.revision, 1) > 1 AS edited,
+    (CASE WHEN lr.revision > 1 THEN lr.created_at END)::timestamptz AS edited_at,
+    lr.editor_id AS last_editor_id,
+    COALESCE(le.username, '')::text AS last_editor_name
 FROM 
     fact f
 JOIN 
@@ -696,12 +785,22 @@ LEFT JOIN
     obj_fact of ON f.id = of.fact_id
 LEFT JOIN 
     obj o ON of.obj_id = o.id
+LEFT JOIN LATERAL (
+    SELECT r.revision, r.editor_id, r.created_at
+    FROM fact_revision r
+    WHERE r.fact_id = f.id
+    ORDER BY r.revision DESC
+    LIMIT 1
+) lr ON true
+LEFT JOIN 
+    creator le ON le.id = lr.editor_id
 WHERE 
     c.org_id = $1 
     AND f.deleted_at IS NULL
     AND ($2::text = '' OR f.text ILIKE '%' || $2 || '%')
+    AND ($5::text = '' OR f.kind = $5)
 GROUP BY 
-    f.id, c.username
+    f.id, c.username, lr.revision, lr.editor_id, lr.created_at, le.username
 ORDER BY 
     f.happened_at DESC
 LIMIT $3 OFFSET $4;
@@ -715,24 +814,62 @@ JOIN
 WHERE 
     c.org_id = $1 
     AND f.deleted_at IS NULL
-    AND ($2::text = '' OR f.text ILIKE '%' || $2 || '%');
+    AND ($2::text = '' OR f.text ILIKE '%' || $2 || '%')
+    AND ($3::text = '' OR f.kind = $3);
 
 -- name: AddObjectsToFact :exec
+-- Links by hand, which also keeps an object the text mentioned linked once
+-- the mention is gone
 INSERT INTO obj_fact (obj_id, fact_id)
-SELECT unnest($1::uuid[]), $2
-WHERE EXISTS (
+SELECT o.id, $2
+FROM obj o
+WHERE o.id = ANY($1::uuid[]) AND o.org_id = $3
+AND EXISTS (
   SELECT 1 FROM fact f
   JOIN creator c ON f.creator_id = c.id
   WHERE f.id = $2 AND c.org_id = $3
 )
-AND NOT EXISTS (
-  SELECT 1 FROM obj_fact
-  WHERE obj_id = ANY($1::uuid[]) AND fact_id = $2
-);
+ON CONFLICT (obj_id, fact_id) DO UPDATE SET mentioned = false
+WHERE obj_fact.mentioned;
+
+-- name: AddMentionedObjectsToFact :exec
+-- Links the objects the text of the fact mentions, those already linked keep
+-- their link as it is
+INSERT INTO obj_fact (obj_id, fact_id, mentioned)
+SELECT o.id, sqlc.arg('fact_id'), true
+FROM obj o
+WHERE o.id = ANY(sqlc.arg('obj_ids')::uuid[]) AND o.org_id = sqlc.arg('org_id')
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = sqlc.arg('fact_id') AND f.org_id = sqlc.arg('org_id')
+)
+ON CONFLICT (obj_id, fact_id) DO NOTHING;
 
 -- name: RemoveObjectsFromFact :exec
 DELETE FROM obj_fact
-WHERE fact_id = $1 AND obj_id = ANY($2::uuid[]);
+WHERE fact_id = $1 AND obj_id = ANY($2::uuid[])
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = $1 AND f.org_id = $3
+);
+
+-- name: RemoveMentionedObjectsFromFact :exec
+-- Unlinks the objects among obj_ids that only a mention linked, except those
+-- among kept_ids
+DELETE FROM obj_fact
+WHERE fact_id = sqlc.arg('fact_id') AND mentioned
+AND obj_id = ANY(sqlc.arg('obj_ids')::uuid[])
+AND NOT obj_id = ANY(sqlc.arg('kept_ids')::uuid[])
+AND EXISTS (
+  SELECT 1 FROM fact f
+  WHERE f.id = sqlc.arg('fact_id') AND f.org_id = sqlc.arg('org_id')
+);
+
+-- name: ListMentionableCreators :many
+-- The creators among creator_ids that can be mentioned in the org
+SELECT id FROM creator
+WHERE id = ANY(sqlc.arg('creator_ids')::uuid[]) AND org_id = sqlc.arg('org_id')
+  AND active AND deleted_at IS NULL;
 
 -- name: GetObjectsForStep :many
 SELECT o.id, o.name, o.description,
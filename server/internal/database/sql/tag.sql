@@ -8,12 +8,13 @@ RETURNING *;
 -- name: UpdateTag :one
 UPDATE tag
 SET description = $2, color_schema = $3
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $4 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteTag :execrows
-DELETE FROM tag 
-WHERE id = $1 AND deleted_at IS NULL
+UPDATE tag
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_tag WHERE tag_id = $1
   );
@@ -0,0 +1,149 @@
+-- name: ListTrash :many
+-- Everything the org has in the trash, most recently deleted first, with the
+-- member who deleted it according to the audit log. Objects merged into
+-- another are left out, undoing the merge brings them back.
+WITH trash AS (
+    SELECT 'object'::text AS kind, 'obj'::text AS entity_type, o.id, o.name AS title, o.deleted_at
+    FROM obj o
+    WHERE o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NOT NULL
+      AND NOT EXISTS (
+        SELECT 1 FROM object_merge_history h
+        WHERE h.org_id = o.org_id AND o.id = ANY(h.source_object_ids) AND h.undone_at IS NULL
+      )
+    UNION ALL
+    SELECT 'fact', 'fact', f.id, f.text, f.deleted_at
+    FROM fact f
+    WHERE f.org_id = sqlc.arg('org_id') AND f.deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'task', 'task', t.id, t.content, t.deleted_at
+    FROM task t
+    WHERE t.org_id = sqlc.arg('org_id') AND t.deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'funnel', 'funnel', fu.id, fu.name, fu.deleted_at
+    FROM funnel fu
+    WHERE fu.org_id = sqlc.arg('org_id') AND fu.deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'tag', 'tag', tg.id, tg.name, tg.deleted_at
+    FROM tag tg
+    WHERE tg.org_id = sqlc.arg('org_id') AND tg.deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'list', 'list', l.id, l.name, l.deleted_at
+    FROM list l
+    WHERE l.org_id = sqlc.arg('org_id') AND l.deleted_at IS NOT NULL
+)
+SELECT tr.kind, tr.id, tr.title::text AS title, tr.deleted_at::TIMESTAMPTZ AS deleted_at,
+    d.actor_id AS deleted_by, COALESCE(c.username, '')::text AS deleted_by_username,
+    COUNT(*) OVER() AS total_count
+FROM trash tr
+LEFT JOIN LATERAL (
+    SELECT e.actor_id
+    FROM audit_event e
+    WHERE e.entity_id = tr.id AND e.entity_type = tr.entity_type AND e.action = 'delete'
+    ORDER BY e.created_at DESC
+    LIMIT 1
+) d ON true
+LEFT JOIN creator c ON c.id = d.actor_id
+WHERE sqlc.narg('kind')::text IS NULL OR tr.kind = sqlc.narg('kind')
+ORDER BY tr.deleted_at DESC, tr.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: RestoreTrashedObject :execrows
+-- Also brings back the funnel steps and relationships that went to the trash
+-- with the object. Objects merged into another are restored by undoing the
+-- merge.
+WITH trashed AS (
+    SELECT o.id, o.deleted_at
+    FROM obj o
+    WHERE o.id = sqlc.arg('id') AND o.org_id = sqlc.arg('org_id') AND o.deleted_at IS NOT NULL
+      AND NOT EXISTS (
+        SELECT 1 FROM object_merge_history h
+        WHERE h.org_id = o.org_id AND o.id = ANY(h.source_object_ids) AND h.undone_at IS NULL
+      )
+),
+steps AS (
+    UPDATE obj_step s
+    SET deleted_at = NULL
+    FROM trashed t
+    WHERE s.obj_id = t.id AND s.deleted_at = t.deleted_at
+),
+relations AS (
+    UPDATE obj_relation r
+    SET deleted_at = NULL
+    FROM trashed t
+    WHERE (r.from_obj_id = t.id OR r.to_obj_id = t.id) AND r.deleted_at = t.deleted_at
+)
+UPDATE obj
+SET deleted_at = NULL
+FROM trashed t
+WHERE obj.id = t.id;
+
+-- name: RestoreTrashedFact :execrows
+UPDATE fact
+SET deleted_at = NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: RestoreTrashedTask :execrows
+UPDATE task
+SET deleted_at = NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: RestoreTrashedFunnel :execrows
+UPDATE funnel
+SET deleted_at = NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: RestoreTrashedTag :execrows
+UPDATE tag
+SET deleted_at = NULL
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: RestoreTrashedList :execrows
+UPDATE list
+SET deleted_at = NULL, last_updated = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: ListOrgProfiles :many
+SELECT id, profile
+FROM org
+WHERE deleted_at IS NULL;
+
+-- name: PurgeTrash :one
+-- Deletes for good what the org put in the trash before the cutoff. Links,
+-- funnel steps, relationships and merges of the deleted rows go with them.
+WITH objects AS (
+    DELETE FROM obj
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+),
+facts AS (
+    DELETE FROM fact
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+),
+tasks AS (
+    DELETE FROM task
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+),
+funnels AS (
+    DELETE FROM funnel
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+),
+tags AS (
+    DELETE FROM tag
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+),
+lists AS (
+    DELETE FROM list
+    WHERE org_id = sqlc.arg('org_id') AND deleted_at < sqlc.arg('before')
+    RETURNING id
+)
+SELECT
+    (SELECT COUNT(*) FROM objects)::int AS objects,
+    (SELECT COUNT(*) FROM facts)::int AS facts,
+    (SELECT COUNT(*) FROM tasks)::int AS tasks,
+    (SELECT COUNT(*) FROM funnels)::int AS funnels,
+    (SELECT COUNT(*) FROM tags)::int AS tags,
+    (SELECT COUNT(*) FROM lists)::int AS lists;
@@ -25,9 +25,8 @@ WITH matched_rows AS (
     )) AS new_aliases_to_add,
     CASE WHEN id_string = ANY($1::text[]) THEN TRUE ELSE FALSE END AS has_id_string_match
   FROM obj
-  JOIN creator c ON obj.creator_id = c.id
-  WHERE c.org_id = $2
-  AND id_string = ANY($1::text[]) OR aliases && $1::text[]
+  WHERE obj.org_id = $2
+  AND (id_string = ANY($1::text[]) OR aliases && $1::text[])
 ),
 updated_rows AS (
   SELECT 
@@ -70,15 +70,21 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 }
 
 const deleteTag = `-- name: DeleteTag :execrows
-DELETE FROM tag 
-WHERE id = $1 AND deleted_at IS NULL
+UPDATE tag
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
   AND NOT EXISTS (
     SELECT 1 FROM obj_tag WHERE tag_id = $1
   )
 `
 
-func (q *Queries) DeleteTag(ctx context.Context, id uuid.UUID) (int64, error) {
-	result, err := q.exec(ctx, q.deleteTagStmt, deleteTag, id)
+type DeleteTagParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteTagStmt, deleteTag, arg.ID, arg.OrgID)
 	if err != nil {
 		return 0, err
 	}
@@ -204,7 +210,7 @@ func (q *Queries) ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsR
 const updateTag = `-- name: UpdateTag :one
 UPDATE tag
 SET description = $2, color_schema = $3
-WHERE id = $1 AND deleted_at IS NULL
+WHERE id = $1 AND org_id = $4 AND deleted_at IS NULL
 RETURNING id, name, description, color_schema, org_id, created_at, deleted_at
 `
 
@@ -212,10 +218,16 @@ type UpdateTagParams struct {
 	ID          uuid.UUID       `json:"id"`
 	Description string          `json:"description"`
 	ColorSchema json.RawMessage `json:"color_schema"`
+	OrgID       uuid.UUID       `json:"org_id"`
 }
 
 func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
-	row := q.queryRow(ctx, q.updateTagStmt, updateTag, arg.ID, arg.Description, arg.ColorSchema)
+	row := q.queryRow(ctx, q.updateTagStmt, updateTag,
+		arg.ID,
+		arg.Description,
+		arg.ColorSchema,
+		arg.OrgID,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
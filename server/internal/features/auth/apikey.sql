@@ -0,0 +1,32 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_key (org_id, creator_id, name, prefix, key_hash, expires_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListAPIKeysByOrgID :many
+SELECT k.id, k.name, k.prefix, k.creator_id, c.username AS creator_name,
+  k.expires_at, k.last_used_at, k.created_by, k.created_at, k.revoked_at
+FROM api_key k
+JOIN creator c ON k.creator_id = c.id
+WHERE k.org_id = $1
+ORDER BY k.created_at DESC;
+
+-- name: GetActiveAPIKeyByPrefix :one
+SELECT k.id, k.key_hash, k.creator_id, k.org_id, c.username, c.role
+FROM api_key k
+JOIN creator c ON k.creator_id = c.id
+WHERE k.prefix = $1
+  AND k.revoked_at IS NULL
+  AND (k.expires_at IS NULL OR k.expires_at > CURRENT_TIMESTAMP)
+  AND c.active = true
+  AND c.deleted_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_key
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_key
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND revoked_at IS NULL;
@@ -1,6 +1,12 @@
 package auth
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+)
 
 type SignUpRequest struct {
 	OrgName  string `json:"org_name"`
@@ -14,9 +20,74 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// TwoFactorChallengeResponse answers a login that needs a second factor,
+// TwoFactor is "verify" or "setup"
+type TwoFactorChallengeResponse struct {
+	TwoFactor string    `json:"two_factor"`
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type TwoFactorCodeRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorSetupResponse struct {
+	AuthResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Username string `json:"username"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
 }
 
+type MeResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	Username      string          `json:"username"`
+	Profile       json.RawMessage `json:"profile"`
+	EmailVerified bool            `json:"email_verified"`
+	TwoFactor     bool            `json:"two_factor"`
+	Role          string          `json:"role"`
+	OrgID         uuid.UUID       `json:"org_id"`
+	OrgName       string          `json:"org_name"`
+	OrgProfile    json.RawMessage `json:"org_profile"`
+	// ImpersonatorID is the admin viewing the app as this member
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	ReadOnly       bool   `json:"read_only,omitempty"`
+}
+
+type RevokedSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
 type UpdateCreatorPasswordRequest struct {
 	NewPassword      string `json:"password"`
 }
@@ -40,4 +111,160 @@ type AddNewOrgCreatorRequest struct {
 	Password string          `json:"password"`
 	Role     string          `json:"role"`
 	Profile  json.RawMessage `json:"profile"`
-}
\ No newline at end of file
+}
+type UnlockCreatorResponse struct {
+	Unlocked int64 `json:"unlocked"`
+}
+
+type LoginLockoutResponse struct {
+	ID          uuid.UUID      `json:"id"`
+	Scope       string         `json:"scope"`
+	Username    string         `json:"username"`
+	IP          string         `json:"ip"`
+	CreatorID   ctype.NullUUID `json:"creator_id"`
+	Failures    int32          `json:"failures"`
+	LockedUntil time.Time      `json:"locked_until"`
+	UnlockedAt  ctype.NullTime `json:"unlocked_at"`
+	UnlockedBy  ctype.NullUUID `json:"unlocked_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// ImpersonateRequest starts a read-only impersonation unless read_only is
+// false, Minutes defaults to 15
+type ImpersonateRequest struct {
+	Reason   string `json:"reason"`
+	ReadOnly *bool  `json:"read_only"`
+	Minutes  int    `json:"minutes"`
+}
+
+type ImpersonateResponse struct {
+	ImpersonationID uuid.UUID `json:"impersonation_id"`
+	Token           string    `json:"token"`
+	ReadOnly        bool      `json:"read_only"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+type ImpersonationResponse struct {
+	ID              uuid.UUID      `json:"id"`
+	AdminID         uuid.UUID      `json:"admin_id"`
+	AdminUsername   string         `json:"admin_username"`
+	CreatorID       uuid.UUID      `json:"creator_id"`
+	CreatorUsername string         `json:"creator_username"`
+	Reason          string         `json:"reason"`
+	ReadOnly        bool           `json:"read_only"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+	EndedAt         ctype.NullTime `json:"ended_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Requests        int32          `json:"requests"`
+}
+
+type ImpersonationRequestResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int32     `json:"status"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateInviteRequest struct {
+	Email     string          `json:"email"`
+	Role      string          `json:"role"`
+	Profile   json.RawMessage `json:"profile"`
+	ExpiresAt ctype.NullTime  `json:"expires_at"`
+}
+
+type InviteResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	Email         string          `json:"email"`
+	Role          string          `json:"role"`
+	Profile       json.RawMessage `json:"profile"`
+	InvitedBy     uuid.UUID       `json:"invited_by"`
+	InvitedByName string          `json:"invited_by_name,omitempty"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// CreateInviteResponse is the only time the token of the invite link is
+// returned
+type CreateInviteResponse struct {
+	InviteResponse
+	Token  string `json:"token"`
+	URL    string `json:"url"`
+	Mailed bool   `json:"mailed"`
+}
+
+type AcceptInviteRequest struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type InviteLookupResponse struct {
+	OrgName   string    `json:"org_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AcceptInviteResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	OrgID    uuid.UUID `json:"org_id"`
+}
+
+type SSOStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type SSOCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type SSODomainRequest struct {
+	Domain string `json:"domain"`
+	Role   string `json:"role"`
+}
+
+type SSODomainResponse struct {
+	Domain    string         `json:"domain"`
+	Role      string         `json:"role"`
+	CreatedBy ctype.NullUUID `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string         `json:"name"`
+	CreatorID ctype.NullUUID `json:"creator_id"`
+	ExpiresAt ctype.NullTime `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Prefix      string         `json:"prefix"`
+	CreatorID   uuid.UUID      `json:"creator_id"`
+	CreatorName string         `json:"creator_name,omitempty"`
+	ExpiresAt   ctype.NullTime `json:"expires_at"`
+	LastUsedAt  ctype.NullTime `json:"last_used_at"`
+	CreatedBy   uuid.UUID      `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	RevokedAt   ctype.NullTime `json:"revoked_at"`
+}
+
+// CreateAPIKeyResponse is the only time the plaintext key is returned
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type RolePermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
@@ -1,24 +1,57 @@
 package auth
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/internal/features/auth/service"
+	"github.com/crea8r/muninn/server/pkg/clientip"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type Handler struct {
-	s *service.Service
+	s  *service.Service
+	db *database.Queries
 }
 
-func NewHandler(db *database.Queries) *Handler {
+func NewHandler(db *database.Queries, sqlDB *sql.DB, m mailer.Mailer, sso *oidc.Provider) *Handler {
 	return &Handler{
-		s: service.NewService(db),
+		s:  service.NewService(db, sqlDB, m, sso),
+		db: db,
+	}
+}
+
+// writeServiceError maps service errors to HTTP status codes
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrSSONotConfigured):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidToken),
+		errors.Is(err, service.ErrInvalidCode), errors.Is(err, service.ErrTwoFactorDisabled):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrTwoFactorRequired), errors.Is(err, service.ErrPasswordLoginDisabled),
+		errors.Is(err, service.ErrNoSSOAccount):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrTwoFactorEnabled), errors.Is(err, service.ErrUsernameTaken),
+		errors.Is(err, service.ErrDomainTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
@@ -26,15 +59,62 @@ func (h *Handler) RegisterRoutes(r chi.Router, wrapWithFeed func(http.HandlerFun
 	r.Post("/auth/signup", h.SignUp)
 	r.Post("/auth/login", h.Login)
 	r.Post("/auth/robotlogin", h.RobotLogin)
+	r.Post("/auth/refresh", h.Refresh)
+	r.Post("/auth/forgot-password", h.ForgotPassword)
+	r.Post("/auth/reset-password", h.ResetPassword)
+	r.Post("/auth/verify-email", h.VerifyEmail)
+	r.Post("/auth/2fa/verify", h.VerifyTwoFactor)
+	r.Post("/auth/2fa/setup", h.SetupTwoFactor)
+	r.Post("/auth/2fa/setup/confirm", h.ConfirmTwoFactorSetup)
+	r.Post("/auth/invites/lookup", h.LookupInvite)
+	r.Post("/auth/invites/accept", h.AcceptInvite)
+	r.Post("/auth/oidc/start", h.StartSSO)
+	r.Post("/auth/oidc/callback", h.CompleteSSO)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Permission(h.db))
+		r.Get("/auth/me", h.Me)
+		r.Post("/auth/logout", h.Logout)
+		r.Post("/auth/logout-all", h.LogoutAll)
+		r.Get("/auth/sessions", h.ListSessions)
+		r.Post("/auth/verify-email/send", h.SendEmailVerification)
+		r.Post("/auth/2fa/enroll", h.EnrollTwoFactor)
+		r.Post("/auth/2fa/confirm", h.ConfirmTwoFactor)
+		r.Post("/auth/2fa/disable", h.DisableTwoFactor)
+		r.Post("/auth/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+	})
 
 	r.Route("/org", func(r chi.Router) {
-		r.Use(middleware.Permission)
+		r.Use(middleware.Permission(h.db))
 		r.Get("/members", h.ListOrgCreators)
 		r.Put("/details", h.UpdateOrgDetails)
 		r.Post("/members", wrapWithFeed(h.AddNewOrgCreator))
 		r.Put("/members/{userID}/permission", h.UpdateCreatorRoleAndStatus)
 		r.Put("/members/{userID}/password", h.UpdateCreatorPassword)
 		r.Put("/members/{userID}/profile", h.UpdateCreatorProfile)
+		r.Post("/members/{userID}/sessions/revoke", h.RevokeMemberSessions)
+		r.Post("/members/{userID}/unlock", h.UnlockCreator)
+		r.Delete("/members/{userID}/2fa", h.ResetMemberTwoFactor)
+		r.Get("/lockouts", h.ListLoginLockouts)
+		r.Post("/members/{userID}/impersonate", h.Impersonate)
+		r.Get("/impersonations", h.ListImpersonations)
+		r.Get("/impersonations/{impersonationID}/requests", h.ListImpersonationRequests)
+		r.Delete("/impersonations/{impersonationID}", h.EndImpersonation)
+
+		r.Get("/invites", h.ListInvites)
+		r.Post("/invites", h.CreateInvite)
+		r.Delete("/invites/{inviteID}", h.RevokeInvite)
+
+		r.Get("/sso/domains", h.ListSSODomains)
+		r.Post("/sso/domains", h.AddSSODomain)
+		r.Delete("/sso/domains/{domain}", h.RemoveSSODomain)
+
+		r.Get("/api-keys", h.ListAPIKeys)
+		r.Post("/api-keys", h.CreateAPIKey)
+		r.Delete("/api-keys/{keyID}", h.RevokeAPIKey)
+
+		r.Get("/roles", h.ListRolePermissions)
+		r.Put("/roles/{role}", h.UpdateRolePermissions)
 	})
 }
 
@@ -60,12 +140,12 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	signedToken, err := h.s.Login(r.Context(), req.Username, req.Password)
+	result, err := h.s.Login(r.Context(), req.Username, req.Password, clientInfo(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		writeLoginError(w, err)
 		return
 	}
-	json.NewEncoder(w).Encode(AuthResponse{Token: signedToken})
+	writeLoginResult(w, result)
 }
 
 func (h *Handler) RobotLogin(w http.ResponseWriter, r *http.Request) {
@@ -74,12 +154,278 @@ func (h *Handler) RobotLogin(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	signedToken, err := h.s.RobotLogin(r.Context(), req.Username, req.Password)
+	result, err := h.s.RobotLogin(r.Context(), req.Username, req.Password, clientInfo(r))
 	if err != nil {
+		writeLoginError(w, err)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	tokens, err := h.s.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(toAuthResponse(tokens))
+}
+
+/* two-factor authentication */
+func (h *Handler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	tokens, err := h.s.VerifyTwoFactor(r.Context(), req.Challenge, req.Code, clientInfo(r))
+	if err != nil {
+		writeLoginError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(toAuthResponse(tokens))
+}
+
+func (h *Handler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	enrollment, err := h.s.SetupTwoFactor(r.Context(), req.Challenge)
+	if err != nil {
+		writeLoginError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(TwoFactorEnrollmentResponse{Secret: enrollment.Secret, URI: enrollment.URI})
+}
+
+func (h *Handler) ConfirmTwoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	tokens, codes, err := h.s.ConfirmTwoFactorSetup(r.Context(), req.Challenge, req.Code, clientInfo(r))
+	if err != nil {
+		writeLoginError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(TwoFactorSetupResponse{
+		AuthResponse:  toAuthResponse(tokens),
+		RecoveryCodes: codes,
+	})
+}
+
+func (h *Handler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	enrollment, err := h.s.EnrollTwoFactor(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(TwoFactorEnrollmentResponse{Secret: enrollment.Secret, URI: enrollment.URI})
+}
+
+func (h *Handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	codes, err := h.s.ConfirmTwoFactor(r.Context(), req.Code)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.DisableTwoFactor(r.Context(), req.Code); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	codes, err := h.s.RegenerateRecoveryCodes(r.Context(), req.Code)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.ForgotPassword(r.Context(), req.Username); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Same answer whether or not the account exists
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.VerifyEmail(r.Context(), req.Token); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) SendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.SendEmailVerification(r.Context()); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// writeLoginError tells throttled clients when to retry and hides why
+// credentials were rejected
+func writeLoginError(w http.ResponseWriter, err error) {
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrInvalidChallenge),
+		errors.Is(err, service.ErrInvalidCode), errors.Is(err, service.ErrInvalidSSOState),
+		errors.Is(err, service.ErrSSOFailed):
 		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		writeServiceError(w, err)
+	}
+}
+
+// writeLoginResult answers with the tokens, or with the challenge when the
+// login needs a second factor first
+func writeLoginResult(w http.ResponseWriter, result service.LoginResult) {
+	if result.Challenge != "" {
+		json.NewEncoder(w).Encode(TwoFactorChallengeResponse{
+			TwoFactor: result.ChallengePurpose,
+			Challenge: result.Challenge,
+			ExpiresAt: result.ChallengeExpiresAt,
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(toAuthResponse(result.Tokens))
+}
+
+func toAuthResponse(tokens service.Tokens) AuthResponse {
+	return AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	}
+}
+
+func clientInfo(r *http.Request) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IP:        clientip.FromRequest(r),
+	}
+}
+
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	creator, org, err := h.s.Me(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	emailVerified, err := h.s.EmailVerified(r.Context(), creator)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	json.NewEncoder(w).Encode(AuthResponse{Token: signedToken})
+	twoFactorEnabled, err := h.s.TwoFactorEnabled(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	claims := r.Context().Value(middleware.UserClaimsKey).(*middleware.Claims)
+	json.NewEncoder(w).Encode(MeResponse{
+		ID:             creator.ID,
+		Username:       creator.Username,
+		Profile:        creator.Profile,
+		EmailVerified:  emailVerified,
+		TwoFactor:      twoFactorEnabled,
+		Role:           creator.Role,
+		OrgID:          org.ID,
+		OrgName:        org.Name,
+		OrgProfile:     org.Profile,
+		ImpersonatorID: claims.ImpersonatorID,
+		ReadOnly:       claims.ReadOnly,
+	})
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.Logout(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	revoked, err := h.s.LogoutAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(RevokedSessionsResponse{Revoked: revoked})
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.s.ListSessions(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(sessions)
 }
 
 /* manage org */
@@ -118,7 +464,7 @@ func (h *Handler) AddNewOrgCreator(w http.ResponseWriter, r *http.Request) {
 	}
 	creator, err := h.s.AddNewOrgCreator(ctx, req.Username, req.Password, req.Role, req.Profile)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	json.NewEncoder(w).Encode(creator)
@@ -137,7 +483,7 @@ func (h *Handler) UpdateCreatorRoleAndStatus(w http.ResponseWriter, r *http.Requ
 	Status := req.Status
 	_, err := h.s.UpdateCreatorRoleAndStatus(r.Context(), UserID, Role, Status)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -175,4 +521,469 @@ func (h *Handler) UpdateCreatorProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	json.NewEncoder(w).Encode(updatedUser)
-}
\ No newline at end of file
+}
+func (h *Handler) RevokeMemberSessions(w http.ResponseWriter, r *http.Request) {
+	UserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	revoked, err := h.s.RevokeMemberSessions(r.Context(), UserID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(RevokedSessionsResponse{Revoked: revoked})
+}
+
+func (h *Handler) UnlockCreator(w http.ResponseWriter, r *http.Request) {
+	UserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	unlocked, err := h.s.UnlockCreator(r.Context(), UserID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(UnlockCreatorResponse{Unlocked: unlocked})
+}
+
+func (h *Handler) ListLoginLockouts(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	lockouts, err := h.s.ListLoginLockouts(r.Context(), int32(limit))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]LoginLockoutResponse, len(lockouts))
+	for i, l := range lockouts {
+		response[i] = LoginLockoutResponse{
+			ID:          l.ID,
+			Scope:       l.Scope,
+			Username:    l.Username,
+			IP:          l.Ip,
+			CreatorID:   ctype.NullUUID{NullUUID: l.CreatorID},
+			Failures:    l.Failures,
+			LockedUntil: l.LockedUntil,
+			UnlockedAt:  ctype.NullTime{NullTime: l.UnlockedAt},
+			UnlockedBy:  ctype.NullUUID{NullUUID: l.UnlockedBy},
+			CreatedAt:   l.CreatedAt,
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	var req ImpersonateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	readOnly := req.ReadOnly == nil || *req.ReadOnly
+	impersonation, err := h.s.Impersonate(r.Context(), userID, req.Reason, readOnly,
+		time.Duration(req.Minutes)*time.Minute)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ImpersonateResponse{
+		ImpersonationID: impersonation.ID,
+		Token:           impersonation.AccessToken,
+		ReadOnly:        impersonation.ReadOnly,
+		ExpiresAt:       impersonation.ExpiresAt,
+	})
+}
+
+func (h *Handler) ListImpersonations(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	impersonations, err := h.s.ListImpersonations(r.Context(), int32(limit))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]ImpersonationResponse, len(impersonations))
+	for i, imp := range impersonations {
+		response[i] = ImpersonationResponse{
+			ID:              imp.ID,
+			AdminID:         imp.AdminID,
+			AdminUsername:   imp.AdminUsername,
+			CreatorID:       imp.CreatorID,
+			CreatorUsername: imp.CreatorUsername,
+			Reason:          imp.Reason,
+			ReadOnly:        imp.ReadOnly,
+			ExpiresAt:       imp.ExpiresAt,
+			EndedAt:         ctype.NullTime{NullTime: imp.EndedAt},
+			CreatedAt:       imp.CreatedAt,
+			Requests:        imp.Requests,
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) ListImpersonationRequests(w http.ResponseWriter, r *http.Request) {
+	impersonationID, err := uuid.Parse(chi.URLParam(r, "impersonationID"))
+	if err != nil {
+		http.Error(w, "Invalid impersonation ID", http.StatusBadRequest)
+		return
+	}
+	limit := 1000
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 10000 {
+		limit = l
+	}
+	requests, err := h.s.ListImpersonationRequests(r.Context(), impersonationID, int32(limit))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]ImpersonationRequestResponse, len(requests))
+	for i, req := range requests {
+		response[i] = ImpersonationRequestResponse{
+			ID:        req.ID,
+			Method:    req.Method,
+			Path:      req.Path,
+			Status:    req.Status,
+			IP:        req.Ip,
+			CreatedAt: req.CreatedAt,
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	impersonationID, err := uuid.Parse(chi.URLParam(r, "impersonationID"))
+	if err != nil {
+		http.Error(w, "Invalid impersonation ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.EndImpersonation(r.Context(), impersonationID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ResetMemberTwoFactor(w http.ResponseWriter, r *http.Request) {
+	UserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.ResetMemberTwoFactor(r.Context(), UserID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* invites */
+func (h *Handler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	invites, err := h.s.ListInvites(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]InviteResponse, len(invites))
+	for i, invite := range invites {
+		response[i] = InviteResponse{
+			ID:            invite.ID,
+			Email:         invite.Email,
+			Role:          invite.Role,
+			Profile:       invite.Profile,
+			InvitedBy:     invite.InvitedBy,
+			InvitedByName: invite.InvitedByName,
+			ExpiresAt:     invite.ExpiresAt,
+			CreatedAt:     invite.CreatedAt,
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	invite, err := h.s.CreateInvite(r.Context(), req.Email, req.Role, req.Profile,
+		sql.NullTime{Time: req.ExpiresAt.Time, Valid: req.ExpiresAt.Valid})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateInviteResponse{
+		InviteResponse: InviteResponse{
+			ID:        invite.ID,
+			Email:     invite.Email,
+			Role:      invite.Role,
+			Profile:   invite.Profile,
+			InvitedBy: invite.InvitedBy,
+			ExpiresAt: invite.ExpiresAt,
+			CreatedAt: invite.CreatedAt,
+		},
+		Token:  invite.Token,
+		URL:    invite.URL,
+		Mailed: invite.Mailed,
+	})
+}
+
+func (h *Handler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	inviteID, err := uuid.Parse(chi.URLParam(r, "inviteID"))
+	if err != nil {
+		http.Error(w, "Invalid invite ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.RevokeInvite(r.Context(), inviteID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) LookupInvite(w http.ResponseWriter, r *http.Request) {
+	var req AcceptInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	invite, err := h.s.LookupInvite(r.Context(), req.Token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(InviteLookupResponse{
+		OrgName:   invite.OrgName,
+		Email:     invite.Email,
+		Role:      invite.Role,
+		ExpiresAt: invite.ExpiresAt,
+	})
+}
+
+// minUsernameLength is the shortest username the creator table accepts
+const minUsernameLength = 3
+
+func (h *Handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	var req AcceptInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Username != "" && utf8.RuneCountInString(req.Username) < minUsernameLength {
+		http.Error(w, "Username must be at least "+strconv.Itoa(minUsernameLength)+" characters", http.StatusBadRequest)
+		return
+	}
+	creator, err := h.s.AcceptInvite(r.Context(), req.Token, req.Username, req.Password)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(AcceptInviteResponse{
+		ID:       creator.ID,
+		Username: creator.Username,
+		Role:     creator.Role,
+		OrgID:    creator.OrgID,
+	})
+}
+
+/* single sign-on */
+
+// ssoCookie binds a single sign-on login to the browser that started it
+const ssoCookie = "muninn_sso"
+
+func (h *Handler) StartSSO(w http.ResponseWriter, r *http.Request) {
+	authorizationURL, binding, err := h.s.StartSSO(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setSSOCookie(w, r, binding, int(service.OIDCStateTTL.Seconds()))
+	json.NewEncoder(w).Encode(SSOStartResponse{AuthorizationURL: authorizationURL})
+}
+
+func (h *Handler) CompleteSSO(w http.ResponseWriter, r *http.Request) {
+	var req SSOCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	var binding string
+	if cookie, err := r.Cookie(ssoCookie); err == nil {
+		binding = cookie.Value
+	}
+	// The binding works once, like the state
+	setSSOCookie(w, r, "", -1)
+	result, err := h.s.CompleteSSO(r.Context(), req.Code, req.State, binding, clientInfo(r))
+	if err != nil {
+		writeLoginError(w, err)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// setSSOCookie keeps value for maxAge seconds, a negative maxAge deletes it
+func setSSOCookie(w http.ResponseWriter, r *http.Request, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    value,
+		Path:     "/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (h *Handler) ListSSODomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := h.s.ListSSODomains(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]SSODomainResponse, len(domains))
+	for i, domain := range domains {
+		response[i] = toSSODomainResponse(domain)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) AddSSODomain(w http.ResponseWriter, r *http.Request) {
+	var req SSODomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	domain, err := h.s.AddSSODomain(r.Context(), req.Domain, req.Role)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toSSODomainResponse(domain))
+}
+
+func (h *Handler) RemoveSSODomain(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.RemoveSSODomain(r.Context(), chi.URLParam(r, "domain")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toSSODomainResponse(domain database.OrgSsoDomain) SSODomainResponse {
+	return SSODomainResponse{
+		Domain:    domain.Domain,
+		Role:      domain.Role,
+		CreatedBy: ctype.NullUUID{NullUUID: domain.CreatedBy},
+		CreatedAt: domain.CreatedAt,
+	}
+}
+
+/* api keys */
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.s.ListAPIKeys(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = APIKeyResponse{
+			ID:          key.ID,
+			Name:        key.Name,
+			Prefix:      key.Prefix,
+			CreatorID:   key.CreatorID,
+			CreatorName: key.CreatorName,
+			ExpiresAt:   ctype.NullTime{NullTime: key.ExpiresAt},
+			LastUsedAt:  ctype.NullTime{NullTime: key.LastUsedAt},
+			CreatedBy:   key.CreatedBy,
+			CreatedAt:   key.CreatedAt,
+			RevokedAt:   ctype.NullTime{NullTime: key.RevokedAt},
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	key, plaintext, err := h.s.CreateAPIKey(r.Context(), req.Name, req.CreatorID.NullUUID,
+		sql.NullTime{Time: req.ExpiresAt.Time, Valid: req.ExpiresAt.Valid})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPIKeyResponse{
+		APIKeyResponse: APIKeyResponse{
+			ID:        key.ID,
+			Name:      key.Name,
+			Prefix:    key.Prefix,
+			CreatorID: key.CreatorID,
+			ExpiresAt: ctype.NullTime{NullTime: key.ExpiresAt},
+			CreatedBy: key.CreatedBy,
+			CreatedAt: key.CreatedAt,
+		},
+		Key: plaintext,
+	})
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyID"))
+	if err != nil {
+		http.Error(w, "Invalid key ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.RevokeAPIKey(r.Context(), keyID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* roles */
+func (h *Handler) ListRolePermissions(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.s.ListRolePermissions(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]RolePermissionsResponse, len(middleware.Roles))
+	for i, role := range middleware.Roles {
+		response[i] = RolePermissionsResponse{
+			Role:        role,
+			Permissions: roles[role],
+		}
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	var req UpdateRolePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.s.UpdateRolePermissions(r.Context(), chi.URLParam(r, "role"), req.Permissions); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
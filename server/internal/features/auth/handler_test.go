@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/features/auth/service"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc/oidctest"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+// TestLoginFailuresCountSpoofedIPs has a caller behind the trusted proxy
+// send a new X-Forwarded-For hop with every failed login. The failures must
+// all count against the address the proxy saw.
+func TestLoginFailuresCountSpoofedIPs(t *testing.T) {
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	// httptest requests come from 192.0.2.1
+	t.Setenv("TRUSTED_PROXIES", "192.0.2.1")
+	queries := database.New(db)
+	r := chi.NewRouter()
+	NewHandler(queries, db, mailer.NewWriterMailer(io.Discard), nil).RegisterRoutes(r, func(h http.HandlerFunc) http.HandlerFunc { return h })
+
+	const attempts = 5
+	for i := 0; i < attempts; i++ {
+		body := fmt.Sprintf(`{"username":"nobody-%d","password":"wrong"}`, i)
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d, 203.0.113.9", i))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("login %d: status = %d, want 401: %s", i, rec.Code, rec.Body)
+		}
+	}
+
+	since := time.Now().Add(-time.Hour)
+	failures, err := queries.CountLoginFailuresByIP(context.Background(), database.CountLoginFailuresByIPParams{Ip: "203.0.113.9", CreatedAt: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failures != attempts {
+		t.Errorf("failures of the caller = %d, want %d", failures, attempts)
+	}
+	for i := 0; i < attempts; i++ {
+		spoofed := fmt.Sprintf("198.51.100.%d", i)
+		failures, err := queries.CountLoginFailuresByIP(context.Background(), database.CountLoginFailuresByIPParams{Ip: spoofed, CreatedAt: since})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failures != 0 {
+			t.Errorf("the spoofed %s has %d failures", spoofed, failures)
+		}
+	}
+}
+
+// TestCompleteSSORequiresCookie has a victim open the callback of a login an
+// attacker started. Without the cookie of the start the callback must refuse.
+func TestCompleteSSORequiresCookie(t *testing.T) {
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	queries := database.New(db)
+	idp := oidctest.NewIdP(t)
+	h := NewHandler(queries, db, mailer.NewWriterMailer(io.Discard), idp.Provider())
+	r := chi.NewRouter()
+	h.RegisterRoutes(r, func(h http.HandlerFunc) http.HandlerFunc { return h })
+
+	admin, err := h.s.SignUp(context.Background(), "org of example.com", "admin@example.com", "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = queries.CreateSSODomain(context.Background(), database.CreateSSODomainParams{
+		Domain: "example.com", OrgID: admin.OrgID, Role: middleware.RoleMember,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The attacker starts a login and logs in at the IdP
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/oidc/start", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("start: status = %d: %s", rec.Code, rec.Body)
+	}
+	var start SSOStartResponse
+	if err := json.NewDecoder(rec.Body).Decode(&start); err != nil {
+		t.Fatal(err)
+	}
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == ssoCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("start set the cookie %+v, want an HttpOnly SameSite=Lax one", cookie)
+	}
+	code, state := idp.Login(t, start.AuthorizationURL, idp.Claims("attacker", "mallory@example.com"))
+	callback := func(c *http.Cookie) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"code":%q,"state":%q}`, code, state)
+		req := httptest.NewRequest(http.MethodPost, "/auth/oidc/callback", strings.NewReader(body))
+		if c != nil {
+			req.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The victim opens the callback
+	if rec := callback(nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("callback without the cookie: status = %d, want 401: %s", rec.Code, rec.Body)
+	}
+	if rec := callback(&http.Cookie{Name: ssoCookie, Value: "the cookie of another start"}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("callback with another cookie: status = %d, want 401: %s", rec.Code, rec.Body)
+	}
+	// The browser that started the login finishes it
+	if rec := callback(cookie); rec.Code != http.StatusOK {
+		t.Errorf("callback with the cookie: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+}
+
+// TestAcceptInviteRejectsShortUsername sends a username the creator table
+// refuses. It must get a 400 and leave the invite usable.
+func TestAcceptInviteRejectsShortUsername(t *testing.T) {
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	queries := database.New(db)
+	r := chi.NewRouter()
+	NewHandler(queries, db, mailer.NewWriterMailer(io.Discard), nil).RegisterRoutes(r, func(h http.HandlerFunc) http.HandlerFunc { return h })
+
+	ctx := context.Background()
+	admin, err := service.NewService(queries, db, mailer.NewWriterMailer(io.Discard), nil).SignUp(ctx, "acme", "admin", "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := token.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = queries.CreateOrgInvite(ctx, database.CreateOrgInviteParams{
+		OrgID:     admin.OrgID,
+		Role:      middleware.RoleMember,
+		Profile:   json.RawMessage(`{}`),
+		TokenHash: token.Hash(plaintext),
+		InvitedBy: admin.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		username string
+		want     int
+	}{
+		{"ab", http.StatusBadRequest},
+		{"ada", http.StatusCreated},
+	} {
+		body := fmt.Sprintf(`{"token":%q,"username":%q,"password":"correct horse battery"}`, plaintext, tt.username)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/invites/accept", strings.NewReader(body)))
+		if rec.Code != tt.want {
+			t.Errorf("accepting as %q: status = %d, want %d: %s", tt.username, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
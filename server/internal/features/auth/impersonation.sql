@@ -0,0 +1,53 @@
+-- name: CreateImpersonation :one
+INSERT INTO impersonation (jti, org_id, admin_id, creator_id, reason, read_only, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetActiveImpersonationByJti :one
+SELECT i.id, i.creator_id, i.admin_id, i.read_only, c.org_id, c.role
+FROM impersonation i
+JOIN creator c ON i.creator_id = c.id
+JOIN creator a ON i.admin_id = a.id
+WHERE i.jti = $1
+  AND i.ended_at IS NULL
+  AND i.expires_at > CURRENT_TIMESTAMP
+  AND c.active = true
+  AND c.deleted_at IS NULL
+  AND a.active = true
+  AND a.deleted_at IS NULL
+  AND a.role = 'admin'
+  AND a.org_id = c.org_id;
+
+-- name: EndImpersonationByJti :execrows
+UPDATE impersonation
+SET ended_at = CURRENT_TIMESTAMP
+WHERE jti = $1 AND ended_at IS NULL;
+
+-- name: EndImpersonation :execrows
+UPDATE impersonation
+SET ended_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2 AND ended_at IS NULL;
+
+-- name: ListImpersonationsByOrgID :many
+SELECT i.id, i.admin_id, a.username AS admin_username,
+  i.creator_id, c.username AS creator_username,
+  i.reason, i.read_only, i.expires_at, i.ended_at, i.created_at,
+  (SELECT COUNT(*) FROM impersonation_request r WHERE r.impersonation_id = i.id)::int AS requests
+FROM impersonation i
+JOIN creator a ON i.admin_id = a.id
+JOIN creator c ON i.creator_id = c.id
+WHERE i.org_id = $1
+ORDER BY i.created_at DESC
+LIMIT $2;
+
+-- name: LogImpersonationRequest :exec
+INSERT INTO impersonation_request (impersonation_id, method, path, status, ip)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListImpersonationRequests :many
+SELECT r.id, r.method, r.path, r.status, r.ip, r.created_at
+FROM impersonation_request r
+JOIN impersonation i ON r.impersonation_id = i.id
+WHERE r.impersonation_id = $1 AND i.org_id = $2
+ORDER BY r.created_at
+LIMIT $3;
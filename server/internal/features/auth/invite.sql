@@ -0,0 +1,49 @@
+-- name: CreateOrgInvite :one
+INSERT INTO org_invite (org_id, email, role, profile, token_hash, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListPendingOrgInvites :many
+SELECT i.*, c.username AS invited_by_name
+FROM org_invite i
+JOIN creator c ON i.invited_by = c.id
+WHERE i.org_id = $1
+  AND i.accepted_at IS NULL
+  AND i.revoked_at IS NULL
+  AND i.expires_at > CURRENT_TIMESTAMP
+ORDER BY i.created_at DESC;
+
+-- name: RevokeOrgInvite :execrows
+UPDATE org_invite
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL;
+
+-- name: GetActiveOrgInviteByHash :one
+SELECT i.id, i.email, i.role, i.expires_at, o.name AS org_name
+FROM org_invite i
+JOIN org o ON i.org_id = o.id
+WHERE i.token_hash = $1
+  AND i.accepted_at IS NULL
+  AND i.revoked_at IS NULL
+  AND i.expires_at > CURRENT_TIMESTAMP
+  AND o.deleted_at IS NULL;
+
+-- name: AcceptOrgInvite :one
+WITH invite AS (
+  UPDATE org_invite
+  SET accepted_at = CURRENT_TIMESTAMP, accepted_by = $1
+  WHERE token_hash = $2
+    AND accepted_at IS NULL
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+    AND NOT EXISTS (
+      SELECT 1 FROM creator WHERE username = $3 AND deleted_at IS NULL
+    )
+  RETURNING org_id, role, profile
+)
+INSERT INTO creator (id, username, pwd, profile, role, org_id, active, created_at)
+SELECT $1, $3, $4::text, profile, role, org_id, true, CURRENT_TIMESTAMP
+FROM invite
+RETURNING *;
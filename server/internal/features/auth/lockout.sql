@@ -0,0 +1,50 @@
+-- name: RecordLoginFailure :exec
+INSERT INTO login_failure (username, ip)
+VALUES ($1, $2);
+
+-- name: CountLoginFailuresByUsername :one
+SELECT count(*)::int AS failures,
+  COALESCE(max(created_at), 'epoch')::timestamptz AS last_failed_at
+FROM login_failure
+WHERE username = $1 AND created_at > $2;
+
+-- name: CountLoginFailuresByIP :one
+SELECT count(*)::int AS failures
+FROM login_failure
+WHERE ip = $1 AND created_at > $2;
+
+-- name: ClearLoginFailures :exec
+DELETE FROM login_failure
+WHERE username = $1 OR created_at < $2;
+
+-- name: ClearLoginFailuresByIP :exec
+DELETE FROM login_failure
+WHERE ip = $1;
+
+-- name: CreateLoginLockout :one
+INSERT INTO login_lockout (scope, username, ip, creator_id, failures, locked_until)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetActiveLoginLockout :one
+SELECT * FROM login_lockout
+WHERE ((scope = 'username' AND username = $1) OR (scope = 'ip' AND ip = $2))
+  AND unlocked_at IS NULL
+  AND locked_until > CURRENT_TIMESTAMP
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: UnlockLoginLockouts :execrows
+UPDATE login_lockout
+SET unlocked_at = CURRENT_TIMESTAMP, unlocked_by = $2
+WHERE scope = 'username' AND username = $1
+  AND unlocked_at IS NULL
+  AND locked_until > CURRENT_TIMESTAMP;
+
+-- name: ListLoginLockoutsByOrgID :many
+SELECT l.*
+FROM login_lockout l
+JOIN creator c ON l.creator_id = c.id
+WHERE c.org_id = $1
+ORDER BY l.created_at DESC
+LIMIT $2;
@@ -0,0 +1,64 @@
+-- name: CreateOIDCState :exec
+INSERT INTO oidc_state (state_hash, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: UseOIDCState :one
+UPDATE oidc_state
+SET used_at = CURRENT_TIMESTAMP
+WHERE state_hash = $1
+  AND used_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING nonce, code_verifier;
+
+-- name: DeleteExpiredOIDCStates :exec
+DELETE FROM oidc_state WHERE expires_at < $1;
+
+-- name: GetCreatorByIdentity :one
+SELECT c.id, c.username, c.org_id, c.role
+FROM creator_identity i
+JOIN creator c ON i.creator_id = c.id
+WHERE i.issuer = $1 AND i.subject = $2
+  AND c.active = true
+  AND c.deleted_at IS NULL;
+
+-- name: CreateCreatorIdentity :one
+INSERT INTO creator_identity (creator_id, issuer, subject, email)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: TouchCreatorIdentity :exec
+UPDATE creator_identity
+SET last_login_at = CURRENT_TIMESTAMP, email = $3
+WHERE issuer = $1 AND subject = $2;
+
+-- name: ListCreatorsByVerifiedEmail :many
+SELECT DISTINCT c.id, c.username, c.org_id, c.role
+FROM creator c
+JOIN creator_token t ON t.creator_id = c.id
+WHERE t.purpose = 'email_verify'
+  AND t.used_at IS NOT NULL
+  AND lower(t.email) = $1
+  AND lower(c.profile->>'email') = $1
+  AND c.active = true
+  AND c.deleted_at IS NULL;
+
+-- name: UsernameExists :one
+SELECT EXISTS (
+  SELECT 1 FROM creator WHERE username = $1 AND deleted_at IS NULL
+)::boolean AS taken;
+
+-- name: GetSSODomain :one
+SELECT * FROM org_sso_domain WHERE domain = $1;
+
+-- name: ListSSODomainsByOrgID :many
+SELECT * FROM org_sso_domain
+WHERE org_id = $1
+ORDER BY domain;
+
+-- name: CreateSSODomain :one
+INSERT INTO org_sso_domain (domain, org_id, role, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: DeleteSSODomain :execrows
+DELETE FROM org_sso_domain WHERE domain = $1 AND org_id = $2;
@@ -0,0 +1,19 @@
+-- name: GetRolePermission :one
+SELECT granted FROM role_permission
+WHERE org_id = $1 AND role = $2 AND permission = $3;
+
+-- name: ListRolePermissionsByOrgID :many
+SELECT * FROM role_permission
+WHERE org_id = $1
+ORDER BY role, permission;
+
+-- name: ReplaceRolePermissions :exec
+WITH removed AS (
+  DELETE FROM role_permission
+  WHERE org_id = $1 AND role = $2 AND permission <> ALL($4::text[])
+)
+INSERT INTO role_permission (org_id, role, permission, granted, updated_by)
+SELECT $1, $2, p.permission, p.granted, $3
+FROM unnest($4::text[], $5::bool[]) AS p(permission, granted)
+ON CONFLICT (org_id, role, permission)
+DO UPDATE SET granted = EXCLUDED.granted, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/apikey"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// CreateAPIKey issues a new key acting as CreatorID (the caller when empty).
+// The plaintext key is only returned here and never stored.
+func (s *Service) CreateAPIKey(c context.Context, Name string, CreatorID uuid.NullUUID, ExpiresAt sql.NullTime) (database.ApiKey, string, error) {
+	if !utils.IsAdmin(c) {
+		return database.ApiKey{}, "", ErrForbidden
+	}
+	if Name == "" {
+		return database.ApiKey{}, "", errors.New("name is required")
+	}
+	OrgID := utils.GetOrgIDFromContext(c)
+	AdminID := utils.GetCreatorIDFromContext(c)
+	KeyOwner := AdminID
+	if CreatorID.Valid {
+		owner, err := s.db.GetCreatorByID(c, CreatorID.UUID)
+		if err != nil || owner.OrgID != OrgID {
+			return database.ApiKey{}, "", ErrForbidden
+		}
+		KeyOwner = owner.ID
+	}
+
+	key, prefix, err := apikey.Generate()
+	if err != nil {
+		return database.ApiKey{}, "", err
+	}
+	hashedKey, err := apikey.Hash(key)
+	if err != nil {
+		return database.ApiKey{}, "", err
+	}
+	created, err := s.db.CreateAPIKey(c, database.CreateAPIKeyParams{
+		OrgID:     OrgID,
+		CreatorID: KeyOwner,
+		Name:      Name,
+		Prefix:    prefix,
+		KeyHash:   hashedKey,
+		ExpiresAt: ExpiresAt,
+		CreatedBy: AdminID,
+	})
+	if err != nil {
+		return database.ApiKey{}, "", err
+	}
+	return created, key, nil
+}
+
+func (s *Service) ListAPIKeys(c context.Context) ([]database.ListAPIKeysByOrgIDRow, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	keys, err := s.db.ListAPIKeysByOrgID(c, utils.GetOrgIDFromContext(c))
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *Service) RevokeAPIKey(c context.Context, KeyID uuid.UUID) error {
+	if !utils.IsAdmin(c) {
+		return ErrForbidden
+	}
+	affected, err := s.db.RevokeAPIKey(c, database.RevokeAPIKeyParams{
+		ID:    KeyID,
+		OrgID: utils.GetOrgIDFromContext(c),
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/token"
 	"github.com/crea8r/muninn/server/pkg/utils"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 func (s *Service) SignUp(c context.Context, OrgName string, UserName string, UserPassword string) (database.Creator, error) {
@@ -41,34 +43,94 @@ func (s *Service) SignUp(c context.Context, OrgName string, UserName string, Use
 	return creator, err
 }
 
-func (s *Service) Login(c context.Context, Username string, Password string) (string, error) {
+const (
+	// AccessTokenTTL is the lifetime of the JWT sent with every request
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is the lifetime of a login, refreshing does not extend it
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Tokens is the result of a login or a refresh
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// LoginResult holds the tokens of a login, or the challenge to complete when
+// the creator has to pass or set up a second factor first
+type LoginResult struct {
+	Tokens
+	Challenge          string
+	ChallengePurpose   string
+	ChallengeExpiresAt time.Time
+}
+
+func (s *Service) Login(c context.Context, Username string, Password string, Client ClientInfo) (LoginResult, error) {
 	// Get creator
-	creator, err := s.getCreator(c, Username, Password)
+	creator, err := s.attemptLogin(c, Username, Password, Client)
 	if err != nil {
-		return "", err
+		return LoginResult{}, err
 	}
-	// Create JWT token, expire in 30 days
-	claims := createClaim(creator, jwt.NewNumericDate(time.Now().Add(30 * 24 * time.Hour)))
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return s.completeLogin(c, creator.ID, creator.OrgID, creator.Role, Client)
+}
+
+func (s *Service) RobotLogin(c context.Context, Username string, Password string, Client ClientInfo) (LoginResult, error) {
+	// Get creator
+	creator, err := s.attemptLogin(c, Username, Password, Client)
 	if err != nil {
-		return "", err
+		return LoginResult{}, err
 	}
-	return signedToken, nil
+	// Robots follow the same access/refresh flow,
+	// long running integrations should use an org API key instead
+	return s.completeLogin(c, creator.ID, creator.OrgID, creator.Role, Client)
 }
 
-func (s *Service) RobotLogin(c context.Context, Username string, Password string) (string, error) {
-	// Get creator
-	creator, err := s.getCreator(c, Username, Password)
+// issueTokens records a session for the creator, the session is the family of
+// every refresh token issued for this login
+func (s *Service) issueTokens(c context.Context, CreatorID uuid.UUID, OrgID uuid.UUID, Role string, Client ClientInfo) (Tokens, error) {
+	session, err := s.db.CreateCreatorSession(c, database.CreateCreatorSessionParams{
+		CreatorID: CreatorID,
+		Jti:       uuid.New().String(),
+		UserAgent: Client.UserAgent,
+		Ip:        Client.IP,
+		ExpiredAt: time.Now().Add(RefreshTokenTTL),
+	})
 	if err != nil {
-		return "", err
+		return Tokens{}, err
 	}
-	// Create JWT token, expire in 3650 days
-	claims := createClaim(creator, jwt.NewNumericDate(time.Now().Add(100 * 365 * 24 * time.Hour)))
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return s.signTokens(c, session.ID, session.Jti, session.ExpiredAt, CreatorID, OrgID, Role)
+}
+
+// signTokens creates a refresh token for the session and signs an access token
+// carrying the session jti
+func (s *Service) signTokens(c context.Context, SessionID uuid.UUID, jti string, SessionExpiredAt time.Time,
+	CreatorID uuid.UUID, OrgID uuid.UUID, Role string) (Tokens, error) {
+	refreshToken, err := token.New()
+	if err != nil {
+		return Tokens{}, err
+	}
+	_, err = s.db.CreateRefreshToken(c, database.CreateRefreshTokenParams{
+		SessionID: SessionID,
+		TokenHash: token.Hash(refreshToken),
+		ExpiresAt: SessionExpiredAt,
+	})
 	if err != nil {
-		return "", err
+		return Tokens{}, err
 	}
-	return signedToken, nil
-}
\ No newline at end of file
+
+	expiresAt := time.Now().Add(AccessTokenTTL)
+	if expiresAt.After(SessionExpiredAt) {
+		expiresAt = SessionExpiredAt
+	}
+	claims := createClaim(CreatorID, OrgID, Role, jti, jwt.NewNumericDate(expiresAt))
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		return Tokens{}, err
+	}
+	return Tokens{
+		AccessToken:  signedToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
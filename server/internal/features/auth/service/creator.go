@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/pkg/utils"
 	"github.com/google/uuid"
@@ -51,10 +53,12 @@ func (s *Service) UpdateCreatorProfile(c context.Context, UserID uuid.UUID, Prof
 }
 
 func (s *Service) UpdateCreatorRoleAndStatus(c context.Context, UserID uuid.UUID, Role string, Active bool) (database.Creator, error) {
-	if !utils.IsCreator(c, UserID) {
-		if !s.isAdminOfTheSameOrg(c, UserID) {
-			return database.Creator{}, errors.New("Forbidden")
-		}
+	// Creators must not be able to change their own role
+	if !s.isAdminOfTheSameOrg(c, UserID) {
+		return database.Creator{}, errors.New("Forbidden")
+	}
+	if !middleware.IsValidRole(Role) {
+		return database.Creator{}, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, Role)
 	}
 	OrgID := utils.GetOrgIDFromContext(c)
 	updatedUser, err := s.db.UpdateCreatorRoleAndStatus(c, database.UpdateCreatorRoleAndStatusParams{
@@ -66,5 +70,11 @@ func (s *Service) UpdateCreatorRoleAndStatus(c context.Context, UserID uuid.UUID
 	if err != nil {
 		return database.Creator{}, err
 	}
+	// A deactivated creator must not keep any live session
+	if !Active {
+		if _, err := s.db.RevokeCreatorSessions(c, UserID); err != nil {
+			return database.Creator{}, err
+		}
+	}
 	return updatedUser, nil
 }
\ No newline at end of file
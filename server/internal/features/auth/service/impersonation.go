@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultImpersonationTTL is how long an admin views the app as a member
+	// unless they choose otherwise, up to MaxImpersonationTTL
+	DefaultImpersonationTTL = 15 * time.Minute
+	MaxImpersonationTTL     = time.Hour
+)
+
+// Impersonation is a new impersonation with its token. There is no refresh
+// token, the admin starts a new impersonation once it expires.
+type Impersonation struct {
+	database.Impersonation
+	AccessToken string
+}
+
+// Impersonate lets an admin view the app as a member of the org. Read-only
+// impersonations can not change anything, and every request is logged.
+func (s *Service) Impersonate(c context.Context, UserID uuid.UUID, Reason string, ReadOnly bool, TTL time.Duration) (Impersonation, error) {
+	claims := c.Value(middleware.UserClaimsKey).(*middleware.Claims)
+	if claims.ImpersonatorID != "" || !s.isAdminOfTheSameOrg(c, UserID) {
+		return Impersonation{}, ErrForbidden
+	}
+	AdminID := utils.GetCreatorIDFromContext(c)
+	if UserID == AdminID {
+		return Impersonation{}, fmt.Errorf("%w: admins can not impersonate themselves", ErrInvalidInput)
+	}
+	creator, err := s.db.GetCreatorByID(c, UserID)
+	if err != nil {
+		return Impersonation{}, err
+	}
+	if !creator.Active || creator.DeletedAt.Valid {
+		return Impersonation{}, fmt.Errorf("%w: member is not active", ErrInvalidInput)
+	}
+	// Admins see everything already, viewing as one would only hide who acted
+	if creator.Role == middleware.RoleAdmin {
+		return Impersonation{}, fmt.Errorf("%w: admins can not be impersonated", ErrForbidden)
+	}
+	if TTL == 0 {
+		TTL = DefaultImpersonationTTL
+	}
+	if TTL < 0 || TTL > MaxImpersonationTTL {
+		return Impersonation{}, fmt.Errorf("%w: impersonation lasts at most %d minutes", ErrInvalidInput, int(MaxImpersonationTTL.Minutes()))
+	}
+
+	created, err := s.db.CreateImpersonation(c, database.CreateImpersonationParams{
+		Jti:       uuid.New().String(),
+		OrgID:     creator.OrgID,
+		AdminID:   AdminID,
+		CreatorID: creator.ID,
+		Reason:    Reason,
+		ReadOnly:  ReadOnly,
+		ExpiresAt: time.Now().Add(TTL),
+	})
+	if err != nil {
+		return Impersonation{}, err
+	}
+	tokenClaims := createClaim(creator.ID, creator.OrgID, creator.Role, created.Jti, jwt.NewNumericDate(created.ExpiresAt))
+	tokenClaims.ImpersonatorID = AdminID.String()
+	tokenClaims.ReadOnly = ReadOnly
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims).SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		return Impersonation{}, err
+	}
+	log.Printf("Admin %s started impersonation %s of creator %s, read-only: %t", AdminID, created.ID, creator.ID, ReadOnly)
+	return Impersonation{Impersonation: created, AccessToken: signedToken}, nil
+}
+
+// EndImpersonation revokes the token of an impersonation of the org before it
+// expires
+func (s *Service) EndImpersonation(c context.Context, ImpersonationID uuid.UUID) error {
+	if !utils.IsAdmin(c) {
+		return ErrForbidden
+	}
+	ended, err := s.db.EndImpersonation(c, database.EndImpersonationParams{
+		ID:    ImpersonationID,
+		OrgID: utils.GetOrgIDFromContext(c),
+	})
+	if err != nil {
+		return err
+	}
+	if ended == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListImpersonations returns the latest impersonations of the org with the
+// number of requests made in each
+func (s *Service) ListImpersonations(c context.Context, Limit int32) ([]database.ListImpersonationsByOrgIDRow, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	return s.db.ListImpersonationsByOrgID(c, database.ListImpersonationsByOrgIDParams{
+		OrgID: utils.GetOrgIDFromContext(c),
+		Limit: Limit,
+	})
+}
+
+// ListImpersonationRequests returns the requests made in an impersonation of
+// the org, oldest first
+func (s *Service) ListImpersonationRequests(c context.Context, ImpersonationID uuid.UUID, Limit int32) ([]database.ListImpersonationRequestsRow, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	return s.db.ListImpersonationRequests(c, database.ListImpersonationRequestsParams{
+		ImpersonationID: ImpersonationID,
+		OrgID:           utils.GetOrgIDFromContext(c),
+		Limit:           Limit,
+	})
+}
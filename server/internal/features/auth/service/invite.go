@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultInviteTTL is how long an invite link works unless the admin
+	// chooses otherwise, up to MaxInviteTTL
+	DefaultInviteTTL = 7 * 24 * time.Hour
+	MaxInviteTTL     = 30 * 24 * time.Hour
+)
+
+var ErrUsernameTaken = errors.New("Username is already taken")
+
+// Invite is a new invite with the plaintext token of its link, the only time
+// the token is available
+type Invite struct {
+	database.OrgInvite
+	Token  string
+	URL    string
+	Mailed bool
+}
+
+// CreateInvite invites someone to the org with a role. When Email is set the
+// link is mailed to it and the email is added to the profile of the member.
+func (s *Service) CreateInvite(c context.Context, Email string, Role string, Profile json.RawMessage, ExpiresAt sql.NullTime) (Invite, error) {
+	if !utils.IsAdmin(c) {
+		return Invite{}, ErrForbidden
+	}
+	if !middleware.IsValidRole(Role) {
+		return Invite{}, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, Role)
+	}
+	expiresAt := time.Now().Add(DefaultInviteTTL)
+	if ExpiresAt.Valid {
+		if ExpiresAt.Time.Before(time.Now()) || ExpiresAt.Time.After(time.Now().Add(MaxInviteTTL)) {
+			return Invite{}, fmt.Errorf("%w: expires_at must be within %d days", ErrInvalidInput, int(MaxInviteTTL.Hours()/24))
+		}
+		expiresAt = ExpiresAt.Time
+	}
+	profile := map[string]interface{}{}
+	if len(Profile) > 0 && string(Profile) != "null" {
+		if err := json.Unmarshal(Profile, &profile); err != nil {
+			return Invite{}, fmt.Errorf("%w: profile must be an object", ErrInvalidInput)
+		}
+	}
+	if Email != "" {
+		email, err := parseEmail(Email)
+		if err != nil {
+			return Invite{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		Email = email
+		if _, ok := profile["email"]; !ok {
+			profile["email"] = Email
+		}
+	}
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	plaintext, err := token.New()
+	if err != nil {
+		return Invite{}, err
+	}
+	OrgID := utils.GetOrgIDFromContext(c)
+	created, err := s.db.CreateOrgInvite(c, database.CreateOrgInviteParams{
+		OrgID:     OrgID,
+		Email:     Email,
+		Role:      Role,
+		Profile:   profileJSON,
+		TokenHash: token.Hash(plaintext),
+		InvitedBy: utils.GetCreatorIDFromContext(c),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return Invite{}, err
+	}
+	invite := Invite{
+		OrgInvite: created,
+		Token:     plaintext,
+		URL:       appURL() + "/accept-invite?token=" + url.QueryEscape(plaintext),
+	}
+	if Email == "" {
+		return invite, nil
+	}
+	// The admin gets the link either way, a failed email is not an error
+	org, err := s.db.GetOrgDetails(c, OrgID)
+	if err != nil {
+		return Invite{}, err
+	}
+	err = s.mailer.Send(c, mailer.Message{
+		To:      Email,
+		Subject: fmt.Sprintf("You are invited to join %s", org.Name),
+		Body: fmt.Sprintf("You are invited to join %s as %s.\n\nOpen this link before %s to choose your username and password:\n%s",
+			org.Name, Role, expiresAt.Format("2006-01-02 15:04 MST"), invite.URL),
+	})
+	if err != nil {
+		log.Printf("Error mailing invite %s: %v", created.ID, err)
+	}
+	invite.Mailed = err == nil
+	return invite, nil
+}
+
+// ListInvites returns the invites of the org that can still be accepted
+func (s *Service) ListInvites(c context.Context) ([]database.ListPendingOrgInvitesRow, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	return s.db.ListPendingOrgInvites(c, utils.GetOrgIDFromContext(c))
+}
+
+func (s *Service) RevokeInvite(c context.Context, InviteID uuid.UUID) error {
+	if !utils.IsAdmin(c) {
+		return ErrForbidden
+	}
+	revoked, err := s.db.RevokeOrgInvite(c, database.RevokeOrgInviteParams{
+		ID:    InviteID,
+		OrgID: utils.GetOrgIDFromContext(c),
+	})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LookupInvite returns the org and role of an invite so the invitee knows
+// what they are joining
+func (s *Service) LookupInvite(c context.Context, Token string) (database.GetActiveOrgInviteByHashRow, error) {
+	if Token == "" {
+		return database.GetActiveOrgInviteByHashRow{}, ErrInvalidToken
+	}
+	invite, err := s.db.GetActiveOrgInviteByHash(c, token.Hash(Token))
+	if err == sql.ErrNoRows {
+		return database.GetActiveOrgInviteByHashRow{}, ErrInvalidToken
+	}
+	return invite, err
+}
+
+// AcceptInvite creates the member of an invite with the username and password
+// of their choice. The invite is used up in the same statement.
+func (s *Service) AcceptInvite(c context.Context, Token string, Username string, Password string) (database.Creator, error) {
+	if Username == "" || Password == "" {
+		return database.Creator{}, fmt.Errorf("%w: username and password are required", ErrInvalidInput)
+	}
+	if _, err := s.LookupInvite(c, Token); err != nil {
+		return database.Creator{}, err
+	}
+	hashedPassword, err := utils.HashPassword(Password)
+	if err != nil {
+		return database.Creator{}, err
+	}
+	creator, err := s.db.AcceptOrgInvite(c, database.AcceptOrgInviteParams{
+		AcceptedBy: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		TokenHash:  token.Hash(Token),
+		Username:   Username,
+		Column4:    hashedPassword,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return database.Creator{}, err
+	}
+	if err == sql.ErrNoRows {
+		// Either the username is taken or the invite was used meanwhile
+		if _, err := s.LookupInvite(c, Token); err != nil {
+			return database.Creator{}, err
+		}
+		return database.Creator{}, ErrUsernameTaken
+	}
+	return creator, nil
+}
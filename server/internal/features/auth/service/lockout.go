@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// LoginFailureWindow is how long a failed login counts against a username
+	// or an IP
+	LoginFailureWindow = 15 * time.Minute
+	// LoginDelayAfter failures of a username, every further attempt has to
+	// wait twice as long as the previous one, up to MaxLoginDelay
+	LoginDelayAfter = 3
+	MaxLoginDelay   = 30 * time.Second
+	// UsernameLockoutThreshold failures lock the username for LockoutDuration
+	UsernameLockoutThreshold = 10
+	// IPLockoutThreshold failures from one IP, across usernames, lock the IP
+	IPLockoutThreshold = 50
+	LockoutDuration    = 15 * time.Minute
+
+	LockoutScopeUsername = "username"
+	LockoutScopeIP       = "ip"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("Invalid username or password")
+	ErrTooManyAttempts    = errors.New("Too many failed logins, try again later")
+)
+
+// ThrottledError is returned instead of checking the password while a
+// username or an IP has to wait
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string { return ErrTooManyAttempts.Error() }
+func (e *ThrottledError) Unwrap() error { return ErrTooManyAttempts }
+
+// attemptLogin checks the password unless the username or IP is throttled and
+// keeps track of the failures
+func (s *Service) attemptLogin(c context.Context, Username string, Password string, Client ClientInfo) (database.GetCreatorByUsernameRow, error) {
+	if err := s.checkLoginThrottle(c, Username, Client.IP); err != nil {
+		return database.GetCreatorByUsernameRow{}, err
+	}
+	creator, err := s.getCreator(c, Username, Password)
+	if err != nil {
+		if err == sql.ErrNoRows || errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			s.recordLoginFailure(c, Username, Client.IP)
+			return database.GetCreatorByUsernameRow{}, ErrInvalidCredentials
+		}
+		return database.GetCreatorByUsernameRow{}, err
+	}
+	// Admins keep password login so a broken IdP can not lock the org out
+	if creator.Role != "admin" {
+		settings, err := s.orgSettings(c, creator.OrgID)
+		if err != nil {
+			return database.GetCreatorByUsernameRow{}, err
+		}
+		if settings.PasswordLogin != nil && !*settings.PasswordLogin {
+			return database.GetCreatorByUsernameRow{}, ErrPasswordLoginDisabled
+		}
+	}
+	// Old failures of other usernames go too, they no longer count
+	err = s.db.ClearLoginFailures(c, database.ClearLoginFailuresParams{
+		Username:  Username,
+		CreatedAt: time.Now().Add(-LoginFailureWindow),
+	})
+	if err != nil {
+		log.Printf("Error clearing login failures of %q: %v", Username, err)
+	}
+	return creator, nil
+}
+
+func (s *Service) checkLoginThrottle(c context.Context, Username string, IP string) error {
+	lockout, err := s.db.GetActiveLoginLockout(c, database.GetActiveLoginLockoutParams{
+		Username: Username,
+		Ip:       IP,
+	})
+	if err == nil {
+		return &ThrottledError{RetryAfter: time.Until(lockout.LockedUntil)}
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	failures, err := s.db.CountLoginFailuresByUsername(c, database.CountLoginFailuresByUsernameParams{
+		Username:  Username,
+		CreatedAt: time.Now().Add(-LoginFailureWindow),
+	})
+	if err != nil {
+		return err
+	}
+	if wait := time.Until(failures.LastFailedAt.Add(loginDelay(failures.Failures))); wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// loginDelay is how long to wait after the last of failures failed logins
+func loginDelay(failures int32) time.Duration {
+	if failures < LoginDelayAfter {
+		return 0
+	}
+	delay := time.Second << (failures - LoginDelayAfter)
+	if delay > MaxLoginDelay || delay <= 0 {
+		return MaxLoginDelay
+	}
+	return delay
+}
+
+// recordLoginFailure stores the failure and locks the username or the IP once
+// it failed too often. Errors are only logged, the login failed anyway.
+func (s *Service) recordLoginFailure(c context.Context, Username string, IP string) {
+	err := s.db.RecordLoginFailure(c, database.RecordLoginFailureParams{
+		Username: Username,
+		Ip:       IP,
+	})
+	if err != nil {
+		log.Printf("Error recording login failure of %q: %v", Username, err)
+		return
+	}
+	since := time.Now().Add(-LoginFailureWindow)
+	byUsername, err := s.db.CountLoginFailuresByUsername(c, database.CountLoginFailuresByUsernameParams{
+		Username:  Username,
+		CreatedAt: since,
+	})
+	if err != nil {
+		log.Printf("Error counting login failures of %q: %v", Username, err)
+		return
+	}
+	if byUsername.Failures >= UsernameLockoutThreshold {
+		s.lockLogin(c, LockoutScopeUsername, Username, IP, byUsername.Failures)
+		return
+	}
+	byIP, err := s.db.CountLoginFailuresByIP(c, database.CountLoginFailuresByIPParams{
+		Ip:        IP,
+		CreatedAt: since,
+	})
+	if err != nil {
+		log.Printf("Error counting login failures from %s: %v", IP, err)
+		return
+	}
+	if byIP >= IPLockoutThreshold {
+		s.lockLogin(c, LockoutScopeIP, Username, IP, byIP)
+	}
+}
+
+func (s *Service) lockLogin(c context.Context, Scope string, Username string, IP string, Failures int32) {
+	// Lockouts of unknown usernames are kept but no org sees them
+	var CreatorID uuid.NullUUID
+	creator, err := s.db.GetCreatorByUsername(c, database.GetCreatorByUsernameParams{
+		Username: Username,
+		Active:   true,
+	})
+	if err == nil {
+		CreatorID = uuid.NullUUID{UUID: creator.ID, Valid: true}
+	}
+	lockout, err := s.db.CreateLoginLockout(c, database.CreateLoginLockoutParams{
+		Scope:       Scope,
+		Username:    Username,
+		Ip:          IP,
+		CreatorID:   CreatorID,
+		Failures:    Failures,
+		LockedUntil: time.Now().Add(LockoutDuration),
+	})
+	if err != nil {
+		log.Printf("Error locking login of %q from %s: %v", Username, IP, err)
+		return
+	}
+	log.Printf("Locked logins by %s until %s after %d failures, username %q from %s",
+		Scope, lockout.LockedUntil.Format(time.RFC3339), Failures, Username, IP)
+	// The count starts over once the lockout ends
+	if Scope == LockoutScopeUsername {
+		err = s.db.ClearLoginFailures(c, database.ClearLoginFailuresParams{
+			Username:  Username,
+			CreatedAt: time.Now().Add(-LoginFailureWindow),
+		})
+	} else {
+		err = s.db.ClearLoginFailuresByIP(c, IP)
+	}
+	if err != nil {
+		log.Printf("Error clearing login failures of %q from %s: %v", Username, IP, err)
+	}
+}
+
+// UnlockCreator lifts the lockouts of the username of a member and forgets its
+// failed logins. Lockouts of IPs expire on their own.
+func (s *Service) UnlockCreator(c context.Context, UserID uuid.UUID) (int64, error) {
+	if !s.isAdminOfTheSameOrg(c, UserID) {
+		return 0, ErrForbidden
+	}
+	creator, err := s.db.GetCreatorByID(c, UserID)
+	if err != nil {
+		return 0, err
+	}
+	unlocked, err := s.db.UnlockLoginLockouts(c, database.UnlockLoginLockoutsParams{
+		Username:   creator.Username,
+		UnlockedBy: uuid.NullUUID{UUID: utils.GetCreatorIDFromContext(c), Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = s.db.ClearLoginFailures(c, database.ClearLoginFailuresParams{
+		Username:  creator.Username,
+		CreatedAt: time.Now().Add(-LoginFailureWindow),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return unlocked, nil
+}
+
+// ListLoginLockouts returns the latest lockouts of usernames of the org
+func (s *Service) ListLoginLockouts(c context.Context, Limit int32) ([]database.LoginLockout, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	return s.db.ListLoginLockoutsByOrgID(c, database.ListLoginLockoutsByOrgIDParams{
+		OrgID: utils.GetOrgIDFromContext(c),
+		Limit: Limit,
+	})
+}
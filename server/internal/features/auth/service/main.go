@@ -2,21 +2,48 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc"
 	"github.com/crea8r/muninn/server/pkg/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrForbidden = errors.New("Forbidden")
+	ErrNotFound  = errors.New("Not found")
+	ErrInvalidInput = errors.New("Invalid input")
+)
+
 type Service struct {
-	db *database.Queries
+	db     *database.Queries
+	sqlDB  *sql.DB
+	mailer mailer.Mailer
+	// sso is nil unless an OpenID Connect provider is configured
+	sso *oidc.Provider
+}
+
+func NewService(db *database.Queries, sqlDB *sql.DB, m mailer.Mailer, sso *oidc.Provider) *Service {
+	return &Service{db: db, sqlDB: sqlDB, mailer: m, sso: sso}
 }
 
-func NewService(db *database.Queries) *Service {
-	return &Service{db: db}
+// inTx runs fn with queries of a transaction that commits when fn succeeds
+func (s *Service) inTx(c context.Context, fn func(q *database.Queries) error) error {
+	tx, err := s.sqlDB.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(s.db.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (s *Service) getCreator(c context.Context, Username string, Password string) (database.GetCreatorByUsernameRow, error) {
@@ -37,15 +64,13 @@ func (s *Service) getCreator(c context.Context, Username string, Password string
 	return creator, nil
 }
 
-func createClaim(creator database.GetCreatorByUsernameRow, expiresAt *jwt.NumericDate) *middleware.Claims {
+func createClaim(CreatorID uuid.UUID, OrgID uuid.UUID, Role string, jti string, expiresAt *jwt.NumericDate) *middleware.Claims {
 	return &middleware.Claims{
-		CreatorID: creator.ID.String(),
-		Name:      creator.Username,
-		OrgID:     creator.OrgID.String(),
-		OrgName:  creator.Orgname,
-		Role:      creator.Role,
-		Profile:  creator.Profile,
+		CreatorID: CreatorID.String(),
+		OrgID:     OrgID.String(),
+		Role:      Role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: expiresAt,
 		},
 	}
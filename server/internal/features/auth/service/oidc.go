@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// OIDCStateTTL is how long the user has to log in at the IdP
+const OIDCStateTTL = 10 * time.Minute
+
+var (
+	ErrSSONotConfigured      = errors.New("Single sign-on is not configured")
+	ErrInvalidSSOState       = errors.New("Invalid or expired single sign-on state")
+	ErrSSOFailed             = errors.New("Single sign-on failed")
+	ErrNoSSOAccount          = errors.New("No account matches this identity, ask an admin for an invite")
+	ErrPasswordLoginDisabled = errors.New("The org requires single sign-on")
+	ErrDomainTaken           = errors.New("Domain is already claimed")
+)
+
+// StartSSO returns the url of the IdP login page and the binding of the login
+// to the browser that starts it. The state, nonce and PKCE verifier of the
+// login are kept until the IdP redirects back, the browser keeps the binding
+// in a cookie.
+func (s *Service) StartSSO(c context.Context) (string, string, error) {
+	if s.sso == nil {
+		return "", "", ErrSSONotConfigured
+	}
+	state, err := token.New()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := token.New()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := token.New()
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.db.DeleteExpiredOIDCStates(c, time.Now()); err != nil {
+		log.Printf("Error deleting expired OIDC states: %v", err)
+	}
+	err = s.db.CreateOIDCState(c, database.CreateOIDCStateParams{
+		StateHash:    token.Hash(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(OIDCStateTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	authorizationURL, err := s.sso.AuthCodeURL(c, state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+	return authorizationURL, token.Hash(state), nil
+}
+
+// CompleteSSO logs in the creator of the identity the IdP redirected back
+// with. Unknown identities are linked to the creator with the same verified
+// email, or provisioned when an org claimed the domain of the email. A second
+// factor is still asked for like after a password login. Binding is what
+// StartSSO returned to the browser, a login started in another browser is
+// refused so nobody can log a victim into their own account.
+func (s *Service) CompleteSSO(c context.Context, Code string, State string, Binding string, Client ClientInfo) (LoginResult, error) {
+	if s.sso == nil {
+		return LoginResult{}, ErrSSONotConfigured
+	}
+	if Code == "" || State == "" {
+		return LoginResult{}, ErrInvalidSSOState
+	}
+	if subtle.ConstantTimeCompare([]byte(Binding), []byte(token.Hash(State))) != 1 {
+		return LoginResult{}, ErrInvalidSSOState
+	}
+	// The state works once, a replayed redirect matches no row
+	state, err := s.db.UseOIDCState(c, token.Hash(State))
+	if err == sql.ErrNoRows {
+		return LoginResult{}, ErrInvalidSSOState
+	}
+	if err != nil {
+		return LoginResult{}, err
+	}
+	raw, err := s.sso.Exchange(c, Code, state.CodeVerifier)
+	if err != nil {
+		log.Printf("Error exchanging OIDC code: %v", err)
+		return LoginResult{}, ErrSSOFailed
+	}
+	claims, err := s.sso.VerifyIDToken(c, raw, state.Nonce)
+	if err != nil {
+		log.Printf("Error verifying OIDC ID token: %v", err)
+		return LoginResult{}, ErrSSOFailed
+	}
+
+	creator, err := s.db.GetCreatorByIdentity(c, database.GetCreatorByIdentityParams{
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+	})
+	if err == nil {
+		err = s.db.TouchCreatorIdentity(c, database.TouchCreatorIdentityParams{
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   claims.Email,
+		})
+		if err != nil {
+			log.Printf("Error updating identity of creator %s: %v", creator.ID, err)
+		}
+		return s.completeLogin(c, creator.ID, creator.OrgID, creator.Role, Client)
+	}
+	if err != sql.ErrNoRows {
+		return LoginResult{}, err
+	}
+
+	// Emails the IdP did not verify could take over any account
+	if claims.Email == "" || !claims.EmailVerified {
+		return LoginResult{}, ErrNoSSOAccount
+	}
+	email, err := parseEmail(claims.Email)
+	if err != nil {
+		return LoginResult{}, ErrNoSSOAccount
+	}
+	creator, err = s.ssoCreator(c, email, claims.Name)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	_, err = s.db.CreateCreatorIdentity(c, database.CreateCreatorIdentityParams{
+		CreatorID: creator.ID,
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		Email:     email,
+	})
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return s.completeLogin(c, creator.ID, creator.OrgID, creator.Role, Client)
+}
+
+// ssoCreator finds the creator who verified email in Muninn, or creates one
+// in the org that claimed the domain of email
+func (s *Service) ssoCreator(c context.Context, email string, name string) (database.GetCreatorByIdentityRow, error) {
+	lower := strings.ToLower(email)
+	matches, err := s.db.ListCreatorsByVerifiedEmail(c, lower)
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	switch {
+	case len(matches) == 1:
+		return database.GetCreatorByIdentityRow(matches[0]), nil
+	case len(matches) > 1:
+		// Accounts in several orgs share the email, the user has to link one
+		// by logging in with a password first
+		log.Printf("OIDC email %q matches %d creators, not linking", email, len(matches))
+		return database.GetCreatorByIdentityRow{}, ErrNoSSOAccount
+	}
+
+	domain, err := s.db.GetSSODomain(c, lower[strings.LastIndex(lower, "@")+1:])
+	if err == sql.ErrNoRows {
+		return database.GetCreatorByIdentityRow{}, ErrNoSSOAccount
+	}
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	taken, err := s.db.UsernameExists(c, email)
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	if taken {
+		return database.GetCreatorByIdentityRow{}, ErrUsernameTaken
+	}
+	// Provisioned creators log in through the IdP, nobody knows this password
+	unusable, err := token.New()
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	hashedPassword, err := utils.HashPassword(unusable)
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	profile := map[string]string{"email": email}
+	if name != "" {
+		profile["name"] = name
+	}
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	created, err := s.db.CreateCreator(c, database.CreateCreatorParams{
+		Username: email,
+		Pwd:      hashedPassword,
+		Profile:  profileJSON,
+		Role:     domain.Role,
+		OrgID:    domain.OrgID,
+		Active:   true,
+	})
+	if err != nil {
+		return database.GetCreatorByIdentityRow{}, err
+	}
+	log.Printf("Provisioned creator %s in org %s from SSO domain %s", created.ID, created.OrgID, domain.Domain)
+	return database.GetCreatorByIdentityRow{
+		ID:       created.ID,
+		Username: created.Username,
+		OrgID:    created.OrgID,
+		Role:     created.Role,
+	}, nil
+}
+
+// ListSSODomains returns the email domains whose users join the org on their
+// first SSO login
+func (s *Service) ListSSODomains(c context.Context) ([]database.OrgSsoDomain, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	return s.db.ListSSODomainsByOrgID(c, utils.GetOrgIDFromContext(c))
+}
+
+// AddSSODomain claims an email domain for the org. The admin must have
+// verified an email at the domain, so nobody can claim a domain of others.
+func (s *Service) AddSSODomain(c context.Context, Domain string, Role string) (database.OrgSsoDomain, error) {
+	if !utils.IsAdmin(c) {
+		return database.OrgSsoDomain{}, ErrForbidden
+	}
+	domain := strings.ToLower(strings.TrimSpace(Domain))
+	if domain == "" || strings.ContainsAny(domain, "@/ ") || !strings.Contains(domain, ".") {
+		return database.OrgSsoDomain{}, fmt.Errorf("%w: invalid domain %q", ErrInvalidInput, Domain)
+	}
+	if !middleware.IsValidRole(Role) {
+		return database.OrgSsoDomain{}, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, Role)
+	}
+	admin, err := s.db.GetCreatorByID(c, utils.GetCreatorIDFromContext(c))
+	if err != nil {
+		return database.OrgSsoDomain{}, err
+	}
+	email, _ := profileEmail(admin.Profile)
+	verified, err := s.EmailVerified(c, admin)
+	if err != nil {
+		return database.OrgSsoDomain{}, err
+	}
+	if !verified || !strings.HasSuffix(strings.ToLower(email), "@"+domain) {
+		return database.OrgSsoDomain{}, fmt.Errorf("%w: verify an email at %s first", ErrForbidden, domain)
+	}
+	created, err := s.db.CreateSSODomain(c, database.CreateSSODomainParams{
+		Domain:    domain,
+		OrgID:     admin.OrgID,
+		Role:      Role,
+		CreatedBy: uuid.NullUUID{UUID: admin.ID, Valid: true},
+	})
+	if err != nil && strings.Contains(err.Error(), "duplicate key value") {
+		return database.OrgSsoDomain{}, ErrDomainTaken
+	}
+	return created, err
+}
+
+func (s *Service) RemoveSSODomain(c context.Context, Domain string) error {
+	if !utils.IsAdmin(c) {
+		return ErrForbidden
+	}
+	removed, err := s.db.DeleteSSODomain(c, database.DeleteSSODomainParams{
+		Domain: strings.ToLower(Domain),
+		OrgID:  utils.GetOrgIDFromContext(c),
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var testClient = ClientInfo{UserAgent: "test", IP: "127.0.0.1"}
+
+func newSSOService(t *testing.T) (*Service, *oidctest.IdP) {
+	t.Helper()
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	idp := oidctest.NewIdP(t)
+	return NewService(database.New(db), db, mailer.NewWriterMailer(io.Discard), idp.Provider()), idp
+}
+
+// completeSSO logs in at the IdP as the user of claims and completes the
+// login with the redirect back
+func completeSSO(t *testing.T, s *Service, idp *oidctest.IdP, claims jwt.MapClaims) (LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, binding, err := s.StartSSO(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.Login(t, authURL, claims)
+	return s.CompleteSSO(ctx, code, state, binding, testClient)
+}
+
+// claimDomain signs up an org that claimed domain for new creators of role
+func claimDomain(t *testing.T, s *Service, domain string, role string) database.Creator {
+	t.Helper()
+	ctx := context.Background()
+	admin, err := s.SignUp(ctx, "org of "+domain, "admin@"+domain, "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.db.CreateSSODomain(ctx, database.CreateSSODomainParams{
+		Domain:    domain,
+		OrgID:     admin.OrgID,
+		Role:      role,
+		CreatedBy: uuid.NullUUID{UUID: admin.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admin
+}
+
+func TestCompleteSSOProvisionsByClaimedDomain(t *testing.T) {
+	s, idp := newSSOService(t)
+	admin := claimDomain(t, s, "example.com", middleware.RoleMember)
+
+	claims := idp.Claims("user-1", "Ada@Example.com")
+	claims["name"] = "Ada"
+	result, err := completeSSO(t, s, idp, claims)
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if result.AccessToken == "" || result.RefreshToken == "" {
+		t.Fatalf("CompleteSSO returned no tokens: %+v", result)
+	}
+	creator, err := s.db.GetCreatorByIdentity(context.Background(), database.GetCreatorByIdentityParams{
+		Issuer:  idp.URL,
+		Subject: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("the identity was not linked: %v", err)
+	}
+	if creator.OrgID != admin.OrgID {
+		t.Errorf("provisioned in org %s, want %s", creator.OrgID, admin.OrgID)
+	}
+	if creator.Role != middleware.RoleMember {
+		t.Errorf("provisioned with role %q, want the role of the domain", creator.Role)
+	}
+
+	// The next login finds the identity
+	again, err := completeSSO(t, s, idp, idp.Claims("user-1", "Ada@Example.com"))
+	if err != nil {
+		t.Fatalf("second CompleteSSO: %v", err)
+	}
+	if again.AccessToken == "" {
+		t.Fatal("second CompleteSSO returned no access token")
+	}
+}
+
+func TestCompleteSSORejectsUnverifiedEmail(t *testing.T) {
+	s, idp := newSSOService(t)
+	claimDomain(t, s, "example.com", middleware.RoleMember)
+
+	claims := idp.Claims("user-1", "ada@example.com")
+	claims["email_verified"] = false
+	if _, err := completeSSO(t, s, idp, claims); !errors.Is(err, ErrNoSSOAccount) {
+		t.Fatalf("CompleteSSO = %v, want ErrNoSSOAccount", err)
+	}
+	taken, err := s.db.UsernameExists(context.Background(), "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taken {
+		t.Error("an unverified email was provisioned")
+	}
+}
+
+func TestCompleteSSORejectsUnclaimedDomain(t *testing.T) {
+	s, idp := newSSOService(t)
+	claimDomain(t, s, "example.com", middleware.RoleMember)
+
+	if _, err := completeSSO(t, s, idp, idp.Claims("user-1", "ada@example.org")); !errors.Is(err, ErrNoSSOAccount) {
+		t.Fatalf("CompleteSSO = %v, want ErrNoSSOAccount", err)
+	}
+}
+
+func TestCompleteSSORejectsInvalidIDToken(t *testing.T) {
+	s, idp := newSSOService(t)
+	claimDomain(t, s, "example.com", middleware.RoleMember)
+
+	tests := map[string]func(c jwt.MapClaims){
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other client" },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"bad nonce":      func(c jwt.MapClaims) { c["nonce"] = "other nonce" },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := idp.Claims("user-1", "ada@example.com")
+			change(claims)
+			if _, err := completeSSO(t, s, idp, claims); !errors.Is(err, ErrSSOFailed) {
+				t.Errorf("CompleteSSO = %v, want ErrSSOFailed", err)
+			}
+		})
+	}
+}
+
+func TestCompleteSSOUsesStateOnce(t *testing.T) {
+	s, idp := newSSOService(t)
+	claimDomain(t, s, "example.com", middleware.RoleMember)
+	ctx := context.Background()
+
+	authURL, binding, err := s.StartSSO(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.Login(t, authURL, idp.Claims("user-1", "ada@example.com"))
+	if _, err := s.CompleteSSO(ctx, code, state, binding, testClient); err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if _, err := s.CompleteSSO(ctx, code, state, binding, testClient); !errors.Is(err, ErrInvalidSSOState) {
+		t.Errorf("replayed CompleteSSO = %v, want ErrInvalidSSOState", err)
+	}
+}
+
+// TestCompleteSSORejectsOtherBrowser has a victim complete the login an
+// attacker started, with the binding of the login of the victim or none
+func TestCompleteSSORejectsOtherBrowser(t *testing.T) {
+	s, idp := newSSOService(t)
+	claimDomain(t, s, "example.com", middleware.RoleMember)
+	ctx := context.Background()
+
+	attackerURL, attackerBinding, err := s.StartSSO(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.Login(t, attackerURL, idp.Claims("attacker", "mallory@example.com"))
+	_, victimBinding, err := s.StartSSO(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, binding := range []string{"", victimBinding} {
+		if _, err := s.CompleteSSO(ctx, code, state, binding, testClient); !errors.Is(err, ErrInvalidSSOState) {
+			t.Errorf("CompleteSSO with binding %q = %v, want ErrInvalidSSOState", binding, err)
+		}
+	}
+	// The refused attempts did not use up the login of the attacker
+	if _, err := s.CompleteSSO(ctx, code, state, attackerBinding, testClient); err != nil {
+		t.Errorf("CompleteSSO in the browser that started it: %v", err)
+	}
+}
@@ -4,11 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
 )
 
+// OrgSettings are the security settings admins keep in the org profile
+type OrgSettings struct {
+	Require2FA bool `json:"require_2fa"`
+	// PasswordLogin is on unless the org turned it off in favour of SSO
+	PasswordLogin *bool `json:"password_login"`
+}
+
+func (s *Service) orgSettings(c context.Context, OrgID uuid.UUID) (OrgSettings, error) {
+	org, err := s.db.GetOrgDetails(c, OrgID)
+	if err != nil {
+		return OrgSettings{}, err
+	}
+	var settings OrgSettings
+	// Profiles are free form, a malformed one keeps the defaults
+	_ = json.Unmarshal(org.Profile, &settings)
+	return settings, nil
+}
+
 func (s *Service) ListOrgCreators(c context.Context, query string) ([]database.ListOrgMembersRow, error) {
 	OrgID := utils.GetOrgIDFromContext(c)
 	creators, err := s.db.ListOrgMembers(c, database.ListOrgMembersParams{
@@ -41,6 +62,9 @@ func (s *Service) AddNewOrgCreator(c context.Context, UserName string, Password
 	if !utils.IsAdmin(c) {
 		return database.Creator{}, errors.New("Forbidden")
 	}
+	if !middleware.IsValidRole(Role) {
+		return database.Creator{}, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, Role)
+	}
 	OrgID := utils.GetOrgIDFromContext(c)
 	hashedPassword, err := utils.HashPassword(Password)
 	if err != nil {
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	PurposePasswordReset = "password_reset"
+	PurposeEmailVerify   = "email_verify"
+
+	// PasswordResetTokenTTL is how long a mailed reset link works
+	PasswordResetTokenTTL = time.Hour
+	// EmailVerifyTokenTTL is how long a mailed verification link works
+	EmailVerifyTokenTTL = 24 * time.Hour
+)
+
+var ErrInvalidToken = errors.New("Invalid or expired token")
+
+// ForgotPassword mails a reset link to the email in the profile of the
+// creator. Unknown usernames and creators without an email are not reported,
+// callers must not learn which accounts exist.
+func (s *Service) ForgotPassword(c context.Context, Username string) error {
+	creator, err := s.db.GetCreatorByUsername(c, database.GetCreatorByUsernameParams{
+		Username: Username,
+		Active:   true,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	email, err := profileEmail(creator.Profile)
+	if err != nil || email == "" {
+		log.Printf("Password reset requested for creator %s without a valid email", creator.ID)
+		return nil
+	}
+	// Mail in the background so the response time does not tell whether the
+	// account exists
+	go func() {
+		c := context.WithoutCancel(c)
+		err := s.sendToken(c, creator.ID, PurposePasswordReset, email, PasswordResetTokenTTL,
+			"Reset your password",
+			"Someone asked to reset the password of %s.\n\nOpen this link within an hour to choose a new password:\n%s\n\nIgnore this email if it was not you.",
+			creator.Username, "/reset-password")
+		if err != nil {
+			log.Printf("Error sending password reset to creator %s: %v", creator.ID, err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. The
+// token works once, and every session of the creator is revoked.
+func (s *Service) ResetPassword(c context.Context, Token string, NewPassword string) error {
+	if NewPassword == "" {
+		return fmt.Errorf("%w: password is required", ErrInvalidInput)
+	}
+	hashedPassword, err := utils.HashPassword(NewPassword)
+	if err != nil {
+		return err
+	}
+	// The token is only used up when the password changes with it
+	return s.inTx(c, func(q *database.Queries) error {
+		t, err := useToken(c, q, Token, PurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		creator, err := q.GetCreatorByID(c, t.CreatorID)
+		if err != nil {
+			return err
+		}
+		if !creator.Active || creator.DeletedAt.Valid {
+			return ErrInvalidToken
+		}
+		err = q.UpdateCreatorPassword(c, database.UpdateCreatorPasswordParams{
+			ID:  creator.ID,
+			Pwd: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+		// Other links mailed before this reset must not work anymore
+		err = q.DeleteUnusedCreatorTokens(c, database.DeleteUnusedCreatorTokensParams{
+			CreatorID: creator.ID,
+			Purpose:   PurposePasswordReset,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.RevokeCreatorSessions(c, creator.ID)
+		return err
+	})
+}
+
+// SendEmailVerification mails a verification link to the email in the profile
+// of the current creator
+func (s *Service) SendEmailVerification(c context.Context) error {
+	creator, err := s.db.GetCreatorByID(c, utils.GetCreatorIDFromContext(c))
+	if err != nil {
+		return err
+	}
+	email, err := profileEmail(creator.Profile)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if email == "" {
+		return fmt.Errorf("%w: profile has no email", ErrInvalidInput)
+	}
+	verified, err := s.EmailVerified(c, creator)
+	if err != nil || verified {
+		return err
+	}
+	// Only the latest link works
+	err = s.db.DeleteUnusedCreatorTokens(c, database.DeleteUnusedCreatorTokensParams{
+		CreatorID: creator.ID,
+		Purpose:   PurposeEmailVerify,
+	})
+	if err != nil {
+		return err
+	}
+	return s.sendToken(c, creator.ID, PurposeEmailVerify, email, EmailVerifyTokenTTL,
+		"Verify your email",
+		"Open this link within a day to verify the email of %s:\n%s",
+		creator.Username, "/verify-email")
+}
+
+// VerifyEmail marks the email a verification token was sent to as verified.
+// Changing the email in the profile makes it unverified again.
+func (s *Service) VerifyEmail(c context.Context, Token string) error {
+	_, err := useToken(c, s.db, Token, PurposeEmailVerify)
+	return err
+}
+
+// EmailVerified reports whether the current email in the profile of the
+// creator has been verified
+func (s *Service) EmailVerified(c context.Context, creator database.Creator) (bool, error) {
+	email, err := profileEmail(creator.Profile)
+	if err != nil || email == "" {
+		return false, nil
+	}
+	return s.db.IsEmailVerified(c, database.IsEmailVerifiedParams{
+		CreatorID: creator.ID,
+		Email:     email,
+	})
+}
+
+func useToken(c context.Context, q *database.Queries, Token string, Purpose string) (database.CreatorToken, error) {
+	if Token == "" {
+		return database.CreatorToken{}, ErrInvalidToken
+	}
+	// Expired and used tokens match no row, racing requests can not both win
+	t, err := q.UseCreatorToken(c, database.UseCreatorTokenParams{
+		TokenHash: token.Hash(Token),
+		Purpose:   Purpose,
+	})
+	if err == sql.ErrNoRows {
+		return database.CreatorToken{}, ErrInvalidToken
+	}
+	return t, err
+}
+
+// sendToken stores a new token for the creator and mails a link to path of
+// the web app carrying it. body gets the username and the link.
+func (s *Service) sendToken(c context.Context, CreatorID uuid.UUID, Purpose string, Email string,
+	ttl time.Duration, subject string, body string, username string, path string) error {
+	plaintext, err := token.New()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.CreateCreatorToken(c, database.CreateCreatorTokenParams{
+		CreatorID: CreatorID,
+		Purpose:   Purpose,
+		TokenHash: token.Hash(plaintext),
+		Email:     Email,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+	link := appURL() + path + "?token=" + url.QueryEscape(plaintext)
+	return s.mailer.Send(c, mailer.Message{
+		To:      Email,
+		Subject: subject,
+		Body:    fmt.Sprintf(body, username, link),
+	})
+}
+
+// profileEmail returns the address of the email field of a creator profile
+func profileEmail(profile json.RawMessage) (string, error) {
+	var p struct {
+		Email string `json:"email"`
+	}
+	if len(profile) == 0 {
+		return "", nil
+	}
+	if err := json.Unmarshal(profile, &p); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(p.Email) == "" {
+		return "", nil
+	}
+	return parseEmail(p.Email)
+}
+
+// parseEmail returns the bare address of an email
+func parseEmail(email string) (string, error) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", fmt.Errorf("invalid email %q", email)
+	}
+	return addr.Address, nil
+}
+
+// appURL is the base url of the web app the mailed links open
+func appURL() string {
+	if u := os.Getenv("APP_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return "http://localhost:3000"
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/crea8r/muninn/server/pkg/mailer"
+	"github.com/crea8r/muninn/server/pkg/token"
+)
+
+// TestResetPasswordKeepsTokenWhenUpdateFails makes the password update fail.
+// The token must not be used up, and works once the update can go through.
+func TestResetPasswordKeepsTokenWhenUpdateFails(t *testing.T) {
+	db := testdb.Open(t)
+	t.Setenv("JWT_SECRET", "test secret")
+	s := NewService(database.New(db), db, mailer.NewWriterMailer(io.Discard), nil)
+	ctx := context.Background()
+
+	creator, err := s.SignUp(ctx, "acme", "ada@example.com", "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := token.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.db.CreateCreatorToken(ctx, database.CreateCreatorTokenParams{
+		CreatorID: creator.ID,
+		Purpose:   PurposePasswordReset,
+		TokenHash: token.Hash(plaintext),
+		Email:     "ada@example.com",
+		ExpiresAt: time.Now().Add(PasswordResetTokenTTL),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stmt := range []string{
+		`CREATE FUNCTION refuse_password() RETURNS trigger LANGUAGE plpgsql AS $$
+			BEGIN RAISE EXCEPTION 'password refused'; END $$`,
+		`CREATE TRIGGER refuse_password BEFORE UPDATE OF pwd ON creator
+			FOR EACH ROW EXECUTE FUNCTION refuse_password()`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.ResetPassword(ctx, plaintext, "new password"); err == nil {
+		t.Fatal("ResetPassword succeeded while the update was refused")
+	}
+
+	if _, err := db.ExecContext(ctx, "DROP TRIGGER refuse_password ON creator"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ResetPassword(ctx, plaintext, "new password"); err != nil {
+		t.Fatalf("ResetPassword after the failed attempt: %v", err)
+	}
+	if _, err := s.getCreator(ctx, "ada@example.com", "new password"); err != nil {
+		t.Errorf("the new password does not log in: %v", err)
+	}
+	if err := s.ResetPassword(ctx, plaintext, "other password"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("reusing the token = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/utils"
+)
+
+// ListRolePermissions returns the effective permissions of every role in the org
+func (s *Service) ListRolePermissions(c context.Context) (map[string][]string, error) {
+	if !utils.IsAdmin(c) {
+		return nil, ErrForbidden
+	}
+	overrides, err := s.db.ListRolePermissionsByOrgID(c, utils.GetOrgIDFromContext(c))
+	if err != nil {
+		return nil, err
+	}
+	granted := map[string]map[string]bool{}
+	for _, role := range middleware.Roles {
+		granted[role] = map[string]bool{}
+		for _, p := range middleware.Permissions {
+			granted[role][p] = middleware.HasDefaultPermission(role, p)
+		}
+	}
+	for _, o := range overrides {
+		if _, ok := granted[o.Role]; ok {
+			granted[o.Role][o.Permission] = o.Granted
+		}
+	}
+	result := map[string][]string{}
+	for _, role := range middleware.Roles {
+		result[role] = []string{}
+		for _, p := range middleware.Permissions {
+			if granted[role][p] {
+				result[role] = append(result[role], p)
+			}
+		}
+	}
+	return result, nil
+}
+
+// UpdateRolePermissions sets the full list of permissions of a role in the org,
+// only the differences with the default matrix are stored
+func (s *Service) UpdateRolePermissions(c context.Context, Role string, Permissions []string) error {
+	if !utils.IsAdmin(c) {
+		return ErrForbidden
+	}
+	if !middleware.IsValidRole(Role) || Role == middleware.RoleAdmin {
+		return fmt.Errorf("%w: role %q cannot be customised", ErrInvalidInput, Role)
+	}
+	wanted := map[string]bool{}
+	for _, p := range Permissions {
+		if !middleware.IsValidPermission(p) {
+			return fmt.Errorf("%w: unknown permission %q", ErrInvalidInput, p)
+		}
+		wanted[p] = true
+	}
+	names := []string{}
+	values := []bool{}
+	for _, p := range middleware.Permissions {
+		if wanted[p] != middleware.HasDefaultPermission(Role, p) {
+			names = append(names, p)
+			values = append(values, wanted[p])
+		}
+	}
+	return s.db.ReplaceRolePermissions(c, database.ReplaceRolePermissionsParams{
+		OrgID:     utils.GetOrgIDFromContext(c),
+		Role:      Role,
+		UpdatedBy: utils.GetCreatorIDFromContext(c),
+		Column4:   names,
+		Column5:   values,
+	})
+}
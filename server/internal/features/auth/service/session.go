@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// ClientInfo describes where a login came from, it is stored on the session
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+var ErrInvalidRefreshToken = errors.New("Invalid refresh token, please login again")
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Each refresh token works once, presenting a used one means it was
+// stolen so the whole session is revoked.
+func (s *Service) Refresh(c context.Context, RefreshToken string) (Tokens, error) {
+	if RefreshToken == "" {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	rt, err := s.db.GetRefreshTokenByHash(c, token.Hash(RefreshToken))
+	if err != nil {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	if rt.UsedAt.Valid {
+		s.revokeTokenFamily(c, rt)
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	now := time.Now()
+	if rt.SessionRevokedAt.Valid || !rt.Active ||
+		now.After(rt.ExpiresAt) || now.After(rt.SessionExpiredAt) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	// Two requests racing with the same token, only one of them wins
+	marked, err := s.db.MarkRefreshTokenUsed(c, rt.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if marked == 0 {
+		s.revokeTokenFamily(c, rt)
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	return s.signTokens(c, rt.SessionID, rt.Jti, rt.SessionExpiredAt, rt.CreatorID, rt.OrgID, rt.Role)
+}
+
+func (s *Service) revokeTokenFamily(c context.Context, rt database.GetRefreshTokenByHashRow) {
+	log.Printf("Refresh token reuse detected for session %s of creator %s, revoking session", rt.SessionID, rt.CreatorID)
+	if err := s.db.RevokeSessionByID(c, rt.SessionID); err != nil {
+		log.Printf("Error revoking session %s: %v", rt.SessionID, err)
+	}
+}
+
+// Me returns the current creator and org, clients use it instead of reading
+// display data from the token
+func (s *Service) Me(c context.Context) (database.Creator, database.Org, error) {
+	creator, err := s.db.GetCreatorByID(c, utils.GetCreatorIDFromContext(c))
+	if err != nil {
+		return database.Creator{}, database.Org{}, err
+	}
+	org, err := s.db.GetOrgDetails(c, creator.OrgID)
+	if err != nil {
+		return database.Creator{}, database.Org{}, err
+	}
+	return creator, org, nil
+}
+
+// Logout revokes the session of the token used for this request, or ends the
+// impersonation the token belongs to
+func (s *Service) Logout(c context.Context) error {
+	claims := c.Value(middleware.UserClaimsKey).(*middleware.Claims)
+	if claims.ID == "" {
+		return nil
+	}
+	if claims.ImpersonatorID != "" {
+		_, err := s.db.EndImpersonationByJti(c, claims.ID)
+		return err
+	}
+	_, err := s.db.RevokeSessionByJti(c, database.RevokeSessionByJtiParams{
+		Jti:       claims.ID,
+		CreatorID: utils.GetCreatorIDFromContext(c),
+	})
+	return err
+}
+
+// LogoutAll revokes every session of the current creator, on all devices
+func (s *Service) LogoutAll(c context.Context) (int64, error) {
+	return s.db.RevokeCreatorSessions(c, utils.GetCreatorIDFromContext(c))
+}
+
+func (s *Service) ListSessions(c context.Context) ([]database.ListActiveSessionsByCreatorIDRow, error) {
+	sessions, err := s.db.ListActiveSessionsByCreatorID(c, utils.GetCreatorIDFromContext(c))
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeMemberSessions lets an admin log a member of the same org out everywhere
+func (s *Service) RevokeMemberSessions(c context.Context, UserID uuid.UUID) (int64, error) {
+	if !s.isAdminOfTheSameOrg(c, UserID) {
+		return 0, ErrForbidden
+	}
+	return s.db.RevokeCreatorSessions(c, UserID)
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/crea8r/muninn/server/pkg/totp"
+	"github.com/crea8r/muninn/server/pkg/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	ChallengeVerify = "verify"
+	ChallengeSetup  = "setup"
+
+	// LoginChallengeTTL is how long a password login waits for its second
+	// factor
+	LoginChallengeTTL = 10 * time.Minute
+	// TOTPIssuer is the account name prefix shown by authenticator apps
+	TOTPIssuer = "Muninn"
+	// RecoveryCodeCount codes are issued when 2FA is enabled
+	RecoveryCodeCount = 10
+	// recoveryCodeLength is the length without the dash
+	recoveryCodeLength = 10
+)
+
+var (
+	ErrInvalidChallenge  = errors.New("Invalid or expired login challenge, please login again")
+	ErrInvalidCode       = errors.New("Invalid authentication code")
+	ErrTwoFactorEnabled  = errors.New("Two-factor authentication is already enabled")
+	ErrTwoFactorDisabled = errors.New("Two-factor authentication is not enabled")
+	ErrTwoFactorRequired = errors.New("The org requires two-factor authentication")
+)
+
+// Enrollment is a pending TOTP secret, the uri is shown as a QR code
+type Enrollment struct {
+	Secret string
+	URI    string
+}
+
+// completeLogin issues tokens once the password is checked, unless the
+// creator must first pass their second factor or set one up for the org
+func (s *Service) completeLogin(c context.Context, CreatorID uuid.UUID, OrgID uuid.UUID, Role string, Client ClientInfo) (LoginResult, error) {
+	enabled, err := s.twoFactorEnabled(c, CreatorID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	purpose := ""
+	if enabled {
+		purpose = ChallengeVerify
+	} else {
+		required, err := s.orgRequiresTwoFactor(c, OrgID)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		if required {
+			purpose = ChallengeSetup
+		}
+	}
+	if purpose == "" {
+		tokens, err := s.issueTokens(c, CreatorID, OrgID, Role, Client)
+		return LoginResult{Tokens: tokens}, err
+	}
+
+	challenge, err := token.New()
+	if err != nil {
+		return LoginResult{}, err
+	}
+	created, err := s.db.CreateLoginChallenge(c, database.CreateLoginChallengeParams{
+		CreatorID: CreatorID,
+		Purpose:   purpose,
+		TokenHash: token.Hash(challenge),
+		ExpiresAt: time.Now().Add(LoginChallengeTTL),
+	})
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{
+		Challenge:          challenge,
+		ChallengePurpose:   purpose,
+		ChallengeExpiresAt: created.ExpiresAt,
+	}, nil
+}
+
+// VerifyTwoFactor completes a login with a TOTP or a recovery code. Wrong
+// codes count as failed logins of the username.
+func (s *Service) VerifyTwoFactor(c context.Context, Challenge string, Code string, Client ClientInfo) (Tokens, error) {
+	ch, err := s.getChallenge(c, Challenge, ChallengeVerify)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if err := s.checkLoginThrottle(c, ch.Username, Client.IP); err != nil {
+		return Tokens{}, err
+	}
+	ok, err := s.checkSecondFactor(c, ch.CreatorID, Code)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if !ok {
+		s.recordLoginFailure(c, ch.Username, Client.IP)
+		return Tokens{}, ErrInvalidCode
+	}
+	return s.finishChallenge(c, ch, Client)
+}
+
+// SetupTwoFactor starts the enrollment a login challenge asks for
+func (s *Service) SetupTwoFactor(c context.Context, Challenge string) (Enrollment, error) {
+	ch, err := s.getChallenge(c, Challenge, ChallengeSetup)
+	if err != nil {
+		return Enrollment{}, err
+	}
+	return s.enroll(c, ch.CreatorID, ch.Username)
+}
+
+// ConfirmTwoFactorSetup enables the secret from SetupTwoFactor and completes
+// the login. The recovery codes are only returned here.
+func (s *Service) ConfirmTwoFactorSetup(c context.Context, Challenge string, Code string, Client ClientInfo) (Tokens, []string, error) {
+	ch, err := s.getChallenge(c, Challenge, ChallengeSetup)
+	if err != nil {
+		return Tokens{}, nil, err
+	}
+	if err := s.checkLoginThrottle(c, ch.Username, Client.IP); err != nil {
+		return Tokens{}, nil, err
+	}
+	codes, err := s.confirm(c, ch.CreatorID, Code)
+	if errors.Is(err, ErrInvalidCode) {
+		s.recordLoginFailure(c, ch.Username, Client.IP)
+	}
+	if err != nil {
+		return Tokens{}, nil, err
+	}
+	tokens, err := s.finishChallenge(c, ch, Client)
+	if err != nil {
+		return Tokens{}, nil, err
+	}
+	return tokens, codes, nil
+}
+
+// EnrollTwoFactor creates a pending secret for the current creator, replacing
+// any earlier pending one
+func (s *Service) EnrollTwoFactor(c context.Context) (Enrollment, error) {
+	creator, err := s.db.GetCreatorByID(c, utils.GetCreatorIDFromContext(c))
+	if err != nil {
+		return Enrollment{}, err
+	}
+	return s.enroll(c, creator.ID, creator.Username)
+}
+
+// ConfirmTwoFactor enables the pending secret of the current creator and
+// returns the recovery codes
+func (s *Service) ConfirmTwoFactor(c context.Context, Code string) ([]string, error) {
+	return s.confirm(c, utils.GetCreatorIDFromContext(c), Code)
+}
+
+// DisableTwoFactor turns 2FA off for the current creator, it needs a valid
+// code and is refused while the org requires 2FA
+func (s *Service) DisableTwoFactor(c context.Context, Code string) error {
+	CreatorID := utils.GetCreatorIDFromContext(c)
+	required, err := s.orgRequiresTwoFactor(c, utils.GetOrgIDFromContext(c))
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+	if err := s.requireSecondFactor(c, CreatorID, Code); err != nil {
+		return err
+	}
+	return s.removeTwoFactor(c, CreatorID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current creator
+func (s *Service) RegenerateRecoveryCodes(c context.Context, Code string) ([]string, error) {
+	CreatorID := utils.GetCreatorIDFromContext(c)
+	if err := s.requireSecondFactor(c, CreatorID, Code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(c, CreatorID)
+}
+
+// ResetMemberTwoFactor lets an admin remove the second factor of a member who
+// lost it, the member sets up a new one on the next login if the org
+// requires it
+func (s *Service) ResetMemberTwoFactor(c context.Context, UserID uuid.UUID) error {
+	if !s.isAdminOfTheSameOrg(c, UserID) {
+		return ErrForbidden
+	}
+	return s.removeTwoFactor(c, UserID)
+}
+
+// TwoFactorEnabled reports whether the current creator has 2FA on
+func (s *Service) TwoFactorEnabled(c context.Context) (bool, error) {
+	return s.twoFactorEnabled(c, utils.GetCreatorIDFromContext(c))
+}
+
+func (s *Service) twoFactorEnabled(c context.Context, CreatorID uuid.UUID) (bool, error) {
+	secret, err := s.db.GetCreatorTOTP(c, CreatorID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return secret.EnabledAt.Valid, nil
+}
+
+// orgRequiresTwoFactor reads the require_2fa setting of the org profile
+func (s *Service) orgRequiresTwoFactor(c context.Context, OrgID uuid.UUID) (bool, error) {
+	settings, err := s.orgSettings(c, OrgID)
+	if err != nil {
+		return false, err
+	}
+	return settings.Require2FA, nil
+}
+
+func (s *Service) getChallenge(c context.Context, Challenge string, Purpose string) (database.GetActiveLoginChallengeRow, error) {
+	if Challenge == "" {
+		return database.GetActiveLoginChallengeRow{}, ErrInvalidChallenge
+	}
+	ch, err := s.db.GetActiveLoginChallenge(c, database.GetActiveLoginChallengeParams{
+		TokenHash: token.Hash(Challenge),
+		Purpose:   Purpose,
+	})
+	if err == sql.ErrNoRows {
+		return database.GetActiveLoginChallengeRow{}, ErrInvalidChallenge
+	}
+	return ch, err
+}
+
+// finishChallenge uses up the challenge and issues the tokens of the login
+func (s *Service) finishChallenge(c context.Context, ch database.GetActiveLoginChallengeRow, Client ClientInfo) (Tokens, error) {
+	// Two requests racing with the same challenge, only one of them wins
+	marked, err := s.db.MarkLoginChallengeUsed(c, ch.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if marked == 0 {
+		return Tokens{}, ErrInvalidChallenge
+	}
+	return s.issueTokens(c, ch.CreatorID, ch.OrgID, ch.Role, Client)
+}
+
+func (s *Service) enroll(c context.Context, CreatorID uuid.UUID, Username string) (Enrollment, error) {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return Enrollment{}, err
+	}
+	_, err = s.db.UpsertPendingCreatorTOTP(c, database.UpsertPendingCreatorTOTPParams{
+		CreatorID: CreatorID,
+		Secret:    secret,
+	})
+	// The upsert skips secrets that are already enabled
+	if err == sql.ErrNoRows {
+		return Enrollment{}, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return Enrollment{}, err
+	}
+	return Enrollment{
+		Secret: secret,
+		URI:    totp.URI(TOTPIssuer, Username, secret),
+	}, nil
+}
+
+func (s *Service) confirm(c context.Context, CreatorID uuid.UUID, Code string) ([]string, error) {
+	secret, err := s.db.GetCreatorTOTP(c, CreatorID)
+	if err == sql.ErrNoRows {
+		return nil, ErrTwoFactorDisabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret.EnabledAt.Valid {
+		return nil, ErrTwoFactorEnabled
+	}
+	ok, err := s.checkTOTP(c, secret, Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+	enabled, err := s.db.EnableCreatorTOTP(c, CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled == 0 {
+		return nil, ErrTwoFactorEnabled
+	}
+	return s.newRecoveryCodes(c, CreatorID)
+}
+
+// requireSecondFactor fails unless 2FA is on and Code passes it
+func (s *Service) requireSecondFactor(c context.Context, CreatorID uuid.UUID, Code string) error {
+	enabled, err := s.twoFactorEnabled(c, CreatorID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrTwoFactorDisabled
+	}
+	ok, err := s.checkSecondFactor(c, CreatorID, Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// checkSecondFactor accepts a current TOTP code or an unused recovery code,
+// each of them only once
+func (s *Service) checkSecondFactor(c context.Context, CreatorID uuid.UUID, Code string) (bool, error) {
+	secret, err := s.db.GetCreatorTOTP(c, CreatorID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !secret.EnabledAt.Valid {
+		return false, nil
+	}
+	ok, err := s.checkTOTP(c, secret, Code)
+	if err != nil || ok {
+		return ok, err
+	}
+	return s.useRecoveryCode(c, CreatorID, Code)
+}
+
+func (s *Service) checkTOTP(c context.Context, secret database.CreatorTotp, Code string) (bool, error) {
+	// One step of clock drift either way
+	step, ok := totp.Validate(secret.Secret, Code, time.Now(), 1)
+	if !ok {
+		return false, nil
+	}
+	used, err := s.db.UseCreatorTOTPStep(c, database.UseCreatorTOTPStepParams{
+		CreatorID:    secret.CreatorID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+	// A code of this step or a later one was already accepted
+	return used > 0, nil
+}
+
+func (s *Service) useRecoveryCode(c context.Context, CreatorID uuid.UUID, Code string) (bool, error) {
+	Code = normalizeRecoveryCode(Code)
+	// Skips the bcrypt comparisons for TOTP codes
+	if len(Code) != recoveryCodeLength {
+		return false, nil
+	}
+	codes, err := s.db.ListUnusedRecoveryCodes(c, CreatorID)
+	if err != nil {
+		return false, err
+	}
+	for _, rc := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(rc.CodeHash), []byte(Code)) != nil {
+			continue
+		}
+		used, err := s.db.UseRecoveryCode(c, rc.ID)
+		if err != nil {
+			return false, err
+		}
+		return used > 0, nil
+	}
+	return false, nil
+}
+
+// newRecoveryCodes replaces the recovery codes of the creator
+func (s *Service) newRecoveryCodes(c context.Context, CreatorID uuid.UUID) ([]string, error) {
+	if err := s.db.DeleteRecoveryCodes(c, CreatorID); err != nil {
+		return nil, err
+	}
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		secret, err := totp.NewSecret()
+		if err != nil {
+			return nil, err
+		}
+		// Two groups of five characters, 50 random bits
+		code := strings.ToLower(secret[:5] + "-" + secret[5:recoveryCodeLength])
+		hash, err := utils.HashPassword(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, err
+		}
+		err = s.db.CreateRecoveryCode(c, database.CreateRecoveryCodeParams{
+			CreatorID: CreatorID,
+			CodeHash:  hash,
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(Code string) string {
+	Code = strings.ToLower(strings.TrimSpace(Code))
+	return strings.ReplaceAll(Code, "-", "")
+}
+
+func (s *Service) removeTwoFactor(c context.Context, CreatorID uuid.UUID) error {
+	deleted, err := s.db.DeleteCreatorTOTP(c, CreatorID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrTwoFactorDisabled
+	}
+	return s.db.DeleteRecoveryCodes(c, CreatorID)
+}
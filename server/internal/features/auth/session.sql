@@ -0,0 +1,56 @@
+-- name: CreateCreatorSession :one
+INSERT INTO creator_session (creator_id, jti, user_agent, ip, expired_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetActiveSessionByJti :one
+SELECT s.id, s.creator_id, c.org_id, c.role
+FROM creator_session s
+JOIN creator c ON s.creator_id = c.id
+WHERE s.jti = $1
+  AND s.revoked_at IS NULL
+  AND s.expired_at > CURRENT_TIMESTAMP
+  AND c.active = true
+  AND c.deleted_at IS NULL;
+
+-- name: ListActiveSessionsByCreatorID :many
+SELECT id, user_agent, ip, expired_at, created_at
+FROM creator_session
+WHERE creator_id = $1
+  AND revoked_at IS NULL
+  AND expired_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC;
+
+-- name: RevokeSessionByJti :execrows
+UPDATE creator_session
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE jti = $1 AND creator_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeCreatorSessions :execrows
+UPDATE creator_session
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE creator_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeSessionByID :exec
+UPDATE creator_session
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_token (session_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetRefreshTokenByHash :one
+SELECT rt.id, rt.session_id, rt.expires_at, rt.used_at,
+  s.jti, s.expired_at AS session_expired_at, s.revoked_at AS session_revoked_at,
+  c.id AS creator_id, c.org_id, c.role, c.active
+FROM refresh_token rt
+JOIN creator_session s ON rt.session_id = s.id
+JOIN creator c ON s.creator_id = c.id
+WHERE rt.token_hash = $1 AND c.deleted_at IS NULL;
+
+-- name: MarkRefreshTokenUsed :execrows
+UPDATE refresh_token
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
@@ -0,0 +1,23 @@
+-- name: CreateCreatorToken :one
+INSERT INTO creator_token (creator_id, purpose, token_hash, email, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UseCreatorToken :one
+UPDATE creator_token
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1 AND purpose = $2
+  AND used_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteUnusedCreatorTokens :exec
+DELETE FROM creator_token
+WHERE creator_id = $1 AND purpose = $2 AND used_at IS NULL;
+
+-- name: IsEmailVerified :one
+SELECT EXISTS (
+  SELECT 1 FROM creator_token
+  WHERE creator_id = $1 AND purpose = 'email_verify'
+    AND email = $2 AND used_at IS NOT NULL
+)::boolean AS verified;
@@ -0,0 +1,59 @@
+-- name: UpsertPendingCreatorTOTP :one
+INSERT INTO creator_totp (creator_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (creator_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+WHERE creator_totp.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetCreatorTOTP :one
+SELECT * FROM creator_totp WHERE creator_id = $1;
+
+-- name: EnableCreatorTOTP :execrows
+UPDATE creator_totp
+SET enabled_at = CURRENT_TIMESTAMP
+WHERE creator_id = $1 AND enabled_at IS NULL;
+
+-- name: UseCreatorTOTPStep :execrows
+UPDATE creator_totp
+SET last_used_step = $2
+WHERE creator_id = $1 AND last_used_step < $2;
+
+-- name: DeleteCreatorTOTP :execrows
+DELETE FROM creator_totp WHERE creator_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO creator_recovery_code (creator_id, code_hash)
+VALUES ($1, $2);
+
+-- name: ListUnusedRecoveryCodes :many
+SELECT id, code_hash FROM creator_recovery_code
+WHERE creator_id = $1 AND used_at IS NULL;
+
+-- name: UseRecoveryCode :execrows
+UPDATE creator_recovery_code
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM creator_recovery_code WHERE creator_id = $1;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenge (creator_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetActiveLoginChallenge :one
+SELECT ch.id, ch.creator_id, ch.expires_at, c.username, c.org_id, c.role
+FROM login_challenge ch
+JOIN creator c ON ch.creator_id = c.id
+WHERE ch.token_hash = $1 AND ch.purpose = $2
+  AND ch.used_at IS NULL
+  AND ch.expires_at > CURRENT_TIMESTAMP
+  AND c.active = true
+  AND c.deleted_at IS NULL;
+
+-- name: MarkLoginChallengeUsed :execrows
+UPDATE login_challenge
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
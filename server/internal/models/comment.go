@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+)
+
+// Comment is a comment about an object or a task. Replies have the comment
+// they answer as ParentID. Mentions lists the creators mentioned with their
+// creatorId and username, Reactions the emoji with their count and
+// creatorIds.
+type Comment struct {
+	ID              uuid.UUID       `json:"id"`
+	ObjectID        ctype.NullUUID  `json:"objectId"`
+	TaskID          ctype.NullUUID  `json:"taskId"`
+	ParentID        ctype.NullUUID  `json:"parentId"`
+	Text            string          `json:"text"`
+	CreatorID       uuid.UUID       `json:"creatorId"`
+	CreatorUsername string          `json:"creatorUsername"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	EditedAt        ctype.NullTime  `json:"editedAt"`
+	DeletedAt       ctype.NullTime  `json:"deletedAt"`
+	Mentions        json.RawMessage `json:"mentions"`
+	Reactions       json.RawMessage `json:"reactions"`
+}
+
+func NewComments(rows []database.ListCommentsRow) []Comment {
+	comments := make([]Comment, len(rows))
+	for i, row := range rows {
+		comments[i] = Comment{
+			ID:              row.ID,
+			ObjectID:        ctype.NullUUID{NullUUID: row.ObjID},
+			TaskID:          ctype.NullUUID{NullUUID: row.TaskID},
+			ParentID:        ctype.NullUUID{NullUUID: row.ParentID},
+			Text:            row.Text,
+			CreatorID:       row.CreatorID,
+			CreatorUsername: row.CreatorUsername,
+			CreatedAt:       row.CreatedAt,
+			EditedAt:        ctype.NullTime{NullTime: row.EditedAt},
+			DeletedAt:       ctype.NullTime{NullTime: row.DeletedAt},
+			Mentions:        row.Mentions,
+			Reactions:       row.Reactions,
+		}
+	}
+	return comments
+}
@@ -2,10 +2,12 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/pkg/ctype"
 	"github.com/google/uuid"
@@ -52,6 +54,9 @@ type ObjectModel struct {
 type ObjectDetail struct {
 	ID          uuid.UUID         `json:"id"`
 	Name        string            `json:"name"`
+	// Photo is a signed URL when PhotoAttachmentID is set, see ObjectHandler
+	Photo       string            `json:"photo"`
+	PhotoAttachmentID ctype.NullUUID `json:"photoAttachmentId"`
 	Description string            `json:"description"`
 	IDString    string            `json:"idString"`
 	CreatorID   uuid.UUID         `json:"creatorId"`
@@ -62,6 +67,8 @@ type ObjectDetail struct {
 	StepsAndFunnels []StepAndFunnel `json:"stepsAndFunnels"`
 	Facts       []Fact            `json:"facts"`
 	Aliases		  []string				  `json:"aliases"`
+	Relations   []ObjectRelation  `json:"relations"`
+	Comments    []Comment         `json:"comments"`
 }
 
 type Task struct {
@@ -97,8 +104,12 @@ func NewObjectModel(db *database.Queries) *ObjectModel {
 	return &ObjectModel{DB: db}
 }
 
+func (m *ObjectModel) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, m.DB)
+}
+
 func (m *ObjectModel) Create(ctx context.Context, name, description, idString string, creatorID uuid.UUID) (*Object, error) {
-	obj, err := m.DB.CreateObject(ctx, database.CreateObjectParams{
+	obj, err := m.q(ctx).CreateObject(ctx, database.CreateObjectParams{
 		Name:        name,
 		Description: description,
 		IDString:    idString,
@@ -118,13 +129,14 @@ func (m *ObjectModel) Create(ctx context.Context, name, description, idString st
 	}, nil
 }
 
-func (m *ObjectModel) Update(ctx context.Context, id uuid.UUID, name, description, idString string, aliases []string) (*Object, error) {
-	obj, err := m.DB.UpdateObject(ctx, database.UpdateObjectParams{
+func (m *ObjectModel) Update(ctx context.Context, id, orgID uuid.UUID, name, description, idString string, aliases []string) (*Object, error) {
+	obj, err := m.q(ctx).UpdateObject(ctx, database.UpdateObjectParams{
 		ID:          id,
 		Name:        name,
 		Description: description,
 		IDString:    idString,
 		Aliases: 		 aliases,
+		OrgID:       orgID,
 	})
 	if err != nil {
 		return nil, err
@@ -140,12 +152,17 @@ func (m *ObjectModel) Update(ctx context.Context, id uuid.UUID, name, descriptio
 	}, nil
 }
 
-func (m *ObjectModel) Delete(ctx context.Context, id uuid.UUID) error {
-	return m.DB.DeleteObject(ctx, id)
+// Delete returns sql.ErrNoRows when the object does not belong to the org
+func (m *ObjectModel) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+	rows, err := m.q(ctx).DeleteObject(ctx, database.DeleteObjectParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+	return noRowsIfZero(rows, err)
 }
 
 func (m *ObjectModel) List(ctx context.Context, orgID uuid.UUID, search string, limit, offset int32) ([]ListObjectsByOrgIdRow, int64, error) {
-	objects, err := m.DB.ListObjectsByOrgID(ctx, database.ListObjectsByOrgIDParams{
+	objects, err := m.q(ctx).ListObjectsByOrgID(ctx, database.ListObjectsByOrgIDParams{
 		OrgID:  orgID,
 		Column2: search,
 		Limit:  limit,
@@ -154,7 +171,7 @@ func (m *ObjectModel) List(ctx context.Context, orgID uuid.UUID, search string,
 	if err != nil {
 		return nil, 0, err
 	}
-	count, err := m.DB.CountObjectsByOrgID(ctx, database.CountObjectsByOrgIDParams{
+	count, err := m.q(ctx).CountObjectsByOrgID(ctx, database.CountObjectsByOrgIDParams{
 		OrgID:  orgID,
 		Column2: search,
 	})
@@ -205,7 +222,7 @@ func (m *ObjectModel) List(ctx context.Context, orgID uuid.UUID, search string,
 }
 
 func (m *ObjectModel) GetDetails(ctx context.Context, id, orgID uuid.UUID) (*ObjectDetail, error) {
-	data, err := m.DB.GetObjectDetails(ctx, database.GetObjectDetailsParams{
+	data, err := m.q(ctx).GetObjectDetails(ctx, database.GetObjectDetailsParams{
 		ID:    id,
 		OrgID: orgID,
 	})
@@ -218,6 +235,7 @@ func (m *ObjectModel) GetDetails(ctx context.Context, id, orgID uuid.UUID) (*Obj
 	var tasks []Task
 	var stepsAndFunnels []StepAndFunnel
 	var facts []Fact
+	var relations []ObjectRelation
 
 	tagsBytes, ok := data.Tags.([]byte)
 	if !ok {
@@ -266,9 +284,28 @@ func (m *ObjectModel) GetDetails(ctx context.Context, id, orgID uuid.UUID) (*Obj
 		return nil, err
 	}
 
+	relationsBytes, ok := data.Relations.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected []byte for Relations, got %T", data.Relations)
+	}
+	err = json.Unmarshal(relationsBytes, &relations)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := m.q(ctx).ListComments(ctx, database.ListCommentsParams{
+		OrgID: orgID,
+		ObjID: uuid.NullUUID{UUID: id, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &ObjectDetail{
 		ID:          data.ID,
 		Name:        data.Name,
+		Photo:       data.Photo,
+		PhotoAttachmentID: ctype.NullUUID{NullUUID: data.PhotoAttachmentID},
 		Description: data.Description,
 		IDString:    data.IDString,
 		CreatorID:   data.CreatorID,
@@ -279,30 +316,37 @@ func (m *ObjectModel) GetDetails(ctx context.Context, id, orgID uuid.UUID) (*Obj
 		StepsAndFunnels: stepsAndFunnels,
 		Facts:       facts,
 		Aliases: 	   data.Aliases,
+		Relations:   relations,
+		Comments:    NewComments(comments),
 	}, nil
 }
 
+// AddTag returns sql.ErrNoRows when the object or the tag is not of the org
 func (m *ObjectModel) AddTag(ctx context.Context, objectID, tagID, orgID uuid.UUID) error {
-	return m.DB.AddTagToObject(ctx, database.AddTagToObjectParams{
+	_, err := m.q(ctx).AddTagToObject(ctx, database.AddTagToObjectParams{
 		ObjID:  objectID,
 		TagID:  tagID,
 		OrgID:  orgID,
 	})
+	return err
 }
 
+// RemoveTag returns sql.ErrNoRows when the object is not of the org
 func (m *ObjectModel) RemoveTag(ctx context.Context, objectID, tagID, orgID uuid.UUID) error {
-	return m.DB.RemoveTagFromObject(ctx, database.RemoveTagFromObjectParams{
+	_, err := m.q(ctx).RemoveTagFromObject(ctx, database.RemoveTagFromObjectParams{
 		ObjID:  objectID,
 		TagID:  tagID,
 		OrgID:  orgID,
 	})
+	return err
 }
 
 func (m *ObjectModel) AddObjectTypeValue(ctx context.Context, objectID, typeID uuid.UUID, values json.RawMessage, orgID uuid.UUID) (*ObjectTypeValue, error) {
-	result, err := m.DB.AddObjectTypeValue(ctx, database.AddObjectTypeValueParams{
+	result, err := m.q(ctx).AddObjectTypeValue(ctx, database.AddObjectTypeValueParams{
 		ObjID:  objectID,
 		TypeID: typeID,
 		Column3: values,
+		OrgID:  orgID,
 	})
 	if err != nil {
 		return nil, err
@@ -320,15 +364,24 @@ func (m *ObjectModel) AddObjectTypeValue(ctx context.Context, objectID, typeID u
 	}, nil
 }
 
+// RemoveObjectTypeValue returns sql.ErrNoRows when the type value is not of
+// an object of the org
 func (m *ObjectModel) RemoveObjectTypeValue(ctx context.Context, typeValueID, orgID uuid.UUID) error {
-	return m.DB.RemoveObjectTypeValue(ctx, database.RemoveObjectTypeValueParams{
+	rows, err := m.q(ctx).RemoveObjectTypeValue(ctx, database.RemoveObjectTypeValueParams{
 		ID:    typeValueID,
 		OrgID: orgID,
 	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 func (m *ObjectModel) UpdateObjectTypeValue(ctx context.Context, typeValueID, orgID uuid.UUID, values json.RawMessage) (*ObjectTypeValue, error) {
-	result, err := m.DB.UpdateObjectTypeValue(ctx, database.UpdateObjectTypeValueParams{
+	result, err := m.q(ctx).UpdateObjectTypeValue(ctx, database.UpdateObjectTypeValueParams{
 		ID:         typeValueID,
 		OrgID:      orgID,
 		Column3: values,
@@ -359,11 +412,12 @@ type ObjStep struct {
 	DeletedAt ctype.NullTime
 }
 
-func (m *ObjectModel) CreateObjStep(ctx context.Context, objID, stepID, creatorID uuid.UUID) (*ObjStep, error) {
-	row, err := m.DB.CreateObjStep(ctx, database.CreateObjStepParams{
+func (m *ObjectModel) CreateObjStep(ctx context.Context, objID, stepID, creatorID, orgID uuid.UUID) (*ObjStep, error) {
+	row, err := m.q(ctx).CreateObjStep(ctx, database.CreateObjStepParams{
 		ObjID:     objID,
 		StepID:    stepID,
 		CreatorID: creatorID,
+		OrgID:     orgID,
 	})
 	if err != nil {
 		return nil, err
@@ -381,12 +435,20 @@ func (m *ObjectModel) CreateObjStep(ctx context.Context, objID, stepID, creatorI
 	}, nil
 }
 
-func (m *ObjectModel) SoftDeleteObjStep(ctx context.Context, id uuid.UUID) error {
-	return m.DB.SoftDeleteObjStep(ctx, id)
+func (m *ObjectModel) SoftDeleteObjStep(ctx context.Context, id, orgID uuid.UUID) error {
+	rows, err := m.q(ctx).SoftDeleteObjStep(ctx, database.SoftDeleteObjStepParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+	return noRowsIfZero(rows, err)
 }
 
-func (m *ObjectModel) HardDeleteObjStep(ctx context.Context, id uuid.UUID) error {
-	return m.DB.HardDeleteObjStep(ctx, id)
+func (m *ObjectModel) HardDeleteObjStep(ctx context.Context, id, orgID uuid.UUID) error {
+	rows, err := m.q(ctx).HardDeleteObjStep(ctx, database.HardDeleteObjStepParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+	return noRowsIfZero(rows, err)
 }
 
 type ObjStepResponse struct {
@@ -399,12 +461,15 @@ type ObjStepResponse struct {
 	FunnelName string `json:"funnelName"`
 }
 
-func (m *ObjectModel) GetObjStep(ctx context.Context, id uuid.UUID) (*ObjStepResponse, error) {
-	row, err := m.DB.GetObjStep(ctx, id)
+func (m *ObjectModel) GetObjStep(ctx context.Context, id, orgID uuid.UUID) (*ObjStepResponse, error) {
+	row, err := m.q(ctx).GetObjStep(ctx, database.GetObjStepParams{
+		ID:    id,
+		OrgID: orgID,
+	})
 	if err != nil {
 		return nil, err
 	}
-	stepDetail, err := m.DB.GetStep(ctx, row.ID)
+	stepDetail, err := m.q(ctx).GetStep(ctx, row.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -420,9 +485,23 @@ func (m *ObjectModel) GetObjStep(ctx context.Context, id uuid.UUID) (*ObjStepRes
 	}, nil
 }
 
-func (m *ObjectModel) UpdateObjStepSubStatus(ctx context.Context, id uuid.UUID, subStatus int32) error {
-	return m.DB.UpdateObjStepSubStatus(ctx, database.UpdateObjStepSubStatusParams{
+func (m *ObjectModel) UpdateObjStepSubStatus(ctx context.Context, id, orgID uuid.UUID, subStatus int32) error {
+	rows, err := m.q(ctx).UpdateObjStepSubStatus(ctx, database.UpdateObjStepSubStatusParams{
 		ID:         id,
 		SubStatus:  subStatus,
+		OrgID:      orgID,
 	})
+	return noRowsIfZero(rows, err)
+}
+
+// noRowsIfZero turns an update or delete that matched nothing, usually a row of
+// another org, into sql.ErrNoRows
+func noRowsIfZero(rows int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
@@ -0,0 +1,350 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// ObjectHistoryEntry is one change of an object or of one of its type values
+type ObjectHistoryEntry struct {
+	ID             uuid.UUID      `json:"id"`
+	EntityType     string         `json:"entityType"`
+	EntityID       uuid.UUID      `json:"entityId"`
+	ObjectTypeID   ctype.NullUUID `json:"objectTypeId"`
+	Action         string         `json:"action"`
+	ActorID        ctype.NullUUID `json:"actorId"`
+	ActorUsername  string         `json:"actorUsername"`
+	ImpersonatorID ctype.NullUUID `json:"impersonatorId"`
+	ChangedAt      time.Time      `json:"changedAt"`
+	Changes        []FieldChange  `json:"changes"`
+}
+
+// FieldChange is the value of a field before and after a change. Keys of type
+// values are fields of their own, named type_values.<key>.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// ObjectAt is an object as it was at AsOf
+type ObjectAt struct {
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	Photo       string            `json:"photo"`
+	Description string            `json:"description"`
+	IDString    string            `json:"idString"`
+	CreatorID   uuid.UUID         `json:"creatorId"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Aliases     []string          `json:"aliases"`
+	TypeValues  []ObjectTypeValue `json:"typeValues"`
+	AsOf        time.Time         `json:"asOf"`
+}
+
+// historyRow is a row in the shape audit events record it, column by column
+type historyRow map[string]json.RawMessage
+
+var jsonNull = json.RawMessage("null")
+
+// replayHistory reverts the changes of the object newer than at, newest first,
+// starting from its current state. Older changes are not read. The object is
+// nil when it did not exist at the time, so are type values.
+func (m *ObjectModel) replayHistory(ctx context.Context, id, orgID uuid.UUID, at time.Time) (historyRow, map[uuid.UUID]historyRow, error) {
+	events, err := m.q(ctx).ListObjectHistoryEvents(ctx, database.ListObjectHistoryEventsParams{
+		OrgID: orgID,
+		Since: at,
+		ObjID: id,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var obj historyRow
+	data, err := m.q(ctx).GetObjectHistoryState(ctx, database.GetObjectHistoryStateParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+	if err == nil {
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, nil, err
+		}
+	} else if err != sql.ErrNoRows || len(events) == 0 {
+		return nil, nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, e := range events {
+		if e.EntityType == "obj_type_value" {
+			ids = append(ids, e.EntityID)
+		}
+	}
+	states, err := m.q(ctx).ListObjectTypeValueHistoryStates(ctx, database.ListObjectTypeValueHistoryStatesParams{
+		ObjID: id,
+		Ids:   ids,
+		OrgID: orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	typeValues := make(map[uuid.UUID]historyRow, len(states))
+	for _, data := range states {
+		var row historyRow
+		if err := json.Unmarshal(data, &row); err != nil {
+			return nil, nil, err
+		}
+		typeValues[row.uuid("id")] = row
+	}
+
+	for _, e := range events {
+		if e.EntityType == "obj" {
+			if obj, err = revert(obj, e.Before, e.After); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if typeValues[e.EntityID], err = revert(typeValues[e.EntityID], e.Before, e.After); err != nil {
+			return nil, nil, err
+		}
+	}
+	return obj, typeValues, nil
+}
+
+// revert undoes one audit event on row. Updates record only the changed
+// columns, creates and hard deletes the whole row.
+func revert(row historyRow, before, after pqtype.NullRawMessage) (historyRow, error) {
+	if !before.Valid {
+		return nil, nil
+	}
+	var changed historyRow
+	if err := json.Unmarshal(before.RawMessage, &changed); err != nil {
+		return nil, err
+	}
+	if !after.Valid || row == nil {
+		return changed, nil
+	}
+	reverted := make(historyRow, len(row))
+	for k, v := range row {
+		reverted[k] = v
+	}
+	for k, v := range changed {
+		reverted[k] = v
+	}
+	return reverted, nil
+}
+
+func (r historyRow) uuid(column string) uuid.UUID {
+	var id uuid.UUID
+	if v, ok := r[column]; ok {
+		json.Unmarshal(v, &id)
+	}
+	return id
+}
+
+func (r historyRow) deleted() bool {
+	v, ok := r["deleted_at"]
+	return ok && !bytes.Equal(v, jsonNull)
+}
+
+// History returns a page of the field level changes of the object and of its
+// type values, newest first. It starts with the audit log, older changes are
+// not known.
+func (m *ObjectModel) History(ctx context.Context, id, orgID uuid.UUID, limit, offset int) ([]ObjectHistoryEntry, error) {
+	events, err := m.q(ctx).ListObjectHistory(ctx, database.ListObjectHistoryParams{
+		OrgID:  orgID,
+		ObjID:  id,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 && offset == 0 {
+		// An object without changes either exists from before the audit log
+		// or not at all
+		if _, err := m.q(ctx).GetObjectHistoryState(ctx, database.GetObjectHistoryStateParams{
+			ID:    id,
+			OrgID: orgID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	entries := make([]ObjectHistoryEntry, 0, len(events))
+	for _, e := range events {
+		changes, err := fieldChanges(e.Before, e.After)
+		if err != nil {
+			return nil, err
+		}
+		entry := ObjectHistoryEntry{
+			ID:             e.ID,
+			EntityType:     e.EntityType,
+			EntityID:       e.EntityID,
+			Action:         e.Action,
+			ActorID:        ctype.NullUUID{NullUUID: e.ActorID},
+			ActorUsername:  e.ActorUsername,
+			ImpersonatorID: ctype.NullUUID{NullUUID: e.ImpersonatorID},
+			ChangedAt:      e.CreatedAt,
+			Changes:        changes,
+		}
+		if e.EntityType == "obj_type_value" {
+			entry.ObjectTypeID = ctype.NullUUID{NullUUID: uuid.NullUUID{UUID: e.ObjectTypeID, Valid: true}}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// fieldChanges lists the fields an audit event changed, sorted by name
+func fieldChanges(before, after pqtype.NullRawMessage) ([]FieldChange, error) {
+	var b, a historyRow
+	if before.Valid {
+		if err := json.Unmarshal(before.RawMessage, &b); err != nil {
+			return nil, err
+		}
+	}
+	if after.Valid {
+		if err := json.Unmarshal(after.RawMessage, &a); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := diffRows("", b, a)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func diffRows(prefix string, before, after historyRow) ([]FieldChange, error) {
+	changes := []FieldChange{}
+	seen := make(map[string]bool, len(before)+len(after))
+	for _, row := range []historyRow{before, after} {
+		for k := range row {
+			if seen[k] || (prefix == "" && k == "id") {
+				continue
+			}
+			seen[k] = true
+			b, a := valueOrNull(before, k), valueOrNull(after, k)
+			if bytes.Equal(b, a) {
+				continue
+			}
+			if prefix == "" && k == "type_values" {
+				var bv, av historyRow
+				json.Unmarshal(b, &bv)
+				json.Unmarshal(a, &av)
+				nested, err := diffRows("type_values.", bv, av)
+				if err != nil {
+					return nil, err
+				}
+				changes = append(changes, nested...)
+				continue
+			}
+			changes = append(changes, FieldChange{Field: prefix + k, Before: b, After: a})
+		}
+	}
+	return changes, nil
+}
+
+func valueOrNull(row historyRow, column string) json.RawMessage {
+	if v, ok := row[column]; ok {
+		return v
+	}
+	return jsonNull
+}
+
+// GetAt reconstructs the object and its type values as they were at the time.
+// It returns sql.ErrNoRows when the object did not exist or was deleted then.
+func (m *ObjectModel) GetAt(ctx context.Context, id, orgID uuid.UUID, at time.Time) (*ObjectAt, error) {
+	obj, typeValues, err := m.replayHistory(ctx, id, orgID, at)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.deleted() {
+		return nil, sql.ErrNoRows
+	}
+	var fields struct {
+		Name        string    `json:"name"`
+		Photo       string    `json:"photo"`
+		Description string    `json:"description"`
+		IDString    string    `json:"id_string"`
+		CreatorID   uuid.UUID `json:"creator_id"`
+		CreatedAt   time.Time `json:"created_at"`
+		Aliases     []string  `json:"aliases"`
+	}
+	if err := obj.decode(&fields); err != nil {
+		return nil, err
+	}
+	result := &ObjectAt{
+		ID:          id,
+		Name:        fields.Name,
+		Photo:       fields.Photo,
+		Description: fields.Description,
+		IDString:    fields.IDString,
+		CreatorID:   fields.CreatorID,
+		CreatedAt:   fields.CreatedAt,
+		Aliases:     fields.Aliases,
+		TypeValues:  []ObjectTypeValue{},
+		AsOf:        at,
+	}
+	for typeValueID, row := range typeValues {
+		if row == nil || row.deleted() || row.uuid("obj_id") != id {
+			continue
+		}
+		typeValue, err := row.typeValue(typeValueID)
+		if err != nil {
+			return nil, err
+		}
+		result.TypeValues = append(result.TypeValues, *typeValue)
+	}
+	sort.Slice(result.TypeValues, func(i, j int) bool {
+		return result.TypeValues[i].ObjectTypeID.String() < result.TypeValues[j].ObjectTypeID.String()
+	})
+	return result, nil
+}
+
+func (r historyRow) decode(v interface{}) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (r historyRow) typeValue(id uuid.UUID) (*ObjectTypeValue, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(valueOrNull(r, "type_values"), &values); err != nil {
+		return nil, err
+	}
+	return &ObjectTypeValue{
+		ID:           id,
+		ObjectTypeID: r.uuid("type_id"),
+		TypeValues:   values,
+	}, nil
+}
+
+// RestoreObjectTypeValue sets the values of a type value of the object back to
+// what they were at the time. A type value removed since is added again. It
+// returns sql.ErrNoRows when the object had no such type value then.
+func (m *ObjectModel) RestoreObjectTypeValue(ctx context.Context, objectID, typeValueID, orgID uuid.UUID, at time.Time) (*ObjectTypeValue, error) {
+	_, typeValues, err := m.replayHistory(ctx, objectID, orgID, at)
+	if err != nil {
+		return nil, err
+	}
+	row := typeValues[typeValueID]
+	if row == nil || row.deleted() || row.uuid("obj_id") != objectID {
+		return nil, sql.ErrNoRows
+	}
+	values := valueOrNull(row, "type_values")
+	restored, err := m.UpdateObjectTypeValue(ctx, typeValueID, orgID, values)
+	if err == sql.ErrNoRows {
+		return m.AddObjectTypeValue(ctx, objectID, row.uuid("type_id"), values, orgID)
+	}
+	return restored, err
+}
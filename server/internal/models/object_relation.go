@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+)
+
+// ObjectRelation is a relationship as seen from one of its objects. Direction
+// is outgoing when the object is the first of the pair, and TypeName is the
+// inverse name of a directional type when it is the second.
+type ObjectRelation struct {
+	ID          uuid.UUID       `json:"id"`
+	TypeID      uuid.UUID       `json:"typeId"`
+	TypeName    string          `json:"typeName"`
+	Directional bool            `json:"directional"`
+	Direction   string          `json:"direction"`
+	ObjectID    uuid.UUID       `json:"objectId"`
+	ObjectName  string          `json:"objectName"`
+	Attributes  json.RawMessage `json:"attributes"`
+	StartsAt    ctype.NullTime  `json:"startsAt"`
+	EndsAt      ctype.NullTime  `json:"endsAt"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// RelationValues are the parts of a relationship that can change
+type RelationValues struct {
+	Attributes json.RawMessage
+	StartsAt   sql.NullTime
+	EndsAt     sql.NullTime
+}
+
+func (v RelationValues) attributes() json.RawMessage {
+	if len(v.Attributes) == 0 || string(v.Attributes) == "null" {
+		return json.RawMessage("{}")
+	}
+	return v.Attributes
+}
+
+// AddRelation relates fromID to toID. It returns sql.ErrNoRows when an
+// object or the type is not found in the org.
+func (m *ObjectModel) AddRelation(ctx context.Context, fromID, toID, typeID, orgID, creatorID uuid.UUID, values RelationValues) (*database.ObjRelation, error) {
+	relation, err := m.q(ctx).CreateObjRelation(ctx, database.CreateObjRelationParams{
+		OrgID:      orgID,
+		Attributes: values.attributes(),
+		StartsAt:   values.StartsAt,
+		EndsAt:     values.EndsAt,
+		CreatorID:  creatorID,
+		TypeID:     typeID,
+		FromObjID:  fromID,
+		ToObjID:    toID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// UpdateRelation sets the attributes and dates of a relationship of the
+// object
+func (m *ObjectModel) UpdateRelation(ctx context.Context, objectID, relationID, orgID uuid.UUID, values RelationValues) (*database.ObjRelation, error) {
+	relation, err := m.q(ctx).UpdateObjRelation(ctx, database.UpdateObjRelationParams{
+		Attributes: values.attributes(),
+		StartsAt:   values.StartsAt,
+		EndsAt:     values.EndsAt,
+		ID:         relationID,
+		ObjID:      objectID,
+		OrgID:      orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// RemoveRelation deletes a relationship of the object, from both its objects
+func (m *ObjectModel) RemoveRelation(ctx context.Context, objectID, relationID, orgID uuid.UUID) error {
+	removed, err := m.q(ctx).DeleteObjRelation(ctx, database.DeleteObjRelationParams{
+		ID:    relationID,
+		ObjID: objectID,
+		OrgID: orgID,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/blobstore"
+	"github.com/google/uuid"
+)
+
+// AttachmentURLExpiry is how long a download URL works unless asked otherwise
+const AttachmentURLExpiry = 15 * time.Minute
+
+// AttachmentLinkKinds are the kinds of records an attachment can be linked to
+var AttachmentLinkKinds = []string{"object", "fact", "task"}
+
+func IsValidAttachmentLinkKind(kind string) bool {
+	for _, k := range AttachmentLinkKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrAttachmentTooLarge = errors.New("file is larger than the upload limit")
+	ErrAttachmentType     = errors.New("file type is not allowed")
+	ErrAttachmentChecksum = errors.New("file does not match the checksum")
+	ErrAttachmentNotImage = errors.New("attachment is not an image")
+)
+
+// AttachmentLink is an object, fact or task an attachment is linked to
+type AttachmentLink struct {
+	Kind  string    `json:"kind"`
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title,omitempty"`
+}
+
+// Upload is a file to store as an attachment. Checksum is the hex SHA-256 the
+// client computed, checked when set.
+type Upload struct {
+	Filename string
+	Body     io.ReadSeeker
+	Checksum string
+}
+
+// extensionTypes tells apart the files that sniff as zip archives or plain
+// text, the mime package only knows them from the system tables
+var extensionTypes = map[string]string{
+	".csv":  "text/csv",
+	".txt":  "text/plain",
+	".md":   "text/plain",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".doc":  "application/msword",
+	".xls":  "application/vnd.ms-excel",
+	".ppt":  "application/vnd.ms-powerpoint",
+}
+
+// detectContentType sniffs the content type from the first bytes of a file.
+// Office documents sniff as zip archives, old ones and CSV as generic binary
+// or text, for those the extension of the filename decides.
+func detectContentType(head []byte, filename string) string {
+	sniffed := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(sniffed)
+	switch mediaType {
+	case "application/zip", "application/octet-stream", "text/plain":
+		ext := strings.ToLower(path.Ext(filename))
+		if byExt, ok := extensionTypes[ext]; ok {
+			return byExt
+		}
+	}
+	return sniffed
+}
+
+// cleanFilename keeps the base name of what the browser sent, some send the
+// full path
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.ToValidUTF8(filename, "")
+	if len(filename) > 255 {
+		// Cutting may split a character, drop what is left of it
+		filename = strings.ToValidUTF8(filename[:255], "")
+	}
+	if filename == "" || filename == "." || filename == "/" {
+		return "file"
+	}
+	return filename
+}
+
+// AttachmentService stores uploaded files in the blob store and links them to
+// objects, facts and tasks
+type AttachmentService struct {
+	db     *database.Queries
+	store  blobstore.BlobStore
+	limits blobstore.Limits
+}
+
+func NewAttachmentService(db *database.Queries, store blobstore.BlobStore, limits blobstore.Limits) *AttachmentService {
+	return &AttachmentService{db: db, store: store, limits: limits}
+}
+
+func (s *AttachmentService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+// Limits returns the size and type limits of uploads
+func (s *AttachmentService) Limits() blobstore.Limits {
+	return s.limits
+}
+
+// Upload checks the file against the limits and its checksum, stores it and
+// links it to the records of links. It returns sql.ErrNoRows when a record
+// of links is not found.
+func (s *AttachmentService) Upload(ctx context.Context, orgID, creatorID uuid.UUID, upload Upload, links []AttachmentLink) (database.Attachment, error) {
+	// Read the file once for its size, type and checksum before storing it
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return database.Attachment{}, err
+	}
+	head = head[:n]
+	hash := sha256.New()
+	hash.Write(head)
+	rest, err := io.Copy(hash, io.LimitReader(upload.Body, s.limits.MaxSize-int64(n)+1))
+	if err != nil {
+		return database.Attachment{}, err
+	}
+	size := int64(n) + rest
+	if size > s.limits.MaxSize {
+		return database.Attachment{}, ErrAttachmentTooLarge
+	}
+	filename := cleanFilename(upload.Filename)
+	contentType := detectContentType(head, filename)
+	if !s.limits.Allows(contentType) {
+		return database.Attachment{}, ErrAttachmentType
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if upload.Checksum != "" && !strings.EqualFold(upload.Checksum, checksum) {
+		return database.Attachment{}, ErrAttachmentChecksum
+	}
+	if _, err := upload.Body.Seek(0, io.SeekStart); err != nil {
+		return database.Attachment{}, err
+	}
+
+	id := uuid.New()
+	key := orgID.String() + "/" + id.String()
+	info := blobstore.Info{Size: size, ContentType: contentType, SHA256: checksum}
+	if err := s.store.Put(ctx, key, upload.Body, info); err != nil {
+		return database.Attachment{}, err
+	}
+	attachment, err := s.q(ctx).CreateAttachment(ctx, database.CreateAttachmentParams{
+		ID:          id,
+		OrgID:       orgID,
+		StorageKey:  key,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		Sha256:      checksum,
+		CreatorID:   creatorID,
+	})
+	for _, link := range links {
+		if err != nil {
+			break
+		}
+		err = s.Link(ctx, orgID, id, link)
+	}
+	if err != nil {
+		// The request transaction rolls back, the content goes with it
+		s.store.Delete(ctx, key)
+		return database.Attachment{}, err
+	}
+	// So does it when the request fails later on
+	middleware.AfterRollback(ctx, func() { s.deleteContent(ctx, key) })
+	return attachment, nil
+}
+
+// Link links the attachment to a record. It returns sql.ErrNoRows when either
+// is not found.
+func (s *AttachmentService) Link(ctx context.Context, orgID, id uuid.UUID, link AttachmentLink) error {
+	var linked int64
+	var err error
+	switch link.Kind {
+	case "object":
+		linked, err = s.q(ctx).LinkObjectAttachment(ctx, database.LinkObjectAttachmentParams{ObjID: link.ID, OrgID: orgID, AttachmentID: id})
+	case "fact":
+		linked, err = s.q(ctx).LinkFactAttachment(ctx, database.LinkFactAttachmentParams{FactID: link.ID, OrgID: orgID, AttachmentID: id})
+	case "task":
+		linked, err = s.q(ctx).LinkTaskAttachment(ctx, database.LinkTaskAttachmentParams{TaskID: link.ID, OrgID: orgID, AttachmentID: id})
+	default:
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+	if linked == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Unlink removes the link of the attachment to a record, the attachment is
+// kept. It returns sql.ErrNoRows when there is no such link.
+func (s *AttachmentService) Unlink(ctx context.Context, orgID, id uuid.UUID, link AttachmentLink) error {
+	var unlinked int64
+	var err error
+	switch link.Kind {
+	case "object":
+		unlinked, err = s.q(ctx).UnlinkObjectAttachment(ctx, database.UnlinkObjectAttachmentParams{ObjID: link.ID, AttachmentID: id, OrgID: orgID})
+	case "fact":
+		unlinked, err = s.q(ctx).UnlinkFactAttachment(ctx, database.UnlinkFactAttachmentParams{FactID: link.ID, AttachmentID: id, OrgID: orgID})
+	case "task":
+		unlinked, err = s.q(ctx).UnlinkTaskAttachment(ctx, database.UnlinkTaskAttachmentParams{TaskID: link.ID, AttachmentID: id, OrgID: orgID})
+	default:
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+	if unlinked == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DownloadURL returns a signed URL of the content of the attachment that
+// works until the returned time, expires is capped to what stores allow
+func (s *AttachmentService) DownloadURL(ctx context.Context, orgID, id uuid.UUID, expires time.Duration) (string, time.Time, error) {
+	attachment, err := s.q(ctx).GetAttachment(ctx, database.GetAttachmentParams{ID: id, OrgID: orgID})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if expires <= 0 {
+		expires = AttachmentURLExpiry
+	}
+	expires = min(expires, blobstore.MaxURLExpiry)
+	url, err := s.store.URL(ctx, attachment.StorageKey, blobstore.Download{
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+	}, expires)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, time.Now().Add(expires), nil
+}
+
+// SetObjectPhoto makes an image attachment the photo of an object and links it
+// to the object, an invalid attachmentID clears the photo. It returns
+// sql.ErrNoRows when the object or the attachment is not found.
+func (s *AttachmentService) SetObjectPhoto(ctx context.Context, orgID, objectID uuid.UUID, attachmentID uuid.NullUUID) error {
+	if attachmentID.Valid {
+		attachment, err := s.q(ctx).GetAttachment(ctx, database.GetAttachmentParams{ID: attachmentID.UUID, OrgID: orgID})
+		if err != nil {
+			return err
+		}
+		mediaType, _, _ := mime.ParseMediaType(attachment.ContentType)
+		if !strings.HasPrefix(mediaType, "image/") {
+			return ErrAttachmentNotImage
+		}
+	}
+	updated, err := s.q(ctx).SetObjectPhoto(ctx, database.SetObjectPhotoParams{
+		PhotoAttachmentID: attachmentID,
+		ID:                objectID,
+		OrgID:             orgID,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+	if !attachmentID.Valid {
+		return nil
+	}
+	return s.Link(ctx, orgID, attachmentID.UUID, AttachmentLink{Kind: "object", ID: objectID})
+}
+
+// Delete deletes the attachment, its links and its content. It returns
+// sql.ErrNoRows when the attachment is not found. The content is deleted once
+// the request transaction committed, a rolled back delete keeps it.
+func (s *AttachmentService) Delete(ctx context.Context, orgID, id uuid.UUID) error {
+	key, err := s.q(ctx).DeleteAttachment(ctx, database.DeleteAttachmentParams{ID: id, OrgID: orgID})
+	if err != nil {
+		return err
+	}
+	middleware.AfterCommit(ctx, func() { s.deleteContent(ctx, key) })
+	return nil
+}
+
+// deleteContent deletes content whose row is gone. The request may be over by
+// then, so it does not wait on its context. Content that fails to delete is
+// only logged, the row no longer points to it.
+func (s *AttachmentService) deleteContent(ctx context.Context, key string) {
+	if err := s.store.Delete(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("Error deleting attachment content %s: %v", key, err)
+	}
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
@@ -43,7 +45,8 @@ type ActionConfig struct {
 
 // AutomationService handles the execution of automated actions
 type AutomationService struct {
-    db *database.Queries
+    db    *database.Queries
+    sqlDB *sql.DB
 }
 
 // ExecuteAction represents the result of an action execution
@@ -54,9 +57,10 @@ type ExecuteAction struct {
 }
 
 // NewAutomationService creates a new automation service
-func NewAutomationService(db *database.Queries) *AutomationService {
+func NewAutomationService(db *database.Queries, sqlDB *sql.DB) *AutomationService {
     return &AutomationService{
-        db: db,
+        db:    db,
+        sqlDB: sqlDB,
     }
 }
 
@@ -142,7 +146,13 @@ func (s *AutomationService) ExecuteAction(
         FunnelID: funnelId,
         CreatorID: action.CreatedBy,
     }
-    rows, err := s.db.AddTagAndStepToFilteredObjects(ctx, params)
+    // The objects are only visible in a transaction scoped to the org
+    var rows []database.AddTagAndStepToFilteredObjectsRow
+    err = middleware.InOrg(ctx, s.sqlDB, s.db, action.OrgID.String(), func(ctx context.Context) error {
+        var err error
+        rows, err = middleware.Queries(ctx, s.db).AddTagAndStepToFilteredObjects(ctx, params)
+        return err
+    })
     status := "completed"
     var noOfAffectedObjects int32 = 0
     var executionLog pqtype.NullRawMessage
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/mention"
+	"github.com/google/uuid"
+)
+
+const (
+	// CommentMaxLength is the longest comment in characters
+	CommentMaxLength = 10000
+	// ReactionMaxLength bounds a reaction, enough for an emoji sequence
+	ReactionMaxLength = 32
+)
+
+// CommentError is a comment the input does not allow
+type CommentError string
+
+func (e CommentError) Error() string {
+	return string(e)
+}
+
+// ErrCommentNotAuthor is returned when a creator changes a comment of another
+var ErrCommentNotAuthor = errors.New("only the author can change the comment")
+
+// CommentTarget is the object or the task a comment is about, one is set
+type CommentTarget struct {
+	ObjID  uuid.NullUUID
+	TaskID uuid.NullUUID
+}
+
+// url is the page of the target in the web app
+func (t CommentTarget) url() string {
+	if t.ObjID.Valid {
+		return "/objects/" + t.ObjID.UUID.String()
+	}
+	return "/tasks/" + t.TaskID.UUID.String()
+}
+
+// CommentService keeps the discussions about objects and tasks, and tells
+// creators when they are mentioned
+type CommentService struct {
+	db *database.Queries
+}
+
+func NewCommentService(db *database.Queries) *CommentService {
+	return &CommentService{db: db}
+}
+
+func (s *CommentService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+func validateCommentText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", CommentError("text is required")
+	}
+	if utf8.RuneCountInString(text) > CommentMaxLength {
+		return "", CommentError("text is longer than 10000 characters")
+	}
+	return text, nil
+}
+
+// List returns the comments about the target, oldest first
+func (s *CommentService) List(ctx context.Context, orgID uuid.UUID, target CommentTarget) ([]database.ListCommentsRow, error) {
+	return s.q(ctx).ListComments(ctx, database.ListCommentsParams{
+		OrgID:  orgID,
+		ObjID:  target.ObjID,
+		TaskID: target.TaskID,
+	})
+}
+
+// Get returns the comment of id as List shows it, none when it is deleted
+func (s *CommentService) Get(ctx context.Context, orgID, id uuid.UUID) ([]database.ListCommentsRow, error) {
+	return s.q(ctx).ListComments(ctx, database.ListCommentsParams{
+		OrgID: orgID,
+		ID:    uuid.NullUUID{UUID: id, Valid: true},
+	})
+}
+
+// Create adds a comment about the target, as a reply when parentID is set.
+// It returns sql.ErrNoRows when the target or the parent is not found.
+func (s *CommentService) Create(ctx context.Context, orgID, authorID uuid.UUID, target CommentTarget, parentID uuid.NullUUID, text string) (database.Comment, error) {
+	text, err := validateCommentText(text)
+	if err != nil {
+		return database.Comment{}, err
+	}
+	comment, err := s.q(ctx).CreateComment(ctx, database.CreateCommentParams{
+		OrgID:     orgID,
+		ObjID:     target.ObjID,
+		TaskID:    target.TaskID,
+		ParentID:  parentID,
+		Text:      text,
+		CreatorID: authorID,
+	})
+	if err != nil {
+		return database.Comment{}, err
+	}
+	return comment, s.syncMentions(ctx, orgID, comment)
+}
+
+// Update changes the text of a comment of the author. It returns
+// sql.ErrNoRows when the comment is not found.
+func (s *CommentService) Update(ctx context.Context, orgID, authorID, id uuid.UUID, text string) (database.Comment, error) {
+	text, err := validateCommentText(text)
+	if err != nil {
+		return database.Comment{}, err
+	}
+	comment, err := s.q(ctx).GetComment(ctx, database.GetCommentParams{ID: id, OrgID: orgID})
+	if err != nil {
+		return database.Comment{}, err
+	}
+	if comment.CreatorID != authorID {
+		return database.Comment{}, ErrCommentNotAuthor
+	}
+	comment, err = s.q(ctx).UpdateComment(ctx, database.UpdateCommentParams{ID: id, OrgID: orgID, Text: text})
+	if err != nil {
+		return database.Comment{}, err
+	}
+	return comment, s.syncMentions(ctx, orgID, comment)
+}
+
+// Delete deletes a comment of the creator, or of anyone when moderate is set.
+// It returns sql.ErrNoRows when the comment is not found.
+func (s *CommentService) Delete(ctx context.Context, orgID, creatorID, id uuid.UUID, moderate bool) error {
+	comment, err := s.q(ctx).GetComment(ctx, database.GetCommentParams{ID: id, OrgID: orgID})
+	if err != nil {
+		return err
+	}
+	if comment.CreatorID != creatorID && !moderate {
+		return ErrCommentNotAuthor
+	}
+	deleted, err := s.q(ctx).DeleteComment(ctx, database.DeleteCommentParams{ID: id, OrgID: orgID})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func validateReaction(emoji string) error {
+	if emoji == "" || len(emoji) > ReactionMaxLength || !utf8.ValidString(emoji) || strings.IndexFunc(emoji, unicode.IsSpace) >= 0 {
+		return CommentError("invalid reaction")
+	}
+	return nil
+}
+
+// React adds the reaction of the creator to a comment. It returns
+// sql.ErrNoRows when the comment is not found.
+func (s *CommentService) React(ctx context.Context, orgID, creatorID, id uuid.UUID, emoji string) error {
+	if err := validateReaction(emoji); err != nil {
+		return err
+	}
+	added, err := s.q(ctx).AddCommentReaction(ctx, database.AddCommentReactionParams{
+		CreatorID: creatorID,
+		Emoji:     emoji,
+		CommentID: id,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return err
+	}
+	if added == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Unreact removes a reaction of the creator. It returns sql.ErrNoRows when
+// the creator did not react so.
+func (s *CommentService) Unreact(ctx context.Context, orgID, creatorID, id uuid.UUID, emoji string) error {
+	removed, err := s.q(ctx).RemoveCommentReaction(ctx, database.RemoveCommentReactionParams{
+		CommentID: id,
+		OrgID:     orgID,
+		CreatorID: creatorID,
+		Emoji:     emoji,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// syncMentions records the creators the text of the comment mentions and
+// notifies those mentioned for the first time, except the author
+func (s *CommentService) syncMentions(ctx context.Context, orgID uuid.UUID, comment database.Comment) error {
+	creatorIDs := mention.IDs(mention.Parse(comment.Text), mention.KindCreator)
+	err := s.q(ctx).DeleteStaleCommentMentions(ctx, database.DeleteStaleCommentMentionsParams{
+		CommentID:  comment.ID,
+		CreatorIds: creatorIDs,
+	})
+	if err != nil {
+		return err
+	}
+	if len(creatorIDs) == 0 {
+		return nil
+	}
+	mentioned, err := s.q(ctx).AddCommentMentions(ctx, database.AddCommentMentionsParams{
+		CommentID:  comment.ID,
+		CreatorIds: creatorIDs,
+		OrgID:      orgID,
+	})
+	if err != nil {
+		return err
+	}
+	var recipients []uuid.UUID
+	for _, creatorID := range mentioned {
+		if creatorID != comment.CreatorID {
+			recipients = append(recipients, creatorID)
+		}
+	}
+	target := CommentTarget{ObjID: comment.ObjID, TaskID: comment.TaskID}
+	return notify(ctx, s.q(ctx), recipients, Notification{
+		Type: "comment_mention",
+		URL:  target.url(),
+		Details: map[string]interface{}{
+			"commentId": comment.ID,
+			"authorId":  comment.CreatorID,
+			"text":      excerpt(comment.Text),
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	// DuplicateMinScore is the score from which a pair is suggested. A shared
+	// email (0.9), phone (0.8), social handle (0.7) or alias (0.6) is enough
+	// on its own. A name weighs its similarity times 0.5, so even an exact
+	// match stays below and needs a second signal: with the weakest one, an
+	// alias, 1 - (1 - 0.5)(1 - 0.6) = 0.8.
+	DuplicateMinScore = 0.6
+	// DuplicateMinNameSimilarity is the trigram similarity from which names
+	// count as a signal
+	DuplicateMinNameSimilarity = 0.6
+)
+
+// DuplicateService finds objects that are likely the same person or company
+// and queues them as suggestions for a member to merge or dismiss
+type DuplicateService struct {
+	db    *database.Queries
+	sqlDB *sql.DB
+}
+
+func NewDuplicateService(db *database.Queries, sqlDB *sql.DB) *DuplicateService {
+	return &DuplicateService{db: db, sqlDB: sqlDB}
+}
+
+func (s *DuplicateService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+// Scan scores the objects of the org changed since the last scan against all
+// its objects, or every object when the org was never scanned. It returns the
+// number of suggestions added or updated.
+func (s *DuplicateService) Scan(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	var since sql.NullTime
+	last, err := s.q(ctx).GetDuplicateScan(ctx, orgID)
+	if err == nil {
+		since = sql.NullTime{Time: last.ScannedAt, Valid: true}
+	} else if err != sql.ErrNoRows {
+		return 0, err
+	}
+	return s.scan(ctx, orgID, since)
+}
+
+// ScanAll scans every org, see Scan. An org that fails to scan does not stop
+// the others, the errors are returned together.
+func (s *DuplicateService) ScanAll(ctx context.Context) (int64, error) {
+	scans, err := s.q(ctx).ListDuplicateScans(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	var errs []error
+	for _, scan := range scans {
+		var found int64
+		err := middleware.InOrg(ctx, s.sqlDB, s.db, scan.OrgID.String(), func(ctx context.Context) error {
+			var err error
+			found, err = s.scan(ctx, scan.OrgID, scan.ScannedAt)
+			return err
+		})
+		if err != nil {
+			log.Printf("Duplicate scan of org %s failed: %v", scan.OrgID, err)
+			errs = append(errs, fmt.Errorf("org %s: %w", scan.OrgID, err))
+			continue
+		}
+		total += found
+	}
+	return total, errors.Join(errs...)
+}
+
+func (s *DuplicateService) scan(ctx context.Context, orgID uuid.UUID, since sql.NullTime) (int64, error) {
+	// Changes made while scanning are picked up by the next scan
+	startedAt := time.Now()
+	if _, err := s.q(ctx).ClearStaleDuplicateSuggestions(ctx, orgID); err != nil {
+		return 0, err
+	}
+	found, err := s.q(ctx).RefreshDuplicateSuggestions(ctx, database.RefreshDuplicateSuggestionsParams{
+		Since:             since,
+		OrgID:             orgID,
+		MinNameSimilarity: DuplicateMinNameSimilarity,
+		MinScore:          DuplicateMinScore,
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = s.q(ctx).SetDuplicateScan(ctx, database.SetDuplicateScanParams{
+		OrgID:     orgID,
+		ScannedAt: startedAt,
+	})
+	return found, err
+}
+
+// ClearStale removes the pending suggestions of the org with an object that
+// was deleted or merged away
+func (s *DuplicateService) ClearStale(ctx context.Context, orgID uuid.UUID) error {
+	_, err := s.q(ctx).ClearStaleDuplicateSuggestions(ctx, orgID)
+	return err
+}
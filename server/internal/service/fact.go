@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/mention"
+	"github.com/google/uuid"
+)
+
+// Built-in kinds of fact, facts recorded without a kind are notes
+const (
+	FactKindCall    = "call"
+	FactKindMeeting = "meeting"
+	FactKindEmail   = "email"
+	FactKindNote    = "note"
+)
+
+// Structured fields a kind of fact can take
+const (
+	FactFieldDuration  = "duration"
+	FactFieldAttendees = "attendees"
+	FactFieldDirection = "direction"
+	FactFieldOutcome   = "outcome"
+)
+
+var FactFields = []string{FactFieldDuration, FactFieldAttendees, FactFieldDirection, FactFieldOutcome}
+
+// Directions of a call or an email
+const (
+	FactDirectionInbound  = "inbound"
+	FactDirectionOutbound = "outbound"
+)
+
+// FactKind is a kind of fact and the structured fields its facts take
+type FactKind struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Fields      []string `json:"fields"`
+	BuiltIn     bool     `json:"builtIn"`
+}
+
+// takes reports whether facts of the kind take the field
+func (k FactKind) takes(field string) bool {
+	for _, f := range k.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltInFactKinds are the kinds every org has
+var BuiltInFactKinds = []FactKind{
+	{Name: FactKindCall, Description: "A phone or video call", Fields: []string{FactFieldDuration, FactFieldAttendees, FactFieldDirection, FactFieldOutcome}, BuiltIn: true},
+	{Name: FactKindMeeting, Description: "A meeting in person or online", Fields: []string{FactFieldDuration, FactFieldAttendees, FactFieldOutcome}, BuiltIn: true},
+	{Name: FactKindEmail, Description: "An email sent or received", Fields: []string{FactFieldAttendees, FactFieldDirection}, BuiltIn: true},
+	{Name: FactKindNote, Description: "Anything else worth remembering", Fields: []string{}, BuiltIn: true},
+}
+
+var factKindName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// FactError is a fact or a kind the input does not allow
+type FactError string
+
+func (e FactError) Error() string {
+	return string(e)
+}
+
+// ErrFactKindInUse is returned when deleting a kind some facts are of
+var ErrFactKindInUse = errors.New("facts of this kind exist")
+
+// FactAttendees are the objects and the creators who took part
+type FactAttendees struct {
+	ObjectIDs  []uuid.UUID `json:"objectIds"`
+	CreatorIDs []uuid.UUID `json:"creatorIds"`
+}
+
+// FactDetails are the kind of a fact and its structured fields. Zero values
+// leave a field empty.
+type FactDetails struct {
+	Kind            string
+	DurationMinutes int32
+	Direction       string
+	Outcome         string
+	Attendees       FactAttendees
+}
+
+// Params returns the columns of the details
+func (d FactDetails) Params() (kind string, duration sql.NullInt32, direction sql.NullString, outcome string) {
+	return d.Kind,
+		sql.NullInt32{Int32: d.DurationMinutes, Valid: d.DurationMinutes > 0},
+		sql.NullString{String: d.Direction, Valid: d.Direction != ""},
+		d.Outcome
+}
+
+// FactService keeps the kinds of facts of an org and their structured
+// fields, and keeps the objects linked to a fact and the creators told about
+// it in step with the mentions written in its text
+type FactService struct {
+	db *database.Queries
+}
+
+func NewFactService(db *database.Queries) *FactService {
+	return &FactService{db: db}
+}
+
+func (s *FactService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+// Kinds returns the built-in kinds followed by those of the org
+func (s *FactService) Kinds(ctx context.Context, orgID uuid.UUID) ([]FactKind, error) {
+	custom, err := s.q(ctx).ListFactKinds(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	kinds := append([]FactKind{}, BuiltInFactKinds...)
+	for _, k := range custom {
+		kinds = append(kinds, newFactKind(k))
+	}
+	return kinds, nil
+}
+
+// Kind returns the kind of name, sql.ErrNoRows when the org has no such kind
+func (s *FactService) Kind(ctx context.Context, orgID uuid.UUID, name string) (FactKind, error) {
+	for _, k := range BuiltInFactKinds {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	k, err := s.q(ctx).GetFactKind(ctx, database.GetFactKindParams{OrgID: orgID, Name: name})
+	if err != nil {
+		return FactKind{}, err
+	}
+	return newFactKind(k), nil
+}
+
+func newFactKind(k database.FactKind) FactKind {
+	fields := k.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	return FactKind{Name: k.Name, Description: k.Description, Fields: fields}
+}
+
+func validateFactFields(fields []string) ([]string, error) {
+	seen := make(map[string]bool)
+	valid := []string{}
+	for _, f := range fields {
+		known := false
+		for _, k := range FactFields {
+			known = known || k == f
+		}
+		if !known {
+			return nil, FactError("unknown field " + f + ", fields are duration, attendees, direction and outcome")
+		}
+		if !seen[f] {
+			seen[f] = true
+			valid = append(valid, f)
+		}
+	}
+	return valid, nil
+}
+
+// CreateKind adds a kind to the org
+func (s *FactService) CreateKind(ctx context.Context, orgID, creatorID uuid.UUID, kind FactKind) (FactKind, error) {
+	if !factKindName.MatchString(kind.Name) {
+		return FactKind{}, FactError("name must be lowercase letters, digits and underscores, starting with a letter")
+	}
+	if _, err := s.Kind(ctx, orgID, kind.Name); err == nil {
+		return FactKind{}, FactError("kind " + kind.Name + " already exists")
+	} else if err != sql.ErrNoRows {
+		return FactKind{}, err
+	}
+	fields, err := validateFactFields(kind.Fields)
+	if err != nil {
+		return FactKind{}, err
+	}
+	created, err := s.q(ctx).CreateFactKind(ctx, database.CreateFactKindParams{
+		OrgID:       orgID,
+		Name:        kind.Name,
+		Description: kind.Description,
+		Fields:      fields,
+		CreatorID:   creatorID,
+	})
+	if err != nil {
+		return FactKind{}, err
+	}
+	return newFactKind(created), nil
+}
+
+// UpdateKind changes the description and the fields of a kind of the org.
+// Facts keep the values of fields the kind no longer takes.
+func (s *FactService) UpdateKind(ctx context.Context, orgID uuid.UUID, kind FactKind) (FactKind, error) {
+	fields, err := validateFactFields(kind.Fields)
+	if err != nil {
+		return FactKind{}, err
+	}
+	updated, err := s.q(ctx).UpdateFactKind(ctx, database.UpdateFactKindParams{
+		OrgID:       orgID,
+		Name:        kind.Name,
+		Description: kind.Description,
+		Fields:      fields,
+	})
+	if err != nil {
+		return FactKind{}, err
+	}
+	return newFactKind(updated), nil
+}
+
+// DeleteKind deletes a kind of the org no fact is of
+func (s *FactService) DeleteKind(ctx context.Context, orgID uuid.UUID, name string) error {
+	if _, err := s.q(ctx).GetFactKind(ctx, database.GetFactKindParams{OrgID: orgID, Name: name}); err != nil {
+		return err
+	}
+	deleted, err := s.q(ctx).DeleteFactKind(ctx, database.DeleteFactKindParams{OrgID: orgID, Name: name})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrFactKindInUse
+	}
+	return nil
+}
+
+// ValidateDetails checks the kind exists and takes the fields that are set
+func (s *FactService) ValidateDetails(ctx context.Context, orgID uuid.UUID, d FactDetails) error {
+	kind, err := s.Kind(ctx, orgID, d.Kind)
+	if err == sql.ErrNoRows {
+		return FactError("unknown kind " + d.Kind)
+	}
+	if err != nil {
+		return err
+	}
+	if d.DurationMinutes < 0 {
+		return FactError("duration must be a positive number of minutes")
+	}
+	if d.Direction != "" && d.Direction != FactDirectionInbound && d.Direction != FactDirectionOutbound {
+		return FactError("direction must be inbound or outbound")
+	}
+	set := map[string]bool{
+		FactFieldDuration:  d.DurationMinutes > 0,
+		FactFieldDirection: d.Direction != "",
+		FactFieldOutcome:   d.Outcome != "",
+		FactFieldAttendees: len(d.Attendees.ObjectIDs) > 0 || len(d.Attendees.CreatorIDs) > 0,
+	}
+	for _, field := range FactFields {
+		if set[field] && !kind.takes(field) {
+			return FactError("a " + kind.Name + " has no " + field)
+		}
+	}
+	return nil
+}
+
+// SetAttendees replaces the attendees of the fact. Attending objects are
+// linked to the fact too, so it shows on their timeline.
+func (s *FactService) SetAttendees(ctx context.Context, orgID, factID uuid.UUID, attendees FactAttendees) error {
+	if err := s.q(ctx).DeleteFactAttendees(ctx, factID); err != nil {
+		return err
+	}
+	if len(attendees.ObjectIDs) == 0 && len(attendees.CreatorIDs) == 0 {
+		return nil
+	}
+	objectIDs := difference(attendees.ObjectIDs, nil)
+	creatorIDs := difference(attendees.CreatorIDs, nil)
+	err := s.q(ctx).AddFactAttendees(ctx, database.AddFactAttendeesParams{
+		FactID:     factID,
+		ObjIds:     objectIDs,
+		CreatorIds: creatorIDs,
+	})
+	if err != nil {
+		return err
+	}
+	if len(objectIDs) == 0 {
+		return nil
+	}
+	return s.q(ctx).AddObjectsToFact(ctx, database.AddObjectsToFactParams{
+		Column1: objectIDs,
+		FactID:  factID,
+		OrgID:   orgID,
+	})
+}
+
+// Revert sets a fact back to a revision, which is recorded as a new one.
+// Objects and creators mentioned follow the text, and attendees are removed
+// when the kind of the revision takes none. It returns sql.ErrNoRows when the
+// fact or the revision is not found.
+func (s *FactService) Revert(ctx context.Context, orgID, editorID, factID uuid.UUID, revision int32) (database.Fact, error) {
+	previous, err := s.q(ctx).GetFactByID(ctx, database.GetFactByIDParams{ID: factID, OrgID: orgID})
+	if err != nil {
+		return database.Fact{}, err
+	}
+	rev, err := s.q(ctx).GetFactRevision(ctx, database.GetFactRevisionParams{
+		FactID:   factID,
+		OrgID:    orgID,
+		Revision: revision,
+	})
+	if err != nil {
+		return database.Fact{}, err
+	}
+	kind, err := s.Kind(ctx, orgID, rev.Kind)
+	if err == sql.ErrNoRows {
+		return database.Fact{}, FactError("kind " + rev.Kind + " of the revision no longer exists")
+	}
+	if err != nil {
+		return database.Fact{}, err
+	}
+	fact, err := s.q(ctx).RevertFact(ctx, database.RevertFactParams{ID: factID, OrgID: orgID, Revision: revision})
+	if err != nil {
+		return database.Fact{}, err
+	}
+	if err := s.SyncMentions(ctx, orgID, editorID, fact, previous.Text, nil); err != nil {
+		return database.Fact{}, err
+	}
+	if !kind.takes(FactFieldAttendees) {
+		if err := s.SetAttendees(ctx, orgID, factID, FactAttendees{}); err != nil {
+			return database.Fact{}, err
+		}
+	}
+	return fact, nil
+}
+
+// SyncMentions links the fact to the objects its text mentions and unlinks
+// those the previous text mentioned and the new one no longer does, unless
+// they were linked by hand or are among kept. Creators mentioned for the first
+// time are notified, except the author of the change. previous is empty for a
+// new fact.
+func (s *FactService) SyncMentions(ctx context.Context, orgID, authorID uuid.UUID, fact database.Fact, previous string, kept []uuid.UUID) error {
+	mentions := mention.Parse(fact.Text)
+	before := mention.Parse(previous)
+
+	objectIDs := mention.IDs(mentions, mention.KindObject)
+	if removed := difference(mention.IDs(before, mention.KindObject), objectIDs); len(removed) > 0 {
+		err := s.q(ctx).RemoveMentionedObjectsFromFact(ctx, database.RemoveMentionedObjectsFromFactParams{
+			FactID:  fact.ID,
+			ObjIds:  removed,
+			KeptIds: difference(kept, nil),
+			OrgID:   orgID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(objectIDs) > 0 {
+		err := s.q(ctx).AddMentionedObjectsToFact(ctx, database.AddMentionedObjectsToFactParams{
+			FactID: fact.ID,
+			ObjIds: objectIDs,
+			OrgID:  orgID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	added := difference(mention.IDs(mentions, mention.KindCreator), mention.IDs(before, mention.KindCreator))
+	if len(added) == 0 {
+		return nil
+	}
+	creatorIDs, err := s.q(ctx).ListMentionableCreators(ctx, database.ListMentionableCreatorsParams{
+		CreatorIds: added,
+		OrgID:      orgID,
+	})
+	if err != nil {
+		return err
+	}
+	var recipients []uuid.UUID
+	for _, creatorID := range creatorIDs {
+		if creatorID != authorID {
+			recipients = append(recipients, creatorID)
+		}
+	}
+	return notify(ctx, s.q(ctx), recipients, Notification{
+		Type: "fact_mention",
+		URL:  "/feed",
+		Details: map[string]interface{}{
+			"factId":   fact.ID,
+			"authorId": authorID,
+			"text":     excerpt(fact.Text),
+		},
+	})
+}
+
+// difference returns the ids of a that are not in b, each once
+func difference(a, b []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(b))
+	for _, id := range b {
+		seen[id] = true
+	}
+	ids := []uuid.UUID{}
+	for _, id := range a {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/google/uuid"
+)
+
+// TestSyncMentionsKeepsLinksByHand takes every mention out of a fact. Only
+// the object linked by the mention alone is unlinked.
+func TestSyncMentionsKeepsLinksByHand(t *testing.T) {
+	db := testdb.Open(t)
+	queries := database.New(db)
+	ctx := context.Background()
+
+	org, err := queries.CreateOrganization(ctx, database.CreateOrganizationParams{Name: "acme", Profile: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator, err := queries.CreateCreator(ctx, database.CreateCreatorParams{
+		Username: "admin", Pwd: "x", Profile: json.RawMessage(`{}`), Role: middleware.RoleAdmin, OrgID: org.ID, Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts := NewFactService(queries)
+
+	// byHand was linked when the fact was created, added is linked by the
+	// edit, kept is in the add list of the edit and mentioned only linked
+	// by its mention
+	names := []string{"byHand", "added", "kept", "mentioned"}
+	objects := map[string]uuid.UUID{}
+	var factID uuid.UUID
+	err = middleware.InOrg(ctx, db, queries, org.ID.String(), func(ctx context.Context) error {
+		q := middleware.Queries(ctx, queries)
+		text := ""
+		for _, name := range names {
+			obj, err := q.CreateObject(ctx, database.CreateObjectParams{
+				Name: name, Description: "", IDString: name, CreatorID: creator.ID,
+			})
+			if err != nil {
+				return err
+			}
+			objects[name] = obj.ID
+			text += fmt.Sprintf("@[%s](object:%s) ", name, obj.ID)
+		}
+		fact, err := q.CreateFact(ctx, database.CreateFactParams{Text: text, CreatorID: creator.ID, Kind: FactKindNote})
+		if err != nil {
+			return err
+		}
+		factID = fact.ID
+		err = q.AddObjectsToFact(ctx, database.AddObjectsToFactParams{
+			Column1: []uuid.UUID{objects["byHand"]},
+			FactID:  fact.ID,
+			OrgID:   org.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := facts.SyncMentions(ctx, org.ID, creator.ID, fact, "", nil); err != nil {
+			return err
+		}
+
+		// The edit drops every mention, as the fact handler does
+		err = q.AddObjectsToFact(ctx, database.AddObjectsToFactParams{
+			Column1: []uuid.UUID{objects["added"]},
+			FactID:  fact.ID,
+			OrgID:   org.ID,
+		})
+		if err != nil {
+			return err
+		}
+		edited := fact
+		edited.Text = "no mention left"
+		return facts.SyncMentions(ctx, org.ID, creator.ID, edited, fact.Text, []uuid.UUID{objects["added"], objects["kept"]})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := middleware.SetOrgID(ctx, tx, org.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		var linked bool
+		err := tx.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM obj_fact WHERE obj_id = $1 AND fact_id = $2)", objects[name], factID).Scan(&linked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := name != "mentioned"; linked != want {
+			t.Errorf("%s linked = %v, want %v", name, linked, want)
+		}
+	}
+}
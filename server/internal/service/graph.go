@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/pkg/ctype"
+	"github.com/google/uuid"
+)
+
+const (
+	// GraphDefaultDepth is how far a neighborhood reaches unless asked
+	GraphDefaultDepth = 2
+	// GraphMaxDepth and GraphMaxNodes are the limits of orgs that did not set
+	// their own in the org profile
+	GraphMaxDepth = 3
+	GraphMaxNodes = 500
+	// GraphDepthCap and GraphNodesCap bound the limits an org can set
+	GraphDepthCap = 6
+	GraphNodesCap = 5000
+)
+
+// GraphLimits bound how far and how wide the graph queries of an org go.
+// Admins set them in the org profile as graph_max_depth and graph_max_nodes.
+type GraphLimits struct {
+	MaxDepth int32 `json:"graph_max_depth"`
+	MaxNodes int32 `json:"graph_max_nodes"`
+}
+
+// GraphError is a graph query the limits or the input do not allow
+type GraphError string
+
+func (e GraphError) Error() string {
+	return string(e)
+}
+
+// GraphNode is an object of a graph. Depth is its distance in hops from the
+// object the graph started from.
+type GraphNode struct {
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name"`
+	IDString string          `json:"id_string"`
+	Photo    string          `json:"photo"`
+	Types    json.RawMessage `json:"types"`
+	Depth    int32           `json:"depth"`
+}
+
+// GraphEdge links two nodes. Kind is relation for a relationship, from source
+// to target when directional, or shared_facts for objects that appear in the
+// same facts, with the number of facts as weight.
+type GraphEdge struct {
+	ID           string          `json:"id"`
+	Kind         string          `json:"kind"`
+	Source       uuid.UUID       `json:"source"`
+	Target       uuid.UUID       `json:"target"`
+	TypeID       ctype.NullUUID  `json:"type_id"`
+	Label        string          `json:"label"`
+	InverseLabel string          `json:"inverse_label"`
+	Directional  bool            `json:"directional"`
+	Weight       int32           `json:"weight"`
+	Attributes   json.RawMessage `json:"attributes"`
+	StartsAt     ctype.NullTime  `json:"starts_at"`
+	EndsAt       ctype.NullTime  `json:"ends_at"`
+}
+
+// Graph is what the graph queries return. Truncated is set when the nodes
+// were cut at the node limit of the org.
+type Graph struct {
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+	Truncated bool        `json:"truncated"`
+}
+
+// GraphQuery narrows a graph query. Depth 0 means the default. Only
+// relationships of RelationTypeIDs are followed, and only objects with a
+// type value of ObjTypeIDs are returned, when given.
+type GraphQuery struct {
+	Depth           int32
+	RelationTypeIDs []uuid.UUID
+	ObjTypeIDs      []uuid.UUID
+}
+
+// GraphService answers questions about the network of objects formed by
+// their relationships and shared facts
+type GraphService struct {
+	db *database.Queries
+}
+
+func NewGraphService(db *database.Queries) *GraphService {
+	return &GraphService{db: db}
+}
+
+func (s *GraphService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+// Limits returns the graph limits of the org
+func (s *GraphService) Limits(ctx context.Context, orgID uuid.UUID) (GraphLimits, error) {
+	org, err := s.q(ctx).GetOrgDetails(ctx, orgID)
+	if err != nil {
+		return GraphLimits{}, err
+	}
+	var limits GraphLimits
+	// Profiles are free form, a malformed one keeps the defaults
+	_ = json.Unmarshal(org.Profile, &limits)
+	if limits.MaxDepth <= 0 {
+		limits.MaxDepth = GraphMaxDepth
+	}
+	if limits.MaxNodes <= 0 {
+		limits.MaxNodes = GraphMaxNodes
+	}
+	limits.MaxDepth = min(limits.MaxDepth, GraphDepthCap)
+	limits.MaxNodes = min(limits.MaxNodes, GraphNodesCap)
+	return limits, nil
+}
+
+func (s *GraphService) depth(ctx context.Context, orgID uuid.UUID, depth, fallback int32) (int32, GraphLimits, error) {
+	limits, err := s.Limits(ctx, orgID)
+	if err != nil {
+		return 0, limits, err
+	}
+	if depth == 0 {
+		depth = min(fallback, limits.MaxDepth)
+	}
+	if depth < 1 || depth > limits.MaxDepth {
+		return 0, limits, GraphError(fmt.Sprintf("depth must be between 1 and %d", limits.MaxDepth))
+	}
+	return depth, limits, nil
+}
+
+// Neighborhood returns the objects within query.Depth hops of the object and
+// the relationships between them. It returns sql.ErrNoRows when the object
+// is not found.
+func (s *GraphService) Neighborhood(ctx context.Context, orgID, objID uuid.UUID, query GraphQuery) (*Graph, error) {
+	depth, limits, err := s.depth(ctx, orgID, query.Depth, GraphDefaultDepth)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.q(ctx).GraphNeighborhood(ctx, database.GraphNeighborhoodParams{
+		OrgID:           orgID,
+		RelationTypeIDs: query.RelationTypeIDs,
+		ObjID:           objID,
+		MaxDepth:        depth,
+		ObjTypeIDs:      query.ObjTypeIDs,
+		// One more tells whether there were more
+		MaxNodes: limits.MaxNodes + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	graph := &Graph{Truncated: len(rows) > int(limits.MaxNodes)}
+	if graph.Truncated {
+		rows = rows[:limits.MaxNodes]
+	}
+	depths := make(map[uuid.UUID]int32, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		depths[row.ID] = row.Depth
+	}
+	if graph.Nodes, err = s.nodes(ctx, orgID, ids, depths); err != nil {
+		return nil, err
+	}
+	if graph.Edges, err = s.edges(ctx, orgID, ids, query.RelationTypeIDs); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// ShortestPath returns a shortest chain of relationships from one object to
+// the other, at most query.Depth long, with the nodes in path order. The
+// graph is empty when the objects are not connected that closely. It
+// returns sql.ErrNoRows when either object is not found.
+func (s *GraphService) ShortestPath(ctx context.Context, orgID, fromID, toID uuid.UUID, query GraphQuery) (*Graph, error) {
+	if fromID == toID {
+		return nil, GraphError("from and to must be different objects")
+	}
+	depth, _, err := s.depth(ctx, orgID, query.Depth, GraphDepthCap)
+	if err != nil {
+		return nil, err
+	}
+	ends, err := s.q(ctx).ListGraphNodes(ctx, database.ListGraphNodesParams{
+		Ids:   []uuid.UUID{fromID, toID},
+		OrgID: orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ends) < 2 {
+		return nil, sql.ErrNoRows
+	}
+	rows, err := s.q(ctx).GraphShortestPath(ctx, database.GraphShortestPathParams{
+		OrgID:           orgID,
+		RelationTypeIDs: query.RelationTypeIDs,
+		FromObjID:       fromID,
+		MaxDepth:        depth,
+		ToObjID:         toID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	graph := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	if len(rows) == 0 {
+		return graph, nil
+	}
+	depths := make(map[uuid.UUID]int32, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	onPath := make(map[uuid.UUID]bool, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ObjID
+		depths[row.ObjID] = row.Depth
+		if row.RelationID.Valid {
+			onPath[row.RelationID.UUID] = true
+		}
+	}
+	if graph.Nodes, err = s.nodes(ctx, orgID, ids, depths); err != nil {
+		return nil, err
+	}
+	edges, err := s.edges(ctx, orgID, ids, query.RelationTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Other relationships between objects of the path are not part of it
+	for _, edge := range edges {
+		if onPath[uuid.MustParse(edge.ID)] {
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	return graph, nil
+}
+
+// SharedFacts returns the objects that appear in at least minShared facts
+// with the object, linked to it by shared_facts edges. It returns
+// sql.ErrNoRows when the object is not found.
+func (s *GraphService) SharedFacts(ctx context.Context, orgID, objID uuid.UUID, minShared int32) (*Graph, error) {
+	if minShared < 1 {
+		return nil, GraphError("min_shared must be at least 1")
+	}
+	limits, err := s.Limits(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.q(ctx).GraphSharedFacts(ctx, database.GraphSharedFactsParams{
+		OrgID:     orgID,
+		ObjID:     objID,
+		MinShared: minShared,
+		// The object itself takes a node, one more tells whether there
+		// were more
+		MaxNodes: limits.MaxNodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	graph := &Graph{Edges: []GraphEdge{}, Truncated: len(rows) > int(limits.MaxNodes)-1}
+	if graph.Truncated {
+		rows = rows[:limits.MaxNodes-1]
+	}
+	depths := map[uuid.UUID]int32{objID: 0}
+	ids := []uuid.UUID{objID}
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+		depths[row.ID] = 1
+		attributes, err := json.Marshal(map[string]interface{}{"last_shared_at": row.LastSharedAt})
+		if err != nil {
+			return nil, err
+		}
+		graph.Edges = append(graph.Edges, GraphEdge{
+			ID:         fmt.Sprintf("facts:%s:%s", objID, row.ID),
+			Kind:       "shared_facts",
+			Source:     objID,
+			Target:     row.ID,
+			Label:      fmt.Sprintf("%d shared facts", row.SharedFacts),
+			Weight:     row.SharedFacts,
+			Attributes: attributes,
+		})
+	}
+	if graph.Nodes, err = s.nodes(ctx, orgID, ids, depths); err != nil {
+		return nil, err
+	}
+	if len(graph.Nodes) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return graph, nil
+}
+
+// nodes loads the objects of ids in that order
+func (s *GraphService) nodes(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID, depths map[uuid.UUID]int32) ([]GraphNode, error) {
+	rows, err := s.q(ctx).ListGraphNodes(ctx, database.ListGraphNodesParams{
+		Ids:   ids,
+		OrgID: orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]database.ListGraphNodesRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	nodes := make([]GraphNode, 0, len(rows))
+	for _, id := range ids {
+		row, ok := byID[id]
+		if !ok {
+			continue
+		}
+		nodes = append(nodes, GraphNode{
+			ID:       row.ID,
+			Name:     row.Name,
+			IDString: row.IDString,
+			Photo:    row.Photo,
+			Types:    row.Types,
+			Depth:    depths[id],
+		})
+	}
+	return nodes, nil
+}
+
+// edges loads the relationships between the objects of ids
+func (s *GraphService) edges(ctx context.Context, orgID uuid.UUID, ids, relationTypeIDs []uuid.UUID) ([]GraphEdge, error) {
+	rows, err := s.q(ctx).ListGraphEdges(ctx, database.ListGraphEdgesParams{
+		OrgID:           orgID,
+		Ids:             ids,
+		RelationTypeIDs: relationTypeIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]GraphEdge, len(rows))
+	for i, row := range rows {
+		edges[i] = GraphEdge{
+			ID:           row.ID.String(),
+			Kind:         "relation",
+			Source:       row.FromObjID,
+			Target:       row.ToObjID,
+			TypeID:       ctype.NullUUID{NullUUID: uuid.NullUUID{UUID: row.TypeID, Valid: true}},
+			Label:        row.TypeName,
+			InverseLabel: row.InverseName,
+			Directional:  row.Directional,
+			Weight:       1,
+			Attributes:   row.Attributes,
+			StartsAt:     ctype.NullTime{NullTime: row.StartsAt},
+			EndsAt:       ctype.NullTime{NullTime: row.EndsAt},
+		}
+	}
+	return edges, nil
+}
@@ -3,9 +3,11 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/google/uuid"
 )
@@ -20,6 +22,12 @@ type DailyMetrics struct {
 	Date                  time.Time `json:"date"`
 	FactsCreated         int64     `json:"factsCreated"`
 	FactObjectsInvolved  int64     `json:"factObjectsInvolved"`
+	// Calls, meetings and emails count on the day they happened, including
+	// those the creator attended, FactsByKind counts every kind so
+	MeetingsHeld         int64     `json:"meetingsHeld"`
+	CallsMade            int64     `json:"callsMade"`
+	EmailsLogged         int64     `json:"emailsLogged"`
+	FactsByKind          map[string]int64 `json:"factsByKind"`
 	TasksTotal          int64     `json:"tasksTotal"`
 	TasksCompleted      int64     `json:"tasksCompleted"`
 	TaskObjectsInvolved int64     `json:"taskObjectsInvolved"`
@@ -43,6 +51,7 @@ type MetricsSummary struct {
 	TotalActivityScore     float64 `json:"totalActivityScore"`
 	AverageActivityScore   float64 `json:"averageActivityScore"`
 	TotalTasksCompleted    int64   `json:"totalTasksCompleted"`
+	TotalMeetingsHeld      int64   `json:"totalMeetingsHeld"`
 	TotalObjectsProcessed  int64   `json:"totalObjectsProcessed"`
 	MostActiveDate         string  `json:"mostActiveDate"`
 }
@@ -65,10 +74,14 @@ func NewMetricsService(db *database.Queries) *MetricsService {
 	}
 }
 
+func (s *MetricsService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
 // GetCreatorMetrics retrieves activity metrics for a specific creator
 func (s *MetricsService) GetCreatorMetrics(ctx context.Context, requesterOrgID, targetCreatorID uuid.UUID) (*MetricsResponse, error) {
 	// First, verify that target creator belongs to the same organization
-	creator, err := s.db.GetCreatorByID(ctx, targetCreatorID)
+	creator, err := s.q(ctx).GetCreatorByID(ctx, targetCreatorID)
 	if err != nil {
 		return nil, ErrNotFound
 	}
@@ -78,7 +91,7 @@ func (s *MetricsService) GetCreatorMetrics(ctx context.Context, requesterOrgID,
 	}
 
 	// Get the daily metrics
-	dailyMetrics, err := s.db.GetCreatorDailyActivity(ctx, targetCreatorID)
+	dailyMetrics, err := s.q(ctx).GetCreatorDailyActivity(ctx, targetCreatorID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,11 +103,19 @@ func (s *MetricsService) GetCreatorMetrics(ctx context.Context, requesterOrgID,
 	var maxScoreDate string
 
 	for i, m := range dailyMetrics {
+		factsByKind := make(map[string]int64)
+		if err := json.Unmarshal(m.FactsByKind, &factsByKind); err != nil {
+			return nil, err
+		}
 		// ActivityDate is now a time.Time directly from the database
 		metrics[i] = DailyMetrics{
 			Date:                  m.ActivityDate,  // No conversion needed
 			FactsCreated:         m.FactsCreated,
 			FactObjectsInvolved:  m.FactObjectsInvolved,
+			MeetingsHeld:         m.MeetingsHeld,
+			CallsMade:            m.CallsMade,
+			EmailsLogged:         m.EmailsLogged,
+			FactsByKind:          factsByKind,
 			TasksTotal:           m.TasksTotal,
 			TasksCompleted:       m.TasksCompleted,
 			TaskObjectsInvolved:  m.TaskObjectsInvolved,
@@ -116,6 +137,7 @@ func (s *MetricsService) GetCreatorMetrics(ctx context.Context, requesterOrgID,
 		// Update summary data
 		summary.TotalActivityScore += m.DailyActivityScore
 		summary.TotalTasksCompleted += m.TasksCompleted
+		summary.TotalMeetingsHeld += m.MeetingsHeld
 		summary.TotalObjectsProcessed += m.ObjectsCreated + m.FactObjectsInvolved + m.TaskObjectsInvolved
 
 		// Track highest activity date
@@ -142,7 +164,7 @@ func (s *MetricsService) GetCreatorMetrics(ctx context.Context, requesterOrgID,
 // GetTeamMetrics retrieves activity metrics for all team members
 func (s *MetricsService) GetTeamMetrics(ctx context.Context, orgID uuid.UUID) (map[string]*MetricsResponse, error) {
 	// Get all active creators in the organization
-	creators, err := s.db.ListOrgMembers(ctx, database.ListOrgMembersParams{
+	creators, err := s.q(ctx).ListOrgMembers(ctx, database.ListOrgMembersParams{
 		OrgID: orgID,
 	});
 	if err != nil {
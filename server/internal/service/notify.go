@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/google/uuid"
+)
+
+// notificationExcerptLength is how much of the text a notification quotes
+const notificationExcerptLength = 200
+
+// Notification is a feed entry telling a creator something happened at url
+type Notification struct {
+	Type    string                 `json:"type"`
+	URL     string                 `json:"url"`
+	Details map[string]interface{} `json:"details"`
+}
+
+// notify adds the notification to the feed of each creator
+func notify(ctx context.Context, q *database.Queries, creatorIDs []uuid.UUID, n Notification) error {
+	content, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	for _, creatorID := range creatorIDs {
+		if _, err := q.CreateFeed(ctx, database.CreateFeedParams{
+			CreatorID: creatorID,
+			Content:   content,
+			Seen:      false,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excerpt cuts text to the length notifications quote
+func excerpt(text string) string {
+	if utf8.RuneCountInString(text) <= notificationExcerptLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:notificationExcerptLength]) + "…"
+}
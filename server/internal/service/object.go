@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/crea8r/muninn/server/internal/api/middleware"
 	"github.com/crea8r/muninn/server/internal/database"
 	"github.com/crea8r/muninn/server/pkg/pagination"
 	"github.com/google/uuid"
@@ -54,6 +55,8 @@ type ListObjectsParams struct {
     TypeValueField    string
     Ascending        bool
     SubStatusFilter   []int32
+    RelationTypeIDs   []uuid.UUID          // Filter by relationship types
+    RelatedObjectIDs  []uuid.UUID          // Filter by related objects
 }
 
 // ListObjectsAdvancedParams represents the database query parameters
@@ -83,6 +86,10 @@ func NewObjectService(db *database.Queries, debug bool) *ObjectService {
 	return &ObjectService{db: db, debug: debug}
 }
 
+func (s *ObjectService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
 func (s *ObjectService) ListObjects(ctx context.Context, params ListObjectsParams) (*pagination.PaginatedResult[database.ListObjectsAdvancedRow], error) {
     // Validate parameters
     if err := params.Validate(); err != nil {
@@ -106,7 +113,7 @@ func (s *ObjectService) ListObjects(ctx context.Context, params ListObjectsParam
     
 
     // Prepare arrays (nil if empty)
-    var stepIDs, tagIDs, typeIDs []uuid.UUID
+    var stepIDs, tagIDs, typeIDs, relationTypeIDs, relatedObjectIDs []uuid.UUID
     var subStatusFilter []int32
     if len(params.StepIDs) > 0 {
         stepIDs = params.StepIDs
@@ -120,13 +127,19 @@ func (s *ObjectService) ListObjects(ctx context.Context, params ListObjectsParam
     if len(params.SubStatusFilter) > 0 {
         subStatusFilter = params.SubStatusFilter
     }
+    if len(params.RelationTypeIDs) > 0 {
+        relationTypeIDs = params.RelationTypeIDs
+    }
+    if len(params.RelatedObjectIDs) > 0 {
+        relatedObjectIDs = params.RelatedObjectIDs
+    }
 
     if s.debug {
         log.Printf("ListObjects params: stepIDs=%v, tagIDs=%v, typeIDs=%v", 
             stepIDs, tagIDs, typeIDs)
     }
     
-    count, err := s.db.CountObjectsAdvanced(ctx, database.CountObjectsAdvancedParams{
+    count, err := s.q(ctx).CountObjectsAdvanced(ctx, database.CountObjectsAdvancedParams{
         OrgID:             params.OrgID,
         Column2:       params.SearchQuery,
         Column3:          stepIDs,
@@ -136,6 +149,8 @@ func (s *ObjectService) ListObjects(ctx context.Context, params ListObjectsParam
         Column7: nullableCriteria2,
         Column8: nullableCriteria3,
         Column9: subStatusFilter,
+        Column10: relationTypeIDs,
+        Column11: relatedObjectIDs,
     })
     if err != nil {
         return nil, fmt.Errorf("error counting objects: %w", err)
@@ -155,8 +170,10 @@ func (s *ObjectService) ListObjects(ctx context.Context, params ListObjectsParam
         Limit:            params.PageSize,
         Offset:           params.GetOffset(),
         Column14: subStatusFilter,
+        Column15: relationTypeIDs,
+        Column16: relatedObjectIDs,
     }
-    items, err := s.db.ListObjectsAdvanced(ctx, listParams)
+    items, err := s.q(ctx).ListObjectsAdvanced(ctx, listParams)
     if err != nil {
         return nil, fmt.Errorf("error listing objects: %w", err)
     }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// TrashRetentionDays is how long deleted records stay in the trash of
+	// orgs that did not set trash_retention_days in the org profile
+	TrashRetentionDays = 30
+	// TrashRetentionCap bounds the retention an org can set
+	TrashRetentionCap = 3650
+)
+
+// TrashKinds are the kinds of records the trash holds
+var TrashKinds = []string{"object", "fact", "task", "funnel", "tag", "list"}
+
+func IsValidTrashKind(kind string) bool {
+	for _, k := range TrashKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrTrashConflict is returned when a record can not be restored because a
+// live record took its name or id string
+var ErrTrashConflict = errors.New("a record with the same name exists")
+
+// TrashSettings are the trash settings admins keep in the org profile
+type TrashSettings struct {
+	RetentionDays int `json:"trash_retention_days"`
+}
+
+// Retention returns how long records stay in the trash
+func (s TrashSettings) Retention() time.Duration {
+	days := s.RetentionDays
+	if days <= 0 {
+		days = TrashRetentionDays
+	}
+	days = min(days, TrashRetentionCap)
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func trashSettings(profile json.RawMessage) TrashSettings {
+	var settings TrashSettings
+	// Profiles are free form, a malformed one keeps the defaults
+	_ = json.Unmarshal(profile, &settings)
+	return settings
+}
+
+// TrashService restores records from the trash and purges those kept longer
+// than the retention of their org
+type TrashService struct {
+	db    *database.Queries
+	sqlDB *sql.DB
+}
+
+func NewTrashService(db *database.Queries, sqlDB *sql.DB) *TrashService {
+	return &TrashService{db: db, sqlDB: sqlDB}
+}
+
+func (s *TrashService) q(ctx context.Context) *database.Queries {
+	return middleware.Queries(ctx, s.db)
+}
+
+// Retention returns how long the org keeps records in the trash
+func (s *TrashService) Retention(ctx context.Context, orgID uuid.UUID) (time.Duration, error) {
+	org, err := s.q(ctx).GetOrgDetails(ctx, orgID)
+	if err != nil {
+		return 0, err
+	}
+	return trashSettings(org.Profile).Retention(), nil
+}
+
+// Restore takes a record of the kind out of the trash. Objects come back
+// with the funnel steps and relationships deleted with them; facts, tasks
+// and their links to objects are kept while in the trash. It returns
+// sql.ErrNoRows when the record is not in the trash.
+func (s *TrashService) Restore(ctx context.Context, orgID uuid.UUID, kind string, id uuid.UUID) error {
+	var restored int64
+	var err error
+	switch kind {
+	case "object":
+		restored, err = s.q(ctx).RestoreTrashedObject(ctx, database.RestoreTrashedObjectParams{ID: id, OrgID: orgID})
+	case "fact":
+		restored, err = s.q(ctx).RestoreTrashedFact(ctx, database.RestoreTrashedFactParams{ID: id, OrgID: orgID})
+	case "task":
+		restored, err = s.q(ctx).RestoreTrashedTask(ctx, database.RestoreTrashedTaskParams{ID: id, OrgID: orgID})
+	case "funnel":
+		restored, err = s.q(ctx).RestoreTrashedFunnel(ctx, database.RestoreTrashedFunnelParams{ID: id, OrgID: orgID})
+	case "tag":
+		restored, err = s.q(ctx).RestoreTrashedTag(ctx, database.RestoreTrashedTagParams{ID: id, OrgID: orgID})
+	case "list":
+		restored, err = s.q(ctx).RestoreTrashedList(ctx, database.RestoreTrashedListParams{ID: id, OrgID: orgID})
+	default:
+		return sql.ErrNoRows
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrTrashConflict
+	}
+	if err != nil {
+		return err
+	}
+	if restored == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeAll deletes for good the records every org kept in the trash longer
+// than its retention. It returns the number of records deleted. An org that
+// fails does not stop the others, the failures are returned together.
+func (s *TrashService) PurgeAll(ctx context.Context) (int64, error) {
+	orgs, err := s.q(ctx).ListOrgProfiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	var errs []error
+	for _, org := range orgs {
+		var purged database.PurgeTrashRow
+		err := middleware.InOrg(ctx, s.sqlDB, s.db, org.ID.String(), func(ctx context.Context) error {
+			var err error
+			purged, err = s.q(ctx).PurgeTrash(ctx, database.PurgeTrashParams{
+				OrgID:  org.ID,
+				Before: time.Now().Add(-trashSettings(org.Profile).Retention()),
+			})
+			return err
+		})
+		if err != nil {
+			log.Printf("Purging the trash of org %s failed: %v", org.ID, err)
+			errs = append(errs, fmt.Errorf("org %s: %w", org.ID, err))
+			continue
+		}
+		total += int64(purged.Objects + purged.Facts + purged.Tasks + purged.Funnels + purged.Tags + purged.Lists)
+	}
+	return total, errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/internal/api/middleware"
+	"github.com/crea8r/muninn/server/internal/database"
+	"github.com/crea8r/muninn/server/internal/testdb"
+	"github.com/google/uuid"
+)
+
+// TestPurgeAllContinuesAfterFailedOrg makes the purge of the first org fail.
+// The next org must still lose its expired trash.
+func TestPurgeAllContinuesAfterFailedOrg(t *testing.T) {
+	db := testdb.Open(t)
+	queries := database.New(db)
+	ctx := context.Background()
+
+	// A fresh org table is scanned in the order the orgs were created
+	var objects []uuid.UUID
+	var orgs []uuid.UUID
+	for _, name := range []string{"failing", "healthy"} {
+		org, err := queries.CreateOrganization(ctx, database.CreateOrganizationParams{Name: name, Profile: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		creator, err := queries.CreateCreator(ctx, database.CreateCreatorParams{
+			Username: name + "-admin", Pwd: "x", Profile: json.RawMessage(`{}`), Role: middleware.RoleAdmin, OrgID: org.ID, Active: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = middleware.InOrg(ctx, db, queries, org.ID.String(), func(ctx context.Context) error {
+			obj, err := middleware.Queries(ctx, queries).CreateObject(ctx, database.CreateObjectParams{
+				Name: "Ada", Description: "", IDString: "ada", CreatorID: creator.ID,
+			})
+			objects = append(objects, obj.ID)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		orgs = append(orgs, org.ID)
+	}
+	// Both objects went to the trash long ago, the first org can not purge
+	exec := func(org uuid.UUID, query string, args ...interface{}) {
+		t.Helper()
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		if err := middleware.SetOrgID(ctx, tx, org.String()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, org := range orgs {
+		exec(org, "UPDATE obj SET deleted_at = $1 WHERE id = $2", time.Now().AddDate(-1, 0, 0), objects[i])
+	}
+	exec(orgs[0], `CREATE FUNCTION refuse_purge() RETURNS trigger LANGUAGE plpgsql AS $$
+		BEGIN RAISE EXCEPTION 'purge refused'; END $$`)
+	exec(orgs[0], fmt.Sprintf(`CREATE TRIGGER refuse_purge BEFORE DELETE ON obj FOR EACH ROW
+		WHEN (OLD.org_id = '%s') EXECUTE FUNCTION refuse_purge()`, orgs[0]))
+
+	purged, err := NewTrashService(queries, db).PurgeAll(ctx)
+	if err == nil {
+		t.Error("PurgeAll reported no error")
+	}
+	if purged != 1 {
+		t.Errorf("PurgeAll purged %d records, want the 1 of the healthy org", purged)
+	}
+	for i, org := range orgs {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := middleware.SetOrgID(ctx, tx, org.String()); err != nil {
+			t.Fatal(err)
+		}
+		var left int
+		err = tx.QueryRowContext(ctx, "SELECT count(*) FROM obj WHERE id = $1", objects[i]).Scan(&left)
+		tx.Rollback()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The failing org keeps its object, the healthy one does not
+		if want := []int{1, 0}[i]; left != want {
+			t.Errorf("org %d kept %d of its expired objects, want %d", i, left, want)
+		}
+	}
+}
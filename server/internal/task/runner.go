@@ -13,22 +13,30 @@ import (
 	"github.com/crea8r/muninn/server/internal/service"
 )
 
-const automationInterval = 10 * time.Minute
+const (
+    automationInterval = 10 * time.Minute
+    duplicateInterval  = time.Hour
+    purgeInterval      = 24 * time.Hour
+)
 
 // Runner handles periodic task execution
 type Runner struct {
     db              *database.Queries
     automationSvc   *service.AutomationService
+    duplicateSvc    *service.DuplicateService
+    trashSvc        *service.TrashService
     wg              sync.WaitGroup
     shutdown        chan struct{}
     log             *log.Logger
 }
 
 // NewRunner creates a new task runner
-func NewRunner(db *database.Queries, automationSvc *service.AutomationService) *Runner {
+func NewRunner(db *database.Queries, automationSvc *service.AutomationService, duplicateSvc *service.DuplicateService, trashSvc *service.TrashService) *Runner {
     return &Runner{
         db:            db,
         automationSvc: automationSvc,
+        duplicateSvc:  duplicateSvc,
+        trashSvc:      trashSvc,
         shutdown:      make(chan struct{}),
         log:          log.New(log.Writer(), "[TaskRunner] ", log.LstdFlags),
     }
@@ -36,8 +44,10 @@ func NewRunner(db *database.Queries, automationSvc *service.AutomationService) *
 
 // Start begins the periodic execution of tasks
 func (r *Runner) Start() {
-    r.wg.Add(1)
+    r.wg.Add(3)
     go r.runAutomationLoop()
+    go r.runDuplicateLoop()
+    go r.runPurgeLoop()
 }
 
 // Stop gracefully shuts down the task runner
@@ -66,6 +76,74 @@ func (r *Runner) runAutomationLoop() {
     }
 }
 
+func (r *Runner) runDuplicateLoop() {
+    defer r.wg.Done()
+
+    ticker := time.NewTicker(duplicateInterval)
+    defer ticker.Stop()
+
+    r.scanDuplicates()
+
+    for {
+        select {
+        case <-ticker.C:
+            r.scanDuplicates()
+        case <-r.shutdown:
+            r.log.Println("Shutting down duplicate detector")
+            return
+        }
+    }
+}
+
+func (r *Runner) runPurgeLoop() {
+    defer r.wg.Done()
+
+    ticker := time.NewTicker(purgeInterval)
+    defer ticker.Stop()
+
+    r.purgeTrash()
+
+    for {
+        select {
+        case <-ticker.C:
+            r.purgeTrash()
+        case <-r.shutdown:
+            r.log.Println("Shutting down trash purge")
+            return
+        }
+    }
+}
+
+// purgeTrash deletes for good what each org kept in the trash longer than
+// its retention
+func (r *Runner) purgeTrash() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Orgs that failed are retried on the next tick, the others are done
+	purged, err := r.trashSvc.PurgeAll(ctx)
+	if err != nil {
+		r.log.Printf("Error purging the trash: %v", err)
+	}
+	if purged > 0 {
+		r.log.Printf("Purged %d records from the trash", purged)
+	}
+}
+
+func (r *Runner) scanDuplicates() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Orgs that failed are retried on the next tick, the others are done
+	found, err := r.duplicateSvc.ScanAll(ctx)
+	if err != nil {
+		r.log.Printf("Error scanning for duplicate objects: %v", err)
+	}
+	if found > 0 {
+		r.log.Printf("Found %d possible duplicate objects", found)
+	}
+}
+
 func (r *Runner) executeAutomatedActions() {
 	// Create context with timeout for the entire batch
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
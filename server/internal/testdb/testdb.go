@@ -0,0 +1,132 @@
+// Package testdb gives integration tests a migrated Postgres schema of their
+// own
+package testdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// Open connects to TEST_DATABASE_URL and applies the migrations to a new
+// schema that is dropped once the test ends. The test is skipped when the
+// variable is not set. Connect as a regular role, as DATABASE_URL should, or
+// the row level security policies are bypassed.
+//
+// The initial migration drops tables by unqualified name, so Open refuses a
+// database whose public schema holds tables.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	var tables int
+	err = admin.QueryRowContext(ctx,
+		"SELECT count(*) FROM pg_tables WHERE schemaname = 'public'").Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables > 0 {
+		t.Fatalf("TEST_DATABASE_URL must name an empty database, its public schema has %d tables", tables)
+	}
+
+	// Tests of several packages run at once, only one may create the
+	// extensions
+	conn, err := admin.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, stmt := range []string{
+		"SELECT pg_advisory_lock(hashtext('testdb'))",
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`,
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public",
+		"SELECT pg_advisory_unlock(hashtext('testdb'))",
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		drop, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer drop.Close()
+		if _, err := drop.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("dropping %s: %v", schema, err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(t, dsn, schema+",public"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrate(t, db)
+	return db
+}
+
+// withSearchPath adds search_path to the URL or key=value form of dsn, the
+// driver sends it as a run time parameter of every connection
+func withSearchPath(t testing.TB, dsn, searchPath string) string {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return fmt.Sprintf("%s search_path='%s'", dsn, searchPath)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", searchPath)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// migrate applies the files of server/migrations in order
+func migrate(t testing.TB, db *sql.DB) {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no migrations in %s", dir)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(b)); err != nil {
+			t.Fatalf("applying %s: %v", filepath.Base(f), err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+-- API keys let robot integrations authenticate without a long-lived JWT.
+-- Only a SHA-256 hash of the secret part of the key is stored; the prefix is
+-- kept in clear so the key can be looked up before the hash is compared.
+CREATE TABLE api_key (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_key_org_id ON api_key(org_id);
+CREATE INDEX idx_api_key_creator_id ON api_key(creator_id);
@@ -0,0 +1,8 @@
+-- Track every login in creator_session so tokens can be revoked server side.
+-- The token itself is never stored, only its jti (JWT ID) claim.
+ALTER TABLE creator_session RENAME COLUMN jwt TO jti;
+ALTER TABLE creator_session ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE creator_session ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE creator_session ADD COLUMN revoked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX idx_creator_session_jti ON creator_session(jti);
@@ -0,0 +1,12 @@
+-- Refresh tokens rotate on every use. All tokens issued for one login share a
+-- creator_session (the token family); presenting a token twice revokes it.
+CREATE TABLE refresh_token (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES creator_session(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_token_session_id ON refresh_token(session_id);
@@ -0,0 +1,17 @@
+-- Roles are fixed, what each role may do comes from the default matrix in
+-- middleware/rbac.go and can be customised per org with role_permission rows.
+ALTER TABLE creator DROP CONSTRAINT creator_role_check;
+ALTER TABLE creator ADD CONSTRAINT creator_role_check
+    CHECK (role IN ('admin', 'member', 'viewer', 'robot'));
+
+-- An override grants (granted = true) or removes (granted = false) a single
+-- permission from a role of the org
+CREATE TABLE role_permission (
+    org_id UUID NOT NULL REFERENCES org(id),
+    role VARCHAR(50) NOT NULL CHECK (role IN ('member', 'viewer', 'robot')),
+    permission VARCHAR(100) NOT NULL,
+    granted BOOLEAN NOT NULL,
+    updated_by UUID NOT NULL REFERENCES creator(id),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, role, permission)
+);
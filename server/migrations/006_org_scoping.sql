@@ -0,0 +1,71 @@
+-- Core tables carry their org directly instead of reaching it through
+-- creator.org_id, so every query can scope a row to the caller's org.
+ALTER TABLE obj ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE fact ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE task ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE funnel ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE list ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE obj_type ADD COLUMN org_id UUID REFERENCES org(id);
+
+-- Backfill from the creator of each row
+UPDATE obj SET org_id = c.org_id FROM creator c WHERE obj.creator_id = c.id;
+UPDATE fact SET org_id = c.org_id FROM creator c WHERE fact.creator_id = c.id;
+UPDATE task SET org_id = c.org_id FROM creator c WHERE task.creator_id = c.id;
+UPDATE funnel SET org_id = c.org_id FROM creator c WHERE funnel.creator_id = c.id;
+UPDATE list SET org_id = c.org_id FROM creator c WHERE list.creator_id = c.id;
+UPDATE obj_type SET org_id = c.org_id FROM creator c WHERE obj_type.creator_id = c.id;
+
+ALTER TABLE obj ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE fact ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE task ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE funnel ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE list ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE obj_type ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX idx_obj_org_id ON obj(org_id);
+CREATE INDEX idx_fact_org_id ON fact(org_id);
+CREATE INDEX idx_task_org_id ON task(org_id);
+CREATE INDEX idx_funnel_org_id ON funnel(org_id);
+CREATE INDEX idx_list_org_id ON list(org_id);
+CREATE INDEX idx_obj_type_org_id ON obj_type(org_id);
+
+-- Inserts keep passing only creator_id, the org is taken from the creator
+CREATE OR REPLACE FUNCTION set_org_id_from_creator()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.org_id IS NULL THEN
+        SELECT org_id INTO NEW.org_id FROM creator WHERE id = NEW.creator_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_obj_org_id
+BEFORE INSERT ON obj
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
+
+CREATE TRIGGER set_fact_org_id
+BEFORE INSERT ON fact
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
+
+CREATE TRIGGER set_task_org_id
+BEFORE INSERT ON task
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
+
+CREATE TRIGGER set_funnel_org_id
+BEFORE INSERT ON funnel
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
+
+CREATE TRIGGER set_list_org_id
+BEFORE INSERT ON list
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
+
+CREATE TRIGGER set_obj_type_org_id
+BEFORE INSERT ON obj_type
+FOR EACH ROW
+EXECUTE FUNCTION set_org_id_from_creator();
@@ -0,0 +1,114 @@
+-- Row level security as a second line of tenancy. Requests run in a
+-- transaction with app.org_id set to the org of the caller (see
+-- middleware.OrgScope), the policies then hide and refuse rows of other orgs.
+-- Without app.org_id, as in background tasks and migrations, every row is
+-- visible.
+CREATE OR REPLACE FUNCTION app_org_id()
+RETURNS UUID AS $$
+    -- current_setting is '' once a transaction that set it has ended
+    SELECT NULLIF(current_setting('app.org_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+-- Tables that carry their org
+ALTER TABLE obj ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_org_isolation ON obj
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE fact ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fact FORCE ROW LEVEL SECURITY;
+CREATE POLICY fact_org_isolation ON fact
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE task ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task FORCE ROW LEVEL SECURITY;
+CREATE POLICY task_org_isolation ON task
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE tag ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tag FORCE ROW LEVEL SECURITY;
+CREATE POLICY tag_org_isolation ON tag
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE funnel ENABLE ROW LEVEL SECURITY;
+ALTER TABLE funnel FORCE ROW LEVEL SECURITY;
+CREATE POLICY funnel_org_isolation ON funnel
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE list ENABLE ROW LEVEL SECURITY;
+ALTER TABLE list FORCE ROW LEVEL SECURITY;
+CREATE POLICY list_org_isolation ON list
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE obj_type ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_type FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_type_org_isolation ON obj_type
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+-- Junction and child tables follow the org of their object or funnel
+ALTER TABLE obj_fact ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_fact FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_fact_org_isolation ON obj_fact
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_fact.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_fact.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER TABLE obj_task ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_task FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_task_org_isolation ON obj_task
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_task.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_task.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER TABLE obj_tag ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_tag FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_tag_org_isolation ON obj_tag
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_tag.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_tag.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER TABLE obj_step ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_step FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_step_org_isolation ON obj_step
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_step.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_step.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER TABLE obj_type_value ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_type_value FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_type_value_org_isolation ON obj_type_value
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_type_value.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_type_value.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER TABLE step ENABLE ROW LEVEL SECURITY;
+ALTER TABLE step FORCE ROW LEVEL SECURITY;
+CREATE POLICY step_org_isolation ON step
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM funnel f WHERE f.id = step.funnel_id AND f.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM funnel f WHERE f.id = step.funnel_id AND f.org_id = app_org_id()
+    ));
@@ -0,0 +1,15 @@
+-- Single use tokens mailed to a creator, for password resets and for
+-- verifying the email address in creator.profile. Only the hash is stored.
+-- An email_verify token that has been used marks its email as verified.
+CREATE TABLE creator_token (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    purpose VARCHAR(50) CHECK (purpose IN ('password_reset', 'email_verify')) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_creator_token_creator_id ON creator_token(creator_id, purpose);
@@ -0,0 +1,30 @@
+-- Failed logins within the last minutes slow down and then lock further
+-- attempts for a username or an IP. Failures are short lived and cleared on a
+-- successful login, lockouts are kept so admins can review them.
+CREATE TABLE login_failure (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    username VARCHAR(255) NOT NULL,
+    ip TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_login_failure_username ON login_failure(username, created_at);
+CREATE INDEX idx_login_failure_ip ON login_failure(ip, created_at);
+
+CREATE TABLE login_lockout (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scope VARCHAR(50) CHECK (scope IN ('username', 'ip')) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    ip TEXT NOT NULL,
+    -- The creator the username belonged to, if any
+    creator_id UUID REFERENCES creator(id) ON DELETE CASCADE,
+    failures INTEGER NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    unlocked_at TIMESTAMP WITH TIME ZONE,
+    unlocked_by UUID REFERENCES creator(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_login_lockout_username ON login_lockout(username, locked_until);
+CREATE INDEX idx_login_lockout_ip ON login_lockout(ip, locked_until);
+CREATE INDEX idx_login_lockout_creator_id ON login_lockout(creator_id);
@@ -0,0 +1,34 @@
+-- TOTP second factor. A secret is pending until the creator confirms it with
+-- a code, last_used_step refuses to accept the same code twice.
+CREATE TABLE creator_totp (
+    creator_id UUID PRIMARY KEY REFERENCES creator(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One time codes to log in without the authenticator, stored bcrypt hashed
+CREATE TABLE creator_recovery_code (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_creator_recovery_code_creator_id ON creator_recovery_code(creator_id);
+
+-- A password login waiting for its second factor ('verify'), or for the
+-- creator to set one up when the org requires it ('setup')
+CREATE TABLE login_challenge (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    purpose VARCHAR(50) CHECK (purpose IN ('verify', 'setup')) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_login_challenge_creator_id ON login_challenge(creator_id);
@@ -0,0 +1,18 @@
+-- Invitations to join an org. The link carries a single use token, only its
+-- hash is stored, and the invitee picks their own username and password.
+CREATE TABLE org_invite (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id) ON DELETE CASCADE,
+    email TEXT NOT NULL DEFAULT '',
+    role VARCHAR(50) NOT NULL,
+    profile JSONB NOT NULL DEFAULT '{}',
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by UUID REFERENCES creator(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_org_invite_org_id ON org_invite(org_id, created_at);
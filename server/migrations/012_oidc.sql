@@ -0,0 +1,36 @@
+-- Single sign on with an OpenID Connect identity provider. A creator can be
+-- linked to IdP subjects, and an org can claim email domains whose new users
+-- are provisioned into it on their first login.
+CREATE TABLE creator_identity (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issuer, subject)
+);
+
+CREATE INDEX idx_creator_identity_creator_id ON creator_identity(creator_id);
+
+-- The state, nonce and PKCE verifier of a login in progress at the IdP
+CREATE TABLE oidc_state (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE org_sso_domain (
+    domain TEXT PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES org(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    created_by UUID REFERENCES creator(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_org_sso_domain_org_id ON org_sso_domain(org_id);
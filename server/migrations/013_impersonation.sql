@@ -0,0 +1,28 @@
+-- Admins view the app as a member of their org with a short lived token. The
+-- token carries both creators, and every request made with it is logged.
+CREATE TABLE impersonation (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    jti VARCHAR(64) NOT NULL UNIQUE,
+    org_id UUID NOT NULL REFERENCES org(id) ON DELETE CASCADE,
+    admin_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    read_only BOOLEAN NOT NULL DEFAULT true,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_impersonation_org_id ON impersonation(org_id, created_at);
+
+CREATE TABLE impersonation_request (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    impersonation_id UUID NOT NULL REFERENCES impersonation(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_impersonation_request_impersonation_id ON impersonation_request(impersonation_id, created_at);
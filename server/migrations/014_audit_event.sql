@@ -0,0 +1,155 @@
+-- Append-only record of every change to the data of an org. Triggers write it
+-- in the transaction of the change, the actor comes from app.creator_id and
+-- app.impersonator_id (see middleware.OrgScope). Changes made outside a
+-- request, such as automations, have no actor.
+CREATE TABLE audit_event (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    actor_id UUID REFERENCES creator(id),
+    impersonator_id UUID REFERENCES creator(id),
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    -- The whole row for creates and deletes, only the changed columns for
+    -- updates
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_event_org_id ON audit_event(org_id, created_at);
+CREATE INDEX idx_audit_event_entity ON audit_event(entity_id, created_at);
+CREATE INDEX idx_audit_event_actor_id ON audit_event(actor_id, created_at);
+
+ALTER TABLE audit_event ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_event FORCE ROW LEVEL SECURITY;
+CREATE POLICY audit_event_org_isolation ON audit_event
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+CREATE OR REPLACE FUNCTION prevent_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_event_append_only
+BEFORE UPDATE OR DELETE ON audit_event
+FOR EACH ROW
+EXECUTE FUNCTION prevent_audit_event_change();
+
+CREATE TRIGGER audit_event_no_truncate
+BEFORE TRUNCATE ON audit_event
+FOR EACH STATEMENT
+EXECUTE FUNCTION prevent_audit_event_change();
+
+CREATE OR REPLACE FUNCTION app_creator_id()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.creator_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION app_impersonator_id()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.impersonator_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+-- Columns maintained by the database are no change of their own
+CREATE OR REPLACE FUNCTION audit_row(r JSONB)
+RETURNS JSONB AS $$
+    SELECT r - 'last_updated' - 'updated_at' - 'search_vector' - 'fields_search';
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION record_audit_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB;
+    new_row JSONB;
+    row_data JSONB;
+    changed_before JSONB := '{}';
+    changed_after JSONB := '{}';
+    col TEXT;
+    event_action TEXT := lower(TG_OP);
+    event_org_id UUID;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := audit_row(to_jsonb(OLD));
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := audit_row(to_jsonb(NEW));
+    END IF;
+    row_data := COALESCE(new_row, old_row);
+
+    IF TG_OP = 'INSERT' THEN
+        event_action := 'create';
+    ELSIF TG_OP = 'UPDATE' THEN
+        FOR col IN SELECT jsonb_object_keys(new_row) LOOP
+            IF new_row -> col IS DISTINCT FROM old_row -> col THEN
+                changed_before := changed_before || jsonb_build_object(col, old_row -> col);
+                changed_after := changed_after || jsonb_build_object(col, new_row -> col);
+            END IF;
+        END LOOP;
+        IF changed_after = '{}' THEN
+            RETURN NULL;
+        END IF;
+        -- Soft deletes are deletes
+        IF changed_after ? 'deleted_at' THEN
+            IF jsonb_typeof(changed_after -> 'deleted_at') = 'null' THEN
+                event_action := 'restore';
+            ELSE
+                event_action := 'delete';
+            END IF;
+        END IF;
+        old_row := changed_before;
+        new_row := changed_after;
+    END IF;
+
+    IF row_data ? 'org_id' THEN
+        event_org_id := (row_data ->> 'org_id')::UUID;
+    ELSIF row_data ? 'obj_id' THEN
+        SELECT org_id INTO event_org_id FROM obj WHERE id = (row_data ->> 'obj_id')::UUID;
+    ELSIF row_data ? 'funnel_id' THEN
+        SELECT org_id INTO event_org_id FROM funnel WHERE id = (row_data ->> 'funnel_id')::UUID;
+    END IF;
+    -- Rows deleted along with their object are covered by the delete of the object
+    IF event_org_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO audit_event (org_id, actor_id, impersonator_id, entity_type, entity_id, action, before, after)
+    VALUES (
+        event_org_id, app_creator_id(), app_impersonator_id(), TG_TABLE_NAME,
+        -- Link tables have no id, their object stands for them
+        COALESCE(row_data ->> 'id', row_data ->> 'obj_id')::UUID,
+        event_action, old_row, new_row
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_obj AFTER INSERT OR UPDATE OR DELETE ON obj
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_type AFTER INSERT OR UPDATE OR DELETE ON obj_type
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_type_value AFTER INSERT OR UPDATE OR DELETE ON obj_type_value
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_tag AFTER INSERT OR UPDATE OR DELETE ON obj_tag
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_fact AFTER INSERT OR UPDATE OR DELETE ON obj_fact
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_task AFTER INSERT OR UPDATE OR DELETE ON obj_task
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_step AFTER INSERT OR UPDATE OR DELETE ON obj_step
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_tag AFTER INSERT OR UPDATE OR DELETE ON tag
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_funnel AFTER INSERT OR UPDATE OR DELETE ON funnel
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_step AFTER INSERT OR UPDATE OR DELETE ON step
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_fact AFTER INSERT OR UPDATE OR DELETE ON fact
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_task AFTER INSERT OR UPDATE OR DELETE ON task
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_list AFTER INSERT OR UPDATE OR DELETE ON list
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
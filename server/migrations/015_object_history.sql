@@ -0,0 +1,11 @@
+-- Events of one transaction share created_at, seq orders them so the history
+-- of an object can be replayed change by change
+ALTER TABLE audit_event ADD COLUMN seq BIGSERIAL;
+
+CREATE INDEX idx_audit_event_entity_seq ON audit_event(entity_id, seq);
+-- Type values that ever belonged to an object are found by the obj_id of their
+-- create, move and hard delete events
+CREATE INDEX idx_audit_event_type_value_after_obj ON audit_event(((after ->> 'obj_id')::UUID))
+    WHERE entity_type = 'obj_type_value';
+CREATE INDEX idx_audit_event_type_value_before_obj ON audit_event(((before ->> 'obj_id')::UUID))
+    WHERE entity_type = 'obj_type_value';
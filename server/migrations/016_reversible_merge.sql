@@ -0,0 +1,18 @@
+-- A merge keeps a snapshot of what it moved where, so it can be undone
+ALTER TABLE object_merge_history ADD COLUMN org_id UUID REFERENCES org(id);
+ALTER TABLE object_merge_history ADD COLUMN snapshot JSONB;
+ALTER TABLE object_merge_history ADD COLUMN undone_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE object_merge_history ADD COLUMN undone_by UUID REFERENCES creator(id);
+
+-- Backfill from the target of each merge, older merges have no snapshot and
+-- can not be undone
+UPDATE object_merge_history h SET org_id = o.org_id FROM obj o WHERE h.target_object_id = o.id;
+ALTER TABLE object_merge_history ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX idx_object_merge_history_org_id ON object_merge_history(org_id, merged_at);
+
+ALTER TABLE object_merge_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE object_merge_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY object_merge_history_org_isolation ON object_merge_history
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
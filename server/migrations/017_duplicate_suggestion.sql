@@ -0,0 +1,55 @@
+-- Possible duplicate objects found by the duplicate detector (see
+-- service.DuplicateService), waiting for a member to merge or dismiss them
+CREATE TABLE duplicate_suggestion (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id) ON DELETE CASCADE,
+    -- A pair is stored once, the smaller id first
+    obj_a_id UUID NOT NULL REFERENCES obj(id) ON DELETE CASCADE,
+    obj_b_id UUID NOT NULL REFERENCES obj(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    -- The signals behind the score, such as a shared email
+    signals JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'dismissed')),
+    resolved_by UUID REFERENCES creator(id),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    merge_history_id UUID REFERENCES object_merge_history(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (obj_a_id, obj_b_id),
+    CHECK (obj_a_id < obj_b_id)
+);
+
+CREATE INDEX idx_duplicate_suggestion_org_status ON duplicate_suggestion(org_id, status, score DESC);
+CREATE INDEX idx_duplicate_suggestion_obj_b ON duplicate_suggestion(obj_b_id);
+
+ALTER TABLE duplicate_suggestion ENABLE ROW LEVEL SECURITY;
+ALTER TABLE duplicate_suggestion FORCE ROW LEVEL SECURITY;
+CREATE POLICY duplicate_suggestion_org_isolation ON duplicate_suggestion
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+-- When the detector last looked at each org, later scans only compare
+-- objects changed since
+CREATE TABLE duplicate_scan (
+    org_id UUID PRIMARY KEY REFERENCES org(id) ON DELETE CASCADE,
+    scanned_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Names are compared by trigram similarity
+CREATE INDEX idx_obj_name_trgm ON obj USING gin (lower(name) gin_trgm_ops) WHERE deleted_at IS NULL;
+
+-- Contact values normalized the way ListObjectsWithNormalizedData does, so
+-- the same handle written differently matches. Keys that are no contact give
+-- NULL.
+CREATE OR REPLACE FUNCTION normalize_contact_value(key TEXT, value JSONB)
+RETURNS TEXT AS $$
+    SELECT NULLIF(btrim(CASE lower(key)
+        WHEN 'email' THEN lower(value #>> '{}')
+        WHEN 'phone' THEN regexp_replace(value #>> '{}', '[^0-9+]', '', 'g')
+        WHEN 'twitter' THEN clean_url_value(value, 'twitter')
+        WHEN 'x or twitter' THEN clean_url_value(value, 'twitter')
+        WHEN 'linkedin' THEN clean_url_value(value, 'linkedin')
+        WHEN 'telegram' THEN lower(value #>> '{}')
+        WHEN 'discord' THEN lower(value #>> '{}')
+    END, ' /@'), '');
+$$ LANGUAGE sql IMMUTABLE;
@@ -0,0 +1,57 @@
+-- Kinds of relationships between objects, defined per org. A directional type
+-- reads from the first object to the second ("works at"), inverse_name reads
+-- it the other way ("employs"). Other types read the same both ways ("knows").
+CREATE TABLE obj_relation_type (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    name VARCHAR(255) NOT NULL,
+    inverse_name VARCHAR(255) NOT NULL DEFAULT '',
+    directional BOOLEAN NOT NULL DEFAULT true,
+    description TEXT NOT NULL DEFAULT '',
+    creator_id UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_obj_relation_type_name ON obj_relation_type(org_id, lower(name)) WHERE deleted_at IS NULL;
+
+-- A relationship from one object to another, with optional attributes such
+-- as a role, and the time it started and ended
+CREATE TABLE obj_relation (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    type_id UUID NOT NULL REFERENCES obj_relation_type(id),
+    from_obj_id UUID NOT NULL REFERENCES obj(id) ON DELETE CASCADE,
+    to_obj_id UUID NOT NULL REFERENCES obj(id) ON DELETE CASCADE,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    creator_id UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (from_obj_id <> to_obj_id),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at <= ends_at)
+);
+
+CREATE INDEX idx_obj_relation_from ON obj_relation(from_obj_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_obj_relation_to ON obj_relation(to_obj_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_obj_relation_type_id ON obj_relation(type_id);
+
+ALTER TABLE obj_relation_type ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_relation_type FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_relation_type_org_isolation ON obj_relation_type
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE obj_relation ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_relation FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_relation_org_isolation ON obj_relation
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+CREATE TRIGGER audit_obj_relation_type AFTER INSERT OR UPDATE OR DELETE ON obj_relation_type
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_relation AFTER INSERT OR UPDATE OR DELETE ON obj_relation
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
@@ -0,0 +1,3 @@
+-- Objects sharing facts are found from the fact side of obj_fact, which the
+-- primary key does not cover
+CREATE INDEX idx_obj_fact_fact_id ON obj_fact(fact_id);
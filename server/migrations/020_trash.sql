@@ -0,0 +1,18 @@
+-- Tags and lists go to the trash like the other records instead of being
+-- deleted, so a tag name is only taken by tags that are not in the trash
+ALTER TABLE tag DROP CONSTRAINT tag_name_org_id_key;
+CREATE UNIQUE INDEX idx_tag_name_org_id ON tag(name, org_id) WHERE deleted_at IS NULL;
+
+-- Purging an object from the trash removes the merges into it, and the
+-- duplicate suggestions accepted with them keep their status
+ALTER TABLE object_merge_history DROP CONSTRAINT object_merge_history_target_object_id_fkey;
+ALTER TABLE object_merge_history ADD CONSTRAINT object_merge_history_target_object_id_fkey
+    FOREIGN KEY (target_object_id) REFERENCES obj(id) ON DELETE CASCADE;
+ALTER TABLE duplicate_suggestion DROP CONSTRAINT duplicate_suggestion_merge_history_id_fkey;
+ALTER TABLE duplicate_suggestion ADD CONSTRAINT duplicate_suggestion_merge_history_id_fkey
+    FOREIGN KEY (merge_history_id) REFERENCES object_merge_history(id) ON DELETE SET NULL;
+
+-- The purge looks for old trash of each table
+CREATE INDEX idx_obj_deleted_at ON obj(org_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_fact_deleted_at ON fact(org_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_task_deleted_at ON task(org_id, deleted_at) WHERE deleted_at IS NOT NULL;
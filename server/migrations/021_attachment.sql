@@ -0,0 +1,81 @@
+-- A file uploaded to the blob store, such as a signed contract or a pitch
+-- deck. storage_key locates the content in the store, sha256 is the hex
+-- checksum computed on upload.
+CREATE TABLE attachment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    storage_key TEXT NOT NULL UNIQUE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    sha256 CHAR(64) NOT NULL,
+    creator_id UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attachment_org_id ON attachment(org_id, created_at DESC);
+
+-- An attachment can be linked to any number of objects, facts and tasks
+CREATE TABLE obj_attachment (
+    obj_id UUID NOT NULL REFERENCES obj(id) ON DELETE CASCADE,
+    attachment_id UUID NOT NULL REFERENCES attachment(id) ON DELETE CASCADE,
+    PRIMARY KEY (obj_id, attachment_id)
+);
+
+CREATE TABLE fact_attachment (
+    fact_id UUID NOT NULL REFERENCES fact(id) ON DELETE CASCADE,
+    attachment_id UUID NOT NULL REFERENCES attachment(id) ON DELETE CASCADE,
+    PRIMARY KEY (fact_id, attachment_id)
+);
+
+CREATE TABLE task_attachment (
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attachment_id UUID NOT NULL REFERENCES attachment(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, attachment_id)
+);
+
+CREATE INDEX idx_obj_attachment_attachment_id ON obj_attachment(attachment_id);
+CREATE INDEX idx_fact_attachment_attachment_id ON fact_attachment(attachment_id);
+CREATE INDEX idx_task_attachment_attachment_id ON task_attachment(attachment_id);
+
+ALTER TABLE attachment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attachment FORCE ROW LEVEL SECURITY;
+CREATE POLICY attachment_org_isolation ON attachment
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+-- Links follow the org of their attachment
+ALTER TABLE obj_attachment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE obj_attachment FORCE ROW LEVEL SECURITY;
+CREATE POLICY obj_attachment_org_isolation ON obj_attachment
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = obj_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = obj_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+ALTER TABLE fact_attachment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fact_attachment FORCE ROW LEVEL SECURITY;
+CREATE POLICY fact_attachment_org_isolation ON fact_attachment
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = fact_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = fact_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+ALTER TABLE task_attachment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_attachment FORCE ROW LEVEL SECURITY;
+CREATE POLICY task_attachment_org_isolation ON task_attachment
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = task_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = task_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+CREATE TRIGGER audit_attachment AFTER INSERT OR UPDATE OR DELETE ON attachment
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
+CREATE TRIGGER audit_obj_attachment AFTER INSERT OR UPDATE OR DELETE ON obj_attachment
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
@@ -0,0 +1,68 @@
+-- Discussion about an object or a task, kept apart from facts which record
+-- what happened. A comment answering another has it as parent.
+CREATE TABLE comment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    obj_id UUID REFERENCES obj(id) ON DELETE CASCADE,
+    task_id UUID REFERENCES task(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comment(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    creator_id UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((obj_id IS NULL) <> (task_id IS NULL))
+);
+
+CREATE INDEX idx_comment_obj_id ON comment(obj_id, created_at) WHERE obj_id IS NOT NULL;
+CREATE INDEX idx_comment_task_id ON comment(task_id, created_at) WHERE task_id IS NOT NULL;
+CREATE INDEX idx_comment_parent_id ON comment(parent_id);
+
+-- Creators mentioned in a comment with @[Name](creator:<id>)
+CREATE TABLE comment_mention (
+    comment_id UUID NOT NULL REFERENCES comment(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, creator_id)
+);
+
+-- One row per creator and emoji
+CREATE TABLE comment_reaction (
+    comment_id UUID NOT NULL REFERENCES comment(id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL REFERENCES creator(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, creator_id, emoji)
+);
+
+CREATE INDEX idx_comment_mention_creator_id ON comment_mention(creator_id);
+
+ALTER TABLE comment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE comment FORCE ROW LEVEL SECURITY;
+CREATE POLICY comment_org_isolation ON comment
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+-- Mentions and reactions follow the org of their comment
+ALTER TABLE comment_mention ENABLE ROW LEVEL SECURITY;
+ALTER TABLE comment_mention FORCE ROW LEVEL SECURITY;
+CREATE POLICY comment_mention_org_isolation ON comment_mention
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_mention.comment_id AND c.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_mention.comment_id AND c.org_id = app_org_id()
+    ));
+
+ALTER TABLE comment_reaction ENABLE ROW LEVEL SECURITY;
+ALTER TABLE comment_reaction FORCE ROW LEVEL SECURITY;
+CREATE POLICY comment_reaction_org_isolation ON comment_reaction
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_reaction.comment_id AND c.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_reaction.comment_id AND c.org_id = app_org_id()
+    ));
+
+CREATE TRIGGER audit_comment AFTER INSERT OR UPDATE OR DELETE ON comment
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
@@ -0,0 +1,53 @@
+-- A fact is a call, a meeting, an email, a note, or a kind the org defines.
+-- Facts recorded before kinds existed are notes.
+ALTER TABLE fact ADD COLUMN kind VARCHAR(50) NOT NULL DEFAULT 'note';
+ALTER TABLE fact ADD COLUMN duration_minutes INTEGER CHECK (duration_minutes > 0);
+ALTER TABLE fact ADD COLUMN direction VARCHAR(10) CHECK (direction IN ('inbound', 'outbound'));
+ALTER TABLE fact ADD COLUMN outcome TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_fact_kind ON fact(org_id, kind) WHERE deleted_at IS NULL;
+
+-- Kinds an org adds to the built-in ones. fields lists the structured fields
+-- its facts take, among duration, attendees, direction and outcome.
+CREATE TABLE fact_kind (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES org(id),
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    fields TEXT[] NOT NULL DEFAULT '{}',
+    creator_id UUID NOT NULL REFERENCES creator(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, name)
+);
+
+-- The objects and creators who took part in a call or a meeting
+CREATE TABLE fact_attendee (
+    fact_id UUID NOT NULL REFERENCES fact(id) ON DELETE CASCADE,
+    obj_id UUID REFERENCES obj(id) ON DELETE CASCADE,
+    creator_id UUID REFERENCES creator(id) ON DELETE CASCADE,
+    CHECK ((obj_id IS NULL) <> (creator_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_fact_attendee_obj ON fact_attendee(fact_id, obj_id) WHERE obj_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_fact_attendee_creator ON fact_attendee(fact_id, creator_id) WHERE creator_id IS NOT NULL;
+CREATE INDEX idx_fact_attendee_creator_id ON fact_attendee(creator_id) WHERE creator_id IS NOT NULL;
+
+ALTER TABLE fact_kind ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fact_kind FORCE ROW LEVEL SECURITY;
+CREATE POLICY fact_kind_org_isolation ON fact_kind
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+-- Attendees follow the org of their fact
+ALTER TABLE fact_attendee ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fact_attendee FORCE ROW LEVEL SECURITY;
+CREATE POLICY fact_attendee_org_isolation ON fact_attendee
+    USING (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM fact f WHERE f.id = fact_attendee.fact_id AND f.org_id = app_org_id()
+    ))
+    WITH CHECK (app_org_id() IS NULL OR EXISTS (
+        SELECT 1 FROM fact f WHERE f.id = fact_attendee.fact_id AND f.org_id = app_org_id()
+    ));
+
+CREATE TRIGGER audit_fact_kind AFTER INSERT OR UPDATE OR DELETE ON fact_kind
+FOR EACH ROW EXECUTE FUNCTION record_audit_event();
@@ -0,0 +1,54 @@
+-- Every version of a fact, the first one included. A trigger records a
+-- revision whenever what a fact says changes, editor_id is the creator of the
+-- request (see middleware.OrgScope), NULL for changes made outside one.
+-- Attendees and linked objects are not part of a revision.
+CREATE TABLE fact_revision (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fact_id UUID NOT NULL REFERENCES fact(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES org(id),
+    revision INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    happened_at TIMESTAMP WITH TIME ZONE,
+    location TEXT NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    duration_minutes INTEGER,
+    direction VARCHAR(10),
+    outcome TEXT NOT NULL,
+    editor_id UUID REFERENCES creator(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (fact_id, revision)
+);
+
+ALTER TABLE fact_revision ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fact_revision FORCE ROW LEVEL SECURITY;
+CREATE POLICY fact_revision_org_isolation ON fact_revision
+    USING (app_org_id() IS NULL OR org_id = app_org_id())
+    WITH CHECK (app_org_id() IS NULL OR org_id = app_org_id());
+
+CREATE OR REPLACE FUNCTION record_fact_revision()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND (NEW.text, NEW.happened_at, NEW.location, NEW.kind, NEW.duration_minutes, NEW.direction, NEW.outcome)
+        IS NOT DISTINCT FROM (OLD.text, OLD.happened_at, OLD.location, OLD.kind, OLD.duration_minutes, OLD.direction, OLD.outcome) THEN
+        RETURN NEW;
+    END IF;
+    -- The row lock of the update keeps concurrent edits of a fact in order
+    INSERT INTO fact_revision (fact_id, org_id, revision, text, happened_at, location, kind,
+        duration_minutes, direction, outcome, editor_id)
+    SELECT NEW.id, NEW.org_id, COALESCE(MAX(r.revision), 0) + 1, NEW.text, NEW.happened_at, NEW.location, NEW.kind,
+        NEW.duration_minutes, NEW.direction, NEW.outcome,
+        CASE WHEN TG_OP = 'INSERT' THEN NEW.creator_id ELSE app_creator_id() END
+    FROM fact_revision r
+    WHERE r.fact_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_fact_revision AFTER INSERT OR UPDATE ON fact
+FOR EACH ROW EXECUTE FUNCTION record_fact_revision();
+
+-- What facts say today is their first revision
+INSERT INTO fact_revision (fact_id, org_id, revision, text, happened_at, location, kind,
+    duration_minutes, direction, outcome, editor_id, created_at)
+SELECT id, org_id, 1, text, happened_at, location, kind, duration_minutes, direction, outcome, creator_id, last_updated
+FROM fact;
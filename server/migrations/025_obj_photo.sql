@@ -0,0 +1,6 @@
+-- An object can show one of its image attachments as its photo. The photo
+-- column keeps a URL from elsewhere, such as an import, and is shown when no
+-- attachment is set. Deleting the attachment clears the photo.
+ALTER TABLE obj ADD COLUMN photo_attachment_id UUID REFERENCES attachment(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_obj_photo_attachment_id ON obj(photo_attachment_id) WHERE photo_attachment_id IS NOT NULL;
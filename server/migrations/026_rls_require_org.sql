@@ -0,0 +1,167 @@
+-- The policies used to let every row through when app.org_id was not set, so
+-- a query that ran outside a request, or a request that forgot the scope, saw
+-- and changed the rows of every org. They now deny instead: app_org_id() is
+-- NULL there and no org_id equals NULL. Background tasks scope their work to
+-- one org at a time (see middleware.InOrg). Superusers and roles with
+-- BYPASSRLS still skip the policies, a migration that changes rows as the
+-- regular role must set app.org_id for each org.
+
+ALTER POLICY obj_org_isolation ON obj
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY fact_org_isolation ON fact
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY task_org_isolation ON task
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY tag_org_isolation ON tag
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY funnel_org_isolation ON funnel
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY list_org_isolation ON list
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY obj_type_org_isolation ON obj_type
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY obj_fact_org_isolation ON obj_fact
+    USING (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_fact.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_fact.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER POLICY obj_task_org_isolation ON obj_task
+    USING (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_task.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_task.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER POLICY obj_tag_org_isolation ON obj_tag
+    USING (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_tag.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_tag.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER POLICY obj_step_org_isolation ON obj_step
+    USING (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_step.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_step.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER POLICY obj_type_value_org_isolation ON obj_type_value
+    USING (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_type_value.obj_id AND o.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM obj o WHERE o.id = obj_type_value.obj_id AND o.org_id = app_org_id()
+    ));
+
+ALTER POLICY step_org_isolation ON step
+    USING (EXISTS (
+        SELECT 1 FROM funnel f WHERE f.id = step.funnel_id AND f.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM funnel f WHERE f.id = step.funnel_id AND f.org_id = app_org_id()
+    ));
+
+ALTER POLICY audit_event_org_isolation ON audit_event
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY object_merge_history_org_isolation ON object_merge_history
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY duplicate_suggestion_org_isolation ON duplicate_suggestion
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY obj_relation_type_org_isolation ON obj_relation_type
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY obj_relation_org_isolation ON obj_relation
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY attachment_org_isolation ON attachment
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY obj_attachment_org_isolation ON obj_attachment
+    USING (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = obj_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = obj_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+ALTER POLICY fact_attachment_org_isolation ON fact_attachment
+    USING (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = fact_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = fact_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+ALTER POLICY task_attachment_org_isolation ON task_attachment
+    USING (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = task_attachment.attachment_id AND a.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM attachment a WHERE a.id = task_attachment.attachment_id AND a.org_id = app_org_id()
+    ));
+
+ALTER POLICY comment_org_isolation ON comment
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY comment_mention_org_isolation ON comment_mention
+    USING (EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_mention.comment_id AND c.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_mention.comment_id AND c.org_id = app_org_id()
+    ));
+
+ALTER POLICY comment_reaction_org_isolation ON comment_reaction
+    USING (EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_reaction.comment_id AND c.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM comment c WHERE c.id = comment_reaction.comment_id AND c.org_id = app_org_id()
+    ));
+
+ALTER POLICY fact_kind_org_isolation ON fact_kind
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
+
+ALTER POLICY fact_attendee_org_isolation ON fact_attendee
+    USING (EXISTS (
+        SELECT 1 FROM fact f WHERE f.id = fact_attendee.fact_id AND f.org_id = app_org_id()
+    ))
+    WITH CHECK (EXISTS (
+        SELECT 1 FROM fact f WHERE f.id = fact_attendee.fact_id AND f.org_id = app_org_id()
+    ));
+
+ALTER POLICY fact_revision_org_isolation ON fact_revision
+    USING (org_id = app_org_id())
+    WITH CHECK (org_id = app_org_id());
@@ -0,0 +1,5 @@
+-- An object linked to a fact only because its text mentions it. Taking the
+-- mention out of the text unlinks such an object, an object linked by hand
+-- stays linked. Links made before this column are all kept as by hand, since
+-- there is no telling which came from a mention.
+ALTER TABLE obj_fact ADD COLUMN mentioned BOOLEAN NOT NULL DEFAULT false;
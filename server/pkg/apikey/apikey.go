@@ -0,0 +1,76 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/crea8r/muninn/server/pkg/token"
+)
+
+// Keys look like "mk_<prefix>_<secret>". The prefix is stored in clear so the
+// key row can be found, the secret is only ever stored as a SHA-256 hash.
+const keyTag = "mk"
+
+var ErrMalformedKey = errors.New("malformed api key")
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PrefixBytes is the randomness of a prefix. Prefixes are unique, 8 bytes make
+// a collision unlikely for any number of keys an org will create, and their
+// hex fits the prefix column.
+const PrefixBytes = 8
+
+// Generate returns a new plaintext key and its lookup prefix
+func Generate() (string, string, error) {
+	prefix, err := randomHex(PrefixBytes)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", "", err
+	}
+	return keyTag + "_" + prefix + "_" + secret, prefix, nil
+}
+
+// Prefix extracts the lookup prefix from a plaintext key
+func Prefix(key string) (string, error) {
+	prefix, _, err := split(key)
+	return prefix, err
+}
+
+func split(key string) (string, string, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != keyTag || parts[1] == "" || parts[2] == "" {
+		return "", "", ErrMalformedKey
+	}
+	return parts[1], parts[2], nil
+}
+
+// Hash returns the value stored for a key. Secrets are random, so like the
+// refresh tokens a fast hash is enough and checking a key costs next to
+// nothing, a slow one would let anyone burn CPU with made up keys.
+func Hash(key string) (string, error) {
+	_, secret, err := split(key)
+	if err != nil {
+		return "", err
+	}
+	return token.Hash(secret), nil
+}
+
+func Compare(hash string, key string) bool {
+	hashed, err := Hash(key)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(hashed)) == 1
+}
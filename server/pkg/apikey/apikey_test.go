@@ -0,0 +1,28 @@
+package apikey
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	key, prefix, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The prefix column is VARCHAR(16)
+	if len(prefix) != 2*PrefixBytes || len(prefix) > 16 {
+		t.Errorf("prefix %q has %d characters", prefix, len(prefix))
+	}
+	got, err := Prefix(key)
+	if err != nil || got != prefix {
+		t.Errorf("Prefix(%q) = %q, %v, want %q", key, got, err, prefix)
+	}
+	hash, err := Hash(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Compare(hash, key) {
+		t.Error("the key does not match its hash")
+	}
+	if _, other, _ := Generate(); other == prefix {
+		t.Error("two keys got the same prefix")
+	}
+}
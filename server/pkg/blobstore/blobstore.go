@@ -0,0 +1,174 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info describes a blob being stored
+type Info struct {
+	Size        int64
+	ContentType string
+	// SHA256 is the hex encoded checksum of the content, stores may use it to
+	// have the backend verify what it received
+	SHA256 string
+}
+
+// Download is how a browser should present a blob fetched through a URL
+type Download struct {
+	Filename    string
+	ContentType string
+}
+
+// BlobStore keeps file contents under keys, the server picks an
+// implementation from the env
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, info Info) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns a signed URL that downloads the blob until expires has passed
+	URL(ctx context.Context, key string, download Download, expires time.Duration) (string, error)
+}
+
+var ErrNotFound = errors.New("Blob not found")
+
+// MaxURLExpiry is the longest a signed URL can be valid, the limit of S3
+const MaxURLExpiry = 7 * 24 * time.Hour
+
+// FromEnv returns the blob store configured by BLOB_STORE:
+//   - local, the default, keeps blobs in the directory BLOB_DIR (default
+//     ./data/blobs) and serves them from BLOB_PUBLIC_URL (default
+//     http://localhost:8080/blobs) with URLs signed with BLOB_SECRET, or
+//     JWT_SECRET when it is not set
+//   - s3 keeps blobs in S3_BUCKET of an S3 compatible service, at S3_ENDPOINT
+//     when set (e.g. a local MinIO), in S3_REGION (default us-east-1) with
+//     S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY. Buckets are addressed by path
+//     when S3_ENDPOINT is set, unless S3_PATH_STYLE is false.
+func FromEnv() (BlobStore, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "./data/blobs"
+		}
+		publicURL := os.Getenv("BLOB_PUBLIC_URL")
+		if publicURL == "" {
+			publicURL = "http://localhost:8080/blobs"
+		}
+		secret := os.Getenv("BLOB_SECRET")
+		if secret == "" {
+			secret = os.Getenv("JWT_SECRET")
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("BLOB_SECRET or JWT_SECRET must be set when BLOB_STORE is local")
+		}
+		return NewLocalStore(dir, publicURL, []byte(secret))
+	case "s3":
+		s := &S3Store{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		}
+		if s.Bucket == "" || s.AccessKeyID == "" || s.SecretAccessKey == "" {
+			return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set when BLOB_STORE is s3")
+		}
+		if s.Region == "" {
+			s.Region = "us-east-1"
+		}
+		s.PathStyle = s.Endpoint != "" && os.Getenv("S3_PATH_STYLE") != "false"
+		if s.Endpoint == "" {
+			s.Endpoint = "https://s3." + s.Region + ".amazonaws.com"
+		}
+		s.Endpoint = strings.TrimRight(s.Endpoint, "/")
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", os.Getenv("BLOB_STORE"))
+	}
+}
+
+// Limits bound what can be uploaded
+type Limits struct {
+	// MaxSize is the largest blob in bytes
+	MaxSize int64
+	// Types are the allowed media types, a type ending in /* allows all its
+	// subtypes
+	Types []string
+}
+
+// DefaultMaxSize is the upload limit unless ATTACHMENT_MAX_SIZE is set
+const DefaultMaxSize = 25 << 20
+
+// DefaultTypes are the media types allowed unless ATTACHMENT_TYPES is set:
+// images, PDFs, office documents, plain text, CSV and zip archives
+var DefaultTypes = []string{
+	"image/*",
+	"application/pdf",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"application/vnd.oasis.opendocument.text",
+	"application/vnd.oasis.opendocument.spreadsheet",
+	"application/vnd.oasis.opendocument.presentation",
+	"text/plain",
+	"text/csv",
+	"application/zip",
+}
+
+// LimitsFromEnv returns the limits set by ATTACHMENT_MAX_SIZE, in bytes, and
+// ATTACHMENT_TYPES, a comma separated list of media types
+func LimitsFromEnv() (Limits, error) {
+	limits := Limits{MaxSize: DefaultMaxSize, Types: DefaultTypes}
+	if size := os.Getenv("ATTACHMENT_MAX_SIZE"); size != "" {
+		value, err := strconv.ParseInt(size, 10, 64)
+		if err != nil || value <= 0 {
+			return limits, fmt.Errorf("invalid ATTACHMENT_MAX_SIZE %q", size)
+		}
+		limits.MaxSize = value
+	}
+	if types := os.Getenv("ATTACHMENT_TYPES"); types != "" {
+		limits.Types = nil
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				limits.Types = append(limits.Types, t)
+			}
+		}
+	}
+	return limits, nil
+}
+
+// Allows reports whether the media type of contentType is allowed
+func (l Limits) Allows(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range l.Types {
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// contentDisposition makes the browser save the blob as filename
+func contentDisposition(filename string) string {
+	if filename == "" {
+		return "attachment"
+	}
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
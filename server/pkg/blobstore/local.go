@@ -0,0 +1,153 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps blobs as files in a directory and serves them itself,
+// through URLs signed with an HMAC of the key, expiry and presentation
+type LocalStore struct {
+	Dir       string
+	PublicURL string
+	secret    []byte
+}
+
+func NewLocalStore(dir, publicURL string, secret []byte) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %v", err)
+	}
+	return &LocalStore{Dir: dir, PublicURL: strings.TrimRight(publicURL, "/"), secret: secret}, nil
+}
+
+// path maps a key to a file, keys can not leave the directory
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, info Info) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Write next to the final file, a partial upload never takes the key
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(f, hash), body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size >= 0 && written != info.Size {
+		return fmt.Errorf("blob %q is %d bytes, expected %d", key, written, info.Size)
+	}
+	if info.SHA256 != "" && hex.EncodeToString(hash.Sum(nil)) != info.SHA256 {
+		return fmt.Errorf("blob %q does not match its checksum", key)
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(ctx context.Context, key string, download Download, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expires).Unix(), 10))
+	query.Set("filename", download.Filename)
+	query.Set("type", download.ContentType)
+	query.Set("signature", s.sign(key, query))
+	return s.PublicURL + "/" + escapePath(key) + "?" + query.Encode(), nil
+}
+
+func (s *LocalStore) sign(key string, query url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", key, query.Get("expires"), query.Get("filename"), query.Get("type"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP serves the blob a signed URL points to, mount it on the path of
+// PublicURL with the prefix stripped
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || !hmac.Equal([]byte(s.sign(key, query)), []byte(query.Get("signature"))) {
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > expires {
+		http.Error(w, "Link expired", http.StatusForbidden)
+		return
+	}
+	path, err := s.path(key)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if contentType := query.Get("type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Content-Disposition", contentDisposition(query.Get("filename")))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.FormatInt(max(expires-time.Now().Unix(), 0), 10))
+	http.ServeContent(w, r, "", stat.ModTime(), f)
+}
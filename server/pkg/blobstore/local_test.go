@@ -0,0 +1,227 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	s, err := NewLocalStore(filepath.Join(t.TempDir(), "blobs"), "http://files.test/blobs/", []byte("test secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func putString(t *testing.T, s BlobStore, key, content string) {
+	t.Helper()
+	info := Info{Size: int64(len(content)), ContentType: "text/plain", SHA256: checksum(content)}
+	if err := s.Put(context.Background(), key, strings.NewReader(content), info); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+}
+
+func readAll(t *testing.T, s BlobStore, key string) string {
+	t.Helper()
+	r, err := s.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%q): %v", key, err)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// serve requests the signed URL from the handler of the store, as the router
+// does once it stripped PublicURL
+func serve(s *LocalStore, signed string) *httptest.ResponseRecorder {
+	target := strings.TrimPrefix(signed, s.PublicURL)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestLocalStorePutOpenDelete(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+	putString(t, s, "org/file", "hello")
+	if got := readAll(t, s, "org/file"); got != "hello" {
+		t.Errorf("Open = %q, want %q", got, "hello")
+	}
+	if err := s.Delete(ctx, "org/file"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Open(ctx, "org/file"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+	// Deleting what is gone is not an error
+	if err := s.Delete(ctx, "org/file"); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+}
+
+func TestLocalStorePutRejectsMismatch(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		info Info
+	}{
+		{"checksum", Info{Size: 5, SHA256: checksum("other")}},
+		{"size", Info{Size: 4, SHA256: checksum("hello")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Put(ctx, "org/file", strings.NewReader("hello"), tt.info); err == nil {
+				t.Fatal("Put succeeded")
+			}
+			if _, err := s.Open(ctx, "org/file"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Open after a failed Put = %v, want ErrNotFound", err)
+			}
+			entries, _ := os.ReadDir(filepath.Join(s.Dir, "org"))
+			if len(entries) != 0 {
+				t.Errorf("a failed Put left %d files behind", len(entries))
+			}
+		})
+	}
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+	for _, key := range []string{"", "../outside", "org/../../outside", "/etc/passwd", "org/./file"} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), Info{Size: 1}); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+		if _, err := s.Open(ctx, key); err == nil {
+			t.Errorf("Open(%q) succeeded", key)
+		}
+		if _, err := s.URL(ctx, key, Download{}, time.Minute); err == nil {
+			t.Errorf("URL(%q) succeeded", key)
+		}
+	}
+}
+
+func TestLocalStoreServesSignedURL(t *testing.T) {
+	s := newTestLocalStore(t)
+	putString(t, s, "org/report", "quarterly numbers")
+	signed, err := s.URL(context.Background(), "org/report", Download{Filename: "Q3 report.txt", ContentType: "text/plain"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed, "http://files.test/blobs/org/report?") {
+		t.Fatalf("URL = %q, want it under the public URL", signed)
+	}
+	rec := serve(s, signed)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Body.String(); got != "quarterly numbers" {
+		t.Errorf("body = %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="Q3 report.txt"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q", got)
+	}
+}
+
+func TestLocalStoreRejectsTamperedURL(t *testing.T) {
+	s := newTestLocalStore(t)
+	putString(t, s, "org/a", "a")
+	putString(t, s, "org/b", "b")
+	signed, err := s.URL(context.Background(), "org/a", Download{Filename: "a.txt", ContentType: "text/plain"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := map[string]func(u *url.URL, q url.Values){
+		"signature": func(u *url.URL, q url.Values) { q.Set("signature", strings.Repeat("0", 64)) },
+		"missing":   func(u *url.URL, q url.Values) { q.Del("signature") },
+		"key":       func(u *url.URL, q url.Values) { u.Path = "/blobs/org/b" },
+		"expiry":    func(u *url.URL, q url.Values) { q.Set("expires", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)) },
+		"filename":  func(u *url.URL, q url.Values) { q.Set("filename", "invoice.exe") },
+		"type":      func(u *url.URL, q url.Values) { q.Set("type", "text/html") },
+	}
+	for name, change := range tamper {
+		t.Run(name, func(t *testing.T) {
+			u, err := url.Parse(signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := u.Query()
+			change(u, q)
+			u.RawQuery = q.Encode()
+			rec := serve(s, u.String())
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403", rec.Code)
+			}
+		})
+	}
+}
+
+func TestLocalStoreRejectsExpiredURL(t *testing.T) {
+	s := newTestLocalStore(t)
+	putString(t, s, "org/a", "a")
+	signed, err := s.URL(context.Background(), "org/a", Download{}, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(s, signed)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("body = %q, want it to say the link expired", rec.Body)
+	}
+}
+
+func TestLocalStoreDoesNotServeEscapingKey(t *testing.T) {
+	s := newTestLocalStore(t)
+	// A file next to the blob directory, a correctly signed URL must still not
+	// reach it
+	outside := filepath.Join(filepath.Dir(s.Dir), "outside")
+	if err := os.WriteFile(outside, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+	query.Set("filename", "")
+	query.Set("type", "")
+	query.Set("signature", s.sign("../outside", query))
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req.URL.Path = "/../outside"
+	req.URL.RawQuery = query.Encode()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "secret") {
+		t.Error("served a file outside the blob directory")
+	}
+}
@@ -0,0 +1,280 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Store keeps blobs in a bucket of S3 or a compatible service such as
+// MinIO, signing requests with AWS Signature Version 4
+type S3Store struct {
+	// Endpoint is the base URL of the service, e.g. http://localhost:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket in the path instead of the host name,
+	// which local stand-ins need
+	PathStyle bool
+
+	Client *http.Client
+}
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3EmptyBodyHash  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3TimeFormat     = "20060102T150405Z"
+	s3DateFormat     = "20060102"
+	s3ClientTimeout  = 5 * time.Minute
+	s3ErrorBodyLimit = 1 << 10
+)
+
+func (s *S3Store) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return &http.Client{Timeout: s3ClientTimeout}
+}
+
+// objectURL returns the URL of the key in the bucket
+func (s *S3Store) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %v", err)
+	}
+	if s.PathStyle {
+		u.Path = "/" + s.Bucket + "/" + key
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = escapePath(u.Path)
+	return u, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, info Info) error {
+	payloadHash := info.SHA256
+	if payloadHash == "" {
+		payloadHash = s3UnsignedBody
+	}
+	if info.Size == 0 {
+		// An empty body of unknown length would be sent chunked
+		body = http.NoBody
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, body, payloadHash)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size
+	if info.ContentType != "" {
+		req.Header.Set("Content-Type", info.ContentType)
+	}
+	s.sign(req, payloadHash, time.Now())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s3Error(resp)
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, s3EmptyBodyHash)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, s3EmptyBodyHash, time.Now())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s3Error(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, s3EmptyBodyHash)
+	if err != nil {
+		return err
+	}
+	s.sign(req, s3EmptyBodyHash, time.Now())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// S3 answers 204 whether or not the key existed
+	if err := s3Error(resp); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// URL presigns a GET of the key, the query asks S3 to answer with the
+// filename and content type of the download
+func (s *S3Store) URL(ctx context.Context, key string, download Download, expires time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	expires = min(max(expires, time.Second), MaxURLExpiry)
+	now := time.Now().UTC()
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.AccessKeyID+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	query.Set("response-content-disposition", contentDisposition(download.Filename))
+	if download.ContentType != "" {
+		query.Set("response-content-type", download.ContentType)
+	}
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(canonical, now))
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader, payloadHash string) (*http.Request, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	return req, nil
+}
+
+// sign adds the Authorization header of the request, signing the host and
+// every X-Amz and Content-Type header
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKeyID, s.scope(now), signedHeaders, s.signature(canonical, now)))
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + s.Region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request with the key derived for the day
+func (s *S3Store) signature(canonical string, now time.Time) string {
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := s3Algorithm + "\n" + now.Format(s3TimeFormat) + "\n" + s.scope(now) + "\n" + hex.EncodeToString(hash[:])
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes the query sorted by name, as signatures expect
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapePath encodes a path the way S3 signs it, keeping the slashes
+func escapePath(path string) string {
+	return uriEncode(path, false)
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and slashes
+// unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Error turns an unsuccessful response into an error
+func s3Error(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+	return fmt.Errorf("S3 answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
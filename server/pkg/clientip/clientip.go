@@ -0,0 +1,90 @@
+// Package clientip finds the address of the caller of a request
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// FromRequest returns the address of the caller. X-Forwarded-For is only
+// believed when the request comes from a proxy in TRUSTED_PROXIES, and then
+// only up to the right-most hop that is not a trusted proxy, the hops left of
+// it are whatever the caller sent.
+func FromRequest(r *http.Request) string {
+	return fromRequest(r, trustedProxies(os.Getenv("TRUSTED_PROXIES")))
+}
+
+func fromRequest(r *http.Request, trusted []*net.IPNet) string {
+	ip := remoteIP(r.RemoteAddr)
+	if !isTrusted(ip, trusted) {
+		return ip
+	}
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip = hops[i]
+		if !isTrusted(ip, trusted) {
+			return ip
+		}
+	}
+	// Every hop is a proxy, the left-most one is as close to the caller as it
+	// gets
+	return ip
+}
+
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// trustedProxies parses a comma separated list of IPs and CIDRs, entries
+// that parse as neither are skipped
+func trustedProxies(list string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				continue
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if _, n, err := net.ParseCIDR(entry); err == nil {
+			nets = append(nets, n)
+		}
+	}
+	return nets
+}
+
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromRequest(t *testing.T) {
+	trusted := trustedProxies("10.0.0.0/8, 192.0.2.1, ::1, not an ip")
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"direct", "198.51.100.7:1234", nil, "198.51.100.7"},
+		{"header of an untrusted caller", "198.51.100.7:1234", []string{"203.0.113.9"}, "198.51.100.7"},
+		{"through a proxy", "10.0.0.2:1234", []string{"203.0.113.9"}, "203.0.113.9"},
+		{"spoofed hop left of the real one", "10.0.0.2:1234", []string{"1.2.3.4, 203.0.113.9"}, "203.0.113.9"},
+		{"through two proxies", "10.0.0.2:1234", []string{"203.0.113.9, 192.0.2.1"}, "203.0.113.9"},
+		{"headers are joined", "10.0.0.2:1234", []string{"1.2.3.4", "203.0.113.9, 10.0.0.3"}, "203.0.113.9"},
+		{"garbage hop", "10.0.0.2:1234", []string{"203.0.113.9, nonsense"}, "nonsense"},
+		{"only proxies", "10.0.0.2:1234", []string{"10.0.0.4, 10.0.0.3"}, "10.0.0.4"},
+		{"proxy without header", "10.0.0.2:1234", nil, "10.0.0.2"},
+		{"ipv6 proxy", "[::1]:1234", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"no port", "198.51.100.7", nil, "198.51.100.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/auth/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := fromRequest(r, trusted); got != tt.want {
+				t.Errorf("fromRequest = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRequestTrustsNoProxyByDefault(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	r := httptest.NewRequest("POST", "/auth/login", nil)
+	r.RemoteAddr = "127.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "203.0.113.9")
+	if got := FromRequest(r); got != "127.0.0.1" {
+		t.Errorf("FromRequest = %q, want the remote address", got)
+	}
+}
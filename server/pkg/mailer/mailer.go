@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails, the server picks an implementation from the env
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var ErrInvalidHeader = errors.New("Invalid email header")
+
+// FromEnv returns the mailer configured by MAILER:
+//   - smtp sends through SMTP_HOST:SMTP_PORT as MAIL_FROM, with SMTP_USERNAME
+//     and SMTP_PASSWORD when set
+//   - file appends every email to MAILER_FILE
+//   - log, the default, prints every email to the server log
+func FromEnv() (Mailer, error) {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		m := &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+		if m.Host == "" || m.From == "" {
+			return nil, fmt.Errorf("SMTP_HOST and MAIL_FROM must be set when MAILER is smtp")
+		}
+		if m.Port == "" {
+			m.Port = "587"
+		}
+		return m, nil
+	case "file":
+		path := os.Getenv("MAILER_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("MAILER_FILE must be set when MAILER is file")
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mailer file: %v", err)
+		}
+		return NewWriterMailer(f), nil
+	case "", "log":
+		return NewWriterMailer(log.Writer()), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", os.Getenv("MAILER"))
+	}
+}
+
+// SMTPMailer sends emails through an SMTP server, using STARTTLS when the
+// server offers it
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	data, err := format(m.From, msg)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	// net/smtp takes no context, give up on the result once ctx is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, []string{msg.To}, data)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriterMailer writes emails to w instead of sending them, for local testing
+type WriterMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterMailer(w io.Writer) *WriterMailer {
+	return &WriterMailer{w: w}
+}
+
+func (m *WriterMailer) Send(ctx context.Context, msg Message) error {
+	data, err := format("muninn@localhost", msg)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err = fmt.Fprintf(m.w, "----- mail %s -----\n%s\n", time.Now().Format(time.RFC3339), data)
+	return err
+}
+
+func format(from string, msg Message) ([]byte, error) {
+	// A line break in a header would let the value add headers of its own
+	for _, v := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
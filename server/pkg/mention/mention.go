@@ -0,0 +1,54 @@
+package mention
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of records a mention can point to
+const (
+	KindObject  = "object"
+	KindCreator = "creator"
+)
+
+// Mention is a reference written in text as @[Name](kind:id), the markup the
+// web app editor inserts
+type Mention struct {
+	Kind string
+	ID   uuid.UUID
+	Name string
+}
+
+var markup = regexp.MustCompile(`@\[([^\]]+)\]\((\w+):([^)]+)\)`)
+
+// Parse returns the mentions of text in order, each record once. Mentions
+// with an id that is not a UUID are left out.
+func Parse(text string) []Mention {
+	var mentions []Mention
+	seen := map[string]bool{}
+	for _, m := range markup.FindAllStringSubmatch(text, -1) {
+		id, err := uuid.Parse(m[3])
+		if err != nil {
+			continue
+		}
+		key := m[2] + ":" + id.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, Mention{Kind: m[2], ID: id, Name: m[1]})
+	}
+	return mentions
+}
+
+// IDs returns the ids of the mentions of kind
+func IDs(mentions []Mention, kind string) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, m := range mentions {
+		if m.Kind == kind {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids
+}
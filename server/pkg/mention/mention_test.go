@@ -0,0 +1,73 @@
+package mention
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ada   = uuid.MustParse("6f1c2a4e-5b7d-4c8e-9f0a-1b2c3d4e5f60")
+	grace = uuid.MustParse("0a9b8c7d-6e5f-4a3b-8c2d-1e0f9a8b7c6d")
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Mention
+	}{
+		{"none", "met at the fair", nil},
+		{"object", "met @[Ada](object:" + ada.String() + ") at the fair",
+			[]Mention{{KindObject, ada, "Ada"}}},
+		{"in order", "@[Grace](creator:" + grace.String() + ") met @[Ada](object:" + ada.String() + ")",
+			[]Mention{{KindCreator, grace, "Grace"}, {KindObject, ada, "Ada"}}},
+		{"punctuation around", "(@[Ada](object:" + ada.String() + ")), then @[Grace](creator:" + grace.String() + ").",
+			[]Mention{{KindObject, ada, "Ada"}, {KindCreator, grace, "Grace"}}},
+		{"punctuation in the name", "@[Ada L., Countess!](object:" + ada.String() + ")?",
+			[]Mention{{KindObject, ada, "Ada L., Countess!"}}},
+		{"no space before", "cc:@[Ada](object:" + ada.String() + ")",
+			[]Mention{{KindObject, ada, "Ada"}}},
+		{"email", "write to ada@example.com or @ada", nil},
+		{"email as the name", "@[ada@example.com](creator:" + ada.String() + ")",
+			[]Mention{{KindCreator, ada, "ada@example.com"}}},
+		{"email next to a mention", "ada@example.com @[Ada](object:" + ada.String() + ")",
+			[]Mention{{KindObject, ada, "Ada"}}},
+		{"duplicate", "@[Ada](object:" + ada.String() + ") and again @[Lovelace](object:" + ada.String() + ")",
+			[]Mention{{KindObject, ada, "Ada"}}},
+		{"duplicate in other case", "@[Ada](object:" + ada.String() + ") @[Ada](object:" + strings.ToUpper(ada.String()) + ")",
+			[]Mention{{KindObject, ada, "Ada"}}},
+		{"same id of other kinds", "@[Ada](object:" + ada.String() + ") @[Ada](creator:" + ada.String() + ")",
+			[]Mention{{KindObject, ada, "Ada"}, {KindCreator, ada, "Ada"}}},
+		{"id not a uuid", "@[Ada](object:42) @[Grace](creator:" + grace.String() + ")",
+			[]Mention{{KindCreator, grace, "Grace"}}},
+		{"markup not closed", "@[Ada](object:" + ada.String(), nil},
+		{"empty name", "@[](object:" + ada.String() + ")", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDs(t *testing.T) {
+	mentions := Parse("@[Ada](object:" + ada.String() + ") @[Grace](creator:" + grace.String() + ") @[Grace](object:" + grace.String() + ")")
+	tests := []struct {
+		kind string
+		want []uuid.UUID
+	}{
+		{KindObject, []uuid.UUID{ada, grace}},
+		{KindCreator, []uuid.UUID{grace}},
+		{"task", []uuid.UUID{}},
+	}
+	for _, tt := range tests {
+		if got := IDs(mentions, tt.kind); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("IDs(%s) = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
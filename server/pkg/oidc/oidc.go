@@ -0,0 +1,365 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Provider is an OpenID Connect identity provider used with the authorization
+// code flow and PKCE. Its metadata and keys are fetched on first use.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// client is http.DefaultClient when nil, as for providers built in tests
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     map[string]interface{}
+	keysAt   time.Time
+}
+
+// Metadata is the part of the discovery document the flow needs
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims are the claims of a verified ID token
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+var ErrInvalidIDToken = errors.New("Invalid ID token")
+
+// keysMinRefresh keeps an unknown kid from refetching the keys on every login
+const keysMinRefresh = time.Minute
+
+// FromEnv returns the provider configured by OIDC_ISSUER, OIDC_CLIENT_ID,
+// OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL and OIDC_SCOPES, or nil when
+// OIDC_ISSUER is not set
+func FromEnv() (*Provider, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+	p := &Provider{
+		Issuer:       strings.TrimRight(issuer, "/"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       []string{"openid", "email", "profile"},
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	if p.ClientID == "" || p.RedirectURL == "" {
+		return nil, fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL must be set with OIDC_ISSUER")
+	}
+	if scopes := os.Getenv("OIDC_SCOPES"); scopes != "" {
+		p.Scopes = strings.Fields(scopes)
+	}
+	return p, nil
+}
+
+// Discover returns the metadata of the issuer, fetched once
+func (p *Provider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+	var m Metadata
+	if err := p.getJSON(ctx, p.Issuer+"/.well-known/openid-configuration", &m); err != nil {
+		return nil, fmt.Errorf("discovery failed: %v", err)
+	}
+	// The document must belong to the issuer it was fetched from
+	if strings.TrimRight(m.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("discovery returned issuer %q, expected %q", m.Issuer, p.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document of %q is incomplete", p.Issuer)
+	}
+	p.metadata = &m
+	return p.metadata, nil
+}
+
+// AuthCodeURL returns the url of the IdP login page
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	m, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.ClientID)
+	v.Set("redirect_uri", p.RedirectURL)
+	v.Set("scope", strings.Join(p.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", CodeChallenge(verifier))
+	v.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(m.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return m.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// CodeChallenge is the S256 PKCE challenge of a verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Exchange trades an authorization code for the ID token of the login
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string) (string, error) {
+	m, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.ClientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint answered %d: %s", resp.StatusCode, body)
+	}
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return "", err
+	}
+	if tokens.IDToken == "" {
+		return "", fmt.Errorf("token endpoint returned no id_token")
+	}
+	return tokens.IDToken, nil
+}
+
+// idTokenClaims are the claims checked on top of the registered ones
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string      `json:"nonce"`
+	AuthorizedBy  string      `json:"azp"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of
+// an ID token
+func (p *Provider) VerifyIDToken(ctx context.Context, raw string, nonce string) (Claims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512"}),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%w: nonce does not match", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.ClientID {
+		return Claims{}, fmt.Errorf("%w: azp does not match", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	return Claims{
+		Issuer:        p.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+	}, nil
+}
+
+// key returns the signing key with the kid, refetching the keys when the
+// IdP rotated them
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	m, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.lookupKey(kid); ok {
+		return k, nil
+	}
+	if time.Since(p.keysAt) < keysMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, m.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("fetching keys failed: %v", err)
+	}
+	p.keys = map[string]interface{}{}
+	p.keysAt = time.Now()
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		k, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		p.keys[jwk.Kid] = k
+	}
+	if k, ok := p.lookupKey(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds kid, a token without kid is accepted when the IdP has one key
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok
+}
+
+func (p *Provider) httpClient() *http.Client {
+	if p.client == nil {
+		return http.DefaultClient
+	}
+	return p.client
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d", u, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("EC key is not on its curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,192 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/pkg/oidc"
+	"github.com/crea8r/muninn/server/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testNonce    = "test nonce"
+	testVerifier = "test verifier"
+)
+
+// login runs the flow up to the redirect back, as the IdP user of claims
+func login(t *testing.T, idp *oidctest.IdP, p *oidc.Provider, claims jwt.MapClaims) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), "test state", testNonce, testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.Login(t, authURL, claims)
+	if state != "test state" {
+		t.Fatalf("state = %q, want it passed through", state)
+	}
+	return code
+}
+
+func TestLoginFlow(t *testing.T) {
+	idp := oidctest.NewIdP(t)
+	p := idp.Provider()
+	ctx := context.Background()
+	claims := idp.Claims("user-1", "ada@example.com")
+	claims["name"] = "Ada"
+	code := login(t, idp, p, claims)
+
+	raw, err := p.Exchange(ctx, code, testVerifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	got, err := p.VerifyIDToken(ctx, raw, testNonce)
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	want := oidc.Claims{Issuer: idp.URL, Subject: "user-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+	if got != want {
+		t.Errorf("claims = %+v, want %+v", got, want)
+	}
+
+	// A code works once
+	if _, err := p.Exchange(ctx, code, testVerifier); err == nil {
+		t.Error("second Exchange of the code succeeded")
+	}
+}
+
+func TestAuthCodeURLSendsChallenge(t *testing.T) {
+	idp := oidctest.NewIdP(t)
+	authURL, err := idp.Provider().AuthCodeURL(context.Background(), "s", "n", testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if got := q.Get("code_challenge"); got != oidc.CodeChallenge(testVerifier) {
+		t.Errorf("code_challenge = %q, want the S256 challenge of the verifier", got)
+	}
+	if strings.Contains(authURL, testVerifier) {
+		t.Error("the login page url carries the verifier")
+	}
+	if got := q.Get("client_id"); got != oidctest.ClientID {
+		t.Errorf("client_id = %q", got)
+	}
+}
+
+func TestExchangeRejectsVerifierMismatch(t *testing.T) {
+	idp := oidctest.NewIdP(t)
+	p := idp.Provider()
+	code := login(t, idp, p, idp.Claims("user-1", "ada@example.com"))
+	if _, err := p.Exchange(context.Background(), code, "other verifier"); err == nil {
+		t.Fatal("Exchange with the wrong verifier succeeded")
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	idp := oidctest.NewIdP(t)
+	p := idp.Provider()
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		nonce string
+		token func(claims jwt.MapClaims) string
+	}{
+		{"wrong audience", testNonce, func(c jwt.MapClaims) string {
+			c["aud"] = "other client"
+			return idp.Sign(t, c)
+		}},
+		{"wrong issuer", testNonce, func(c jwt.MapClaims) string {
+			c["iss"] = "https://evil.example.com"
+			return idp.Sign(t, c)
+		}},
+		{"expired", testNonce, func(c jwt.MapClaims) string {
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return idp.Sign(t, c)
+		}},
+		{"no expiry", testNonce, func(c jwt.MapClaims) string {
+			delete(c, "exp")
+			return idp.Sign(t, c)
+		}},
+		{"bad nonce", "other nonce", func(c jwt.MapClaims) string {
+			return idp.Sign(t, c)
+		}},
+		{"no nonce", "", func(c jwt.MapClaims) string {
+			delete(c, "nonce")
+			return idp.Sign(t, c)
+		}},
+		{"no subject", testNonce, func(c jwt.MapClaims) string {
+			delete(c, "sub")
+			return idp.Sign(t, c)
+		}},
+		{"other key", testNonce, func(c jwt.MapClaims) string {
+			tok := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+			tok.Header["kid"] = oidctest.KeyID
+			raw, err := tok.SignedString(otherKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return raw
+		}},
+		{"unsigned", testNonce, func(c jwt.MapClaims) string {
+			raw, err := jwt.NewWithClaims(jwt.SigningMethodNone, c).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return raw
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := idp.Claims("user-1", "ada@example.com")
+			claims["nonce"] = testNonce
+			_, err := p.VerifyIDToken(context.Background(), tt.token(claims), tt.nonce)
+			if !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("VerifyIDToken = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenEmailVerified(t *testing.T) {
+	idp := oidctest.NewIdP(t)
+	p := idp.Provider()
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{true, true},
+		{"true", true},
+		{false, false},
+		{"false", false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		claims := idp.Claims("user-1", "ada@example.com")
+		claims["nonce"] = testNonce
+		if tt.value == nil {
+			delete(claims, "email_verified")
+		} else {
+			claims["email_verified"] = tt.value
+		}
+		got, err := p.VerifyIDToken(context.Background(), idp.Sign(t, claims), testNonce)
+		if err != nil {
+			t.Fatalf("email_verified %v: %v", tt.value, err)
+		}
+		if got.EmailVerified != tt.want {
+			t.Errorf("email_verified %v gave EmailVerified %v, want %v", tt.value, got.EmailVerified, tt.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package oidctest runs an OpenID Connect identity provider for tests. It
+// serves discovery, its signing keys and a token endpoint that checks the
+// PKCE verifier, the login page is skipped by Login.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crea8r/muninn/server/pkg/oidc"
+	"github.com/crea8r/muninn/server/pkg/token"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "muninn"
+	ClientSecret = "client secret"
+	RedirectURL  = "http://app.test/sso/callback"
+	KeyID        = "test-key"
+)
+
+// IdP is an identity provider on a local HTTP server
+type IdP struct {
+	*httptest.Server
+	Key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant is an authorization code waiting to be exchanged
+type grant struct {
+	challenge string
+	idToken   string
+}
+
+// NewIdP starts an identity provider that stops when the test ends
+func NewIdP(t testing.TB) *IdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &IdP{Key: key, grants: map[string]grant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// Provider returns a provider registered with the IdP
+func (idp *IdP) Provider() *oidc.Provider {
+	return &oidc.Provider{
+		Issuer:       idp.URL,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+// Claims returns the claims of a valid ID token of the user, without nonce
+func (idp *IdP) Claims(subject string, email string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            idp.URL,
+		"aud":            ClientID,
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// Sign returns claims as an ID token signed with the key of the IdP
+func (idp *IdP) Sign(t testing.TB, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = KeyID
+	raw, err := tok.SignedString(idp.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// Login plays the user logging in at the login page authURL points to. It
+// returns the code and state the IdP redirects back with, the code is
+// exchanged for an ID token with claims and the nonce of authURL unless
+// claims has one.
+func (idp *IdP) Login(t testing.TB, authURL string, claims jwt.MapClaims) (code string, state string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("login page url %s has no S256 code challenge", authURL)
+	}
+	if _, ok := claims["nonce"]; !ok {
+		claims["nonce"] = q.Get("nonce")
+	}
+	code, err = token.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	idp.grants[code] = grant{challenge: q.Get("code_challenge"), idToken: idp.Sign(t, claims)}
+	idp.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (idp *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 idp.URL,
+		"authorization_endpoint": idp.URL + "/authorize",
+		"token_endpoint":         idp.URL + "/token",
+		"jwks_uri":               idp.URL + "/jwks",
+	})
+}
+
+func (idp *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := idp.Key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// token exchanges a code once, for the client it was issued to and the
+// verifier of its challenge
+func (idp *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	user, pass, _ := r.BasicAuth()
+	if user != ClientID || pass != url.QueryEscape(ClientSecret) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	code := r.PostForm.Get("code")
+	idp.mu.Lock()
+	g, ok := idp.grants[code]
+	delete(idp.grants, code)
+	idp.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != RedirectURL ||
+		oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-" + code,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     g.idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,41 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// The SHA1 test vectors of RFC 6238 Appendix B. The RFC lists 8 digit codes,
+// a 6 digit code is their last 6 digits.
+func TestCodeRFC6238(t *testing.T) {
+	// base32 of the ASCII seed "12345678901234567890"
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		unix int64
+		step int64
+		want string
+	}{
+		{59, 0x1, "287082"},
+		{1111111109, 0x23523EC, "081804"},
+		{1111111111, 0x23523ED, "050471"},
+		{1234567890, 0x273EF07, "005924"},
+		{2000000000, 0x3F940AA, "279037"},
+		{20000000000, 0x27BC86AA, "353130"},
+	}
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0).UTC()
+		if got := Step(now); got != tt.step {
+			t.Errorf("Step(%d) = %#x, want %#x", tt.unix, got, tt.step)
+		}
+		got, err := Code(secret, tt.step)
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+		if step, ok := Validate(secret, tt.want, now, 0); !ok || step != tt.step {
+			t.Errorf("Validate at %d = %#x, %v, want %#x, true", tt.unix, step, ok, tt.step)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 func IsAdmin(ctx context.Context) bool {
 	claims := ctx.Value(middleware.UserClaimsKey).(*middleware.Claims)
-	return claims.Role == "admin"
+	return claims.Role == middleware.RoleAdmin
 }
 
 func GetOrgIDFromContext(ctx context.Context) (uuid.UUID) {
@@ -17,6 +17,11 @@ func GetOrgIDFromContext(ctx context.Context) (uuid.UUID) {
 	return uuid.MustParse(claims.OrgID)
 }
 
+func GetCreatorIDFromContext(ctx context.Context) uuid.UUID {
+	claims := ctx.Value(middleware.UserClaimsKey).(*middleware.Claims)
+	return uuid.MustParse(claims.CreatorID)
+}
+
 func IsCreator(ctx context.Context, creatorID uuid.UUID) bool {
 	claims := ctx.Value(middleware.UserClaimsKey).(*middleware.Claims)
 	return uuid.MustParse(claims.CreatorID)  == creatorID
@@ -58,7 +58,7 @@ const App: React.FC = () => {
   const details = authService.getDetails();
 
   useEffect(() => {
-    if (details) {
+    if (details?.orgName) {
       document.title = details.orgName;
     } else {
       document.title = 'Muninn, shape your data your ways';
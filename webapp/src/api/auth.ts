@@ -1,9 +1,13 @@
 import axios from 'axios';
+import authService, { Me } from 'src/services/authService';
+import { axiosWithAuth } from './utils';
 
 const API_URL = process.env.REACT_APP_API_URL;
 
 interface LoginResponse {
   token: string;
+  refresh_token: string;
+  expires_at: string;
   creators?: any[];
 }
 
@@ -52,3 +56,43 @@ export const signup = async (req: SignUpRequest): Promise<void> => {
     );
   }
 };
+
+export const getMe = async (): Promise<Me> => {
+  const response = await axiosWithAuth().get('/auth/me');
+  return response.data;
+};
+
+let refreshing: Promise<string> | null = null;
+
+// refreshTokens trades the refresh token for a new pair and resolves to the
+// new access token. A refresh token works only once and presenting it again
+// ends the login, so concurrent callers share one request.
+export const refreshTokens = (): Promise<string> => {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const refreshToken = authService.getRefreshToken();
+      if (!refreshToken) {
+        throw new Error('Not logged in');
+      }
+      const response = await axios.post<LoginResponse>(
+        `${API_URL}/auth/refresh`,
+        { refresh_token: refreshToken }
+      );
+      const token = response.data.token;
+      authService.setTokens(token, response.data.refresh_token);
+      try {
+        // Not through axiosWithAuth, a 401 here must not refresh again
+        const me = await axios.get<Me>(`${API_URL}/auth/me`, {
+          headers: { Authorization: `Bearer ${token}` },
+        });
+        authService.setMe(me.data);
+      } catch {
+        // The names shown stay as they were until the next refresh
+      }
+      return token;
+    })().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+};
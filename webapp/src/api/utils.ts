@@ -1,5 +1,6 @@
 import axios from 'axios';
 import authService from 'src/services/authService';
+import { refreshTokens } from './auth';
 
 export const axiosWithAuth = () => {
   const token = authService.getToken();
@@ -14,8 +15,20 @@ export const axiosWithAuth = () => {
   // Add a response interceptor
   instance.interceptors.response.use(
     (response) => response,
-    (error) => {
+    async (error) => {
       if (error.response && error.response.status === 401) {
+        // Access tokens live 15 minutes, renew it once and retry
+        const request = error.config;
+        if (request && !request._retried && authService.getRefreshToken()) {
+          request._retried = true;
+          try {
+            const token = await refreshTokens();
+            request.headers.Authorization = `Bearer ${token}`;
+            return instance(request);
+          } catch {
+            authService.logout();
+          }
+        }
         // Redirect to login page
         if (window.location.pathname !== '/login') {
           window.location.href = '/login';
@@ -13,7 +13,7 @@ import {
 } from '@chakra-ui/react';
 import { Link as RouterLink, useHistory } from 'react-router-dom';
 import authService from 'src/services/authService';
-import { login } from 'src/api/auth';
+import { getMe, login } from 'src/api/auth';
 import { useGlobalContext } from 'src/contexts/GlobalContext';
 import LoadingScreen from 'src/components/LoadingScreen';
 
@@ -34,7 +34,12 @@ const LoginPage: React.FC = () => {
     setIsLoading(true);
     try {
       const response = await login(email, password);
-      authService.login(response.token, response.creators || []);
+      authService.login(
+        response.token,
+        response.refresh_token,
+        response.creators || []
+      );
+      authService.setMe(await getMe());
       toast({
         title: 'Login successful',
         status: 'success',
@@ -4,18 +4,28 @@ interface DecodedToken {
   exp: number;
   role: string;
   org_id: string;
-  org_name: string;
   creator_id: string;
-  name: string;
   iat: number;
+}
+
+// Me is what GET /auth/me returns, tokens no longer carry names or profiles
+export interface Me {
+  id: string;
+  username: string;
   profile: any;
+  role: string;
+  org_id: string;
+  org_name: string;
+  org_profile: any;
 }
 
 const TOKEN_KEY = 'auth_token';
+const REFRESH_TOKEN_KEY = 'auth_refresh_token';
+const ME_KEY = 'auth_me';
 
 const authService = {
-  login: (token: string, creators?: any[]) => {
-    localStorage.setItem(TOKEN_KEY, token);
+  login: (token: string, refreshToken: string, creators?: any[]) => {
+    authService.setTokens(token, refreshToken);
     if (creators) {
       localStorage.setItem('creators', JSON.stringify(creators));
     }
@@ -23,12 +33,38 @@ const authService = {
 
   logout: () => {
     localStorage.removeItem(TOKEN_KEY);
+    localStorage.removeItem(REFRESH_TOKEN_KEY);
+    localStorage.removeItem(ME_KEY);
+  },
+
+  setTokens: (token: string, refreshToken: string) => {
+    localStorage.setItem(TOKEN_KEY, token);
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+  },
+
+  setMe: (me: Me) => {
+    localStorage.setItem(ME_KEY, JSON.stringify(me));
   },
 
   getToken: (): string | null => {
     return localStorage.getItem(TOKEN_KEY);
   },
 
+  getRefreshToken: (): string | null => {
+    return localStorage.getItem(REFRESH_TOKEN_KEY);
+  },
+
+  getMe: (): Me | undefined => {
+    const me = localStorage.getItem(ME_KEY);
+    if (!me) return undefined;
+
+    try {
+      return JSON.parse(me);
+    } catch {
+      return undefined;
+    }
+  },
+
   getCreatorId: (): string | null => {
     const token = authService.getToken();
     if (!token) return null;
@@ -52,9 +88,12 @@ const authService = {
     }
   },
 
+  // An expired access token is renewed with the refresh token on the next
+  // request, the login only ends when that fails
   isAuthenticated: (): boolean => {
     const token = authService.getToken();
     if (!token) return false;
+    if (authService.getRefreshToken()) return true;
 
     try {
       const decodedToken = jwtDecode<DecodedToken>(token);
@@ -82,13 +121,14 @@ const authService = {
 
     try {
       const decodedToken = jwtDecode<DecodedToken>(token);
+      const me = authService.getMe();
       return {
         creatorId: decodedToken.creator_id,
         orgId: decodedToken.org_id,
-        name: decodedToken.name,
-        orgName: decodedToken.org_name,
+        name: me?.username || '',
+        orgName: me?.org_name || '',
         role: decodedToken.role,
-        profile: decodedToken.profile,
+        profile: me?.profile || {},
         others: authService.getOtherOwnedCreators(),
       };
     } catch {